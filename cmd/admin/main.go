@@ -0,0 +1,282 @@
+// Command admin runs maintenance operations against the database the service
+// uses, configured by the same environment variables:
+//
+//	admin force-password-change -phone +62812345678
+//	admin register-oauth-client -name "Partner App" -redirect-uris https://partner.example/callback -scopes profile:read
+//	admin register-oauth-client -name "Payment" -client-credentials -scopes profiles:read
+//	admin register-oauth-client -name "Weighing Kiosk" -device -public -scopes openid,profile
+//	admin assign-role -phone +62812345678 -role admin
+//	admin unassign-role -phone +62812345678 -role admin
+//
+// force-password-change ends every session of the profile and makes its
+// owner replace the password on their next login. register-oauth-client
+// prints the id of the new client, and the secret of a confidential one,
+// which cannot be shown again. assign-role gives the first admin the role
+// the role endpoints need.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/helper"
+	"sawitpro/repository"
+	"sawitpro/service"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "force-password-change":
+		err = forcePasswordChange(os.Args[2:])
+	case "register-oauth-client":
+		err = registerOAuthClient(os.Args[2:])
+	case "assign-role":
+		err = assignRole(os.Args[2:])
+	case "unassign-role":
+		err = unassignRole(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s force-password-change -phone <phone number>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s register-oauth-client -name <name> -redirect-uris <uri,...> [-post-logout-redirect-uris <uri,...>] -scopes <scope,...> [-public] [-client-credentials | -device]\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s assign-role -phone <phone number> -role <name>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "       %s unassign-role -phone <phone number> -role <name>\n", os.Args[0])
+}
+
+func forcePasswordChange(args []string) error {
+	flags := flag.NewFlagSet("force-password-change", flag.ExitOnError)
+	phoneNumber := flags.String("phone", "", "phone number of the profile")
+	flags.Parse(args)
+
+	if *phoneNumber == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	profileRepository := repository.NewUserProfileRepository(conn)
+
+	// only what revoking sessions needs, no tokens are issued from here
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		ProfileRepository:      profileRepository,
+		RefreshTokenRepository: repository.NewRefreshTokenRepository(conn),
+		SessionRepository:      repository.NewSessionRepository(conn),
+	})
+	profileService := service.NewProfileService(service.ProfileServiceDeps{
+		ProfileRepository: profileRepository,
+		AuthService:       authService,
+	})
+
+	err = profileService.ForcePasswordChange(context.Background(), entity.ForcePasswordChangeRequest{
+		PhoneNumber: *phoneNumber,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%s has to replace their password on the next login\n", *phoneNumber)
+
+	return nil
+}
+
+func registerOAuthClient(args []string) error {
+	flags := flag.NewFlagSet("register-oauth-client", flag.ExitOnError)
+	name := flags.String("name", "", "name of the client shown to users on consent")
+	redirectURIs := flags.String("redirect-uris", "", "comma separated redirect uris the client may use")
+	postLogoutRedirectURIs := flags.String("post-logout-redirect-uris", "", "comma separated uris an openid connect logout may return to")
+	scopes := flags.String("scopes", "", "comma separated scopes the client may ask for")
+	public := flags.Bool("public", false, "register a client that cannot keep a secret, such as a mobile app")
+	clientCredentials := flags.Bool("client-credentials", false, "register a service calling with a token of its own rather than signing users in")
+	device := flags.Bool("device", false, "register a device without a keyboard, users sign it in from another device")
+	flags.Parse(args)
+
+	if *clientCredentials && *device {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	request := entity.RegisterOAuthClientRequest{
+		Name:                   *name,
+		RedirectURIs:           splitList(*redirectURIs),
+		PostLogoutRedirectURIs: splitList(*postLogoutRedirectURIs),
+		Scopes:                 splitList(*scopes),
+		Confidential:           !*public,
+		ClientCredentials:      *clientCredentials,
+		Device:                 *device,
+	}
+
+	err := helper.NewValidatorHelper(helper.ValidatorHelperOptions{}).ValidateStruct(request)
+	if err != nil {
+		flags.Usage()
+		return err
+	}
+
+	conn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// hashing a secret needs neither signing keys nor a password hasher
+	oauthService := service.NewOAuthService(service.OAuthServiceDeps{
+		OAuthClientRepository: repository.NewOAuthClientRepository(conn),
+		Authhelper:            helper.NewAuthHelper(helper.AuthHelperOptions{}),
+	})
+
+	result, err := oauthService.RegisterOAuthClient(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "client_id: %s\n", result.Client.Id)
+	if result.ClientSecret != "" {
+		fmt.Fprintf(os.Stdout, "client_secret: %s\n", result.ClientSecret)
+	}
+
+	return nil
+}
+
+func assignRole(args []string) error {
+	flags := flag.NewFlagSet("assign-role", flag.ExitOnError)
+	phoneNumber := flags.String("phone", "", "phone number of the profile")
+	roleName := flags.String("role", "", "name of the role")
+	flags.Parse(args)
+
+	if *phoneNumber == "" || *roleName == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	profileId, err := profileIdByPhoneNumber(conn, *phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	roleService := service.NewRoleService(service.RoleServiceDeps{
+		RoleRepository:    repository.NewRoleRepository(conn),
+		ProfileRepository: repository.NewUserProfileRepository(conn),
+	})
+
+	err = roleService.AssignRole(context.Background(), entity.AssignRoleRequest{
+		ProfileId: profileId,
+		RoleName:  *roleName,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%s holds %s from their next login or refresh\n", *phoneNumber, *roleName)
+
+	return nil
+}
+
+func unassignRole(args []string) error {
+	flags := flag.NewFlagSet("unassign-role", flag.ExitOnError)
+	phoneNumber := flags.String("phone", "", "phone number of the profile")
+	roleName := flags.String("role", "", "name of the role")
+	flags.Parse(args)
+
+	if *phoneNumber == "" || *roleName == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	conn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	profileId, err := profileIdByPhoneNumber(conn, *phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	roleService := service.NewRoleService(service.RoleServiceDeps{
+		RoleRepository:    repository.NewRoleRepository(conn),
+		ProfileRepository: repository.NewUserProfileRepository(conn),
+	})
+
+	err = roleService.UnassignRole(context.Background(), entity.UnassignRoleRequest{
+		ProfileId: profileId,
+		RoleName:  *roleName,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%s no longer holds %s once their access tokens expire\n", *phoneNumber, *roleName)
+
+	return nil
+}
+
+func profileIdByPhoneNumber(conn *sqlx.DB, phoneNumber string) (string, error) {
+	profile, err := repository.NewUserProfileRepository(conn).GetProfileByPhoneNumber(context.Background(), nil, phoneNumber)
+	if err != nil {
+		return "", err
+	}
+
+	if profile.Id == "" {
+		return "", error_list.ErrProfileNotFound
+	}
+
+	return profile.Id, nil
+}
+
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func connectDB() (*sqlx.DB, error) {
+	connString := fmt.Sprintf("user=%s dbname=%s host=%s port=%s password=%s sslmode=disable",
+		constant.EnvPostgresUser,
+		constant.EnvPostgresDatabase,
+		constant.EnvPostgresHost,
+		constant.EnvPostgresPort,
+		constant.EnvPostgresPassword,
+	)
+
+	return sqlx.Open("pgx", connString)
+}
@@ -0,0 +1,76 @@
+// Command breachindex builds the breached password index the service screens
+// new passwords against, from a dump of the Pwned Passwords SHA-1 hashes:
+//
+//	breachindex -dump pwnedpasswords.txt -out breached-passwords.idx
+//
+// The dump is read from standard input when -dump is not given. It must be
+// ordered by hash, as the Pwned Passwords downloader writes it.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sawitpro/helper"
+)
+
+func main() {
+	dumpPath := flag.String("dump", "", "path of the HASH:COUNT dump, standard input when empty")
+	outPath := flag.String("out", "", "path to write the index to")
+	flag.Parse()
+
+	if *outPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	count, err := buildIndex(*dumpPath, *outPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to build index after %d hashes: %v\n", count, err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stdout, "Indexed %d hashes into %s\n", count, *outPath)
+}
+
+func buildIndex(dumpPath string, outPath string) (int64, error) {
+	var dump io.Reader = os.Stdin
+	if dumpPath != "" {
+		file, err := os.Open(dumpPath)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+
+		dump = file
+	}
+
+	// written next to the destination and renamed over it once complete, so
+	// a starting service never opens a half written index
+	out, err := os.CreateTemp(filepath.Dir(outPath), ".breachindex-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	count, err := helper.BuildBreachedPasswordIndex(dump, out)
+	if err != nil {
+		return count, err
+	}
+
+	// the service reading it may run as another user
+	err = out.Chmod(0644)
+	if err != nil {
+		return count, err
+	}
+
+	err = out.Close()
+	if err != nil {
+		return count, err
+	}
+
+	return count, os.Rename(out.Name(), outPath)
+}
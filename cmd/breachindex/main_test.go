@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sawitpro/error_list"
+	"sawitpro/helper"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_buildIndex(t *testing.T) {
+	// SHA-1 of "password" and of "123456", in order
+	const dump = "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n" +
+		"7C4A8D09CA3762AF61E59520943DC26494F8941B:37359195\r\n"
+
+	tests := []struct {
+		name      string
+		dump      string
+		wantCount int64
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			dump:      dump,
+			wantCount: 2,
+			wantErr:   nil,
+		},
+		{
+			name:      "error malformed dump",
+			dump:      dump + "not-a-hash\r\n",
+			wantCount: 2,
+			wantErr:   error_list.ErrMalformedBreachedPasswordDump,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dumpPath := filepath.Join(dir, "pwnedpasswords.txt")
+			outPath := filepath.Join(dir, "breached-passwords.idx")
+			assert.NoError(t, os.WriteFile(dumpPath, []byte(tt.dump), 0600))
+
+			got, err := buildIndex(dumpPath, outPath)
+			assert.Equal(t, tt.wantCount, got)
+			assert.Equal(t, tt.wantErr, err)
+
+			// nothing is left behind but the dump and a complete index
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+
+			if tt.wantErr != nil {
+				assert.Len(t, entries, 1)
+				return
+			}
+			assert.Len(t, entries, 2)
+
+			idx, err := helper.NewBreachedPasswordIndex(outPath)
+			assert.NoError(t, err)
+
+			breached, err := idx.IsBreached(context.TODO(), "password")
+			assert.NoError(t, err)
+			assert.True(t, breached)
+
+			breached, err = idx.IsBreached(context.TODO(), "correct horse battery staple")
+			assert.NoError(t, err)
+			assert.False(t, breached)
+		})
+	}
+
+	t.Run("error missing dump", func(t *testing.T) {
+		dir := t.TempDir()
+
+		_, err := buildIndex(filepath.Join(dir, "missing.txt"), filepath.Join(dir, "breached-passwords.idx"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
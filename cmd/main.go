@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,8 @@ import (
 	"sawitpro/helper"
 	"sawitpro/repository"
 	"sawitpro/service"
+	"strconv"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
@@ -18,6 +21,7 @@ import (
 
 func main() {
 	e := echo.New()
+	e.IPExtractor = newIPExtractor()
 
 	var server = newServer()
 	mw, err := server.CreateMiddleware()
@@ -58,22 +62,386 @@ func newServer() *handler.Server {
 
 	//repository
 	profileRepository := repository.NewUserProfileRepository(conn)
+	refreshTokenRepository := repository.NewRefreshTokenRepository(conn)
+	revokedTokenRepository := newRevokedTokenRepository(conn)
+	signingKeyRepository := repository.NewSigningKeyRepository(conn)
+	sessionRepository := repository.NewSessionRepository(conn)
+	personalAccessTokenRepository := repository.NewPersonalAccessTokenRepository(conn)
+	oauthClientRepository := repository.NewOAuthClientRepository(conn)
+	oauthAuthorizationCodeRepository := repository.NewOAuthAuthorizationCodeRepository(conn)
+	oauthGrantRepository := repository.NewOAuthGrantRepository(conn)
+	oauthDeviceCodeRepository := repository.NewOAuthDeviceCodeRepository(conn)
+	roleRepository := repository.NewRoleRepository(conn)
+	oneTimeCodeRepository := repository.NewOneTimeCodeRepository(conn)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(conn)
+	totpCredentialRepository := repository.NewTOTPCredentialRepository(conn)
+	mfaChallengeRepository := repository.NewMFAChallengeRepository(conn)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository(conn)
+	rateLimitRepository := newRateLimitRepository(conn)
 
 	//helper
-	authHelper := helper.NewAuthHelper()
-	validatorHelper := helper.NewValidatorHelper()
+	keyRing, err := helper.NewKeyRing(helper.KeyRingOptions{
+		Algorithm: constant.EnvJWTSigningAlgorithm,
+		Secret:    constant.EnvJWTSecretKey,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Unable to create signing key ring: %v\n", err)
+		os.Exit(1)
+	}
+	passwordHasherOptions, err := newPasswordHasherOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid password hashing configuration: %v\n", err)
+		os.Exit(1)
+	}
+	passwordHasher, err := helper.NewPasswordHasher(passwordHasherOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Unable to create password hasher: %v\n", err)
+		os.Exit(1)
+	}
+	authHelperOptions, err := newAuthHelperOptions(keyRing, passwordHasher)
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid token configuration: %v\n", err)
+		os.Exit(1)
+	}
+	authHelper := helper.NewAuthHelper(authHelperOptions)
+	totpHelper, err := helper.NewTOTPHelper(helper.TOTPHelperOptions{
+		Issuer: constant.TOTPIssuer,
+		Secret: envOrDefault(constant.EnvTOTPSecretKey, constant.EnvJWTSecretKey),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Unable to create TOTP helper: %v\n", err)
+		os.Exit(1)
+	}
+	loginLockoutPolicy, err := newLoginLockoutPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid login lockout configuration: %v\n", err)
+		os.Exit(1)
+	}
+	breachedPasswordChecker, err := newBreachedPasswordChecker()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Unable to load breached password index: %v\n", err)
+		os.Exit(1)
+	}
+	passwordPolicy, err := newPasswordPolicy()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid password policy: %v\n", err)
+		os.Exit(1)
+	}
+	passwordHistorySize, err := newPasswordHistorySize()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid password history configuration: %v\n", err)
+		os.Exit(1)
+	}
+	passwordMaxAge, err := newPasswordMaxAge()
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Invalid password expiry configuration: %v\n", err)
+		os.Exit(1)
+	}
+	validatorHelper := helper.NewValidatorHelper(helper.ValidatorHelperOptions{
+		PasswordPolicy: passwordPolicy,
+	})
+	smsSender := newSMSSender()
 
 	//service
+	signingKeyService := service.NewSigningKeyService(service.SigningKeyServiceDeps{
+		SigningKeyRepository: signingKeyRepository,
+		KeyRing:              keyRing,
+	})
+	err = signingKeyService.RotateSigningKeys(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "Unable to load signing keys: %v\n", err)
+		os.Exit(1)
+	}
+
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		ProfileRepository:             profileRepository,
+		RefreshTokenRepository:        refreshTokenRepository,
+		RevokedTokenRepository:        revokedTokenRepository,
+		SessionRepository:             sessionRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		OAuthGrantRepository:          oauthGrantRepository,
+		OAuthClientRepository:         oauthClientRepository,
+		RoleRepository:                roleRepository,
+		Authhelper:                    authHelper,
+	})
 	profileService := service.NewProfileService(service.ProfileServiceDeps{
+		ProfileRepository:             profileRepository,
+		OneTimeCodeRepository:         oneTimeCodeRepository,
+		RecoveryCodeRepository:        recoveryCodeRepository,
+		TOTPCredentialRepository:      totpCredentialRepository,
+		MFAChallengeRepository:        mfaChallengeRepository,
+		PasswordHistoryRepository:     passwordHistoryRepository,
+		SessionRepository:             sessionRepository,
+		RefreshTokenRepository:        refreshTokenRepository,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+		OAuthGrantRepository:          oauthGrantRepository,
+		Authhelper:                    authHelper,
+		TOTPHelper:                    totpHelper,
+		SMSSender:                     smsSender,
+		ValidatorHelper:               validatorHelper,
+		BreachedPasswordChecker:       breachedPasswordChecker,
+		AuthService:                   authService,
+		LoginLockoutPolicy:            loginLockoutPolicy,
+		PasswordHistorySize:           passwordHistorySize,
+		PasswordMaxAge:                passwordMaxAge,
+	})
+
+	oauthService := service.NewOAuthService(service.OAuthServiceDeps{
+		ProfileRepository:                profileRepository,
+		OAuthClientRepository:            oauthClientRepository,
+		OAuthAuthorizationCodeRepository: oauthAuthorizationCodeRepository,
+		OAuthGrantRepository:             oauthGrantRepository,
+		RefreshTokenRepository:           refreshTokenRepository,
+		OAuthDeviceCodeRepository:        oauthDeviceCodeRepository,
+		AuthService:                      authService,
+		Authhelper:                       authHelper,
+		KeyRing:                          keyRing,
+		Issuer:                           authHelperOptions.Issuer,
+		AuthorizationEndpoint:            envOrDefault(constant.EnvOIDCAuthorizationEndpoint, authHelperOptions.Issuer+constant.OAuthAuthorizePath),
+		DeviceVerificationURI:            envOrDefault(constant.EnvOAuthDeviceVerificationURI, authHelperOptions.Issuer+constant.OAuthDevicePath),
+	})
+
+	roleService := service.NewRoleService(service.RoleServiceDeps{
+		RoleRepository:    roleRepository,
 		ProfileRepository: profileRepository,
-		Authhelper:        authHelper,
 	})
 
+	rateLimitService := service.NewRateLimitService(service.RateLimitServiceDeps{
+		RateLimitRepository: rateLimitRepository,
+	})
+
+	//background jobs
+	go runPeriodically(constant.RevokedTokenPruneInterval, authService.PruneRevokedTokens)
+	go runPeriodically(constant.SigningKeyRefreshInterval, signingKeyService.RotateSigningKeys)
+	go runPeriodically(constant.SessionPruneInterval, authService.PruneIdleSessions)
+	go runPeriodically(constant.OneTimeCodePruneInterval, profileService.PruneOneTimeCodes)
+	go runPeriodically(constant.UnverifiedProfilePruneInterval, profileService.PruneUnverifiedProfiles)
+	go runPeriodically(constant.MFAChallengePruneInterval, profileService.PruneMFAChallenges)
+	go runPeriodically(constant.RateLimitPruneInterval, rateLimitService.PruneRateLimitBuckets)
+	go runPeriodically(constant.OAuthAuthorizationCodePruneInterval, oauthService.PruneOAuthAuthorizationCodes)
+	go runPeriodically(constant.OAuthDeviceCodePruneInterval, oauthService.PruneOAuthDeviceCodes)
+
 	opts := handler.NewServerOptions{
-		ProfileService:  profileService,
-		AuthHelper:      authHelper,
-		ValidatorHelper: validatorHelper,
+		ProfileService:    profileService,
+		AuthService:       authService,
+		SigningKeyService: signingKeyService,
+		RateLimitService:  rateLimitService,
+		OAuthService:      oauthService,
+		RoleService:       roleService,
+		AuthHelper:        authHelper,
+		ValidatorHelper:   validatorHelper,
 	}
 
 	return handler.NewServer(opts)
 }
+
+func newAuthHelperOptions(keyRing helper.KeyRingInterface, passwordHasher helper.PasswordHasherInterface) (helper.AuthHelperOptions, error) {
+	opts := helper.AuthHelperOptions{
+		KeyRing:        keyRing,
+		PasswordHasher: passwordHasher,
+		Issuer:         envOrDefault(constant.EnvJWTIssuer, constant.DefaultJWTIssuer),
+		Audience:       envOrDefault(constant.EnvJWTAudience, constant.DefaultJWTAudience),
+		Leeway:         constant.DefaultJWTLeeway,
+	}
+
+	if constant.EnvJWTLeeway != "" {
+		leeway, err := time.ParseDuration(constant.EnvJWTLeeway)
+		if err != nil {
+			return opts, fmt.Errorf("JWT_LEEWAY: %w", err)
+		}
+		opts.Leeway = leeway
+	}
+
+	return opts, nil
+}
+
+// newPasswordHasherOptions leaves unset parameters at zero, the hasher
+// fills in its defaults for them.
+func newPasswordHasherOptions() (helper.PasswordHasherOptions, error) {
+	opts := helper.PasswordHasherOptions{
+		Algorithm: constant.EnvPasswordHashAlgorithm,
+	}
+
+	if constant.EnvArgon2idMemory != "" {
+		memory, err := strconv.ParseUint(constant.EnvArgon2idMemory, 10, 32)
+		if err != nil {
+			return opts, fmt.Errorf("ARGON2ID_MEMORY: %w", err)
+		}
+		opts.Argon2idMemory = uint32(memory)
+	}
+
+	if constant.EnvArgon2idIterations != "" {
+		iterations, err := strconv.ParseUint(constant.EnvArgon2idIterations, 10, 32)
+		if err != nil {
+			return opts, fmt.Errorf("ARGON2ID_ITERATIONS: %w", err)
+		}
+		opts.Argon2idIterations = uint32(iterations)
+	}
+
+	if constant.EnvArgon2idParallelism != "" {
+		parallelism, err := strconv.ParseUint(constant.EnvArgon2idParallelism, 10, 8)
+		if err != nil {
+			return opts, fmt.Errorf("ARGON2ID_PARALLELISM: %w", err)
+		}
+		opts.Argon2idParallelism = uint8(parallelism)
+	}
+
+	if constant.EnvBcryptCost != "" {
+		cost, err := strconv.Atoi(constant.EnvBcryptCost)
+		if err != nil {
+			return opts, fmt.Errorf("BCRYPT_COST: %w", err)
+		}
+		opts.BcryptCost = cost
+	}
+
+	return opts, nil
+}
+
+func newLoginLockoutPolicy() (service.LoginLockoutPolicy, error) {
+	policy := service.LoginLockoutPolicy{
+		DelayAfter:      constant.DefaultLoginDelayAfterFailures,
+		BaseDelay:       constant.DefaultLoginBaseDelay,
+		LockoutAfter:    constant.DefaultLoginLockoutAfterFailures,
+		LockoutDuration: constant.DefaultLoginLockoutDuration,
+	}
+
+	var err error
+
+	if constant.EnvLoginDelayAfterFailures != "" {
+		policy.DelayAfter, err = strconv.Atoi(constant.EnvLoginDelayAfterFailures)
+		if err != nil {
+			return policy, fmt.Errorf("LOGIN_DELAY_AFTER_FAILURES: %w", err)
+		}
+	}
+
+	if constant.EnvLoginBaseDelay != "" {
+		policy.BaseDelay, err = time.ParseDuration(constant.EnvLoginBaseDelay)
+		if err != nil {
+			return policy, fmt.Errorf("LOGIN_BASE_DELAY: %w", err)
+		}
+	}
+
+	if constant.EnvLoginLockoutAfterFailures != "" {
+		policy.LockoutAfter, err = strconv.Atoi(constant.EnvLoginLockoutAfterFailures)
+		if err != nil {
+			return policy, fmt.Errorf("LOGIN_LOCKOUT_AFTER_FAILURES: %w", err)
+		}
+	}
+
+	if constant.EnvLoginLockoutDuration != "" {
+		policy.LockoutDuration, err = time.ParseDuration(constant.EnvLoginLockoutDuration)
+		if err != nil {
+			return policy, fmt.Errorf("LOGIN_LOCKOUT_DURATION: %w", err)
+		}
+	}
+
+	if policy.BaseDelay <= 0 || policy.LockoutDuration <= 0 {
+		return policy, fmt.Errorf("LOGIN_BASE_DELAY and LOGIN_LOCKOUT_DURATION must be positive")
+	}
+
+	return policy, nil
+}
+
+func envOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+func newRevokedTokenRepository(conn *sqlx.DB) repository.RevokedTokenRepositoryInterface {
+	if constant.EnvTokenRevocationStore == constant.TokenRevocationStoreMemory {
+		return repository.NewMemoryRevokedTokenRepository()
+	}
+
+	return repository.NewRevokedTokenRepository(conn)
+}
+
+func newRateLimitRepository(conn *sqlx.DB) repository.RateLimitRepositoryInterface {
+	if constant.EnvRateLimitStore == constant.RateLimitStoreMemory {
+		return repository.NewMemoryRateLimitRepository()
+	}
+
+	return repository.NewRateLimitRepository(conn)
+}
+
+// newIPExtractor only believes X-Forwarded-For when told a proxy sets it,
+// otherwise any client could pick the address its requests are limited by.
+func newIPExtractor() echo.IPExtractor {
+	if constant.EnvTrustProxyHeaders == "true" {
+		return echo.ExtractIPFromXFFHeader()
+	}
+
+	return echo.ExtractIPDirect()
+}
+
+func newSMSSender() helper.SMSSenderInterface {
+	if constant.EnvSMSSender == constant.SMSSenderFile {
+		return helper.NewFileSMSSender(envOrDefault(constant.EnvSMSOutboxPath, constant.DefaultSMSOutboxPath))
+	}
+
+	return helper.NewLogSMSSender()
+}
+
+func newPasswordHistorySize() (int, error) {
+	if constant.EnvPasswordHistorySize == "" {
+		return constant.DefaultPasswordHistorySize, nil
+	}
+
+	size, err := strconv.Atoi(constant.EnvPasswordHistorySize)
+	if err != nil {
+		return 0, fmt.Errorf("PASSWORD_HISTORY_SIZE: %w", err)
+	}
+
+	if size < 0 {
+		return 0, fmt.Errorf("PASSWORD_HISTORY_SIZE must not be negative")
+	}
+
+	return size, nil
+}
+
+func newPasswordMaxAge() (time.Duration, error) {
+	if constant.EnvPasswordMaxAgeDays == "" {
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(constant.EnvPasswordMaxAgeDays)
+	if err != nil {
+		return 0, fmt.Errorf("PASSWORD_MAX_AGE_DAYS: %w", err)
+	}
+
+	if days < 0 {
+		return 0, fmt.Errorf("PASSWORD_MAX_AGE_DAYS must not be negative")
+	}
+
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+func newPasswordPolicy() (helper.PasswordPolicy, error) {
+	if constant.EnvPasswordPolicyPath == "" {
+		return helper.DefaultPasswordPolicy(), nil
+	}
+
+	return helper.LoadPasswordPolicy(constant.EnvPasswordPolicyPath)
+}
+
+func newBreachedPasswordChecker() (helper.BreachedPasswordCheckerInterface, error) {
+	if constant.EnvBreachedPasswordIndexPath == "" {
+		return helper.NewNoBreachedPasswordChecker(), nil
+	}
+
+	return helper.NewBreachedPasswordIndex(constant.EnvBreachedPasswordIndexPath)
+}
+
+func runPeriodically(interval time.Duration, job func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		err := job(context.Background())
+		if err != nil {
+			log.Println("error running background job:", err)
+		}
+	}
+}
@@ -1,3 +1,78 @@
 package constant
 
+import "time"
+
 const ProfileIdJwtField = "profile_id"
+
+// PasswordChangeRequiredJwtField marks a token issued to a login whose
+// password has expired or was flagged by an admin
+const PasswordChangeRequiredJwtField = "pwd_change"
+
+// SetNewPasswordOperationId is the only operation accepting such a token, as
+// the embedded spec holds it, oapi-codegen capitalizes the ids of api.yml
+const SetNewPasswordOperationId = "SetNewPassword"
+
+const (
+	DefaultJWTIssuer   = "sawitpro"
+	DefaultJWTAudience = "sawitpro-api"
+	DefaultJWTLeeway   = 30 * time.Second
+)
+
+const (
+	AccessTokenDuration  = 15 * time.Minute
+	RefreshTokenDuration = 30 * 24 * time.Hour
+
+	RevokedTokenPruneInterval = time.Hour
+)
+
+const (
+	// last seen is only written back once it is this stale, so authenticated
+	// requests do not each cost a write
+	SessionLastSeenResolution = time.Minute
+
+	// a session unseen for as long as a refresh token lives cannot be resumed
+	SessionIdleTimeout = RefreshTokenDuration
+
+	SessionPruneInterval = time.Hour
+)
+
+const (
+	// after this many consecutive failed logins every further failure locks
+	// the account for a delay that doubles each time, starting at
+	// DefaultLoginBaseDelay
+	DefaultLoginDelayAfterFailures = 3
+	DefaultLoginBaseDelay          = time.Second
+
+	// after this many the account is locked for DefaultLoginLockoutDuration
+	DefaultLoginLockoutAfterFailures = 10
+	DefaultLoginLockoutDuration      = 15 * time.Minute
+)
+
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmES256 = "ES256"
+	SigningAlgorithmEdDSA = "EdDSA"
+
+	DefaultSigningAlgorithm = SigningAlgorithmRS256
+)
+
+const (
+	SigningKeyRotationInterval = 30 * 24 * time.Hour
+	SigningKeyRefreshInterval  = time.Minute
+
+	// a new key is published in the JWKS this long before it signs anything,
+	// so verifiers that cache the key set have picked it up by then
+	SigningKeyPublishLeadTime = 2 * JWKSCacheMaxAge
+
+	// a replaced key stays published until every token it signed has expired
+	SigningKeyRetirementOverlap = AccessTokenDuration + SigningKeyRefreshInterval
+
+	JWKSCacheMaxAge = 5 * time.Minute
+)
+
+const TokenClaimsContextKey = "token_claims"
+
+const (
+	TokenRevocationStorePostgres = "postgres"
+	TokenRevocationStoreMemory   = "memory"
+)
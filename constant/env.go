@@ -3,10 +3,63 @@ package constant
 import "os"
 
 var (
-	EnvJWTSecretKey     = os.Getenv("JWT_KEY")
+	// EnvJWTSecretKey encrypts the private signing keys stored in the database
+	EnvJWTSecretKey        = os.Getenv("JWT_KEY")
+	EnvJWTSigningAlgorithm = os.Getenv("JWT_SIGNING_ALGORITHM")
+	// EnvJWTIssuer has to be the public url of the service for OpenID
+	// Connect clients, which fetch the discovery document below it
+	EnvJWTIssuer        = os.Getenv("JWT_ISSUER")
+	EnvJWTAudience      = os.Getenv("JWT_AUDIENCE")
+	EnvJWTLeeway        = os.Getenv("JWT_LEEWAY")
 	EnvPostgresHost     = os.Getenv("PGHOST")
 	EnvPostgresPort     = os.Getenv("PGPORT")
 	EnvPostgresUser     = os.Getenv("PGUSER")
 	EnvPostgresDatabase = os.Getenv("PGDATABASE")
 	EnvPostgresPassword = os.Getenv("PGPASSWORD")
+
+	EnvTokenRevocationStore = os.Getenv("TOKEN_REVOCATION_STORE")
+	EnvRateLimitStore       = os.Getenv("RATE_LIMIT_STORE")
+	// EnvTrustProxyHeaders takes the client IP from X-Forwarded-For, only set
+	// it when a trusted proxy in front of the service overwrites that header
+	EnvTrustProxyHeaders = os.Getenv("TRUST_PROXY_HEADERS")
+
+	EnvLoginDelayAfterFailures   = os.Getenv("LOGIN_DELAY_AFTER_FAILURES")
+	EnvLoginBaseDelay            = os.Getenv("LOGIN_BASE_DELAY")
+	EnvLoginLockoutAfterFailures = os.Getenv("LOGIN_LOCKOUT_AFTER_FAILURES")
+	EnvLoginLockoutDuration      = os.Getenv("LOGIN_LOCKOUT_DURATION")
+
+	EnvPasswordHashAlgorithm = os.Getenv("PASSWORD_HASH_ALGORITHM")
+	// EnvArgon2idMemory is in KiB
+	EnvArgon2idMemory      = os.Getenv("ARGON2ID_MEMORY")
+	EnvArgon2idIterations  = os.Getenv("ARGON2ID_ITERATIONS")
+	EnvArgon2idParallelism = os.Getenv("ARGON2ID_PARALLELISM")
+	EnvBcryptCost          = os.Getenv("BCRYPT_COST")
+	// EnvPasswordPolicyPath is a JSON file of the password policy, rules it
+	// leaves out keep their default
+	EnvPasswordPolicyPath  = os.Getenv("PASSWORD_POLICY_FILE")
+	EnvPasswordHistorySize = os.Getenv("PASSWORD_HISTORY_SIZE")
+	// EnvPasswordMaxAgeDays is how many days a password lasts, passwords
+	// never expire when it is not set or zero
+	EnvPasswordMaxAgeDays = os.Getenv("PASSWORD_MAX_AGE_DAYS")
+	// EnvBreachedPasswordIndexPath is an index built by cmd/breachindex, new
+	// passwords are not screened when it is not set
+	EnvBreachedPasswordIndexPath = os.Getenv("BREACHED_PASSWORD_INDEX")
+
+	// EnvTOTPSecretKey encrypts the TOTP secrets stored in the database,
+	// JWT_KEY is used when it is not set
+	EnvTOTPSecretKey = os.Getenv("TOTP_KEY")
+
+	EnvSMSSender = os.Getenv("SMS_SENDER")
+	// EnvSMSOutboxPath is where the file sender appends the messages it would
+	// have sent
+	EnvSMSOutboxPath = os.Getenv("SMS_OUTBOX_PATH")
+
+	// EnvOIDCAuthorizationEndpoint is the consent page of the web front end,
+	// which calls /oauth/authorize with the login of the user. OIDC clients
+	// are sent there, JWT_ISSUER/oauth/authorize when it is not set
+	EnvOIDCAuthorizationEndpoint = os.Getenv("OIDC_AUTHORIZATION_ENDPOINT")
+	// EnvOAuthDeviceVerificationURI is the page of the web front end where a
+	// user enters the code shown on a device, which calls /oauth/device.
+	// JWT_ISSUER/oauth/device when it is not set
+	EnvOAuthDeviceVerificationURI = os.Getenv("OAUTH_DEVICE_VERIFICATION_URI")
 )
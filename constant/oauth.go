@@ -0,0 +1,69 @@
+package constant
+
+import "time"
+
+const (
+	OAuthResponseTypeCode           = "code"
+	OAuthCodeChallengeMethodS256    = "S256"
+	OAuthGrantTypeAuthorizationCode = "authorization_code"
+	OAuthGrantTypeRefreshToken      = "refresh_token"
+	OAuthGrantTypeClientCredentials = "client_credentials"
+	OAuthGrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	OAuthTokenTypeBearer            = "Bearer"
+)
+
+// claims of the access tokens issued to OAuth clients, whose sid is the id
+// of the grant rather than of a session. A client acting on its own with
+// the client credentials grant is the sub of its tokens and has no sid.
+const (
+	ClientIdJwtField = "client_id"
+	ScopeJwtField    = "scope"
+)
+
+// scopes only a client acting on its own can be given, operations list the
+// ones they need in a ClientAuth security requirement of api.yml
+const (
+	ScopeProfilesRead     = "profiles:read"
+	ScopeTokensIntrospect = "tokens:introspect"
+)
+
+// ClientAuthSecurityScheme is the security scheme of api.yml whose operations
+// are called by other services rather than on behalf of a user
+const ClientAuthSecurityScheme = "ClientAuth"
+
+// IntrospectionMaxAge caps how long a service may cache what introspection
+// said about a token, a revocation reaches it within that time
+const IntrospectionMaxAge = time.Minute
+
+const (
+	// the code only has to survive the redirect back to the client
+	OAuthAuthorizationCodeDuration      = time.Minute
+	OAuthAuthorizationCodePruneInterval = time.Hour
+)
+
+// a PKCE code verifier is 43 to 128 characters, an S256 challenge is the
+// unpadded base64url of a SHA-256 digest
+const (
+	OAuthCodeVerifierMinLength = 43
+	OAuthCodeVerifierMaxLength = 128
+)
+
+// the device authorization grant of RFC 8628, for devices without a keyboard
+// worth typing a password on
+const (
+	// long enough to walk over to a phone and sign in
+	OAuthDeviceCodeDuration      = 10 * time.Minute
+	OAuthDeviceCodePruneInterval = time.Hour
+	// a device polling faster than its interval is told to slow down, and
+	// has to wait this much longer from then on
+	OAuthDevicePollInterval = 5 * time.Second
+	OAuthDeviceSlowDownStep = 5 * time.Second
+
+	// user codes are read off the device and typed in, in two groups
+	OAuthUserCodeLength    = 8
+	OAuthUserCodeGroupSize = 4
+
+	OAuthDeviceCodeStatusPending  = "pending"
+	OAuthDeviceCodeStatusApproved = "approved"
+	OAuthDeviceCodeStatusDenied   = "denied"
+)
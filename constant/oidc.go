@@ -0,0 +1,26 @@
+package constant
+
+import "time"
+
+// scopes of OpenID Connect, which an OAuth client asks for to sign users in
+// rather than to act on their behalf
+const (
+	ScopeOpenId  = "openid"
+	ScopeProfile = "profile"
+	ScopePhone   = "phone"
+)
+
+const IDTokenDuration = time.Hour
+
+// the paths discovery advertises, relative to the issuer
+const (
+	OAuthAuthorizePath = "/oauth/authorize"
+	OAuthTokenPath     = "/oauth/token"
+	OAuthLogoutPath    = "/oauth/logout"
+	OAuthDevicePath    = "/oauth/device"
+	// OAuthDeviceAuthorizationPath is where a device starts its sign in
+	OAuthDeviceAuthorizationPath = "/oauth/device_authorization"
+	OAuthIntrospectPath          = "/oauth/introspect"
+	UserInfoPath                 = "/userinfo"
+	JSONWebKeySetPath            = "/.well-known/jwks.json"
+)
@@ -0,0 +1,17 @@
+package constant
+
+import "time"
+
+const (
+	OneTimeCodePurposePasswordReset     = "password_reset"
+	OneTimeCodePurposePhoneVerification = "phone_verification"
+	OneTimeCodePurposePhoneChange       = "phone_change"
+)
+
+const (
+	OneTimeCodeLength      = 6
+	OneTimeCodeDuration    = 10 * time.Minute
+	OneTimeCodeMaxAttempts = 5
+
+	OneTimeCodePruneInterval = time.Hour
+)
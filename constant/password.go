@@ -0,0 +1,28 @@
+package constant
+
+const (
+	PasswordHashAlgorithmArgon2id = "argon2id"
+	PasswordHashAlgorithmBcrypt   = "bcrypt"
+
+	DefaultPasswordHashAlgorithm = PasswordHashAlgorithmArgon2id
+)
+
+const (
+	// the OWASP recommended minimum of 19 MiB memory, two iterations and one
+	// degree of parallelism
+	DefaultArgon2idMemory      = 19 * 1024 // KiB
+	DefaultArgon2idIterations  = 2
+	DefaultArgon2idParallelism = 1
+
+	Argon2idSaltSize = 16 // bytes
+	Argon2idKeySize  = 32 // bytes
+
+	// the cost every password was hashed with before argon2id
+	DefaultBcryptCost = 10
+)
+
+const (
+	// how many previous passwords, besides the current one, cannot be picked
+	// again
+	DefaultPasswordHistorySize = 5
+)
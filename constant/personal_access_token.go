@@ -0,0 +1,19 @@
+package constant
+
+import "time"
+
+// PersonalAccessTokenPrefix tells personal access tokens apart from the JWTs
+// sent in the same Authorization header, and makes leaked ones easy to grep
+// for
+const PersonalAccessTokenPrefix = "pat_"
+
+// scopes a personal access token can be limited to, operations list the ones
+// they need in the security requirement of api.yml
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+)
+
+// last used is only written back once it is this stale, like the last seen
+// of sessions
+const PersonalAccessTokenLastUsedResolution = time.Minute
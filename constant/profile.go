@@ -0,0 +1,12 @@
+package constant
+
+import "time"
+
+const (
+	// UnverifiedProfileDuration is how long a registration may wait for its
+	// phone number to be verified before it is pruned. It matches the
+	// lifetime of the code sent at registration.
+	UnverifiedProfileDuration = OneTimeCodeDuration
+
+	UnverifiedProfilePruneInterval = 15 * time.Minute
+)
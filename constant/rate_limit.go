@@ -0,0 +1,29 @@
+package constant
+
+import "time"
+
+// RateLimitExtension is the api.yml operation extension listing the token
+// buckets a request to that operation draws from.
+const RateLimitExtension = "x-rate-limit"
+
+const (
+	RateLimitKeyIp          = "ip"
+	RateLimitKeyPhoneNumber = "phone_number"
+)
+
+// RateLimitMaxPeekedBodySize is how much of a body the rate limiter reads to
+// find its phone number. A larger body is limited by ip alone.
+const RateLimitMaxPeekedBodySize = 4 << 10
+
+const (
+	RateLimitStorePostgres = "postgres"
+	RateLimitStoreMemory   = "memory"
+)
+
+const (
+	// a bucket untouched for this long has refilled under any sane limit and
+	// is dropped
+	RateLimitBucketIdleTimeout = 24 * time.Hour
+
+	RateLimitPruneInterval = time.Hour
+)
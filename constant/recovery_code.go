@@ -0,0 +1,10 @@
+package constant
+
+const (
+	RecoveryCodeCount = 10
+
+	// characters of a code leaving out the separators, drawn from an
+	// alphabet of 32 symbols they make 80 bits
+	RecoveryCodeLength    = 16
+	RecoveryCodeGroupSize = 4
+)
@@ -0,0 +1,12 @@
+package constant
+
+// permissions a role can grant, operations list the ones they need in the
+// security requirement of api.yml like the scopes of tokens
+const (
+	ScopeRolesRead  = "roles:read"
+	ScopeRolesWrite = "roles:write"
+)
+
+// RolesJwtField names the roles of a login, the scope claim holds what they
+// grant
+const RolesJwtField = "roles"
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	SMSSenderLog  = "log"
+	SMSSenderFile = "file"
+
+	DefaultSMSOutboxPath = "sms_outbox.log"
+)
@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+const (
+	TOTPIssuer     = "SawitPro"
+	TOTPSecretSize = 20 // bytes, the length of an HMAC-SHA1 key
+	TOTPDigits     = 6
+	TOTPPeriod     = 30 * time.Second
+
+	// codes of this many periods before and after the current one are still
+	// accepted, to make up for clock drift on the device
+	TOTPDriftSteps = 1
+)
+
+const (
+	MFAChallengeDuration    = 5 * time.Minute
+	MFAChallengeMaxAttempts = 5
+
+	MFAChallengePruneInterval = time.Hour
+)
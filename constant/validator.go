@@ -1,26 +1,17 @@
 package constant
 
-var SpecialCharacter = map[rune]bool{
-	'~': true,
-	'!': true,
-	'@': true,
-	'#': true,
-	'$': true,
-	'%': true,
-	'^': true,
-	'&': true,
-	'*': true,
-	'(': true,
-	')': true,
-	'-': true,
-	'_': true,
-	'=': true,
-	'+': true,
-	'[': true,
-	']': true,
-	'{': true,
-	'}': true,
-	'|': true,
-	';': true,
-	':': true,
-}
+const (
+	DefaultPasswordMinLength         = 10
+	DefaultPasswordMaxLength         = 64
+	DefaultPasswordSpecialCharacters = "~!@#$%^&*()-_=+[]{}|;:"
+
+	// about what ten characters drawn from lowercase letters and digits give
+	DefaultPasswordMinEntropyBits = 50
+
+	// words of the owner's name or phone number shorter than this are not
+	// looked for in the password, they turn up by chance too often
+	PasswordPersonalInfoMinTokenLength = 3
+
+	// the trailing digits of a phone number looked for in the password
+	PasswordPersonalInfoDigits = 6
+)
@@ -13,10 +13,318 @@ CREATE TABLE public.user_profile (
 	id uuid NOT NULL DEFAULT uuid_generate_v4(),
 	full_name varchar NOT NULL,
 	phone_number varchar NOT NULL,
-	"password" varchar(60) NOT NULL,
+	"password" varchar(255) NOT NULL,
 	created_at timestamp NOT NULL,
 	updated_at timestamp NOT NULL,
 	success_count int8 NOT NULL DEFAULT 0,
+	failed_login_count int4 NOT NULL DEFAULT 0,
+	locked_until timestamp NULL,
+	password_changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	must_change_password bool NOT NULL DEFAULT false,
+	pending_phone_number varchar NULL,
+	phone_verified_at timestamp NULL,
 	CONSTRAINT user_profile_un UNIQUE (phone_number),
 	CONSTRAINT user_table_pk PRIMARY KEY (id)
 );
+
+-- password holds a PHC string, or a bcrypt hash for profiles that have not
+-- signed in since argon2id, widened from the 60 characters of bcrypt:
+-- ALTER TABLE user_profile ALTER COLUMN "password" TYPE varchar(255);
+-- passwords set before expiry existed count from when the columns were added:
+-- ALTER TABLE user_profile ADD COLUMN password_changed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+--   ADD COLUMN must_change_password bool NOT NULL DEFAULT false;
+-- profiles registered before phone verification existed are trusted as
+-- verified, the backfill runs once together with adding the column: a
+-- registration left unverified since must never be verified by it
+-- ALTER TABLE user_profile ADD COLUMN phone_verified_at timestamp NULL;
+-- UPDATE user_profile SET phone_verified_at = created_at;
+-- a new phone number waits here until its owner enters the code sent to it:
+-- ALTER TABLE user_profile ADD COLUMN pending_phone_number varchar NULL;
+-- registrations whose phone number was never verified are removed by age
+CREATE INDEX user_profile_unverified_created_at_idx ON public.user_profile (created_at) WHERE phone_verified_at IS NULL;
+
+-- single-use codes that sign in instead of the password, for owners who lost
+-- their phone number. Generating a new set deletes the old one.
+CREATE TABLE public.recovery_code (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	code_hash varchar(64) NOT NULL,
+	used_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT recovery_code_un UNIQUE (profile_id, code_hash),
+	CONSTRAINT recovery_code_pk PRIMARY KEY (id),
+	CONSTRAINT recovery_code_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+-- passwords a profile used before the current one, newest first, kept to the
+-- configured history size so they cannot be picked again
+CREATE TABLE public.password_history (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	"password" varchar(255) NOT NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT password_history_pk PRIMARY KEY (id),
+	CONSTRAINT password_history_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_history_profile_created_at_idx ON public.password_history (profile_id, created_at);
+
+CREATE TABLE public.user_session (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	device_name varchar(100) NOT NULL,
+	user_agent varchar NOT NULL,
+	ip_address varchar(45) NOT NULL,
+	created_at timestamp NOT NULL,
+	last_seen_at timestamp NOT NULL,
+	CONSTRAINT user_session_pk PRIMARY KEY (id),
+	CONSTRAINT user_session_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_session_profile_idx ON public.user_session (profile_id);
+CREATE INDEX user_session_last_seen_at_idx ON public.user_session (last_seen_at);
+
+-- personal access tokens authenticate scripts as their owner, limited to the
+-- space separated scopes
+CREATE TABLE public.personal_access_token (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	name varchar(100) NOT NULL,
+	token_hash varchar(64) NOT NULL,
+	scopes varchar NOT NULL,
+	expires_at timestamp NULL,
+	last_used_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT personal_access_token_un UNIQUE (token_hash),
+	CONSTRAINT personal_access_token_pk PRIMARY KEY (id),
+	CONSTRAINT personal_access_token_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX personal_access_token_profile_idx ON public.personal_access_token (profile_id);
+
+CREATE TABLE public.one_time_code (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	purpose varchar(32) NOT NULL,
+	code_hash varchar(64) NOT NULL,
+	expires_at timestamp NOT NULL,
+	attempts int4 NOT NULL DEFAULT 0,
+	consumed_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT one_time_code_pk PRIMARY KEY (id),
+	CONSTRAINT one_time_code_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX one_time_code_profile_purpose_idx ON public.one_time_code (profile_id, purpose);
+CREATE INDEX one_time_code_expires_at_idx ON public.one_time_code (expires_at);
+
+-- secrets are encrypted with TOTP_KEY, a pending secret replaces the active
+-- one once a code generated from it is confirmed
+CREATE TABLE public.totp_credential (
+	profile_id uuid NOT NULL,
+	secret varchar NULL,
+	pending_secret varchar NULL,
+	last_used_step int8 NOT NULL DEFAULT 0,
+	enabled_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	updated_at timestamp NOT NULL,
+	CONSTRAINT totp_credential_pk PRIMARY KEY (profile_id),
+	CONSTRAINT totp_credential_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+-- issued by a password login of a profile with two-factor authentication on,
+-- redeemed together with a TOTP code for the tokens
+CREATE TABLE public.mfa_challenge (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	token_hash varchar(64) NOT NULL,
+	attempts int4 NOT NULL DEFAULT 0,
+	expires_at timestamp NOT NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT mfa_challenge_un UNIQUE (token_hash),
+	CONSTRAINT mfa_challenge_pk PRIMARY KEY (id),
+	CONSTRAINT mfa_challenge_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX mfa_challenge_expires_at_idx ON public.mfa_challenge (expires_at);
+
+-- family_id is the id of the user_session, or of the oauth_grant, the token
+-- chain was issued to
+CREATE TABLE public.refresh_token (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	family_id uuid NOT NULL,
+	token_hash varchar(64) NOT NULL,
+	expires_at timestamp NOT NULL,
+	used_at timestamp NULL,
+	revoked_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT refresh_token_un UNIQUE (token_hash),
+	CONSTRAINT refresh_token_pk PRIMARY KEY (id),
+	CONSTRAINT refresh_token_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX refresh_token_family_idx ON public.refresh_token (family_id);
+
+CREATE TABLE public.revoked_token (
+	token_id varchar NOT NULL,
+	expires_at timestamp NOT NULL,
+	revoked_at timestamp NOT NULL,
+	CONSTRAINT revoked_token_pk PRIMARY KEY (token_id)
+);
+
+CREATE INDEX revoked_token_expires_at_idx ON public.revoked_token (expires_at);
+
+-- token buckets of the rate limiter, tokens is the level at updated_at
+CREATE TABLE public.rate_limit_bucket (
+	bucket_key varchar NOT NULL,
+	tokens float8 NOT NULL,
+	updated_at timestamp NOT NULL,
+	CONSTRAINT rate_limit_bucket_pk PRIMARY KEY (bucket_key)
+);
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON public.rate_limit_bucket (updated_at);
+
+CREATE TABLE public.signing_key (
+	id uuid NOT NULL,
+	algorithm varchar(16) NOT NULL,
+	private_key text NOT NULL,
+	activates_at timestamp NOT NULL,
+	retires_at timestamp NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT signing_key_pk PRIMARY KEY (id)
+);
+
+-- apps acting on behalf of users, or on their own with the client
+-- credentials grant. redirect_uris, post_logout_redirect_uris, scopes and
+-- grant_types are space separated and secret_hash is NULL for public clients
+CREATE TABLE public.oauth_client (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	name varchar(100) NOT NULL,
+	secret_hash varchar(64) NULL,
+	redirect_uris varchar NOT NULL,
+	post_logout_redirect_uris varchar NOT NULL DEFAULT '',
+	scopes varchar NOT NULL,
+	grant_types varchar NOT NULL DEFAULT 'authorization_code refresh_token',
+	created_at timestamp NOT NULL,
+	CONSTRAINT oauth_client_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE public.oauth_authorization_code (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	code_hash varchar(64) NOT NULL,
+	client_id uuid NOT NULL,
+	profile_id uuid NOT NULL,
+	session_id uuid NOT NULL,
+	redirect_uri varchar NOT NULL,
+	scopes varchar NOT NULL,
+	code_challenge varchar(43) NOT NULL,
+	nonce varchar(255) NOT NULL DEFAULT '',
+	expires_at timestamp NOT NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT oauth_authorization_code_un UNIQUE (code_hash),
+	CONSTRAINT oauth_authorization_code_pk PRIMARY KEY (id),
+	CONSTRAINT oauth_authorization_code_client_fk FOREIGN KEY (client_id) REFERENCES public.oauth_client(id) ON DELETE CASCADE,
+	CONSTRAINT oauth_authorization_code_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX oauth_authorization_code_expires_at_idx ON public.oauth_authorization_code (expires_at);
+
+-- OpenID Connect came after the first clients were registered, codes live
+-- for a minute so outstanding ones can simply be dropped:
+-- ALTER TABLE oauth_client ADD COLUMN post_logout_redirect_uris varchar NOT NULL DEFAULT '';
+-- DELETE FROM oauth_authorization_code;
+-- ALTER TABLE oauth_authorization_code ADD COLUMN session_id uuid NOT NULL,
+-- 	ADD COLUMN nonce varchar(255) NOT NULL DEFAULT '';
+
+-- clients registered before the client credentials grant all sign users in:
+-- ALTER TABLE oauth_client ADD COLUMN grant_types varchar NOT NULL DEFAULT 'authorization_code refresh_token';
+
+-- what a user allowed a client to do, its refresh tokens share its id as
+-- their family_id
+CREATE TABLE public.oauth_grant (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	profile_id uuid NOT NULL,
+	client_id uuid NOT NULL,
+	scopes varchar NOT NULL,
+	created_at timestamp NOT NULL,
+	last_used_at timestamp NULL,
+	CONSTRAINT oauth_grant_un UNIQUE (profile_id, client_id),
+	CONSTRAINT oauth_grant_pk PRIMARY KEY (id),
+	CONSTRAINT oauth_grant_client_fk FOREIGN KEY (client_id) REFERENCES public.oauth_client(id) ON DELETE CASCADE,
+	CONSTRAINT oauth_grant_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+-- a sign in started on a device without a keyboard, the device polls with
+-- its device code while the user enters the user code on their phone
+CREATE TABLE public.oauth_device_code (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	device_code_hash varchar(64) NOT NULL,
+	user_code varchar(8) NOT NULL,
+	client_id uuid NOT NULL,
+	scopes varchar NOT NULL,
+	status varchar(8) NOT NULL DEFAULT 'pending',
+	profile_id uuid NULL,
+	poll_interval int4 NOT NULL,
+	last_polled_at timestamp NULL,
+	expires_at timestamp NOT NULL,
+	created_at timestamp NOT NULL,
+	CONSTRAINT oauth_device_code_un UNIQUE (device_code_hash),
+	CONSTRAINT oauth_device_code_user_code_un UNIQUE (user_code),
+	CONSTRAINT oauth_device_code_pk PRIMARY KEY (id),
+	CONSTRAINT oauth_device_code_client_fk FOREIGN KEY (client_id) REFERENCES public.oauth_client(id) ON DELETE CASCADE,
+	CONSTRAINT oauth_device_code_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE
+);
+
+CREATE INDEX oauth_device_code_expires_at_idx ON public.oauth_device_code (expires_at);
+
+-- what a role can do, each permission is a scope operations list in the
+-- security requirements of api.yml
+CREATE TABLE public.permission (
+	name varchar(64) NOT NULL,
+	description varchar NOT NULL,
+	CONSTRAINT permission_pk PRIMARY KEY (name)
+);
+
+CREATE TABLE public.role (
+	id uuid NOT NULL DEFAULT uuid_generate_v4(),
+	name varchar(64) NOT NULL,
+	description varchar NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	CONSTRAINT role_un UNIQUE (name),
+	CONSTRAINT role_pk PRIMARY KEY (id)
+);
+
+CREATE TABLE public.role_permission (
+	role_id uuid NOT NULL,
+	permission varchar(64) NOT NULL,
+	CONSTRAINT role_permission_pk PRIMARY KEY (role_id, permission),
+	CONSTRAINT role_permission_role_fk FOREIGN KEY (role_id) REFERENCES public.role(id) ON DELETE CASCADE,
+	CONSTRAINT role_permission_permission_fk FOREIGN KEY (permission) REFERENCES public.permission(name) ON DELETE CASCADE
+);
+
+-- the roles of a profile are read into its access tokens when they are
+-- issued, a change reaches the profile on its next refresh
+CREATE TABLE public.profile_role (
+	profile_id uuid NOT NULL,
+	role_id uuid NOT NULL,
+	assigned_by uuid NULL,
+	assigned_at timestamp NOT NULL,
+	CONSTRAINT profile_role_pk PRIMARY KEY (profile_id, role_id),
+	CONSTRAINT profile_role_profile_fk FOREIGN KEY (profile_id) REFERENCES public.user_profile(id) ON DELETE CASCADE,
+	CONSTRAINT profile_role_role_fk FOREIGN KEY (role_id) REFERENCES public.role(id) ON DELETE CASCADE,
+	CONSTRAINT profile_role_assigned_by_fk FOREIGN KEY (assigned_by) REFERENCES public.user_profile(id) ON DELETE SET NULL
+);
+
+CREATE INDEX profile_role_role_idx ON public.profile_role (role_id);
+
+INSERT INTO public.permission (name, description) VALUES
+	('roles:read', 'See the roles and who holds them'),
+	('roles:write', 'Assign roles to profiles and take them away');
+
+INSERT INTO public.role (name, description) VALUES
+	('admin', 'Manages who holds which role');
+
+INSERT INTO public.role_permission (role_id, permission)
+	SELECT id, permission FROM public.role, (VALUES ('roles:read'), ('roles:write')) AS p(permission)
+	WHERE name = 'admin';
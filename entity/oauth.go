@@ -0,0 +1,214 @@
+package entity
+
+import "time"
+
+// OAuthClient is an app acting on behalf of users, or on its own when its
+// GrantTypes hold client_credentials. RedirectURIs, PostLogoutRedirectURIs,
+// Scopes and GrantTypes are space separated, SecretHash is nil for public
+// clients such as mobile apps, which cannot keep a secret.
+type OAuthClient struct {
+	Id                     string    `db:"id"`
+	Name                   string    `db:"name"`
+	SecretHash             *string   `db:"secret_hash"`
+	RedirectURIs           string    `db:"redirect_uris"`
+	PostLogoutRedirectURIs string    `db:"post_logout_redirect_uris"`
+	Scopes                 string    `db:"scopes"`
+	GrantTypes             string    `db:"grant_types"`
+	CreatedAt              time.Time `db:"created_at"`
+}
+
+// OAuthAuthorizationCode carries what the ID token of an OpenID Connect
+// sign in needs: the Nonce of the client and the login session it ends
+// with.
+type OAuthAuthorizationCode struct {
+	Id            string    `db:"id"`
+	CodeHash      string    `db:"code_hash"`
+	ClientId      string    `db:"client_id"`
+	ProfileId     string    `db:"profile_id"`
+	SessionId     string    `db:"session_id"`
+	RedirectURI   string    `db:"redirect_uri"`
+	Scopes        string    `db:"scopes"`
+	CodeChallenge string    `db:"code_challenge"`
+	Nonce         string    `db:"nonce"`
+	ExpiresAt     time.Time `db:"expires_at"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// OAuthDeviceCode is a sign in started on a device without a keyboard, which
+// polls with the device code while a user enters UserCode elsewhere.
+// ProfileId is the user who decided, once Status is no longer pending.
+// Interval is in seconds, how often the device may poll.
+type OAuthDeviceCode struct {
+	Id             string     `db:"id"`
+	DeviceCodeHash string     `db:"device_code_hash"`
+	UserCode       string     `db:"user_code"`
+	ClientId       string     `db:"client_id"`
+	Scopes         string     `db:"scopes"`
+	Status         string     `db:"status"`
+	ProfileId      *string    `db:"profile_id"`
+	Interval       int64      `db:"poll_interval"`
+	LastPolledAt   *time.Time `db:"last_polled_at"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// OAuthGrant is what a user has allowed a client to do. Its refresh tokens
+// form a family that shares the grant id.
+type OAuthGrant struct {
+	Id         string     `db:"id"`
+	ProfileId  string     `db:"profile_id"`
+	ClientId   string     `db:"client_id"`
+	ClientName string     `db:"client_name"`
+	Scopes     string     `db:"scopes"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+}
+
+type RegisterOAuthClientRequest struct {
+	Name         string   `validate:"required,max=100"`
+	RedirectURIs []string `validate:"required_without_all=ClientCredentials Device,dive,url"`
+	// PostLogoutRedirectURIs are where an OpenID Connect logout may return to
+	PostLogoutRedirectURIs []string `validate:"dive,url"`
+	Scopes                 []string `validate:"required,min=1,dive,oneof=profile:read profile:write openid profile phone profiles:read tokens:introspect"`
+	// Confidential clients get a secret to authenticate with
+	Confidential bool
+	// ClientCredentials clients are services acting on their own, they
+	// never sign users in and are always confidential
+	ClientCredentials bool
+	// Device clients sign users in with the device authorization grant, from
+	// a kiosk a user cannot type a password on
+	Device bool
+}
+
+type RegisterOAuthClientResponse struct {
+	Client OAuthClient
+	// ClientSecret is only ever shown here, empty for public clients
+	ClientSecret string
+}
+
+type OAuthAuthorizationRequest struct {
+	ProfileId string
+	// SessionId is the login consenting, which an OpenID Connect logout ends
+	SessionId    string
+	ResponseType string `validate:"required,eq=code"`
+	ClientId     string `validate:"required,uuid"`
+	RedirectURI  string `validate:"required"`
+	// Scope is space separated, every scope of the client when empty
+	Scope               string
+	State               string
+	Nonce               string `validate:"max=255"`
+	CodeChallenge       string `validate:"required,len=43"`
+	CodeChallengeMethod string `validate:"required,eq=S256"`
+}
+
+type OAuthAuthorizationResponse struct {
+	ClientId    string
+	ClientName  string
+	RedirectURI string
+	Scopes      []string
+}
+
+type OAuthConsentRequest struct {
+	OAuthAuthorizationRequest
+	Approved bool
+}
+
+type OAuthConsentResponse struct {
+	// RedirectTo carries the code, or the refusal, back to the client
+	RedirectTo string
+}
+
+type OAuthTokenRequest struct {
+	GrantType    string `validate:"required"`
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	DeviceCode   string
+	// Scope is space separated, only asked for with client_credentials
+	Scope        string
+	ClientId     string `validate:"required,uuid"`
+	ClientSecret string
+}
+
+type OAuthTokenResponse struct {
+	AccessToken string
+	TokenType   string
+	ExpiresIn   int64
+	// RefreshToken is empty for client_credentials, the client simply asks
+	// again
+	RefreshToken string
+	Scope        string
+	// IDToken is only issued for a code the openid scope was granted with
+	IDToken string
+}
+
+type OAuthDeviceAuthorizationRequest struct {
+	ClientId     string `validate:"required,uuid"`
+	ClientSecret string
+	// Scope is space separated, every scope of the client when empty
+	Scope string
+}
+
+type OAuthDeviceAuthorizationResponse struct {
+	// DeviceCode is what the device polls the token endpoint with
+	DeviceCode string
+	// UserCode is what the user types in at VerificationURI,
+	// VerificationURIComplete carries it already, for a QR code
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresIn               int64
+	Interval                int64
+}
+
+type OAuthDeviceRequest struct {
+	ProfileId string
+	UserCode  string `validate:"required,max=20"`
+}
+
+type OAuthDeviceResponse struct {
+	ClientId   string
+	ClientName string
+	Scopes     []string
+}
+
+type OAuthDeviceConsentRequest struct {
+	OAuthDeviceRequest
+	Approved bool
+}
+
+type IntrospectTokenRequest struct {
+	Token string `validate:"required"`
+	// TokenTypeHint is accepted as RFC 7662 asks, every kind of token is
+	// looked up the same way
+	TokenTypeHint string
+}
+
+// IntrospectTokenResponse of a token that is not active carries nothing else,
+// the caller learns nothing about tokens it cannot use. Subject is the
+// profile, or the client acting on its own. Scopes are empty for a login,
+// which is not limited to any.
+type IntrospectTokenResponse struct {
+	Active    bool
+	Subject   string
+	ClientId  string
+	Scopes    []string
+	TokenType string
+	ExpiresAt *time.Time
+	// CacheFor is how long the answer may be reused, not at all when zero
+	CacheFor time.Duration
+}
+
+type ListOAuthGrantsRequest struct {
+	ProfileId string
+}
+
+type ListOAuthGrantsResponse struct {
+	Grants []OAuthGrant
+}
+
+type RevokeOAuthGrantRequest struct {
+	ProfileId string
+	ClientId  string `validate:"required,uuid"`
+}
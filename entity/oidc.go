@@ -0,0 +1,61 @@
+package entity
+
+// UserInfo holds the standard claims of a profile, a claim is nil when the
+// scope that releases it was not granted.
+type UserInfo struct {
+	ProfileId           string
+	Name                *string
+	PhoneNumber         *string
+	PhoneNumberVerified *bool
+}
+
+type GenerateIDTokenRequest struct {
+	ClientId  string
+	SessionId string
+	Nonce     string
+	UserInfo  UserInfo
+}
+
+type IDTokenClaims struct {
+	ProfileId string
+	ClientId  string
+	SessionId string
+}
+
+type GetUserInfoRequest struct {
+	ProfileId string
+	Scopes    []string
+}
+
+type OpenIDConfiguration struct {
+	Issuer                            string
+	AuthorizationEndpoint             string
+	TokenEndpoint                     string
+	UserInfoEndpoint                  string
+	JWKSURI                           string
+	EndSessionEndpoint                string
+	DeviceAuthorizationEndpoint       string
+	IntrospectionEndpoint             string
+	ScopesSupported                   []string
+	ResponseTypesSupported            []string
+	GrantTypesSupported               []string
+	SubjectTypesSupported             []string
+	IDTokenSigningAlgValuesSupported  []string
+	TokenEndpointAuthMethodsSupported []string
+	CodeChallengeMethodsSupported     []string
+	ClaimsSupported                   []string
+}
+
+// OIDCLogoutRequest comes from the browser of the user, sent by the client
+// with the ID token it was given as IDTokenHint.
+type OIDCLogoutRequest struct {
+	IDTokenHint           string `validate:"required"`
+	ClientId              string
+	PostLogoutRedirectURI string
+	State                 string
+}
+
+type OIDCLogoutResponse struct {
+	// RedirectTo is empty when the client did not ask to be returned to
+	RedirectTo string
+}
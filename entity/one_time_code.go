@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+type OneTimeCode struct {
+	Id         string     `db:"id"`
+	ProfileId  string     `db:"profile_id"`
+	Purpose    string     `db:"purpose"`
+	CodeHash   string     `db:"code_hash"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	Attempts   int        `db:"attempts"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+}
+
+type RequestPasswordResetRequest struct {
+	PhoneNumber string `validate:"required,e164,startswith=+62"`
+}
+
+type ConfirmPasswordResetRequest struct {
+	PhoneNumber string `validate:"required,e164,startswith=+62"`
+	Code        string `validate:"required,numeric,len=6"`
+	// checked against the password policy by the service
+	NewPassword string `validate:"required"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// PasswordHistory is a hash a profile used as its password before the
+// current one.
+type PasswordHistory struct {
+	Id        string    `db:"id"`
+	ProfileId string    `db:"profile_id"`
+	Password  string    `db:"password"`
+	CreatedAt time.Time `db:"created_at"`
+}
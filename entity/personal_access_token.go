@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// PersonalAccessToken lets a script call the API as its owner without the
+// password. Scopes is space separated, as OAuth writes them.
+type PersonalAccessToken struct {
+	Id         string     `db:"id"`
+	ProfileId  string     `db:"profile_id"`
+	Name       string     `db:"name"`
+	TokenHash  string     `db:"token_hash"`
+	Scopes     string     `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	ProfileId string
+	Name      string   `validate:"required,max=100"`
+	Scopes    []string `validate:"required,min=1,dive,oneof=profile:read profile:write"`
+	// ExpiresAt is left out for a token that never expires
+	ExpiresAt *time.Time `validate:"omitempty,gt"`
+}
+
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessToken PersonalAccessToken
+	// Token is only ever shown here, only its hash is stored
+	Token string
+}
+
+type ListPersonalAccessTokensRequest struct {
+	ProfileId string
+}
+
+type ListPersonalAccessTokensResponse struct {
+	PersonalAccessTokens []PersonalAccessToken
+}
+
+type RevokePersonalAccessTokenRequest struct {
+	ProfileId             string
+	PersonalAccessTokenId string `validate:"required,uuid"`
+}
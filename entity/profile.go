@@ -1,42 +1,100 @@
 package entity
 
+import "time"
+
 type UserProfile struct {
-	Id          string `db:"id"`
-	FullName    string `db:"full_name"`
-	PhoneNumber string `db:"phone_number"`
-	Password    string `db:"password"`
+	Id              string     `db:"id"`
+	FullName        string     `db:"full_name"`
+	PhoneNumber     string     `db:"phone_number"`
+	Password        string     `db:"password"`
+	CreatedAt       time.Time  `db:"created_at"`
+	PhoneVerifiedAt *time.Time `db:"phone_verified_at"` // nil until the owner of the number confirms it
+
+	FailedLoginCount int        `db:"failed_login_count"` // consecutive failures since the last successful login
+	LockedUntil      *time.Time `db:"locked_until"`
+
+	PasswordChangedAt  time.Time `db:"password_changed_at"`
+	MustChangePassword bool      `db:"must_change_password"` // set by an admin, cleared by a new password
+
+	PendingPhoneNumber *string `db:"pending_phone_number"` // replaces PhoneNumber once the code sent to it is confirmed
 }
 
 type ProfileRegisterRequest struct {
 	FullName    string `validate:"required,gte=3,lte=60,alpha"`
 	PhoneNumber string `validate:"required,e164,startswith=+62"`
-	Password    string `validate:"required,gte=3,lte=64,anyAlphaCapital,anyNumeric,anySpecialChar"`
+	// checked against the password policy by the service
+	Password string `validate:"required"`
 }
 
 type ProfileRegisterResponse struct {
 	Id string
 }
 
+type VerifyPhoneRequest struct {
+	PhoneNumber string `validate:"required,e164,startswith=+62"`
+	Code        string `validate:"required,numeric,len=6"`
+}
+
 type GetProfileRequest struct {
-	ProfileId string
+	ProfileId string `validate:"required,uuid"`
 }
 
 type GetProfileResponse struct {
 	FullName    string `validate:"required,gte=3,lte=60,alpha"`
 	PhoneNumber string `validate:"required,e164,startswith=+62"`
+
+	RecoveryCodesRemaining int
 }
 
 type LoginRequest struct {
 	PhoneNumber string `validate:"required,e164,startswith=+62"`
-	Password    string // no need to validate password on login
+	// either one signs in, no need to validate their format on login
+	Password     string `validate:"required_without=RecoveryCode"`
+	RecoveryCode string `validate:"required_without=Password"`
+	DeviceName   string `validate:"lte=100"`
+	UserAgent    string
+	IpAddress    string
 }
 
+// LoginResponse carries either the issued tokens or, when the profile has
+// two-factor authentication on, the MFA challenge that completes the login.
 type LoginResponse struct {
-	Token string
+	Token        string
+	RefreshToken string
+	ExpiresIn    int64
+	// PasswordChangeRequired logins get a token only good to set a new
+	// password and no refresh token
+	PasswordChangeRequired bool
+
+	MFAToken     string
+	MFAExpiresIn int64
+}
+
+type SetNewPasswordRequest struct {
+	ProfileId string
+	// checked against the password policy by the service
+	NewPassword string `validate:"required"`
+}
+
+type ForcePasswordChangeRequest struct {
+	PhoneNumber string `validate:"required,e164,startswith=+62"`
+}
+
+type ChangePasswordRequest struct {
+	ProfileId       string
+	SessionId       string
+	CurrentPassword string `validate:"required"`
+	// checked against the password policy by the service
+	NewPassword string `validate:"required,nefield=CurrentPassword"`
 }
 
 type UpdateProfileRequest struct {
 	Id          string
 	FullName    string `validate:"required,gte=3,lte=60,alpha"`
-	PhoneNumber string `validate:"required,gte=3,lte=64,anyAlphaCapital,anyNumeric,anySpecialChar"`
+	PhoneNumber string `validate:"required,e164,startswith=+62"`
+}
+
+type ConfirmPhoneChangeRequest struct {
+	ProfileId string
+	Code      string `validate:"required,numeric,len=6"`
 }
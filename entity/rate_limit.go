@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+// RateLimitRule is one token bucket an operation draws from, as listed in the
+// x-rate-limit extension of api.yml. Key names what the bucket is per, the
+// client IP or the phone number in the request body.
+type RateLimitRule struct {
+	Key             string  `json:"key"`
+	Capacity        float64 `json:"capacity"`
+	RefillPerMinute float64 `json:"refill_per_minute"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `db:"bucket_key"`
+	Tokens    float64   `db:"tokens"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type TakeRateLimitTokenRequest struct {
+	BucketKey string
+	Rule      RateLimitRule
+}
+
+type TakeRateLimitTokenResponse struct {
+	Allowed    bool
+	RetryAfter time.Duration
+}
@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type RecoveryCode struct {
+	Id        string     `db:"id"`
+	ProfileId string     `db:"profile_id"`
+	CodeHash  string     `db:"code_hash"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+
+type GenerateRecoveryCodesRequest struct {
+	ProfileId string
+}
+
+type GenerateRecoveryCodesResponse struct {
+	Codes []string
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Role is a named set of permissions, space separated like the scopes of a
+// grant. The permissions of the roles of a profile are scopes of its access
+// tokens.
+type Role struct {
+	Id          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Permissions string    `db:"permissions"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ProfileRole is a role held by a profile. AssignedBy is nil when the role
+// was assigned from the admin command rather than by another profile.
+type ProfileRole struct {
+	ProfileId  string    `db:"profile_id"`
+	RoleId     string    `db:"role_id"`
+	AssignedBy *string   `db:"assigned_by"`
+	AssignedAt time.Time `db:"assigned_at"`
+}
+
+type ListRolesResponse struct {
+	Roles []Role
+}
+
+type ListProfileRolesRequest struct {
+	ProfileId string `validate:"required,uuid"`
+}
+
+type ListProfileRolesResponse struct {
+	Roles []Role
+}
+
+type AssignRoleRequest struct {
+	ProfileId string `validate:"required,uuid"`
+	RoleName  string `validate:"required,max=64"`
+	// AssignedBy is the profile assigning the role, empty from the admin
+	// command
+	AssignedBy string `validate:"omitempty,uuid"`
+}
+
+type UnassignRoleRequest struct {
+	ProfileId string `validate:"required,uuid"`
+	RoleName  string `validate:"required,max=64"`
+}
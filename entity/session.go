@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Session is one logged-in device. The refresh tokens issued to it form a
+// family that shares the session id.
+type Session struct {
+	Id         string    `db:"id"`
+	ProfileId  string    `db:"profile_id"`
+	DeviceName string    `db:"device_name"`
+	UserAgent  string    `db:"user_agent"`
+	IpAddress  string    `db:"ip_address"`
+	CreatedAt  time.Time `db:"created_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+}
+
+type ListSessionsRequest struct {
+	ProfileId string
+}
+
+type ListSessionsResponse struct {
+	Sessions []Session
+}
+
+type RevokeSessionRequest struct {
+	ProfileId string
+	SessionId string `validate:"required,uuid"`
+}
+
+type RevokeAllSessionsRequest struct {
+	ProfileId string
+}
+
+type RevokeOtherSessionsRequest struct {
+	ProfileId        string
+	CurrentSessionId string
+}
@@ -0,0 +1,103 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	Id        string     `db:"id"`
+	ProfileId string     `db:"profile_id"`
+	FamilyId  string     `db:"family_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type TokenClaims struct {
+	ProfileId string
+	SessionId string
+	TokenId   string
+	ExpiresAt time.Time
+	// PasswordChangeRequired tokens are only accepted to set a new password
+	PasswordChangeRequired bool
+	// PersonalAccessTokenId is set when a personal access token was presented
+	// in place of a JWT, ClientId when the token was issued to an OAuth
+	// client. Either only grants its Scopes. A client acting on its own with
+	// the client credentials grant has neither ProfileId nor SessionId.
+	PersonalAccessTokenId string
+	ClientId              string
+	Scopes                []string
+	// Roles of a login, whose Scopes hold their permissions besides what
+	// every login may do
+	Roles []string
+}
+
+// GenerateTokenRequest without a ProfileId but with a ClientId is a token
+// of the client itself. A login has Roles and the Scopes they grant.
+type GenerateTokenRequest struct {
+	ProfileId              string
+	SessionId              string
+	PasswordChangeRequired bool
+	ClientId               string
+	Scopes                 []string
+	Roles                  []string
+}
+
+type IssueTokenRequest struct {
+	ProfileId              string
+	DeviceName             string
+	UserAgent              string
+	IpAddress              string
+	PasswordChangeRequired bool
+}
+
+type IssueTokenResponse struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `validate:"required"`
+}
+
+type RefreshTokenResponse struct {
+	Token        string
+	RefreshToken string
+	ExpiresIn    int64
+}
+
+type AuthenticateRequest struct {
+	Token string
+}
+
+type LogoutRequest struct {
+	ProfileId    string
+	SessionId    string
+	TokenId      string
+	ExpiresAt    time.Time
+	RefreshToken string
+}
+
+type SigningKey struct {
+	Id          string     `db:"id"`
+	Algorithm   string     `db:"algorithm"`
+	PrivateKey  string     `db:"private_key"`
+	ActivatesAt time.Time  `db:"activates_at"`
+	RetiresAt   *time.Time `db:"retires_at"`
+}
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
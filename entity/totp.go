@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// TOTPCredential holds the encrypted TOTP secrets of a profile. Secret is the
+// one logins are checked against, PendingSecret one being enrolled that only
+// replaces it once a code generated from it has been confirmed.
+type TOTPCredential struct {
+	ProfileId     string     `db:"profile_id"`
+	Secret        *string    `db:"secret"`
+	PendingSecret *string    `db:"pending_secret"`
+	LastUsedStep  int64      `db:"last_used_step"` // codes of this step or earlier are never accepted again
+	EnabledAt     *time.Time `db:"enabled_at"`
+}
+
+type MFAChallenge struct {
+	Id        string    `db:"id"`
+	ProfileId string    `db:"profile_id"`
+	TokenHash string    `db:"token_hash"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type EnrollTOTPRequest struct {
+	ProfileId string
+	Password  string `validate:"required"`
+}
+
+type EnrollTOTPResponse struct {
+	Secret string
+	KeyURI string
+}
+
+type ConfirmTOTPRequest struct {
+	ProfileId string
+	Code      string `validate:"required,numeric,len=6"`
+}
+
+type DisableTOTPRequest struct {
+	ProfileId string
+	Password  string `validate:"required"`
+}
+
+type VerifyLoginMFARequest struct {
+	MFAToken   string `validate:"required"`
+	Code       string `validate:"required,numeric,len=6"`
+	DeviceName string `validate:"lte=100"`
+	UserAgent  string
+	IpAddress  string
+}
@@ -6,4 +6,25 @@ var (
 	ErrPasswordNotMatch = errors.New("error password not match with hashed password")
 	ErrInvalidToken     = errors.New("error invalid token")
 	ErrNotAuthenticated = errors.New("error not authenticated")
+
+	ErrTokenExpired          = errors.New("error token has expired")
+	ErrTokenNotValidYet      = errors.New("error token is not valid yet")
+	ErrTokenMalformed        = errors.New("error token is malformed")
+	ErrTokenSignatureInvalid = errors.New("error token signature is invalid")
+	ErrTokenInvalidIssuer    = errors.New("error token has an invalid issuer")
+	ErrTokenInvalidAudience  = errors.New("error token has an invalid audience")
+
+	ErrUnsupportedSigningAlgorithm = errors.New("error unsupported signing algorithm")
+	ErrMissingKeySecret            = errors.New("error signing key secret is not configured")
+	ErrNoSigningKey                = errors.New("error no active signing key")
+	ErrUnknownSigningKey           = errors.New("error unknown signing key")
+	ErrMalformedSecret             = errors.New("error encrypted secret is malformed")
+
+	ErrUnsupportedPasswordHashAlgorithm = errors.New("error unsupported password hash algorithm")
+	ErrInvalidPasswordHashParams        = errors.New("error invalid password hash parameters")
+	ErrMalformedPasswordHash            = errors.New("error password hash is malformed")
+
+	ErrMalformedBreachedPasswordIndex = errors.New("error breached password index is malformed")
+	ErrMalformedBreachedPasswordDump  = errors.New("error breached password dump is malformed")
+	ErrUnorderedBreachedPasswordDump  = errors.New("error breached password dump is not ordered by hash")
 )
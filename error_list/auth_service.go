@@ -0,0 +1,33 @@
+package error_list
+
+import "errors"
+
+var (
+	ErrIssueToken          = errors.New("error when issuing token")
+	ErrRefreshToken        = errors.New("error when refreshing token")
+	ErrInvalidRefreshToken = errors.New("error invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("error refresh token reuse detected")
+
+	ErrAuthenticate       = errors.New("error when authenticating token")
+	ErrTokenRevoked       = errors.New("error token has been revoked")
+	ErrLogout             = errors.New("error when logging out")
+	ErrPruneRevokedTokens = errors.New("error when pruning revoked tokens")
+
+	ErrRotateSigningKeys = errors.New("error when rotating signing keys")
+
+	ErrSessionEnded      = errors.New("error session has ended")
+	ErrSessionNotFound   = errors.New("error session not found")
+	ErrListSessions      = errors.New("error when listing sessions")
+	ErrRevokeSession     = errors.New("error when revoking session")
+	ErrPruneIdleSessions = errors.New("error when pruning idle sessions")
+
+	ErrCreatePersonalAccessToken   = errors.New("error when creating personal access token")
+	ErrListPersonalAccessTokens    = errors.New("error when listing personal access tokens")
+	ErrRevokePersonalAccessToken   = errors.New("error when revoking personal access token")
+	ErrPersonalAccessTokenNotFound = errors.New("error personal access token not found")
+	ErrInvalidPersonalAccessToken  = errors.New("error invalid personal access token")
+	ErrInsufficientScope           = errors.New("error token does not have the scope for this operation")
+
+	ErrUserTokenRequired   = errors.New("error operation needs a token of a user")
+	ErrClientTokenRequired = errors.New("error operation needs a token of a client")
+)
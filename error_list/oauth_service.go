@@ -0,0 +1,38 @@
+package error_list
+
+import "errors"
+
+var (
+	ErrRegisterOAuthClient = errors.New("error when registering oauth client")
+	ErrInvalidOAuthClient  = errors.New("error invalid oauth client")
+	ErrInvalidRedirectURI  = errors.New("error redirect uri is not registered for the client")
+	ErrInvalidOAuthScope   = errors.New("error scope is not allowed for the client")
+	ErrAuthorizeOAuth      = errors.New("error when authorizing oauth client")
+
+	ErrInvalidOAuthRequest     = errors.New("error invalid oauth request")
+	ErrInvalidOAuthGrant       = errors.New("error invalid or expired authorization grant")
+	ErrUnsupportedGrantType    = errors.New("error unsupported grant type")
+	ErrUnauthorizedOAuthClient = errors.New("error grant type is not allowed for the client")
+	ErrOAuthToken              = errors.New("error when issuing oauth token")
+
+	ErrOAuthGrantRevoked            = errors.New("error oauth grant has been revoked")
+	ErrOAuthClientRevoked           = errors.New("error oauth client has been revoked")
+	ErrOAuthGrantNotFound           = errors.New("error oauth grant not found")
+	ErrListOAuthGrants              = errors.New("error when listing oauth grants")
+	ErrRevokeOAuthGrant             = errors.New("error when revoking oauth grant")
+	ErrPruneOAuthAuthorizationCodes = errors.New("error when pruning oauth authorization codes")
+
+	ErrAuthorizeOAuthDevice  = errors.New("error when authorizing oauth device")
+	ErrInvalidUserCode       = errors.New("error invalid or expired user code")
+	ErrAuthorizationPending  = errors.New("error the user has not answered yet")
+	ErrSlowDown              = errors.New("error polling too often")
+	ErrOAuthAccessDenied     = errors.New("error the user denied the authorization")
+	ErrExpiredDeviceCode     = errors.New("error device code has expired")
+	ErrPruneOAuthDeviceCodes = errors.New("error when pruning oauth device codes")
+
+	ErrIntrospectToken = errors.New("error when introspecting token")
+
+	ErrGetUserInfo        = errors.New("error when getting user info")
+	ErrInvalidIDTokenHint = errors.New("error invalid id token hint")
+	ErrEndOIDCSession     = errors.New("error when ending openid connect session")
+)
@@ -0,0 +1,20 @@
+package error_list
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidPasswordPolicy = errors.New("error invalid password policy")
+)
+
+// PasswordPolicyError lists every rule of the password policy a new
+// password breaks.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (err PasswordPolicyError) Error() string {
+	return "error password does not meet the policy: " + strings.Join(err.Violations, "; ")
+}
@@ -8,8 +8,42 @@ var (
 	ErrProfileNotFound = errors.New("error profile not found")
 	ErrDataConflict    = errors.New("error there existing data conficted with new data")
 
-	ErrLoginCredential = errors.New("error credentials combination not match")
-	ErrLogin           = errors.New("error when try to login")
+	ErrLoginCredential  = errors.New("error credentials combination not match")
+	ErrLogin            = errors.New("error when try to login")
+	ErrPhoneNotVerified = errors.New("error phone number not verified")
+	ErrAccountLocked    = errors.New("error account is temporarily locked, try again later")
 
-	ErrUpdateProfile = errors.New("error when updating profile")
+	ErrVerifyPhone             = errors.New("error when verifying phone number")
+	ErrPruneUnverifiedProfiles = errors.New("error when pruning unverified profiles")
+
+	ErrUpdateProfile      = errors.New("error when updating profile")
+	ErrConfirmPhoneChange = errors.New("error when confirming phone number change")
+
+	ErrCurrentPasswordNotMatch = errors.New("error current password not match")
+	ErrBreachedPassword        = errors.New("error password has appeared in a data breach, choose another one")
+	ErrPasswordReused          = errors.New("error password was used recently, choose another one")
+	ErrChangePassword          = errors.New("error when changing password")
+
+	ErrPasswordChangeRequired    = errors.New("error password must be replaced before continuing")
+	ErrPasswordChangeNotRequired = errors.New("error password does not need to be replaced, change it instead")
+	ErrSetNewPassword            = errors.New("error when setting new password")
+	ErrForcePasswordChange       = errors.New("error when forcing password change")
+
+	ErrRequestPasswordReset        = errors.New("error when requesting password reset")
+	ErrResetPassword               = errors.New("error when resetting password")
+	ErrInvalidOneTimeCode          = errors.New("error invalid or expired code")
+	ErrOneTimeCodeAttemptsExceeded = errors.New("error too many attempts for this code")
+	ErrPruneOneTimeCodes           = errors.New("error when pruning one time codes")
+
+	ErrEnrollTOTP                   = errors.New("error when enrolling two-factor authentication")
+	ErrConfirmTOTP                  = errors.New("error when confirming two-factor authentication")
+	ErrDisableTOTP                  = errors.New("error when disabling two-factor authentication")
+	ErrTOTPNotPending               = errors.New("error no two-factor enrollment to confirm")
+	ErrTOTPNotEnabled               = errors.New("error two-factor authentication is not enabled")
+	ErrInvalidTOTPCode              = errors.New("error invalid two-factor code")
+	ErrInvalidMFAChallenge          = errors.New("error invalid or expired two-factor challenge")
+	ErrMFAChallengeAttemptsExceeded = errors.New("error too many attempts for this two-factor challenge")
+	ErrPruneMFAChallenges           = errors.New("error when pruning two-factor challenges")
+
+	ErrGenerateRecoveryCodes = errors.New("error when generating recovery codes")
 )
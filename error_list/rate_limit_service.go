@@ -0,0 +1,9 @@
+package error_list
+
+import "errors"
+
+var (
+	ErrTooManyRequests       = errors.New("error too many requests, try again later")
+	ErrRateLimit             = errors.New("error when checking rate limit")
+	ErrPruneRateLimitBuckets = errors.New("error when pruning rate limit buckets")
+)
@@ -0,0 +1,12 @@
+package error_list
+
+import "errors"
+
+var (
+	ErrListRoles        = errors.New("error when listing roles")
+	ErrListProfileRoles = errors.New("error when listing roles of profile")
+	ErrAssignRole       = errors.New("error when assigning role")
+	ErrUnassignRole     = errors.New("error when unassigning role")
+	ErrRoleNotFound     = errors.New("error role not found")
+	ErrRoleNotAssigned  = errors.New("error profile does not hold the role")
+)
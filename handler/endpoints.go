@@ -41,6 +41,34 @@ func (s *Server) RegisterProfile(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, resp)
 }
 
+func (s *Server) VerifyPhone(ctx echo.Context) error {
+	var req generated.VerifyPhoneRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	verifyPhoneReq := entity.VerifyPhoneRequest{
+		PhoneNumber: req.PhoneNumber,
+		Code:        req.Code,
+	}
+	err = s.validate(verifyPhoneReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.VerifyPhone(ctx.Request().Context(), verifyPhoneReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.VerifyPhoneResponse{
+		Message: "Success verify phone number",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
 func (s *Server) Login(ctx echo.Context) error {
 	var req generated.LoginRequest
 
@@ -51,7 +79,17 @@ func (s *Server) Login(ctx echo.Context) error {
 
 	loginReq := entity.LoginRequest{
 		PhoneNumber: req.PhoneNumber,
-		Password:    req.Password,
+		UserAgent:   ctx.Request().UserAgent(),
+		IpAddress:   ctx.RealIP(),
+	}
+	if req.Password != nil {
+		loginReq.Password = *req.Password
+	}
+	if req.RecoveryCode != nil {
+		loginReq.RecoveryCode = *req.RecoveryCode
+	}
+	if req.DeviceName != nil {
+		loginReq.DeviceName = *req.DeviceName
 	}
 	err = s.validate(loginReq)
 	if err != nil {
@@ -63,8 +101,21 @@ func (s *Server) Login(ctx echo.Context) error {
 		return s.sendErrorResponse(ctx, err)
 	}
 
+	// two-factor authentication is on, the login goes on at /login/mfa
+	if result.MFAToken != "" {
+		resp := generated.MfaChallengeResponse{
+			MfaToken:  result.MFAToken,
+			ExpiresIn: result.MFAExpiresIn,
+		}
+
+		return ctx.JSON(http.StatusAccepted, resp)
+	}
+
 	resp := generated.LoginResponse{
-		Token: result.Token,
+		Token:                  result.Token,
+		RefreshToken:           optionalString(result.RefreshToken),
+		ExpiresIn:              result.ExpiresIn,
+		PasswordChangeRequired: result.PasswordChangeRequired,
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -90,6 +141,32 @@ func (s *Server) GetProfile(ctx echo.Context, params generated.GetProfileParams)
 	}
 
 	resp := generated.GetProfileResponse{
+		FullName:               result.FullName,
+		PhoneNumber:            result.PhoneNumber,
+		RecoveryCodesRemaining: result.RecoveryCodesRemaining,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// GetProfileById is called by other services with a token of their own, the
+// profile is the one they ask for rather than one of the token.
+func (s *Server) GetProfileById(ctx echo.Context, id string, params generated.GetProfileByIdParams) error {
+	getProfileReq := entity.GetProfileRequest{
+		ProfileId: id,
+	}
+	err := s.validate(getProfileReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.profileService.GetProfile(ctx.Request().Context(), getProfileReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.GetProfileByIdResponse{
+		Id:          id,
 		FullName:    result.FullName,
 		PhoneNumber: result.PhoneNumber,
 	}
@@ -130,3 +207,109 @@ func (s *Server) UpdateProfile(ctx echo.Context, params generated.UpdateProfileP
 
 	return ctx.JSON(http.StatusOK, resp)
 }
+
+func (s *Server) ConfirmPhoneChange(ctx echo.Context, params generated.ConfirmPhoneChangeParams) error {
+	profileId, ok := ctx.Get(constant.ProfileIdJwtField).(string)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.ConfirmPhoneChangeRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	confirmReq := entity.ConfirmPhoneChangeRequest{
+		ProfileId: profileId,
+		Code:      req.Code,
+	}
+	err = s.validate(confirmReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.ConfirmPhoneChange(ctx.Request().Context(), confirmReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ConfirmPhoneChangeResponse{
+		Message: "Success change phone number",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ChangePassword(ctx echo.Context, params generated.ChangePasswordParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.ChangePasswordRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	changePasswordReq := entity.ChangePasswordRequest{
+		ProfileId:       claims.ProfileId,
+		SessionId:       claims.SessionId,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.NewPassword,
+	}
+	err = s.validate(changePasswordReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.ChangePassword(ctx.Request().Context(), changePasswordReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ChangePasswordResponse{
+		Message: "Success change password",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) SetNewPassword(ctx echo.Context, params generated.SetNewPasswordParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	// a full token changes the password knowing the current one
+	if !claims.PasswordChangeRequired {
+		return s.sendErrorResponse(ctx, error_list.ErrPasswordChangeNotRequired)
+	}
+
+	var req generated.SetNewPasswordRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	setNewPasswordReq := entity.SetNewPasswordRequest{
+		ProfileId:   claims.ProfileId,
+		NewPassword: req.NewPassword,
+	}
+	err = s.validate(setNewPasswordReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.SetNewPassword(ctx.Request().Context(), setNewPasswordReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ChangePasswordResponse{
+		Message: "Success set new password, sign in again with it",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sawitpro/entity"
+	"sawitpro/error_list"
 	"sawitpro/generated"
 	"sawitpro/helper"
 	"sawitpro/mocks"
@@ -145,6 +146,48 @@ func TestServer_RegisterProfile(t *testing.T) {
 				}).Return(entity.ProfileRegisterResponse{}, errors.New("error there existing data conficted with new data"))
 			},
 		},
+		{
+			name: "error password breaks the policy",
+			fields: fields{
+				profileService:  mockProfileService,
+				authHelper:      mockAuthHelper,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.RegisterProfileRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345A!",
+				},
+			},
+			want:    generated.RegisterProfileResponse{},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error password does not meet the policy: must be at least 10 characters; must not contain your name or phone number",
+				Details: &[]string{
+					"must be at least 10 characters",
+					"must not contain your name or phone number",
+				},
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345A!",
+				}).Return(nil)
+				mockProfileService.EXPECT().Register(gomock.Any(), entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345A!",
+				}).Return(entity.ProfileRegisterResponse{}, error_list.PasswordPolicyError{
+					Violations: []string{
+						"must be at least 10 characters",
+						"must not contain your name or phone number",
+					},
+				})
+			},
+		},
 		{
 			name: "error invalid payload",
 			fields: fields{
@@ -210,6 +253,89 @@ func TestServer_RegisterProfile(t *testing.T) {
 	}
 }
 
+func TestServer_VerifyPhone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	request := entity.VerifyPhoneRequest{
+		PhoneNumber: "+62345",
+		Code:        "012345",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success verify phone",
+			want: generated.VerifyPhoneResponse{
+				Message: "Success verify phone number",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().VerifyPhone(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name: "error invalid code",
+			want: generated.ErrorResponse{
+				Message: "error invalid or expired code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().VerifyPhone(gomock.Any(), request).Return(errors.New("error invalid or expired code"))
+			},
+		},
+		{
+			name: "error request not valid",
+			want: generated.ErrorResponse{
+				Message: "error code not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(errors.New("error code not valid"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			e.POST("/register/verify", s.VerifyPhone)
+
+			requestBody, _ := json.Marshal(generated.VerifyPhoneRequest{
+				PhoneNumber: "+62345",
+				Code:        "012345",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/register/verify", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
 func TestServer_Login(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -230,7 +356,7 @@ func TestServer_Login(t *testing.T) {
 		name       string
 		fields     fields
 		args       args
-		want       generated.LoginResponse
+		want       interface{}
 		wantErr    bool
 		errResp    *generated.ErrorResponse
 		statusCode int
@@ -245,12 +371,55 @@ func TestServer_Login(t *testing.T) {
 			},
 			args: args{
 				req: generated.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    optionalString("12345A!"),
+					DeviceName:  optionalString("Pixel 8"),
+				},
+			},
+			want: generated.LoginResponse{
+				Token:        "token1",
+				RefreshToken: optionalString("refresh-token1"),
+				ExpiresIn:    900,
+			},
+			wantErr:    false,
+			errResp:    nil,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					DeviceName:  "Pixel 8",
+					IpAddress:   "192.0.2.1",
+				}).Return(nil)
+				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345A!",
+					DeviceName:  "Pixel 8",
+					IpAddress:   "192.0.2.1",
+				}).Return(entity.LoginResponse{
+					Token:        "token1",
+					RefreshToken: "refresh-token1",
+					ExpiresIn:    900,
+				}, nil)
+			},
+		},
+		{
+			name: "success login with password change required",
+			fields: fields{
+				profileService:  mockProfileService,
+				authHelper:      mockAuthHelper,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    optionalString("12345A!"),
 				},
 			},
 			want: generated.LoginResponse{
-				Token: "token1",
+				Token:                  "token1",
+				ExpiresIn:              900,
+				PasswordChangeRequired: true,
 			},
 			wantErr:    false,
 			errResp:    nil,
@@ -259,17 +428,21 @@ func TestServer_Login(t *testing.T) {
 				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(nil)
 				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(entity.LoginResponse{
-					Token: "token1",
+					Token:                  "token1",
+					ExpiresIn:              900,
+					PasswordChangeRequired: true,
 				}, nil)
 			},
 		},
 		{
-			name: "error profile not found",
+			name: "password accepted, two-factor challenge",
 			fields: fields{
 				profileService:  mockProfileService,
 				authHelper:      mockAuthHelper,
@@ -277,8 +450,82 @@ func TestServer_Login(t *testing.T) {
 			},
 			args: args{
 				req: generated.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    optionalString("12345A!"),
+				},
+			},
+			want: generated.MfaChallengeResponse{
+				MfaToken:  "mfa-token1",
+				ExpiresIn: 300,
+			},
+			wantErr:    false,
+			errResp:    nil,
+			statusCode: http.StatusAccepted,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
+				}).Return(nil)
+				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
+				}).Return(entity.LoginResponse{
+					MFAToken:     "mfa-token1",
+					MFAExpiresIn: 300,
+				}, nil)
+			},
+		},
+		{
+			name: "success login with recovery code",
+			fields: fields{
+				profileService:  mockProfileService,
+				authHelper:      mockAuthHelper,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.LoginRequest{
+					PhoneNumber:  "+62345",
+					RecoveryCode: optionalString("ABCD-EFGH-JKMN-PQRS"),
+				},
+			},
+			want: generated.LoginResponse{
+				Token:        "token1",
+				RefreshToken: optionalString("refresh-token1"),
+				ExpiresIn:    900,
+			},
+			wantErr:    false,
+			errResp:    nil,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
+					PhoneNumber:  "+62345",
+					RecoveryCode: "ABCD-EFGH-JKMN-PQRS",
+					IpAddress:    "192.0.2.1",
+				}).Return(nil)
+				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
+					PhoneNumber:  "+62345",
+					RecoveryCode: "ABCD-EFGH-JKMN-PQRS",
+					IpAddress:    "192.0.2.1",
+				}).Return(entity.LoginResponse{
+					Token:        "token1",
+					RefreshToken: "refresh-token1",
+					ExpiresIn:    900,
+				}, nil)
+			},
+		},
+		{
+			name: "error profile not found",
+			fields: fields{
+				profileService:  mockProfileService,
+				authHelper:      mockAuthHelper,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    optionalString("12345A!"),
 				},
 			},
 			want:    generated.LoginResponse{},
@@ -291,10 +538,12 @@ func TestServer_Login(t *testing.T) {
 				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(nil)
 				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(entity.LoginResponse{}, errors.New("error profile not found"))
 			},
 		},
@@ -308,7 +557,7 @@ func TestServer_Login(t *testing.T) {
 			args: args{
 				req: generated.LoginRequest{
 					PhoneNumber: "+62345",
-					Password:    "12345A!",
+					Password:    optionalString("12345A!"),
 				},
 			},
 			want:    generated.LoginResponse{},
@@ -321,10 +570,12 @@ func TestServer_Login(t *testing.T) {
 				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(nil)
 				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(entity.LoginResponse{}, errors.New("error credentials combination not match"))
 			},
 		},
@@ -338,7 +589,7 @@ func TestServer_Login(t *testing.T) {
 			args: args{
 				req: generated.LoginRequest{
 					PhoneNumber: "+62345",
-					Password:    "12345A!",
+					Password:    optionalString("12345A!"),
 				},
 			},
 			want:    generated.LoginResponse{},
@@ -351,10 +602,12 @@ func TestServer_Login(t *testing.T) {
 				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(nil)
 				mockProfileService.EXPECT().Login(gomock.Any(), entity.LoginRequest{
 					PhoneNumber: "+62345",
 					Password:    "12345A!",
+					IpAddress:   "192.0.2.1",
 				}).Return(entity.LoginResponse{}, errors.New("error when try to login"))
 			},
 		},
@@ -368,7 +621,7 @@ func TestServer_Login(t *testing.T) {
 			args: args{
 				req: generated.LoginRequest{
 					PhoneNumber: "62345",
-					Password:    "12345",
+					Password:    optionalString("12345"),
 				},
 			},
 			want:    generated.LoginResponse{},
@@ -381,6 +634,7 @@ func TestServer_Login(t *testing.T) {
 				mockValidatorHelper.EXPECT().ValidateStruct(entity.LoginRequest{
 					PhoneNumber: "62345",
 					Password:    "12345",
+					IpAddress:   "192.0.2.1",
 				}).Return(errors.New("error phone number not valid"))
 			},
 		},
@@ -458,8 +712,9 @@ func TestServer_GetProfile(t *testing.T) {
 				profileId: "profile-id-1",
 			},
 			want: generated.GetProfileResponse{
-				FullName:    "jonathan",
-				PhoneNumber: "+62345",
+				FullName:               "jonathan",
+				PhoneNumber:            "+62345",
+				RecoveryCodesRemaining: 10,
 			},
 			wantErr:    false,
 			errResp:    nil,
@@ -471,8 +726,9 @@ func TestServer_GetProfile(t *testing.T) {
 				mockProfileService.EXPECT().GetProfile(gomock.Any(), entity.GetProfileRequest{
 					ProfileId: "profile-id-1",
 				}).Return(entity.GetProfileResponse{
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
+					FullName:               "jonathan",
+					PhoneNumber:            "+62345",
+					RecoveryCodesRemaining: 10,
 				}, nil)
 			},
 		},
@@ -759,3 +1015,386 @@ func TestServer_UpdateProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_ConfirmPhoneChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	request := entity.ConfirmPhoneChangeRequest{
+		ProfileId: "profile-id-1",
+		Code:      "012345",
+	}
+
+	tests := []struct {
+		name       string
+		want       generated.ConfirmPhoneChangeResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success confirm phone change",
+			want: generated.ConfirmPhoneChangeResponse{
+				Message: "Success change phone number",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPhoneChange(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name:    "error invalid code",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid or expired code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPhoneChange(gomock.Any(), request).Return(errors.New("error invalid or expired code"))
+			},
+		},
+		{
+			name:    "error number taken meanwhile",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error there existing data conficted with new data",
+			},
+			statusCode: http.StatusConflict,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPhoneChange(gomock.Any(), request).Return(errors.New("error there existing data conficted with new data"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("profile_id", "profile-id-1")
+
+				return s.ConfirmPhoneChange(ctx, generated.ConfirmPhoneChangeParams{})
+			}
+
+			e.POST("/profile/phone/verify", wrapper)
+
+			requestBody, _ := json.Marshal(generated.ConfirmPhoneChangeRequest{
+				Code: "012345",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/profile/phone/verify", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+	changePasswordReq := entity.ChangePasswordRequest{
+		ProfileId:       "profile-id-1",
+		SessionId:       "session-id-1",
+		CurrentPassword: "12345A!",
+		NewPassword:     "67890B!",
+	}
+
+	type args struct {
+		req    generated.ChangePasswordRequest
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.ChangePasswordResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success change password",
+			args: args{
+				req: generated.ChangePasswordRequest{
+					CurrentPassword: "12345A!",
+					NewPassword:     "67890B!",
+				},
+				claims: claims,
+			},
+			want: generated.ChangePasswordResponse{
+				Message: "Success change password",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(changePasswordReq).Return(nil)
+				mockProfileService.EXPECT().ChangePassword(gomock.Any(), changePasswordReq).Return(nil)
+			},
+		},
+		{
+			name: "error current password not match",
+			args: args{
+				req: generated.ChangePasswordRequest{
+					CurrentPassword: "12345A!",
+					NewPassword:     "67890B!",
+				},
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error current password not match",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(changePasswordReq).Return(nil)
+				mockProfileService.EXPECT().ChangePassword(gomock.Any(), changePasswordReq).Return(errors.New("error current password not match"))
+			},
+		},
+		{
+			name: "error when validate",
+			args: args{
+				req: generated.ChangePasswordRequest{
+					CurrentPassword: "12345A!",
+					NewPassword:     "67890B!",
+				},
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error in new password",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(changePasswordReq).Return(errors.New("error in new password"))
+			},
+		},
+		{
+			name: "error missing token claims",
+			args: args{
+				req:    generated.ChangePasswordRequest{},
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+
+				return s.ChangePassword(ctx, generated.ChangePasswordParams{})
+			}
+
+			e.PUT("/profile/password", wrapper)
+
+			requestBody, _ := json.Marshal(tt.args.req)
+
+			req := httptest.NewRequest(http.MethodPut, "/profile/password", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_SetNewPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId:              "profile-id-1",
+		SessionId:              "session-id-1",
+		PasswordChangeRequired: true,
+	}
+	setNewPasswordReq := entity.SetNewPasswordRequest{
+		ProfileId:   "profile-id-1",
+		NewPassword: "67890B!",
+	}
+
+	type args struct {
+		req    generated.SetNewPasswordRequest
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.ChangePasswordResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success set new password",
+			args: args{
+				req: generated.SetNewPasswordRequest{
+					NewPassword: "67890B!",
+				},
+				claims: claims,
+			},
+			want: generated.ChangePasswordResponse{
+				Message: "Success set new password, sign in again with it",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(setNewPasswordReq).Return(nil)
+				mockProfileService.EXPECT().SetNewPassword(gomock.Any(), setNewPasswordReq).Return(nil)
+			},
+		},
+		{
+			name: "error password used recently",
+			args: args{
+				req: generated.SetNewPasswordRequest{
+					NewPassword: "67890B!",
+				},
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error password was used recently, choose another one",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(setNewPasswordReq).Return(nil)
+				mockProfileService.EXPECT().SetNewPassword(gomock.Any(), setNewPasswordReq).Return(errors.New("error password was used recently, choose another one"))
+			},
+		},
+		{
+			name: "error token not restricted",
+			args: args{
+				req: generated.SetNewPasswordRequest{
+					NewPassword: "67890B!",
+				},
+				claims: entity.TokenClaims{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+				},
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error password does not need to be replaced, change it instead",
+			},
+			statusCode: http.StatusForbidden,
+			mock:       func() {},
+		},
+		{
+			name: "error missing claims",
+			args: args{
+				req: generated.SetNewPasswordRequest{
+					NewPassword: "67890B!",
+				},
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+
+				return s.SetNewPassword(ctx, generated.SetNewPasswordParams{})
+			}
+
+			e.PUT("/profile/password/new", wrapper)
+
+			requestBody, _ := json.Marshal(tt.args.req)
+
+			req := httptest.NewRequest(http.MethodPut, "/profile/password/new", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
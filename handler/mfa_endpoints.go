@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) EnrollTotp(ctx echo.Context, params generated.EnrollTotpParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.EnrollTotpRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	enrollTOTPReq := entity.EnrollTOTPRequest{
+		ProfileId: claims.ProfileId,
+		Password:  req.Password,
+	}
+	err = s.validate(enrollTOTPReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.profileService.EnrollTOTP(ctx.Request().Context(), enrollTOTPReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.EnrollTotpResponse{
+		Secret:     result.Secret,
+		OtpauthUri: result.KeyURI,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ConfirmTotp(ctx echo.Context, params generated.ConfirmTotpParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.ConfirmTotpRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	confirmTOTPReq := entity.ConfirmTOTPRequest{
+		ProfileId: claims.ProfileId,
+		Code:      req.Code,
+	}
+	err = s.validate(confirmTOTPReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.ConfirmTOTP(ctx.Request().Context(), confirmTOTPReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ConfirmTotpResponse{
+		Message: "Success enable two-factor authentication",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) DisableTotp(ctx echo.Context, params generated.DisableTotpParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.DisableTotpRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	disableTOTPReq := entity.DisableTOTPRequest{
+		ProfileId: claims.ProfileId,
+		Password:  req.Password,
+	}
+	err = s.validate(disableTOTPReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.DisableTOTP(ctx.Request().Context(), disableTOTPReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.DisableTotpResponse{
+		Message: "Success disable two-factor authentication",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) VerifyLoginMfa(ctx echo.Context) error {
+	var req generated.VerifyLoginMfaRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	verifyLoginMFAReq := entity.VerifyLoginMFARequest{
+		MFAToken:  req.MfaToken,
+		Code:      req.Code,
+		UserAgent: ctx.Request().UserAgent(),
+		IpAddress: ctx.RealIP(),
+	}
+	if req.DeviceName != nil {
+		verifyLoginMFAReq.DeviceName = *req.DeviceName
+	}
+	err = s.validate(verifyLoginMFAReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.profileService.VerifyLoginMFA(ctx.Request().Context(), verifyLoginMFAReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.LoginResponse{
+		Token:                  result.Token,
+		RefreshToken:           optionalString(result.RefreshToken),
+		ExpiresIn:              result.ExpiresIn,
+		PasswordChangeRequired: result.PasswordChangeRequired,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
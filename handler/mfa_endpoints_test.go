@@ -0,0 +1,379 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_EnrollTotp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+	request := entity.EnrollTOTPRequest{
+		ProfileId: "profile-id-1",
+		Password:  "12345A!",
+	}
+
+	tests := []struct {
+		name       string
+		claims     interface{}
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name:   "success enroll",
+			claims: claims,
+			want: generated.EnrollTotpResponse{
+				Secret:     "JBSWY3DPEHPK3PXP",
+				OtpauthUri: "otpauth://totp/SawitPro:%2B62812345678?secret=JBSWY3DPEHPK3PXP",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().EnrollTOTP(gomock.Any(), request).Return(entity.EnrollTOTPResponse{
+					Secret: "JBSWY3DPEHPK3PXP",
+					KeyURI: "otpauth://totp/SawitPro:%2B62812345678?secret=JBSWY3DPEHPK3PXP",
+				}, nil)
+			},
+		},
+		{
+			name:   "error wrong password",
+			claims: claims,
+			want: generated.ErrorResponse{
+				Message: "error current password not match",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().EnrollTOTP(gomock.Any(), request).Return(entity.EnrollTOTPResponse{}, errors.New("error current password not match"))
+			},
+		},
+		{
+			name:   "error no token claims",
+			claims: nil,
+			want: generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.claims)
+				return s.EnrollTotp(ctx, generated.EnrollTotpParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/mfa/totp/enroll", wrapper)
+
+			requestBody, _ := json.Marshal(generated.EnrollTotpRequest{
+				Password: "12345A!",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/mfa/totp/enroll", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ConfirmTotp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+	request := entity.ConfirmTOTPRequest{
+		ProfileId: "profile-id-1",
+		Code:      "123456",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success confirm",
+			want: generated.ConfirmTotpResponse{
+				Message: "Success enable two-factor authentication",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmTOTP(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name: "error wrong code",
+			want: generated.ErrorResponse{
+				Message: "error invalid two-factor code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmTOTP(gomock.Any(), request).Return(errors.New("error invalid two-factor code"))
+			},
+		},
+		{
+			name: "error nothing to confirm",
+			want: generated.ErrorResponse{
+				Message: "error no two-factor enrollment to confirm",
+			},
+			statusCode: http.StatusConflict,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmTOTP(gomock.Any(), request).Return(errors.New("error no two-factor enrollment to confirm"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.ConfirmTotp(ctx, generated.ConfirmTotpParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/mfa/totp/confirm", wrapper)
+
+			requestBody, _ := json.Marshal(generated.ConfirmTotpRequest{
+				Code: "123456",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/mfa/totp/confirm", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_DisableTotp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+	request := entity.DisableTOTPRequest{
+		ProfileId: "profile-id-1",
+		Password:  "12345A!",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success disable",
+			want: generated.DisableTotpResponse{
+				Message: "Success disable two-factor authentication",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().DisableTOTP(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name: "error not enabled",
+			want: generated.ErrorResponse{
+				Message: "error two-factor authentication is not enabled",
+			},
+			statusCode: http.StatusConflict,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().DisableTOTP(gomock.Any(), request).Return(errors.New("error two-factor authentication is not enabled"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.DisableTotp(ctx, generated.DisableTotpParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/mfa/totp/disable", wrapper)
+
+			requestBody, _ := json.Marshal(generated.DisableTotpRequest{
+				Password: "12345A!",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/mfa/totp/disable", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_VerifyLoginMfa(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	request := entity.VerifyLoginMFARequest{
+		MFAToken:   "mfa-token1",
+		Code:       "123456",
+		DeviceName: "Pixel 8",
+		IpAddress:  "192.0.2.1",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success verify",
+			want: generated.LoginResponse{
+				Token:        "token1",
+				RefreshToken: optionalString("refresh-token1"),
+				ExpiresIn:    900,
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().VerifyLoginMFA(gomock.Any(), request).Return(entity.LoginResponse{
+					Token:        "token1",
+					RefreshToken: "refresh-token1",
+					ExpiresIn:    900,
+				}, nil)
+			},
+		},
+		{
+			name: "error invalid challenge",
+			want: generated.ErrorResponse{
+				Message: "error invalid or expired two-factor challenge",
+			},
+			statusCode: http.StatusUnauthorized,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().VerifyLoginMFA(gomock.Any(), request).Return(entity.LoginResponse{}, errors.New("error invalid or expired two-factor challenge"))
+			},
+		},
+		{
+			name: "error request not valid",
+			want: generated.ErrorResponse{
+				Message: "error code not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(errors.New("error code not valid"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			e.POST("/login/mfa", s.VerifyLoginMfa)
+
+			requestBody, _ := json.Marshal(generated.VerifyLoginMfaRequest{
+				MfaToken:   "mfa-token1",
+				Code:       "123456",
+				DeviceName: optionalString("Pixel 8"),
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/login/mfa", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) OauthDeviceAuthorization(ctx echo.Context) error {
+	// the generated body type has no form tags, read the fields by hand
+	authorizationReq := entity.OAuthDeviceAuthorizationRequest{
+		ClientId:     ctx.FormValue("client_id"),
+		ClientSecret: ctx.FormValue("client_secret"),
+		Scope:        ctx.FormValue("scope"),
+	}
+
+	// confidential clients may authenticate with basic auth instead
+	if clientId, clientSecret, ok := ctx.Request().BasicAuth(); ok {
+		authorizationReq.ClientId = clientId
+		authorizationReq.ClientSecret = clientSecret
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	err := s.validate(authorizationReq)
+	if err != nil {
+		return s.sendOAuthErrorResponse(ctx, error_list.ErrInvalidOAuthRequest)
+	}
+
+	result, err := s.oauthService.AuthorizeOAuthDevice(ctx.Request().Context(), authorizationReq)
+	if err != nil {
+		return s.sendOAuthErrorResponse(ctx, err)
+	}
+
+	resp := generated.OAuthDeviceAuthorizationResponse{
+		DeviceCode:              result.DeviceCode,
+		UserCode:                result.UserCode,
+		VerificationUri:         result.VerificationURI,
+		VerificationUriComplete: result.VerificationURIComplete,
+		ExpiresIn:               result.ExpiresIn,
+		Interval:                result.Interval,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetOAuthDevice(ctx echo.Context, params generated.GetOAuthDeviceParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	deviceReq := entity.OAuthDeviceRequest{
+		ProfileId: claims.ProfileId,
+		UserCode:  params.UserCode,
+	}
+
+	err := s.validate(deviceReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.oauthService.GetOAuthDevice(ctx.Request().Context(), deviceReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.OAuthDeviceResponse{
+		ClientId:   result.ClientId,
+		ClientName: result.ClientName,
+		Scopes:     result.Scopes,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ConsentOAuthDevice(ctx echo.Context, params generated.ConsentOAuthDeviceParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.OAuthDeviceConsentRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	consentReq := entity.OAuthDeviceConsentRequest{
+		OAuthDeviceRequest: entity.OAuthDeviceRequest{
+			ProfileId: claims.ProfileId,
+			UserCode:  req.UserCode,
+		},
+		Approved: req.Approved,
+	}
+
+	err = s.validate(consentReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.oauthService.ConsentOAuthDevice(ctx.Request().Context(), consentReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	message := "Success deny device"
+	if req.Approved {
+		message = "Success approve device"
+	}
+
+	resp := generated.OAuthDeviceConsentResponse{
+		Message: message,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,406 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_OauthDeviceAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockRateLimitService := mocks.NewMockRateLimitServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	authorizationReq := entity.OAuthDeviceAuthorizationRequest{
+		ClientId: "client-id-1",
+		Scope:    "openid profile",
+	}
+
+	form := url.Values{
+		"client_id": {"client-id-1"},
+		"scope":     {"openid profile"},
+	}
+
+	errorDescription := func(message string) *string {
+		return &message
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success authorize device",
+			want: generated.OAuthDeviceAuthorizationResponse{
+				DeviceCode:              "device-code-1",
+				UserCode:                "BCDF-GHJK",
+				VerificationUri:         "https://sawitpro.example/device",
+				VerificationUriComplete: "https://sawitpro.example/device?user_code=BCDF-GHJK",
+				ExpiresIn:               600,
+				Interval:                5,
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(authorizationReq).Return(nil)
+				mockOAuthService.EXPECT().AuthorizeOAuthDevice(gomock.Any(), authorizationReq).Return(entity.OAuthDeviceAuthorizationResponse{
+					DeviceCode:              "device-code-1",
+					UserCode:                "BCDF-GHJK",
+					VerificationURI:         "https://sawitpro.example/device",
+					VerificationURIComplete: "https://sawitpro.example/device?user_code=BCDF-GHJK",
+					ExpiresIn:               600,
+					Interval:                5,
+				}, nil)
+			},
+		},
+		{
+			name: "error client not registered for devices",
+			want: generated.OAuthErrorResponse{
+				Error:            "unauthorized_client",
+				ErrorDescription: errorDescription("error grant type is not allowed for the client"),
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(authorizationReq).Return(nil)
+				mockOAuthService.EXPECT().AuthorizeOAuthDevice(gomock.Any(), authorizationReq).Return(
+					entity.OAuthDeviceAuthorizationResponse{}, errors.New("error grant type is not allowed for the client"),
+				)
+			},
+		},
+		{
+			name: "error invalid request",
+			want: generated.OAuthErrorResponse{
+				Error:            "invalid_request",
+				ErrorDescription: errorDescription("error invalid oauth request"),
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(authorizationReq).Return(errors.New("client id not valid"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).Return(entity.TakeRateLimitTokenResponse{
+				Allowed: true,
+			}, nil)
+
+			s := &Server{
+				oauthService:     mockOAuthService,
+				rateLimitService: mockRateLimitService,
+				validatorHelper:  mockValidatorHelper,
+			}
+
+			// through the request validator, which has to accept a form body
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/device_authorization", strings.NewReader(form.Encode()))
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_OauthToken_deviceCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockRateLimitService := mocks.NewMockRateLimitServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	tokenReq := entity.OAuthTokenRequest{
+		GrantType:  "urn:ietf:params:oauth:grant-type:device_code",
+		DeviceCode: "device-code-1",
+		ClientId:   "client-id-1",
+	}
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {"device-code-1"},
+		"client_id":   {"client-id-1"},
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantError  string
+		statusCode int
+	}{
+		{
+			name:       "authorization pending",
+			err:        errors.New("error the user has not answered yet"),
+			wantError:  "authorization_pending",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "slow down",
+			err:        errors.New("error polling too often"),
+			wantError:  "slow_down",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "access denied",
+			err:        errors.New("error the user denied the authorization"),
+			wantError:  "access_denied",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "expired token",
+			err:        errors.New("error device code has expired"),
+			wantError:  "expired_token",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).Return(entity.TakeRateLimitTokenResponse{
+				Allowed: true,
+			}, nil)
+			mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(nil)
+			mockOAuthService.EXPECT().ExchangeOAuthToken(gomock.Any(), tokenReq).Return(entity.OAuthTokenResponse{}, tt.err)
+
+			s := &Server{
+				oauthService:     mockOAuthService,
+				rateLimitService: mockRateLimitService,
+				validatorHelper:  mockValidatorHelper,
+			}
+
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var resp generated.OAuthErrorResponse
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Equal(t, tt.wantError, resp.Error)
+		})
+	}
+}
+
+func TestServer_GetOAuthDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	deviceReq := entity.OAuthDeviceRequest{
+		ProfileId: "profile-id-1",
+		UserCode:  "BCDF-GHJK",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success get device",
+			want: generated.OAuthDeviceResponse{
+				ClientId:   "client-id-1",
+				ClientName: "Weighing Kiosk",
+				Scopes:     []string{"openid", "profile"},
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(deviceReq).Return(nil)
+				mockOAuthService.EXPECT().GetOAuthDevice(gomock.Any(), deviceReq).Return(entity.OAuthDeviceResponse{
+					ClientId:   "client-id-1",
+					ClientName: "Weighing Kiosk",
+					Scopes:     []string{"openid", "profile"},
+				}, nil)
+			},
+		},
+		{
+			name: "error invalid user code",
+			want: generated.ErrorResponse{
+				Message: "error invalid or expired user code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(deviceReq).Return(nil)
+				mockOAuthService.EXPECT().GetOAuthDevice(gomock.Any(), deviceReq).Return(
+					entity.OAuthDeviceResponse{}, errors.New("error invalid or expired user code"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.GetOAuthDevice(ctx, generated.GetOAuthDeviceParams{
+					UserCode: "BCDF-GHJK",
+				})
+			}
+
+			e := echo.New()
+
+			e.GET("/oauth/device", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/device?user_code=BCDF-GHJK", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ConsentOAuthDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	consentReq := func(approved bool) entity.OAuthDeviceConsentRequest {
+		return entity.OAuthDeviceConsentRequest{
+			OAuthDeviceRequest: entity.OAuthDeviceRequest{
+				ProfileId: "profile-id-1",
+				UserCode:  "BCDF-GHJK",
+			},
+			Approved: approved,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success approve device",
+			body: `{"user_code":"BCDF-GHJK","approved":true}`,
+			want: generated.OAuthDeviceConsentResponse{
+				Message: "Success approve device",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(consentReq(true)).Return(nil)
+				mockOAuthService.EXPECT().ConsentOAuthDevice(gomock.Any(), consentReq(true)).Return(nil)
+			},
+		},
+		{
+			name: "success deny device",
+			body: `{"user_code":"BCDF-GHJK","approved":false}`,
+			want: generated.OAuthDeviceConsentResponse{
+				Message: "Success deny device",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(consentReq(false)).Return(nil)
+				mockOAuthService.EXPECT().ConsentOAuthDevice(gomock.Any(), consentReq(false)).Return(nil)
+			},
+		},
+		{
+			name: "error user code already decided",
+			body: `{"user_code":"BCDF-GHJK","approved":true}`,
+			want: generated.ErrorResponse{
+				Message: "error invalid or expired user code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(consentReq(true)).Return(nil)
+				mockOAuthService.EXPECT().ConsentOAuthDevice(gomock.Any(), consentReq(true)).Return(errors.New("error invalid or expired user code"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.ConsentOAuthDevice(ctx, generated.ConsentOAuthDeviceParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/oauth/device", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/device", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
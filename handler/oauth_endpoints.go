@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+type oauthError struct {
+	code       string
+	statusCode int
+}
+
+// oauthErrorMap turns the errors of the token endpoint into the error codes
+// of RFC 6749, and of RFC 8628 for devices polling, anything else is a
+// server_error.
+var oauthErrorMap = map[string]oauthError{
+	error_list.ErrInvalidOAuthClient.Error():      {code: "invalid_client", statusCode: http.StatusUnauthorized},
+	error_list.ErrInvalidOAuthGrant.Error():       {code: "invalid_grant", statusCode: http.StatusBadRequest},
+	error_list.ErrRefreshTokenReused.Error():      {code: "invalid_grant", statusCode: http.StatusBadRequest},
+	error_list.ErrUnsupportedGrantType.Error():    {code: "unsupported_grant_type", statusCode: http.StatusBadRequest},
+	error_list.ErrUnauthorizedOAuthClient.Error(): {code: "unauthorized_client", statusCode: http.StatusBadRequest},
+	error_list.ErrInvalidOAuthScope.Error():       {code: "invalid_scope", statusCode: http.StatusBadRequest},
+	error_list.ErrInvalidOAuthRequest.Error():     {code: "invalid_request", statusCode: http.StatusBadRequest},
+	error_list.ErrAuthorizationPending.Error():    {code: "authorization_pending", statusCode: http.StatusBadRequest},
+	error_list.ErrSlowDown.Error():                {code: "slow_down", statusCode: http.StatusBadRequest},
+	error_list.ErrOAuthAccessDenied.Error():       {code: "access_denied", statusCode: http.StatusBadRequest},
+	error_list.ErrExpiredDeviceCode.Error():       {code: "expired_token", statusCode: http.StatusBadRequest},
+}
+
+func (s *Server) GetOAuthAuthorization(ctx echo.Context, params generated.GetOAuthAuthorizationParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	authorizationReq := entity.OAuthAuthorizationRequest{
+		ProfileId:           claims.ProfileId,
+		ResponseType:        string(params.ResponseType),
+		ClientId:            params.ClientId,
+		RedirectURI:         params.RedirectUri,
+		CodeChallenge:       params.CodeChallenge,
+		CodeChallengeMethod: string(params.CodeChallengeMethod),
+	}
+	if params.Scope != nil {
+		authorizationReq.Scope = *params.Scope
+	}
+	if params.State != nil {
+		authorizationReq.State = *params.State
+	}
+	if params.Nonce != nil {
+		authorizationReq.Nonce = *params.Nonce
+	}
+
+	err := s.validate(authorizationReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.oauthService.GetOAuthAuthorization(ctx.Request().Context(), authorizationReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.OAuthAuthorizationResponse{
+		ClientId:    result.ClientId,
+		ClientName:  result.ClientName,
+		RedirectUri: result.RedirectURI,
+		Scopes:      result.Scopes,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ConsentOAuthAuthorization(ctx echo.Context, params generated.ConsentOAuthAuthorizationParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.OAuthConsentRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	consentReq := entity.OAuthConsentRequest{
+		OAuthAuthorizationRequest: entity.OAuthAuthorizationRequest{
+			ProfileId:           claims.ProfileId,
+			SessionId:           claims.SessionId,
+			ResponseType:        string(req.ResponseType),
+			ClientId:            req.ClientId,
+			RedirectURI:         req.RedirectUri,
+			CodeChallenge:       req.CodeChallenge,
+			CodeChallengeMethod: string(req.CodeChallengeMethod),
+		},
+		Approved: req.Approved,
+	}
+	if req.Scope != nil {
+		consentReq.Scope = *req.Scope
+	}
+	if req.State != nil {
+		consentReq.State = *req.State
+	}
+	if req.Nonce != nil {
+		consentReq.Nonce = *req.Nonce
+	}
+
+	err = s.validate(consentReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.oauthService.ConsentOAuthAuthorization(ctx.Request().Context(), consentReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.OAuthConsentResponse{
+		RedirectTo: result.RedirectTo,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) OauthToken(ctx echo.Context) error {
+	// the generated body type has no form tags, read the fields by hand
+	tokenReq := entity.OAuthTokenRequest{
+		GrantType:    ctx.FormValue("grant_type"),
+		Code:         ctx.FormValue("code"),
+		RedirectURI:  ctx.FormValue("redirect_uri"),
+		CodeVerifier: ctx.FormValue("code_verifier"),
+		RefreshToken: ctx.FormValue("refresh_token"),
+		DeviceCode:   ctx.FormValue("device_code"),
+		Scope:        ctx.FormValue("scope"),
+		ClientId:     ctx.FormValue("client_id"),
+		ClientSecret: ctx.FormValue("client_secret"),
+	}
+
+	// confidential clients may authenticate with basic auth instead
+	if clientId, clientSecret, ok := ctx.Request().BasicAuth(); ok {
+		tokenReq.ClientId = clientId
+		tokenReq.ClientSecret = clientSecret
+	}
+
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	err := s.validate(tokenReq)
+	if err != nil {
+		return s.sendOAuthErrorResponse(ctx, error_list.ErrInvalidOAuthRequest)
+	}
+
+	result, err := s.oauthService.ExchangeOAuthToken(ctx.Request().Context(), tokenReq)
+	if err != nil {
+		return s.sendOAuthErrorResponse(ctx, err)
+	}
+
+	resp := generated.OAuthTokenResponse{
+		AccessToken:  result.AccessToken,
+		TokenType:    result.TokenType,
+		ExpiresIn:    result.ExpiresIn,
+		RefreshToken: optionalString(result.RefreshToken),
+		Scope:        result.Scope,
+		IdToken:      optionalString(result.IDToken),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) IntrospectToken(ctx echo.Context, params generated.IntrospectTokenParams) error {
+	// the generated body type has no form tags, read the fields by hand
+	introspectReq := entity.IntrospectTokenRequest{
+		Token:         ctx.FormValue("token"),
+		TokenTypeHint: ctx.FormValue("token_type_hint"),
+	}
+
+	err := s.validate(introspectReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.oauthService.IntrospectToken(ctx.Request().Context(), introspectReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	// the answer is about the token in the body, only the caller may reuse it
+	cacheControl := "no-store"
+	if result.CacheFor > 0 {
+		cacheControl = fmt.Sprintf("private, max-age=%d", int64(result.CacheFor.Seconds()))
+	}
+	ctx.Response().Header().Set(echo.HeaderCacheControl, cacheControl)
+
+	resp := generated.IntrospectTokenResponse{
+		Active: result.Active,
+	}
+	if result.Active {
+		resp.Sub = optionalString(result.Subject)
+		resp.ClientId = optionalString(result.ClientId)
+		resp.Scope = optionalString(strings.Join(result.Scopes, " "))
+		resp.TokenType = optionalString(result.TokenType)
+		if result.ExpiresAt != nil {
+			exp := result.ExpiresAt.Unix()
+			resp.Exp = &exp
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ListOAuthGrants(ctx echo.Context, params generated.ListOAuthGrantsParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	result, err := s.oauthService.ListOAuthGrants(ctx.Request().Context(), entity.ListOAuthGrantsRequest{
+		ProfileId: claims.ProfileId,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ListOAuthGrantsResponse{
+		Grants: make([]generated.OAuthGrant, 0, len(result.Grants)),
+	}
+	for _, grant := range result.Grants {
+		resp.Grants = append(resp.Grants, generated.OAuthGrant{
+			ClientId:   grant.ClientId,
+			ClientName: grant.ClientName,
+			Scopes:     strings.Fields(grant.Scopes),
+			CreatedAt:  grant.CreatedAt,
+			LastUsedAt: grant.LastUsedAt,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) RevokeOAuthGrant(ctx echo.Context, clientId string, params generated.RevokeOAuthGrantParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	revokeReq := entity.RevokeOAuthGrantRequest{
+		ProfileId: claims.ProfileId,
+		ClientId:  clientId,
+	}
+	err := s.validate(revokeReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.oauthService.RevokeOAuthGrant(ctx.Request().Context(), revokeReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RevokeOAuthGrantResponse{
+		Message: "Success revoke oauth grant",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// sendOAuthErrorResponse answers the token endpoint in the shape OAuth
+// clients expect rather than with an ErrorResponse.
+func (srv *Server) sendOAuthErrorResponse(ctx echo.Context, err error) error {
+	var errorMessage = err.Error()
+
+	oauthErr, exists := oauthErrorMap[errorMessage]
+	if !exists {
+		oauthErr = oauthError{code: "server_error", statusCode: http.StatusInternalServerError}
+	}
+
+	resp := generated.OAuthErrorResponse{
+		Error:            oauthErr.code,
+		ErrorDescription: &errorMessage,
+	}
+
+	return ctx.JSON(oauthErr.statusCode, resp)
+}
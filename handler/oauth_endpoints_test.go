@@ -0,0 +1,589 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ConsentOAuthAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	consentReq := entity.OAuthConsentRequest{
+		OAuthAuthorizationRequest: entity.OAuthAuthorizationRequest{
+			ProfileId:           "profile-id-1",
+			SessionId:           "session-id-1",
+			ResponseType:        "code",
+			ClientId:            "client-id-1",
+			RedirectURI:         "https://partner.example/callback",
+			State:               "state-1",
+			Nonce:               "nonce-1",
+			CodeChallenge:       "challenge-1",
+			CodeChallengeMethod: "S256",
+		},
+		Approved: true,
+	}
+
+	body := `{"response_type":"code","client_id":"client-id-1","redirect_uri":"https://partner.example/callback","state":"state-1","nonce":"nonce-1","code_challenge":"challenge-1","code_challenge_method":"S256","approved":true}`
+
+	tests := []struct {
+		name       string
+		want       generated.OAuthConsentResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success consent",
+			want: generated.OAuthConsentResponse{
+				RedirectTo: "https://partner.example/callback?code=code-1&state=state-1",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(consentReq).Return(nil)
+				mockOAuthService.EXPECT().ConsentOAuthAuthorization(gomock.Any(), consentReq).Return(entity.OAuthConsentResponse{
+					RedirectTo: "https://partner.example/callback?code=code-1&state=state-1",
+				}, nil)
+			},
+		},
+		{
+			name:    "error redirect uri not registered",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error redirect uri is not registered for the client",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(consentReq).Return(nil)
+				mockOAuthService.EXPECT().ConsentOAuthAuthorization(gomock.Any(), consentReq).Return(
+					entity.OAuthConsentResponse{}, errors.New("error redirect uri is not registered for the client"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.ConsentOAuthAuthorization(ctx, generated.ConsentOAuthAuthorizationParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/oauth/authorize", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_OauthToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockRateLimitService := mocks.NewMockRateLimitServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	tokenReq := entity.OAuthTokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "code-1",
+		RedirectURI:  "https://partner.example/callback",
+		CodeVerifier: "verifier-1",
+		ClientId:     "client-id-1",
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"code-1"},
+		"redirect_uri":  {"https://partner.example/callback"},
+		"code_verifier": {"verifier-1"},
+		"client_id":     {"client-id-1"},
+	}
+
+	errorDescription := func(message string) *string {
+		return &message
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success exchange authorization code",
+			want: generated.OAuthTokenResponse{
+				AccessToken:  "token-1",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: optionalString("refresh-token-1"),
+				Scope:        "openid profile:read",
+				IdToken:      optionalString("id-token-1"),
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(nil)
+				mockOAuthService.EXPECT().ExchangeOAuthToken(gomock.Any(), tokenReq).Return(entity.OAuthTokenResponse{
+					AccessToken:  "token-1",
+					TokenType:    "Bearer",
+					ExpiresIn:    900,
+					RefreshToken: "refresh-token-1",
+					Scope:        "openid profile:read",
+					IDToken:      "id-token-1",
+				}, nil)
+			},
+		},
+		{
+			name: "error invalid grant",
+			want: generated.OAuthErrorResponse{
+				Error:            "invalid_grant",
+				ErrorDescription: errorDescription("error invalid or expired authorization grant"),
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(nil)
+				mockOAuthService.EXPECT().ExchangeOAuthToken(gomock.Any(), tokenReq).Return(
+					entity.OAuthTokenResponse{}, errors.New("error invalid or expired authorization grant"),
+				)
+			},
+		},
+		{
+			name: "error invalid client",
+			want: generated.OAuthErrorResponse{
+				Error:            "invalid_client",
+				ErrorDescription: errorDescription("error invalid oauth client"),
+			},
+			statusCode: http.StatusUnauthorized,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(nil)
+				mockOAuthService.EXPECT().ExchangeOAuthToken(gomock.Any(), tokenReq).Return(
+					entity.OAuthTokenResponse{}, errors.New("error invalid oauth client"),
+				)
+			},
+		},
+		{
+			name: "error invalid request",
+			want: generated.OAuthErrorResponse{
+				Error:            "invalid_request",
+				ErrorDescription: errorDescription("error invalid oauth request"),
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(errors.New("client id not valid"))
+			},
+		},
+		{
+			name: "error when issuing token",
+			want: generated.OAuthErrorResponse{
+				Error:            "server_error",
+				ErrorDescription: errorDescription("error when issuing oauth token"),
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(tokenReq).Return(nil)
+				mockOAuthService.EXPECT().ExchangeOAuthToken(gomock.Any(), tokenReq).Return(
+					entity.OAuthTokenResponse{}, errors.New("error when issuing oauth token"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).Return(entity.TakeRateLimitTokenResponse{
+				Allowed: true,
+			}, nil)
+
+			s := &Server{
+				oauthService:     mockOAuthService,
+				rateLimitService: mockRateLimitService,
+				validatorHelper:  mockValidatorHelper,
+			}
+
+			// through the request validator, which has to accept a form body
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_IntrospectToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	expiresAt := time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC)
+	exp := expiresAt.Unix()
+
+	introspectReq := entity.IntrospectTokenRequest{
+		Token:         "token-1",
+		TokenTypeHint: "access_token",
+	}
+
+	form := url.Values{
+		"token":           {"token-1"},
+		"token_type_hint": {"access_token"},
+	}
+
+	optional := func(value string) *string {
+		return &value
+	}
+
+	tests := []struct {
+		name         string
+		want         interface{}
+		statusCode   int
+		cacheControl string
+		mock         func()
+	}{
+		{
+			name: "success active token",
+			want: generated.IntrospectTokenResponse{
+				Active:    true,
+				Sub:       optional("profile-id-1"),
+				ClientId:  optional("client-id-1"),
+				Scope:     optional("profile:read profile:write"),
+				TokenType: optional("Bearer"),
+				Exp:       &exp,
+			},
+			statusCode:   http.StatusOK,
+			cacheControl: "private, max-age=60",
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(introspectReq).Return(nil)
+				mockOAuthService.EXPECT().IntrospectToken(gomock.Any(), introspectReq).Return(entity.IntrospectTokenResponse{
+					Active:    true,
+					Subject:   "profile-id-1",
+					ClientId:  "client-id-1",
+					Scopes:    []string{"profile:read", "profile:write"},
+					TokenType: "Bearer",
+					ExpiresAt: &expiresAt,
+					CacheFor:  time.Minute,
+				}, nil)
+			},
+		},
+		{
+			name: "success inactive token",
+			want: generated.IntrospectTokenResponse{
+				Active: false,
+			},
+			statusCode:   http.StatusOK,
+			cacheControl: "private, max-age=60",
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(introspectReq).Return(nil)
+				mockOAuthService.EXPECT().IntrospectToken(gomock.Any(), introspectReq).Return(entity.IntrospectTokenResponse{
+					CacheFor: time.Minute,
+				}, nil)
+			},
+		},
+		{
+			name: "success inactive token not to be cached",
+			want: generated.IntrospectTokenResponse{
+				Active: false,
+			},
+			statusCode:   http.StatusOK,
+			cacheControl: "no-store",
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(introspectReq).Return(nil)
+				mockOAuthService.EXPECT().IntrospectToken(gomock.Any(), introspectReq).Return(entity.IntrospectTokenResponse{}, nil)
+			},
+		},
+		{
+			name: "error when introspect token",
+			want: generated.ErrorResponse{
+				Message: "error when introspecting token",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(introspectReq).Return(nil)
+				mockOAuthService.EXPECT().IntrospectToken(gomock.Any(), introspectReq).Return(
+					entity.IntrospectTokenResponse{}, errors.New("error when introspecting token"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				return s.IntrospectToken(ctx, generated.IntrospectTokenParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/oauth/introspect", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", strings.NewReader(form.Encode()))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, tt.cacheControl, rec.Header().Get(echo.HeaderCacheControl))
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ListOAuthGrants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	tests := []struct {
+		name       string
+		want       generated.ListOAuthGrantsResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success list oauth grants",
+			want: generated.ListOAuthGrantsResponse{
+				Grants: []generated.OAuthGrant{
+					{
+						ClientId:   "client-id-1",
+						ClientName: "Partner App",
+						Scopes:     []string{"profile:read", "profile:write"},
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockOAuthService.EXPECT().ListOAuthGrants(gomock.Any(), entity.ListOAuthGrantsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListOAuthGrantsResponse{
+					Grants: []entity.OAuthGrant{
+						{
+							Id:         "grant-id-1",
+							ProfileId:  "profile-id-1",
+							ClientId:   "client-id-1",
+							ClientName: "Partner App",
+							Scopes:     "profile:read profile:write",
+							CreatedAt:  createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:    "error when list oauth grants",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when listing oauth grants",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockOAuthService.EXPECT().ListOAuthGrants(gomock.Any(), entity.ListOAuthGrantsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListOAuthGrantsResponse{}, errors.New("error when listing oauth grants"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService: mockOAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.ListOAuthGrants(ctx, generated.ListOAuthGrantsParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/oauth/grants", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/grants", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_RevokeOAuthGrant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	revokeReq := entity.RevokeOAuthGrantRequest{
+		ProfileId: "profile-id-1",
+		ClientId:  "client-id-1",
+	}
+
+	tests := []struct {
+		name       string
+		want       generated.RevokeOAuthGrantResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success revoke oauth grant",
+			want: generated.RevokeOAuthGrantResponse{
+				Message: "Success revoke oauth grant",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockOAuthService.EXPECT().RevokeOAuthGrant(gomock.Any(), revokeReq).Return(nil)
+			},
+		},
+		{
+			name:    "error oauth grant not found",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error oauth grant not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockOAuthService.EXPECT().RevokeOAuthGrant(gomock.Any(), revokeReq).Return(errors.New("error oauth grant not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.RevokeOAuthGrant(ctx, ctx.Param("client_id"), generated.RevokeOAuthGrantParams{})
+			}
+
+			e := echo.New()
+
+			e.DELETE("/oauth/grants/:client_id", wrapper)
+
+			req := httptest.NewRequest(http.MethodDelete, "/oauth/grants/client-id-1", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) GetOpenIDConfiguration(ctx echo.Context) error {
+	result, err := s.oauthService.GetOpenIDConfiguration(ctx.Request().Context())
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.OpenIDConfiguration{
+		Issuer:                            result.Issuer,
+		AuthorizationEndpoint:             result.AuthorizationEndpoint,
+		TokenEndpoint:                     result.TokenEndpoint,
+		UserinfoEndpoint:                  result.UserInfoEndpoint,
+		JwksUri:                           result.JWKSURI,
+		EndSessionEndpoint:                result.EndSessionEndpoint,
+		DeviceAuthorizationEndpoint:       result.DeviceAuthorizationEndpoint,
+		IntrospectionEndpoint:             result.IntrospectionEndpoint,
+		ScopesSupported:                   result.ScopesSupported,
+		ResponseTypesSupported:            result.ResponseTypesSupported,
+		GrantTypesSupported:               result.GrantTypesSupported,
+		SubjectTypesSupported:             result.SubjectTypesSupported,
+		IdTokenSigningAlgValuesSupported:  result.IDTokenSigningAlgValuesSupported,
+		TokenEndpointAuthMethodsSupported: result.TokenEndpointAuthMethodsSupported,
+		CodeChallengeMethodsSupported:     result.CodeChallengeMethodsSupported,
+		ClaimsSupported:                   result.ClaimsSupported,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetUserInfo(ctx echo.Context, params generated.GetUserInfoParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	// a login sees all of its own claims, a client only what it was granted
+	scopes := claims.Scopes
+	if claims.ClientId == "" {
+		scopes = []string{constant.ScopeProfile, constant.ScopePhone}
+	}
+
+	result, err := s.oauthService.GetUserInfo(ctx.Request().Context(), entity.GetUserInfoRequest{
+		ProfileId: claims.ProfileId,
+		Scopes:    scopes,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.UserInfoResponse{
+		Sub:                 result.ProfileId,
+		Name:                result.Name,
+		PhoneNumber:         result.PhoneNumber,
+		PhoneNumberVerified: result.PhoneNumberVerified,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) OidcLogout(ctx echo.Context, params generated.OidcLogoutParams) error {
+	logoutReq := entity.OIDCLogoutRequest{
+		IDTokenHint: params.IdTokenHint,
+	}
+	if params.ClientId != nil {
+		logoutReq.ClientId = *params.ClientId
+	}
+	if params.PostLogoutRedirectUri != nil {
+		logoutReq.PostLogoutRedirectURI = *params.PostLogoutRedirectUri
+	}
+	if params.State != nil {
+		logoutReq.State = *params.State
+	}
+
+	err := s.validate(logoutReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.oauthService.EndOIDCSession(ctx.Request().Context(), logoutReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	if result.RedirectTo != "" {
+		return ctx.Redirect(http.StatusFound, result.RedirectTo)
+	}
+
+	resp := generated.OIDCLogoutResponse{
+		Message: "Success logout",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
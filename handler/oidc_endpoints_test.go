@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_GetUserInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+
+	name := "Budi"
+	phoneNumber := "+6281234567890"
+	phoneNumberVerified := true
+
+	tests := []struct {
+		name       string
+		claims     entity.TokenClaims
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "oauth client sees the claims of its scopes",
+			claims: entity.TokenClaims{
+				ProfileId: "profile-id-1",
+				SessionId: "grant-id-1",
+				ClientId:  "client-id-1",
+				Scopes:    []string{"openid", "profile"},
+			},
+			want: generated.UserInfoResponse{
+				Sub:  "profile-id-1",
+				Name: &name,
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockOAuthService.EXPECT().GetUserInfo(gomock.Any(), entity.GetUserInfoRequest{
+					ProfileId: "profile-id-1",
+					Scopes:    []string{"openid", "profile"},
+				}).Return(entity.UserInfo{
+					ProfileId: "profile-id-1",
+					Name:      &name,
+				}, nil)
+			},
+		},
+		{
+			name: "login sees all of its claims",
+			claims: entity.TokenClaims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+			},
+			want: generated.UserInfoResponse{
+				Sub:                 "profile-id-1",
+				Name:                &name,
+				PhoneNumber:         &phoneNumber,
+				PhoneNumberVerified: &phoneNumberVerified,
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockOAuthService.EXPECT().GetUserInfo(gomock.Any(), entity.GetUserInfoRequest{
+					ProfileId: "profile-id-1",
+					Scopes:    []string{"profile", "phone"},
+				}).Return(entity.UserInfo{
+					ProfileId:           "profile-id-1",
+					Name:                &name,
+					PhoneNumber:         &phoneNumber,
+					PhoneNumberVerified: &phoneNumberVerified,
+				}, nil)
+			},
+		},
+		{
+			name: "error profile not found",
+			claims: entity.TokenClaims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+			},
+			want: generated.ErrorResponse{
+				Message: "error profile not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				mockOAuthService.EXPECT().GetUserInfo(gomock.Any(), gomock.Any()).Return(
+					entity.UserInfo{}, errors.New("error profile not found"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService: mockOAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.claims)
+				return s.GetUserInfo(ctx, generated.GetUserInfoParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/userinfo", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_OidcLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	clientId := "client-id-1"
+	postLogoutRedirectURI := "https://partner.example/logged-out"
+	state := "state-1"
+
+	tests := []struct {
+		name         string
+		params       generated.OidcLogoutParams
+		statusCode   int
+		wantLocation string
+		wantBody     string
+		mock         func()
+	}{
+		{
+			name: "success logout with redirect",
+			params: generated.OidcLogoutParams{
+				IdTokenHint:           "id-token-1",
+				ClientId:              &clientId,
+				PostLogoutRedirectUri: &postLogoutRedirectURI,
+				State:                 &state,
+			},
+			statusCode:   http.StatusFound,
+			wantLocation: "https://partner.example/logged-out?state=state-1",
+			mock: func() {
+				logoutReq := entity.OIDCLogoutRequest{
+					IDTokenHint:           "id-token-1",
+					ClientId:              "client-id-1",
+					PostLogoutRedirectURI: "https://partner.example/logged-out",
+					State:                 "state-1",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(logoutReq).Return(nil)
+				mockOAuthService.EXPECT().EndOIDCSession(gomock.Any(), logoutReq).Return(entity.OIDCLogoutResponse{
+					RedirectTo: "https://partner.example/logged-out?state=state-1",
+				}, nil)
+			},
+		},
+		{
+			name: "success logout without redirect",
+			params: generated.OidcLogoutParams{
+				IdTokenHint: "id-token-1",
+			},
+			statusCode: http.StatusOK,
+			wantBody:   `{"message":"Success logout"}`,
+			mock: func() {
+				logoutReq := entity.OIDCLogoutRequest{
+					IDTokenHint: "id-token-1",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(logoutReq).Return(nil)
+				mockOAuthService.EXPECT().EndOIDCSession(gomock.Any(), logoutReq).Return(entity.OIDCLogoutResponse{}, nil)
+			},
+		},
+		{
+			name: "error invalid id token hint",
+			params: generated.OidcLogoutParams{
+				IdTokenHint: "id-token-1",
+			},
+			statusCode: http.StatusBadRequest,
+			wantBody:   `{"message":"error invalid id token hint"}`,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(gomock.Any()).Return(nil)
+				mockOAuthService.EXPECT().EndOIDCSession(gomock.Any(), gomock.Any()).Return(
+					entity.OIDCLogoutResponse{}, errors.New("error invalid id token hint"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				oauthService:    mockOAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				return s.OidcLogout(ctx, tt.params)
+			}
+
+			e := echo.New()
+
+			e.GET("/oauth/logout", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/oauth/logout", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, tt.wantLocation, rec.Header().Get(echo.HeaderLocation))
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_GetOpenIDConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthService := mocks.NewMockOAuthServiceInterface(ctrl)
+	mockOAuthService.EXPECT().GetOpenIDConfiguration(gomock.Any()).Return(entity.OpenIDConfiguration{
+		Issuer:                "https://id.sawitpro.example",
+		AuthorizationEndpoint: "https://id.sawitpro.example/oauth/authorize",
+		JWKSURI:               "https://id.sawitpro.example/.well-known/jwks.json",
+		ScopesSupported:       []string{"openid"},
+	}, nil)
+
+	s := &Server{
+		oauthService: mockOAuthService,
+	}
+
+	e := echo.New()
+	e.GET("/.well-known/openid-configuration", s.GetOpenIDConfiguration)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	var resp generated.OpenIDConfiguration
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "https://id.sawitpro.example", resp.Issuer)
+	assert.Equal(t, "https://id.sawitpro.example/.well-known/jwks.json", resp.JwksUri)
+	assert.Equal(t, []string{"openid"}, resp.ScopesSupported)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) RequestPasswordReset(ctx echo.Context) error {
+	var req generated.RequestPasswordResetRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	requestPasswordResetReq := entity.RequestPasswordResetRequest{
+		PhoneNumber: req.PhoneNumber,
+	}
+	err = s.validate(requestPasswordResetReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.RequestPasswordReset(ctx.Request().Context(), requestPasswordResetReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RequestPasswordResetResponse{
+		Message: "If the phone number is registered, a reset code has been sent to it",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ConfirmPasswordReset(ctx echo.Context) error {
+	var req generated.ConfirmPasswordResetRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	confirmPasswordResetReq := entity.ConfirmPasswordResetRequest{
+		PhoneNumber: req.PhoneNumber,
+		Code:        req.Code,
+		NewPassword: req.NewPassword,
+	}
+	err = s.validate(confirmPasswordResetReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.profileService.ConfirmPasswordReset(ctx.Request().Context(), confirmPasswordResetReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ConfirmPasswordResetResponse{
+		Message: "Success reset password",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	request := entity.RequestPasswordResetRequest{
+		PhoneNumber: "+62812345678",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success request password reset",
+			want: generated.RequestPasswordResetResponse{
+				Message: "If the phone number is registered, a reset code has been sent to it",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().RequestPasswordReset(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name: "error request not valid",
+			want: generated.ErrorResponse{
+				Message: "error phone number not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(errors.New("error phone number not valid"))
+			},
+		},
+		{
+			name: "error when request password reset",
+			want: generated.ErrorResponse{
+				Message: "error when requesting password reset",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().RequestPasswordReset(gomock.Any(), request).Return(errors.New("error when requesting password reset"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			e.POST("/password/reset/request", s.RequestPasswordReset)
+
+			requestBody, _ := json.Marshal(generated.RequestPasswordResetRequest{
+				PhoneNumber: "+62812345678",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset/request", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ConfirmPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	request := entity.ConfirmPasswordResetRequest{
+		PhoneNumber: "+62812345678",
+		Code:        "012345",
+		NewPassword: "67890B!",
+	}
+
+	tests := []struct {
+		name       string
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success confirm password reset",
+			want: generated.ConfirmPasswordResetResponse{
+				Message: "Success reset password",
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPasswordReset(gomock.Any(), request).Return(nil)
+			},
+		},
+		{
+			name: "error invalid code",
+			want: generated.ErrorResponse{
+				Message: "error invalid or expired code",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPasswordReset(gomock.Any(), request).Return(errors.New("error invalid or expired code"))
+			},
+		},
+		{
+			name: "error too many attempts",
+			want: generated.ErrorResponse{
+				Message: "error too many attempts for this code",
+			},
+			statusCode: http.StatusTooManyRequests,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(request).Return(nil)
+				mockProfileService.EXPECT().ConfirmPasswordReset(gomock.Any(), request).Return(errors.New("error too many attempts for this code"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService:  mockProfileService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			e := echo.New()
+
+			e.POST("/password/reset/confirm", s.ConfirmPasswordReset)
+
+			requestBody, _ := json.Marshal(generated.ConfirmPasswordResetRequest{
+				PhoneNumber: "+62812345678",
+				Code:        "012345",
+				NewPassword: "67890B!",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset/confirm", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
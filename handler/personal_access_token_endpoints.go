@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"net/http"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) CreatePersonalAccessToken(ctx echo.Context, params generated.CreatePersonalAccessTokenParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.CreatePersonalAccessTokenRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	createReq := entity.CreatePersonalAccessTokenRequest{
+		ProfileId: claims.ProfileId,
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	err = s.validate(createReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.authService.CreatePersonalAccessToken(ctx.Request().Context(), createReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.CreatePersonalAccessTokenResponse{
+		Token:               result.Token,
+		PersonalAccessToken: personalAccessTokenResponse(result.PersonalAccessToken),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ListPersonalAccessTokens(ctx echo.Context, params generated.ListPersonalAccessTokensParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	result, err := s.authService.ListPersonalAccessTokens(ctx.Request().Context(), entity.ListPersonalAccessTokensRequest{
+		ProfileId: claims.ProfileId,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ListPersonalAccessTokensResponse{
+		PersonalAccessTokens: make([]generated.PersonalAccessToken, 0, len(result.PersonalAccessTokens)),
+	}
+	for _, personalAccessToken := range result.PersonalAccessTokens {
+		resp.PersonalAccessTokens = append(resp.PersonalAccessTokens, personalAccessTokenResponse(personalAccessToken))
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) RevokePersonalAccessToken(ctx echo.Context, id string, params generated.RevokePersonalAccessTokenParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	revokeReq := entity.RevokePersonalAccessTokenRequest{
+		ProfileId:             claims.ProfileId,
+		PersonalAccessTokenId: id,
+	}
+	err := s.validate(revokeReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.authService.RevokePersonalAccessToken(ctx.Request().Context(), revokeReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RevokePersonalAccessTokenResponse{
+		Message: "Success revoke personal access token",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func personalAccessTokenResponse(personalAccessToken entity.PersonalAccessToken) generated.PersonalAccessToken {
+	return generated.PersonalAccessToken{
+		Id:         personalAccessToken.Id,
+		Name:       personalAccessToken.Name,
+		Scopes:     strings.Fields(personalAccessToken.Scopes),
+		ExpiresAt:  personalAccessToken.ExpiresAt,
+		LastUsedAt: personalAccessToken.LastUsedAt,
+		CreatedAt:  personalAccessToken.CreatedAt,
+	}
+}
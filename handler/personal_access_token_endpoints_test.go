@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_CreatePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	createReq := entity.CreatePersonalAccessTokenRequest{
+		ProfileId: "profile-id-1",
+		Name:      "backup script",
+		Scopes:    []string{"profile:read"},
+		ExpiresAt: &expiresAt,
+	}
+
+	type args struct {
+		body   string
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.CreatePersonalAccessTokenResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success create personal access token",
+			args: args{
+				body:   `{"name":"backup script","scopes":["profile:read"],"expires_at":"2025-01-01T00:00:00Z"}`,
+				claims: claims,
+			},
+			want: generated.CreatePersonalAccessTokenResponse{
+				Token: "pat_secret-1",
+				PersonalAccessToken: generated.PersonalAccessToken{
+					Id:        "token-id-1",
+					Name:      "backup script",
+					Scopes:    []string{"profile:read"},
+					ExpiresAt: &expiresAt,
+					CreatedAt: createdAt,
+				},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(createReq).Return(nil)
+				mockAuthService.EXPECT().CreatePersonalAccessToken(gomock.Any(), createReq).Return(entity.CreatePersonalAccessTokenResponse{
+					Token: "pat_secret-1",
+					PersonalAccessToken: entity.PersonalAccessToken{
+						Id:        "token-id-1",
+						ProfileId: "profile-id-1",
+						Name:      "backup script",
+						TokenHash: "token-hash-1",
+						Scopes:    "profile:read",
+						ExpiresAt: &expiresAt,
+						CreatedAt: createdAt,
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "error invalid scope",
+			args: args{
+				body:   `{"name":"backup script","scopes":["admin"]}`,
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error scope not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.CreatePersonalAccessTokenRequest{
+					ProfileId: "profile-id-1",
+					Name:      "backup script",
+					Scopes:    []string{"admin"},
+				}).Return(errors.New("error scope not valid"))
+			},
+		},
+		{
+			name: "error when create personal access token",
+			args: args{
+				body:   `{"name":"backup script","scopes":["profile:read"],"expires_at":"2025-01-01T00:00:00Z"}`,
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when creating personal access token",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(createReq).Return(nil)
+				mockAuthService.EXPECT().CreatePersonalAccessToken(gomock.Any(), createReq).Return(
+					entity.CreatePersonalAccessTokenResponse{}, errors.New("error when creating personal access token"),
+				)
+			},
+		},
+		{
+			name: "error missing token claims",
+			args: args{
+				body:   `{"name":"backup script","scopes":["profile:read"]}`,
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.CreatePersonalAccessToken(ctx, generated.CreatePersonalAccessTokenParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/profile/tokens", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/profile/tokens", strings.NewReader(tt.args.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ListPersonalAccessTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastUsedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	tests := []struct {
+		name       string
+		want       generated.ListPersonalAccessTokensResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success list personal access tokens",
+			want: generated.ListPersonalAccessTokensResponse{
+				PersonalAccessTokens: []generated.PersonalAccessToken{
+					{
+						Id:         "token-id-1",
+						Name:       "backup script",
+						Scopes:     []string{"profile:read", "profile:write"},
+						LastUsedAt: &lastUsedAt,
+						CreatedAt:  createdAt,
+					},
+				},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockAuthService.EXPECT().ListPersonalAccessTokens(gomock.Any(), entity.ListPersonalAccessTokensRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListPersonalAccessTokensResponse{
+					PersonalAccessTokens: []entity.PersonalAccessToken{
+						{
+							Id:         "token-id-1",
+							ProfileId:  "profile-id-1",
+							Name:       "backup script",
+							TokenHash:  "token-hash-1",
+							Scopes:     "profile:read profile:write",
+							LastUsedAt: &lastUsedAt,
+							CreatedAt:  createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:    "error when list personal access tokens",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when listing personal access tokens",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockAuthService.EXPECT().ListPersonalAccessTokens(gomock.Any(), entity.ListPersonalAccessTokensRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListPersonalAccessTokensResponse{}, errors.New("error when listing personal access tokens"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService: mockAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.ListPersonalAccessTokens(ctx, generated.ListPersonalAccessTokensParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/profile/tokens", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/profile/tokens", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_RevokePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	revokeReq := entity.RevokePersonalAccessTokenRequest{
+		ProfileId:             "profile-id-1",
+		PersonalAccessTokenId: "token-id-1",
+	}
+
+	tests := []struct {
+		name       string
+		want       generated.RevokePersonalAccessTokenResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success revoke personal access token",
+			want: generated.RevokePersonalAccessTokenResponse{
+				Message: "Success revoke personal access token",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockAuthService.EXPECT().RevokePersonalAccessToken(gomock.Any(), revokeReq).Return(nil)
+			},
+		},
+		{
+			name:    "error personal access token not found",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error personal access token not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockAuthService.EXPECT().RevokePersonalAccessToken(gomock.Any(), revokeReq).Return(errors.New("error personal access token not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", claims)
+				return s.RevokePersonalAccessToken(ctx, ctx.Param("id"), generated.RevokePersonalAccessTokenParams{})
+			}
+
+			e := echo.New()
+
+			e.DELETE("/profile/tokens/:id", wrapper)
+
+			req := httptest.NewRequest(http.MethodDelete, "/profile/tokens/token-id-1", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
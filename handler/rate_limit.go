@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// operationRateLimit is the x-rate-limit extension of one operation.
+type operationRateLimit struct {
+	operationId string
+	rules       []entity.RateLimitRule
+}
+
+var pathParameterPattern = regexp.MustCompile(`{([^}]+)}`)
+
+// operationRateLimits reads the x-rate-limit extension of every operation in
+// spec, keyed by the method and echo route of the operation.
+func operationRateLimits(spec *openapi3.T) (map[string]operationRateLimit, error) {
+	rateLimits := map[string]operationRateLimit{}
+
+	for path, pathItem := range spec.Paths.Map() {
+		route := pathParameterPattern.ReplaceAllString(path, ":$1")
+
+		for method, operation := range pathItem.Operations() {
+			extension, exists := operation.Extensions[constant.RateLimitExtension]
+			if !exists {
+				continue
+			}
+
+			// the extension arrives as decoded JSON, round trip it into rules
+			raw, err := json.Marshal(extension)
+			if err != nil {
+				return nil, err
+			}
+
+			var rules []entity.RateLimitRule
+			err = json.Unmarshal(raw, &rules)
+			if err != nil {
+				return nil, fmt.Errorf("%s of %s: %w", constant.RateLimitExtension, operation.OperationID, err)
+			}
+
+			for _, rule := range rules {
+				err = validateRateLimitRule(rule)
+				if err != nil {
+					return nil, fmt.Errorf("%s of %s: %w", constant.RateLimitExtension, operation.OperationID, err)
+				}
+			}
+
+			rateLimits[method+" "+route] = operationRateLimit{
+				operationId: operation.OperationID,
+				rules:       rules,
+			}
+		}
+	}
+
+	return rateLimits, nil
+}
+
+func validateRateLimitRule(rule entity.RateLimitRule) error {
+	if rule.Key != constant.RateLimitKeyIp && rule.Key != constant.RateLimitKeyPhoneNumber {
+		return fmt.Errorf("unknown key %q", rule.Key)
+	}
+
+	if rule.Capacity < 1 || rule.RefillPerMinute <= 0 {
+		return fmt.Errorf("capacity must be at least 1 and refill_per_minute positive")
+	}
+
+	return nil
+}
+
+// rateLimiter takes a token from every bucket the operation of the request
+// draws from and answers 429 when any of them is empty. A failing store lets
+// requests through rather than locking everyone out.
+func (srv *Server) rateLimiter(rateLimits map[string]operationRateLimit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			rateLimit, exists := rateLimits[ctx.Request().Method+" "+ctx.Path()]
+			if !exists {
+				return next(ctx)
+			}
+
+			var retryAfter time.Duration
+			for _, rule := range rateLimit.rules {
+				value := rateLimitKeyValue(ctx, rule.Key)
+				if value == "" {
+					continue
+				}
+
+				res, err := srv.rateLimitService.TakeRateLimitToken(ctx.Request().Context(), entity.TakeRateLimitTokenRequest{
+					BucketKey: rateLimit.operationId + ":" + rule.Key + ":" + value,
+					Rule:      rule,
+				})
+				if err != nil {
+					ctx.Logger().Error(err)
+					continue
+				}
+
+				if !res.Allowed && res.RetryAfter > retryAfter {
+					retryAfter = res.RetryAfter
+				}
+			}
+
+			if retryAfter > 0 {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(seconds))
+
+				return srv.sendErrorResponse(ctx, error_list.ErrTooManyRequests)
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func rateLimitKeyValue(ctx echo.Context, key string) string {
+	switch key {
+	case constant.RateLimitKeyIp:
+		return ctx.RealIP()
+	case constant.RateLimitKeyPhoneNumber:
+		return requestPhoneNumber(ctx.Request())
+	}
+
+	return ""
+}
+
+// requestPhoneNumber peeks at the phone_number of a JSON body and puts the
+// body back for the handler. A body it cannot read, or too large to be worth
+// reading before the request is even validated, yields no phone number.
+func requestPhoneNumber(req *http.Request) string {
+	if req.Body == nil {
+		return ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, constant.RateLimitMaxPeekedBodySize+1))
+	req.Body = peekedBody{
+		Reader: io.MultiReader(bytes.NewReader(body), req.Body),
+		Closer: req.Body,
+	}
+	if err != nil || len(body) > constant.RateLimitMaxPeekedBodySize {
+		return ""
+	}
+
+	var payload struct {
+		PhoneNumber string `json:"phone_number"`
+	}
+	_ = json.Unmarshal(body, &payload)
+
+	return payload.PhoneNumber
+}
+
+// peekedBody is a request body whose start was read already, served again in
+// front of the rest.
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
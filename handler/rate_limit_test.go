@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_operationRateLimits(t *testing.T) {
+	spec, err := generated.GetSwagger()
+	assert.NoError(t, err)
+
+	rateLimits, err := operationRateLimits(spec)
+	assert.NoError(t, err)
+
+	login, exists := rateLimits["POST /login"]
+	assert.True(t, exists)
+	assert.Equal(t, "Login", login.operationId)
+	assert.Equal(t, []entity.RateLimitRule{
+		{Key: "ip", Capacity: 20, RefillPerMinute: 10},
+		{Key: "phone_number", Capacity: 5, RefillPerMinute: 1},
+	}, login.rules)
+
+	// guesses at a reset code are limited like guesses at a password
+	_, exists = rateLimits["POST /password/reset/confirm"]
+	assert.True(t, exists)
+
+	_, exists = rateLimits["GET /profile"]
+	assert.False(t, exists)
+}
+
+func TestServer_rateLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRateLimitService := mocks.NewMockRateLimitServiceInterface(ctrl)
+
+	ipRule := entity.RateLimitRule{Key: "ip", Capacity: 20, RefillPerMinute: 10}
+	phoneNumberRule := entity.RateLimitRule{Key: "phone_number", Capacity: 5, RefillPerMinute: 1}
+	rateLimits := map[string]operationRateLimit{
+		"POST /login": {
+			operationId: "login",
+			rules:       []entity.RateLimitRule{ipRule, phoneNumberRule},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		wantStatusCode int
+		wantRetryAfter string
+		wantBody       string
+		mock           func()
+	}{
+		{
+			name:           "allowed request reaches the handler with its body",
+			path:           "/login",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"phone_number":"+62345","password":"12345A!"}`,
+			mock: func() {
+				mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), entity.TakeRateLimitTokenRequest{
+					BucketKey: "login:ip:192.0.2.1",
+					Rule:      ipRule,
+				}).Return(entity.TakeRateLimitTokenResponse{Allowed: true}, nil)
+				mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), entity.TakeRateLimitTokenRequest{
+					BucketKey: "login:phone_number:+62345",
+					Rule:      phoneNumberRule,
+				}).Return(entity.TakeRateLimitTokenResponse{Allowed: true}, nil)
+			},
+		},
+		{
+			name:           "throttled request gets 429 with retry after",
+			path:           "/login",
+			wantStatusCode: http.StatusTooManyRequests,
+			wantRetryAfter: "60",
+			wantBody:       `{"message":"error too many requests, try again later"}`,
+			mock: func() {
+				mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).
+					Return(entity.TakeRateLimitTokenResponse{Allowed: false, RetryAfter: 5500 * time.Millisecond}, nil)
+				mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).
+					Return(entity.TakeRateLimitTokenResponse{Allowed: false, RetryAfter: 59500 * time.Millisecond}, nil)
+			},
+		},
+		{
+			name:           "failing store lets the request through",
+			path:           "/login",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"phone_number":"+62345","password":"12345A!"}`,
+			mock: func() {
+				mockRateLimitService.EXPECT().TakeRateLimitToken(gomock.Any(), gomock.Any()).
+					Return(entity.TakeRateLimitTokenResponse{}, errors.New("error when checking rate limit")).Times(2)
+			},
+		},
+		{
+			name:           "operation without limits is not checked",
+			path:           "/register",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"phone_number":"+62345","password":"12345A!"}`,
+			mock:           func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				rateLimitService: mockRateLimitService,
+			}
+
+			echoBody := func(ctx echo.Context) error {
+				body, _ := io.ReadAll(ctx.Request().Body)
+				return ctx.String(http.StatusOK, string(body))
+			}
+
+			e := echo.New()
+			e.Use(s.rateLimiter(rateLimits))
+			e.POST("/login", echoBody)
+			e.POST("/register", echoBody)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"phone_number":"+62345","password":"12345A!"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			assert.Equal(t, tt.wantRetryAfter, rec.Header().Get(echo.HeaderRetryAfter))
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func Test_requestPhoneNumber(t *testing.T) {
+	large := `{"phone_number":"+62345","password":"` + strings.Repeat("A", constant.RateLimitMaxPeekedBodySize) + `"}`
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "phone number of the body",
+			body: `{"phone_number":"+62345","password":"12345A!"}`,
+			want: "+62345",
+		},
+		{
+			name: "no phone number in the body",
+			body: `{"password":"12345A!"}`,
+			want: "",
+		},
+		{
+			name: "body not json",
+			body: `phone_number=+62345`,
+			want: "",
+		},
+		{
+			name: "body too large to read",
+			body: large,
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.want, requestPhoneNumber(req))
+
+			// the handler still gets the whole body
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.body, string(body))
+			assert.NoError(t, req.Body.Close())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) GenerateRecoveryCodes(ctx echo.Context, params generated.GenerateRecoveryCodesParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	result, err := s.profileService.GenerateRecoveryCodes(ctx.Request().Context(), entity.GenerateRecoveryCodesRequest{
+		ProfileId: claims.ProfileId,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.GenerateRecoveryCodesResponse{
+		Codes: result.Codes,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_GenerateRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+	request := entity.GenerateRecoveryCodesRequest{
+		ProfileId: "profile-id-1",
+	}
+
+	tests := []struct {
+		name       string
+		claims     interface{}
+		want       interface{}
+		statusCode int
+		mock       func()
+	}{
+		{
+			name:   "success generate recovery codes",
+			claims: claims,
+			want: generated.GenerateRecoveryCodesResponse{
+				Codes: []string{"ABCD-EFGH-JKMN-PQRS", "TVWX-YZ01-2345-6789"},
+			},
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockProfileService.EXPECT().GenerateRecoveryCodes(gomock.Any(), request).Return(entity.GenerateRecoveryCodesResponse{
+					Codes: []string{"ABCD-EFGH-JKMN-PQRS", "TVWX-YZ01-2345-6789"},
+				}, nil)
+			},
+		},
+		{
+			name:   "error when generate recovery codes",
+			claims: claims,
+			want: generated.ErrorResponse{
+				Message: "error when generating recovery codes",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockProfileService.EXPECT().GenerateRecoveryCodes(gomock.Any(), request).Return(entity.GenerateRecoveryCodesResponse{}, errors.New("error when generating recovery codes"))
+			},
+		},
+		{
+			name:   "error no token claims",
+			claims: nil,
+			want: generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				profileService: mockProfileService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.claims)
+				return s.GenerateRecoveryCodes(ctx, generated.GenerateRecoveryCodesParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/profile/recovery-codes", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/profile/recovery-codes", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			expectBody, _ := json.Marshal(tt.want)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) ListRoles(ctx echo.Context, params generated.ListRolesParams) error {
+	result, err := s.roleService.ListRoles(ctx.Request().Context())
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ListRolesResponse{
+		Roles: toGeneratedRoles(result.Roles),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) ListProfileRoles(ctx echo.Context, id string, params generated.ListProfileRolesParams) error {
+	listReq := entity.ListProfileRolesRequest{
+		ProfileId: id,
+	}
+	err := s.validate(listReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.roleService.ListProfileRoles(ctx.Request().Context(), listReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ListProfileRolesResponse{
+		Roles: toGeneratedRoles(result.Roles),
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) AssignRole(ctx echo.Context, id string, name string, params generated.AssignRoleParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	assignReq := entity.AssignRoleRequest{
+		ProfileId:  id,
+		RoleName:   name,
+		AssignedBy: claims.ProfileId,
+	}
+	err := s.validate(assignReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.roleService.AssignRole(ctx.Request().Context(), assignReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.AssignRoleResponse{
+		Message: "Success assign role",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) UnassignRole(ctx echo.Context, id string, name string, params generated.UnassignRoleParams) error {
+	unassignReq := entity.UnassignRoleRequest{
+		ProfileId: id,
+		RoleName:  name,
+	}
+	err := s.validate(unassignReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.roleService.UnassignRole(ctx.Request().Context(), unassignReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.UnassignRoleResponse{
+		Message: "Success unassign role",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func toGeneratedRoles(roles []entity.Role) []generated.Role {
+	res := make([]generated.Role, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, generated.Role{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: strings.Fields(role.Permissions),
+		})
+	}
+
+	return res
+}
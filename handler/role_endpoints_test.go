@@ -0,0 +1,397 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ListRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleService := mocks.NewMockRoleServiceInterface(ctrl)
+
+	tests := []struct {
+		name       string
+		want       generated.ListRolesResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success list roles",
+			want: generated.ListRolesResponse{
+				Roles: []generated.Role{
+					{
+						Name:        "admin",
+						Description: "Manages the roles of profiles",
+						Permissions: []string{"roles:read", "roles:write"},
+					},
+				},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockRoleService.EXPECT().ListRoles(gomock.Any()).Return(entity.ListRolesResponse{
+					Roles: []entity.Role{
+						{
+							Id:          "role-id-1",
+							Name:        "admin",
+							Description: "Manages the roles of profiles",
+							Permissions: "roles:read roles:write",
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:    "error when list roles",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when listing roles",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockRoleService.EXPECT().ListRoles(gomock.Any()).Return(entity.ListRolesResponse{}, errors.New("error when listing roles"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				roleService: mockRoleService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				return s.ListRoles(ctx, generated.ListRolesParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/roles", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/roles", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_ListProfileRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleService := mocks.NewMockRoleServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	tests := []struct {
+		name       string
+		want       generated.ListProfileRolesResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success list profile roles without any",
+			want: generated.ListProfileRolesResponse{
+				Roles: []generated.Role{},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				listReq := entity.ListProfileRolesRequest{
+					ProfileId: "profile-id-2",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(listReq).Return(nil)
+				mockRoleService.EXPECT().ListProfileRoles(gomock.Any(), listReq).Return(entity.ListProfileRolesResponse{}, nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error profile not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				listReq := entity.ListProfileRolesRequest{
+					ProfileId: "profile-id-2",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(listReq).Return(nil)
+				mockRoleService.EXPECT().ListProfileRoles(gomock.Any(), listReq).Return(entity.ListProfileRolesResponse{}, errors.New("error profile not found"))
+			},
+		},
+		{
+			name:    "error invalid profile id",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error profile id not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.ListProfileRolesRequest{
+					ProfileId: "profile-id-2",
+				}).Return(errors.New("error profile id not valid"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				roleService:     mockRoleService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				return s.ListProfileRoles(ctx, ctx.Param("id"), generated.ListProfileRolesParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/profiles/:id/roles", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/profiles/profile-id-2/roles", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_AssignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleService := mocks.NewMockRoleServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	type args struct {
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.AssignRoleResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success assign role",
+			args: args{
+				claims: claims,
+			},
+			want: generated.AssignRoleResponse{
+				Message: "Success assign role",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				assignReq := entity.AssignRoleRequest{
+					ProfileId:  "profile-id-2",
+					RoleName:   "admin",
+					AssignedBy: "profile-id-1",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(assignReq).Return(nil)
+				mockRoleService.EXPECT().AssignRole(gomock.Any(), assignReq).Return(nil)
+			},
+		},
+		{
+			name: "error role not found",
+			args: args{
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error role not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				assignReq := entity.AssignRoleRequest{
+					ProfileId:  "profile-id-2",
+					RoleName:   "admin",
+					AssignedBy: "profile-id-1",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(assignReq).Return(nil)
+				mockRoleService.EXPECT().AssignRole(gomock.Any(), assignReq).Return(errors.New("error role not found"))
+			},
+		},
+		{
+			name: "error missing token claims",
+			args: args{
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				roleService:     mockRoleService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.AssignRole(ctx, ctx.Param("id"), ctx.Param("name"), generated.AssignRoleParams{})
+			}
+
+			e := echo.New()
+
+			e.PUT("/profiles/:id/roles/:name", wrapper)
+
+			req := httptest.NewRequest(http.MethodPut, "/profiles/profile-id-2/roles/admin", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_UnassignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleService := mocks.NewMockRoleServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	tests := []struct {
+		name       string
+		want       generated.UnassignRoleResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success unassign role",
+			want: generated.UnassignRoleResponse{
+				Message: "Success unassign role",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				unassignReq := entity.UnassignRoleRequest{
+					ProfileId: "profile-id-2",
+					RoleName:  "admin",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(unassignReq).Return(nil)
+				mockRoleService.EXPECT().UnassignRole(gomock.Any(), unassignReq).Return(nil)
+			},
+		},
+		{
+			name:    "error role not assigned",
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error profile does not hold the role",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				unassignReq := entity.UnassignRoleRequest{
+					ProfileId: "profile-id-2",
+					RoleName:  "admin",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(unassignReq).Return(nil)
+				mockRoleService.EXPECT().UnassignRole(gomock.Any(), unassignReq).Return(errors.New("error profile does not hold the role"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				roleService:     mockRoleService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				return s.UnassignRole(ctx, ctx.Param("id"), ctx.Param("name"), generated.UnassignRoleParams{})
+			}
+
+			e := echo.New()
+
+			e.DELETE("/profiles/:id/roles/:name", wrapper)
+
+			req := httptest.NewRequest(http.MethodDelete, "/profiles/profile-id-2/roles/admin", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
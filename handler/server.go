@@ -2,13 +2,15 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"sawitpro/constant"
+	"sawitpro/entity"
 	"sawitpro/error_list"
 	"sawitpro/generated"
 	"sawitpro/helper"
+	"sawitpro/pkg/authn"
 	"sawitpro/service"
-	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
@@ -16,26 +18,51 @@ import (
 )
 
 type Server struct {
-	profileService  service.ProfileServiceInterface
-	authHelper      helper.AuthHelperInterface
-	validatorHelper helper.ValidatorHelperInterface
+	profileService    service.ProfileServiceInterface
+	authService       service.AuthServiceInterface
+	signingKeyService service.SigningKeyServiceInterface
+	rateLimitService  service.RateLimitServiceInterface
+	oauthService      service.OAuthServiceInterface
+	roleService       service.RoleServiceInterface
+	authHelper        helper.AuthHelperInterface
+	validatorHelper   helper.ValidatorHelperInterface
 }
 
 type NewServerOptions struct {
-	ProfileService  service.ProfileServiceInterface
-	AuthHelper      helper.AuthHelperInterface
-	ValidatorHelper helper.ValidatorHelperInterface
+	ProfileService    service.ProfileServiceInterface
+	AuthService       service.AuthServiceInterface
+	SigningKeyService service.SigningKeyServiceInterface
+	RateLimitService  service.RateLimitServiceInterface
+	OAuthService      service.OAuthServiceInterface
+	RoleService       service.RoleServiceInterface
+	AuthHelper        helper.AuthHelperInterface
+	ValidatorHelper   helper.ValidatorHelperInterface
 }
 
 func NewServer(opts NewServerOptions) *Server {
 	return &Server{
-		profileService:  opts.ProfileService,
-		authHelper:      opts.AuthHelper,
-		validatorHelper: opts.ValidatorHelper,
+		profileService:    opts.ProfileService,
+		authService:       opts.AuthService,
+		signingKeyService: opts.SigningKeyService,
+		rateLimitService:  opts.RateLimitService,
+		oauthService:      opts.OAuthService,
+		roleService:       opts.RoleService,
+		authHelper:        opts.AuthHelper,
+		validatorHelper:   opts.ValidatorHelper,
 	}
 }
 
 func (srv *Server) sendErrorResponse(ctx echo.Context, err error) error {
+	var policyErr error_list.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		resp := generated.ErrorResponse{
+			Message: policyErr.Error(),
+			Details: &policyErr.Violations,
+		}
+
+		return ctx.JSON(http.StatusBadRequest, resp)
+	}
+
 	var statusCode int
 	var errorMessage = err.Error()
 
@@ -63,18 +90,15 @@ func (srv *Server) sendValidationErrorResponse(ctx echo.Context, err error) erro
 	return ctx.JSON(http.StatusBadRequest, resp)
 }
 
+// getJWSFromRequest parses the Authorization header the way services using
+// package authn do.
 func (srv *Server) getJWSFromRequest(req *http.Request) (string, error) {
-	authHdr := req.Header.Get("Authorization")
-
-	if authHdr == "" {
+	token, err := authn.BearerToken(req)
+	if err != nil {
 		return "", error_list.ErrNotAuthenticated
 	}
 
-	prefix := "Bearer "
-	if !strings.HasPrefix(authHdr, prefix) {
-		return "", error_list.ErrNotAuthenticated
-	}
-	return strings.TrimPrefix(authHdr, prefix), nil
+	return token, nil
 }
 
 func (srv *Server) CreateMiddleware() ([]echo.MiddlewareFunc, error) {
@@ -83,6 +107,11 @@ func (srv *Server) CreateMiddleware() ([]echo.MiddlewareFunc, error) {
 		return nil, err
 	}
 
+	rateLimits, err := operationRateLimits(spec)
+	if err != nil {
+		return nil, err
+	}
+
 	authenticator := middleware.OapiRequestValidatorWithOptions(spec, &middleware.Options{
 		Options: openapi3filter.Options{
 			AuthenticationFunc: func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
@@ -91,22 +120,91 @@ func (srv *Server) CreateMiddleware() ([]echo.MiddlewareFunc, error) {
 					return err
 				}
 
-				profileId, err := srv.authHelper.VerifyToken(ctx, token)
+				claims, err := srv.authService.Authenticate(ctx, entity.AuthenticateRequest{
+					Token: token,
+				})
 				if err != nil {
 					return err
 				}
 
+				// a login held until its password is replaced can do nothing else
+				if claims.PasswordChangeRequired && input.RequestValidationInput.Route.Operation.OperationID != constant.SetNewPasswordOperationId {
+					return error_list.ErrPasswordChangeRequired
+				}
+
+				// a client acting on its own only reaches the operations of
+				// the ClientAuth scheme, and nothing else does
+				clientToken := claims.ClientId != "" && claims.ProfileId == ""
+				clientOperation := input.SecuritySchemeName == constant.ClientAuthSecurityScheme
+				if clientOperation && !clientToken {
+					return error_list.ErrClientTokenRequired
+				}
+				if !clientOperation && clientToken {
+					return error_list.ErrUserTokenRequired
+				}
+
+				// a token only reaches operations whose scopes it holds, a
+				// login those of every login and the permissions of its
+				// roles. The operations listing none need a login.
+				if (claims.PersonalAccessTokenId != "" || claims.ClientId != "" || len(input.Scopes) > 0) && !grantsScopes(claims.Scopes, input.Scopes) {
+					return error_list.ErrInsufficientScope
+				}
+
 				eCtx := middleware.GetEchoContext(ctx)
-				eCtx.Set(constant.ProfileIdJwtField, profileId)
+				eCtx.Set(constant.ProfileIdJwtField, claims.ProfileId)
+				eCtx.Set(constant.TokenClaimsContextKey, claims)
 
 				return nil
 			},
 		},
+		ErrorHandler: func(c echo.Context, err *echo.HTTPError) error {
+			// authentication failures arrive wrapped in a security
+			// requirements error, unwrap them so clients get the same
+			// precise message and status as from the handlers
+			var securityErr *openapi3filter.SecurityRequirementsError
+			if errors.As(err.Internal, &securityErr) && len(securityErr.Errors) > 0 {
+				return srv.sendErrorResponse(c, securityErr.Errors[0])
+			}
+
+			return err
+		},
 	})
 
-	return []echo.MiddlewareFunc{authenticator}, nil
+	// throttled requests are turned away before any validation work
+	return []echo.MiddlewareFunc{srv.rateLimiter(rateLimits), authenticator}, nil
+}
+
+// grantsScopes reports whether granted holds every one of the required
+// scopes, and at least one is required.
+func grantsScopes(granted []string, required []string) bool {
+	if len(required) == 0 {
+		return false
+	}
+
+	for _, scope := range required {
+		found := false
+		for _, grantedScope := range granted {
+			if grantedScope == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
 }
 
 func (srv *Server) validate(obj interface{}) error {
 	return srv.validatorHelper.ValidateStruct(obj)
 }
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,427 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_CreateMiddleware_passwordChangeRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	restrictedClaims := entity.TokenClaims{
+		ProfileId:              "profile-id-1",
+		SessionId:              "session-id-1",
+		PasswordChangeRequired: true,
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatusCode int
+		wantBody       string
+		mock           func()
+	}{
+		{
+			name:           "restricted token sets a new password",
+			method:         http.MethodPut,
+			path:           "/profile/password/new",
+			body:           `{"new_password":"67890B!"}`,
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"message":"Success set new password, sign in again with it"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(restrictedClaims, nil)
+				mockValidatorHelper.EXPECT().ValidateStruct(gomock.Any()).Return(nil)
+				mockProfileService.EXPECT().SetNewPassword(gomock.Any(), entity.SetNewPasswordRequest{
+					ProfileId:   "profile-id-1",
+					NewPassword: "67890B!",
+				}).Return(nil)
+			},
+		},
+		{
+			name:           "restricted token is refused anywhere else",
+			method:         http.MethodGet,
+			path:           "/profile",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error password must be replaced before continuing"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(restrictedClaims, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			s := &Server{
+				profileService:  mockProfileService,
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			// api.yml only serves http://localhost
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderAuthorization, "Bearer token-1")
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_CreateMiddleware_personalAccessTokenScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	readOnlyClaims := entity.TokenClaims{
+		ProfileId:             "profile-id-1",
+		PersonalAccessTokenId: "token-id-1",
+		Scopes:                []string{"profile:read"},
+	}
+	oauthClientClaims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "grant-id-1",
+		ClientId:  "client-id-1",
+		Scopes:    []string{"profile:read", "profile:write"},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatusCode int
+		wantBody       string
+		mock           func()
+	}{
+		{
+			name:           "token reaches an operation it has the scope of",
+			method:         http.MethodGet,
+			path:           "/profile",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"full_name":"Budi","phone_number":"+6281234567890","recovery_codes_remaining":0}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "pat_secret-1"}).Return(readOnlyClaims, nil)
+				mockValidatorHelper.EXPECT().ValidateStruct(gomock.Any()).Return(nil)
+				mockProfileService.EXPECT().GetProfile(gomock.Any(), entity.GetProfileRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.GetProfileResponse{
+					FullName:    "Budi",
+					PhoneNumber: "+6281234567890",
+				}, nil)
+			},
+		},
+		{
+			name:           "token is refused an operation it lacks the scope of",
+			method:         http.MethodPut,
+			path:           "/profile",
+			body:           `{"full_name":"Budi"}`,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "pat_secret-1"}).Return(readOnlyClaims, nil)
+			},
+		},
+		{
+			name:           "token is refused an operation listing no scope",
+			method:         http.MethodGet,
+			path:           "/profile/tokens",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "pat_secret-1"}).Return(readOnlyClaims, nil)
+			},
+		},
+		{
+			name:           "oauth client token is refused an operation listing no scope",
+			method:         http.MethodGet,
+			path:           "/oauth/grants",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "pat_secret-1"}).Return(oauthClientClaims, nil)
+			},
+		},
+		{
+			name:           "oauth client token without openid is refused userinfo",
+			method:         http.MethodGet,
+			path:           "/userinfo",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "pat_secret-1"}).Return(oauthClientClaims, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			s := &Server{
+				profileService:  mockProfileService,
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderAuthorization, "Bearer pat_secret-1")
+			if tt.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_CreateMiddleware_roles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockRoleService := mocks.NewMockRoleServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	loginClaims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+		Scopes:    []string{"openid", "profile:read", "profile:write"},
+	}
+	adminClaims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+		Scopes:    []string{"openid", "profile:read", "profile:write", "roles:read", "roles:write"},
+		Roles:     []string{"admin"},
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		wantStatusCode int
+		wantBody       string
+		mock           func()
+	}{
+		{
+			name:           "login reaches an operation of every login",
+			method:         http.MethodGet,
+			path:           "/profile",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"full_name":"Budi","phone_number":"+6281234567890","recovery_codes_remaining":0}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(loginClaims, nil)
+				mockValidatorHelper.EXPECT().ValidateStruct(gomock.Any()).Return(nil)
+				mockProfileService.EXPECT().GetProfile(gomock.Any(), entity.GetProfileRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.GetProfileResponse{
+					FullName:    "Budi",
+					PhoneNumber: "+6281234567890",
+				}, nil)
+			},
+		},
+		{
+			name:           "login without a role holding the permission is refused",
+			method:         http.MethodGet,
+			path:           "/roles",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(loginClaims, nil)
+			},
+		},
+		{
+			name:           "login with a role holding the permission reaches the operation",
+			method:         http.MethodGet,
+			path:           "/roles",
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"roles":[]}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(adminClaims, nil)
+				mockRoleService.EXPECT().ListRoles(gomock.Any()).Return(entity.ListRolesResponse{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			s := &Server{
+				profileService:  mockProfileService,
+				authService:     mockAuthService,
+				roleService:     mockRoleService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderAuthorization, "Bearer token-1")
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_CreateMiddleware_clientCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileService := mocks.NewMockProfileServiceInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	clientClaims := entity.TokenClaims{
+		TokenId:  "token-id-1",
+		ClientId: "client-id-1",
+		Scopes:   []string{"profiles:read"},
+	}
+	loginClaims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	profileId := "8c6a3b5e-2f0d-4c39-9d4b-6a1f3e2b7c10"
+
+	tests := []struct {
+		name           string
+		path           string
+		wantStatusCode int
+		wantBody       string
+		mock           func()
+	}{
+		{
+			name:           "client token reaches an operation of services",
+			path:           "/profiles/" + profileId,
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"full_name":"Budi","id":"` + profileId + `","phone_number":"+6281234567890"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(clientClaims, nil)
+				mockValidatorHelper.EXPECT().ValidateStruct(gomock.Any()).Return(nil)
+				mockProfileService.EXPECT().GetProfile(gomock.Any(), entity.GetProfileRequest{
+					ProfileId: profileId,
+				}).Return(entity.GetProfileResponse{
+					FullName:    "Budi",
+					PhoneNumber: "+6281234567890",
+				}, nil)
+			},
+		},
+		{
+			name:           "login token is refused an operation of services",
+			path:           "/profiles/" + profileId,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error operation needs a token of a client"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(loginClaims, nil)
+			},
+		},
+		{
+			name:           "client token is refused an operation of users",
+			path:           "/profile",
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error operation needs a token of a user"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(clientClaims, nil)
+			},
+		},
+		{
+			name:           "client token is refused an operation it lacks the scope of",
+			path:           "/profiles/" + profileId,
+			wantStatusCode: http.StatusForbidden,
+			wantBody:       `{"message":"error token does not have the scope for this operation"}`,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{
+					TokenId:  "token-id-1",
+					ClientId: "client-id-1",
+				}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			s := &Server{
+				profileService:  mockProfileService,
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			mw, err := s.CreateMiddleware()
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.Use(mw...)
+			generated.RegisterHandlers(e, s)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = "localhost"
+			req.Header.Set(echo.HeaderAuthorization, "Bearer token-1")
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatusCode, rec.Code)
+			assert.Equal(t, tt.wantBody, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
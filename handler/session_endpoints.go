@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) ListSessions(ctx echo.Context, params generated.ListSessionsParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	result, err := s.authService.ListSessions(ctx.Request().Context(), entity.ListSessionsRequest{
+		ProfileId: claims.ProfileId,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.ListSessionsResponse{
+		Sessions: make([]generated.Session, 0, len(result.Sessions)),
+	}
+	for _, session := range result.Sessions {
+		resp.Sessions = append(resp.Sessions, generated.Session{
+			Id:         session.Id,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IpAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.Id == claims.SessionId,
+		})
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) RevokeSession(ctx echo.Context, id string, params generated.RevokeSessionParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	revokeReq := entity.RevokeSessionRequest{
+		ProfileId: claims.ProfileId,
+		SessionId: id,
+	}
+	err := s.validate(revokeReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	err = s.authService.RevokeSession(ctx.Request().Context(), revokeReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RevokeSessionResponse{
+		Message: "Success revoke session",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) RevokeOtherSessions(ctx echo.Context, params generated.RevokeOtherSessionsParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	err := s.authService.RevokeOtherSessions(ctx.Request().Context(), entity.RevokeOtherSessionsRequest{
+		ProfileId:        claims.ProfileId,
+		CurrentSessionId: claims.SessionId,
+	})
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RevokeSessionResponse{
+		Message: "Success revoke other sessions",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,380 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastSeenAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	type args struct {
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.ListSessionsResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success list sessions",
+			args: args{
+				claims: claims,
+			},
+			want: generated.ListSessionsResponse{
+				Sessions: []generated.Session{
+					{
+						Id:         "session-id-1",
+						DeviceName: "Pixel 8",
+						UserAgent:  "okhttp/4.12.0",
+						IpAddress:  "192.0.2.1",
+						CreatedAt:  createdAt,
+						LastSeenAt: lastSeenAt,
+						Current:    true,
+					},
+					{
+						Id:         "session-id-2",
+						DeviceName: "iPhone",
+						UserAgent:  "CFNetwork",
+						IpAddress:  "192.0.2.2",
+						CreatedAt:  createdAt,
+						LastSeenAt: createdAt,
+						Current:    false,
+					},
+				},
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockAuthService.EXPECT().ListSessions(gomock.Any(), entity.ListSessionsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListSessionsResponse{
+					Sessions: []entity.Session{
+						{
+							Id:         "session-id-1",
+							ProfileId:  "profile-id-1",
+							DeviceName: "Pixel 8",
+							UserAgent:  "okhttp/4.12.0",
+							IpAddress:  "192.0.2.1",
+							CreatedAt:  createdAt,
+							LastSeenAt: lastSeenAt,
+						},
+						{
+							Id:         "session-id-2",
+							ProfileId:  "profile-id-1",
+							DeviceName: "iPhone",
+							UserAgent:  "CFNetwork",
+							IpAddress:  "192.0.2.2",
+							CreatedAt:  createdAt,
+							LastSeenAt: createdAt,
+						},
+					},
+				}, nil)
+			},
+		},
+		{
+			name: "error when list sessions",
+			args: args{
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when listing sessions",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockAuthService.EXPECT().ListSessions(gomock.Any(), entity.ListSessionsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.ListSessionsResponse{}, errors.New("error when listing sessions"))
+			},
+		},
+		{
+			name: "error missing token claims",
+			args: args{
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService: mockAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.ListSessions(ctx, generated.ListSessionsParams{})
+			}
+
+			e := echo.New()
+
+			e.GET("/sessions", wrapper)
+
+			req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	type args struct {
+		id     string
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.RevokeSessionResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success revoke session",
+			args: args{
+				id:     "session-id-2",
+				claims: claims,
+			},
+			want: generated.RevokeSessionResponse{
+				Message: "Success revoke session",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				revokeReq := entity.RevokeSessionRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-2",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockAuthService.EXPECT().RevokeSession(gomock.Any(), revokeReq).Return(nil)
+			},
+		},
+		{
+			name: "error session not found",
+			args: args{
+				id:     "session-id-2",
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error session not found",
+			},
+			statusCode: http.StatusNotFound,
+			mock: func() {
+				revokeReq := entity.RevokeSessionRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-2",
+				}
+				mockValidatorHelper.EXPECT().ValidateStruct(revokeReq).Return(nil)
+				mockAuthService.EXPECT().RevokeSession(gomock.Any(), revokeReq).Return(errors.New("error session not found"))
+			},
+		},
+		{
+			name: "error invalid session id",
+			args: args{
+				id:     "not-a-uuid",
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error session id not valid",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.RevokeSessionRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "not-a-uuid",
+				}).Return(errors.New("error session id not valid"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.RevokeSession(ctx, ctx.Param("id"), generated.RevokeSessionParams{})
+			}
+
+			e := echo.New()
+
+			e.DELETE("/sessions/:id", wrapper)
+
+			req := httptest.NewRequest(http.MethodDelete, "/sessions/"+tt.args.id, nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_RevokeOtherSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	type args struct {
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.RevokeSessionResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success revoke other sessions",
+			args: args{
+				claims: claims,
+			},
+			want: generated.RevokeSessionResponse{
+				Message: "Success revoke other sessions",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockAuthService.EXPECT().RevokeOtherSessions(gomock.Any(), entity.RevokeOtherSessionsRequest{
+					ProfileId:        "profile-id-1",
+					CurrentSessionId: "session-id-1",
+				}).Return(nil)
+			},
+		},
+		{
+			name: "error when revoke other sessions",
+			args: args{
+				claims: claims,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when revoking session",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockAuthService.EXPECT().RevokeOtherSessions(gomock.Any(), entity.RevokeOtherSessionsRequest{
+					ProfileId:        "profile-id-1",
+					CurrentSessionId: "session-id-1",
+				}).Return(errors.New("error when revoking session"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService: mockAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.RevokeOtherSessions(ctx, generated.RevokeOtherSessionsParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/sessions/revoke-others", wrapper)
+
+			req := httptest.NewRequest(http.MethodPost, "/sessions/revoke-others", nil)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
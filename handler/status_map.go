@@ -11,8 +11,96 @@ var statusResponseMap = map[string]int{
 	error_list.ErrProfileNotFound.Error():  http.StatusNotFound,
 	error_list.ErrLoginCredential.Error():  http.StatusBadRequest,
 	error_list.ErrLogin.Error():            http.StatusInternalServerError,
+	error_list.ErrPhoneNotVerified.Error(): http.StatusForbidden,
+	error_list.ErrAccountLocked.Error():    http.StatusLocked,
+	error_list.ErrVerifyPhone.Error():      http.StatusInternalServerError,
 	error_list.ErrUpdateProfile.Error():    http.StatusInternalServerError,
+	error_list.ErrChangePassword.Error():   http.StatusInternalServerError,
 	error_list.ErrNotAuthenticated.Error(): http.StatusForbidden,
 	error_list.ErrInvalidRequest.Error():   http.StatusBadRequest,
 	error_list.ErrDataConflict.Error():     http.StatusConflict,
+	error_list.ErrInvalidToken.Error():     http.StatusUnauthorized,
+
+	error_list.ErrTooManyRequests.Error(): http.StatusTooManyRequests,
+
+	error_list.ErrConfirmPhoneChange.Error(): http.StatusInternalServerError,
+
+	error_list.ErrCurrentPasswordNotMatch.Error(): http.StatusBadRequest,
+	error_list.ErrBreachedPassword.Error():        http.StatusBadRequest,
+	error_list.ErrPasswordReused.Error():          http.StatusBadRequest,
+
+	error_list.ErrPasswordChangeRequired.Error():    http.StatusForbidden,
+	error_list.ErrPasswordChangeNotRequired.Error(): http.StatusForbidden,
+	error_list.ErrSetNewPassword.Error():            http.StatusInternalServerError,
+	error_list.ErrForcePasswordChange.Error():       http.StatusInternalServerError,
+
+	error_list.ErrRequestPasswordReset.Error():        http.StatusInternalServerError,
+	error_list.ErrResetPassword.Error():               http.StatusInternalServerError,
+	error_list.ErrInvalidOneTimeCode.Error():          http.StatusBadRequest,
+	error_list.ErrOneTimeCodeAttemptsExceeded.Error(): http.StatusTooManyRequests,
+
+	error_list.ErrEnrollTOTP.Error():                   http.StatusInternalServerError,
+	error_list.ErrConfirmTOTP.Error():                  http.StatusInternalServerError,
+	error_list.ErrDisableTOTP.Error():                  http.StatusInternalServerError,
+	error_list.ErrTOTPNotPending.Error():               http.StatusConflict,
+	error_list.ErrTOTPNotEnabled.Error():               http.StatusConflict,
+	error_list.ErrInvalidTOTPCode.Error():              http.StatusBadRequest,
+	error_list.ErrInvalidMFAChallenge.Error():          http.StatusUnauthorized,
+	error_list.ErrMFAChallengeAttemptsExceeded.Error(): http.StatusTooManyRequests,
+
+	error_list.ErrGenerateRecoveryCodes.Error(): http.StatusInternalServerError,
+
+	error_list.ErrIssueToken.Error():          http.StatusInternalServerError,
+	error_list.ErrRefreshToken.Error():        http.StatusInternalServerError,
+	error_list.ErrInvalidRefreshToken.Error(): http.StatusUnauthorized,
+	error_list.ErrRefreshTokenReused.Error():  http.StatusUnauthorized,
+	error_list.ErrAuthenticate.Error():        http.StatusInternalServerError,
+	error_list.ErrTokenRevoked.Error():        http.StatusUnauthorized,
+	error_list.ErrLogout.Error():              http.StatusInternalServerError,
+
+	error_list.ErrTokenExpired.Error():          http.StatusUnauthorized,
+	error_list.ErrTokenNotValidYet.Error():      http.StatusUnauthorized,
+	error_list.ErrTokenMalformed.Error():        http.StatusUnauthorized,
+	error_list.ErrTokenSignatureInvalid.Error(): http.StatusUnauthorized,
+	error_list.ErrTokenInvalidIssuer.Error():    http.StatusUnauthorized,
+	error_list.ErrTokenInvalidAudience.Error():  http.StatusUnauthorized,
+
+	error_list.ErrSessionEnded.Error():    http.StatusUnauthorized,
+	error_list.ErrSessionNotFound.Error(): http.StatusNotFound,
+	error_list.ErrListSessions.Error():    http.StatusInternalServerError,
+	error_list.ErrRevokeSession.Error():   http.StatusInternalServerError,
+
+	error_list.ErrCreatePersonalAccessToken.Error():   http.StatusInternalServerError,
+	error_list.ErrListPersonalAccessTokens.Error():    http.StatusInternalServerError,
+	error_list.ErrRevokePersonalAccessToken.Error():   http.StatusInternalServerError,
+	error_list.ErrPersonalAccessTokenNotFound.Error(): http.StatusNotFound,
+	error_list.ErrInvalidPersonalAccessToken.Error():  http.StatusUnauthorized,
+	error_list.ErrInsufficientScope.Error():           http.StatusForbidden,
+	error_list.ErrUserTokenRequired.Error():           http.StatusForbidden,
+	error_list.ErrClientTokenRequired.Error():         http.StatusForbidden,
+
+	error_list.ErrRegisterOAuthClient.Error(): http.StatusInternalServerError,
+	error_list.ErrInvalidOAuthClient.Error():  http.StatusBadRequest,
+	error_list.ErrInvalidRedirectURI.Error():  http.StatusBadRequest,
+	error_list.ErrInvalidOAuthScope.Error():   http.StatusBadRequest,
+	error_list.ErrAuthorizeOAuth.Error():      http.StatusInternalServerError,
+	error_list.ErrOAuthGrantRevoked.Error():   http.StatusUnauthorized,
+	error_list.ErrOAuthClientRevoked.Error():  http.StatusUnauthorized,
+	error_list.ErrOAuthGrantNotFound.Error():  http.StatusNotFound,
+	error_list.ErrListOAuthGrants.Error():     http.StatusInternalServerError,
+	error_list.ErrRevokeOAuthGrant.Error():    http.StatusInternalServerError,
+	error_list.ErrGetUserInfo.Error():         http.StatusInternalServerError,
+	error_list.ErrInvalidIDTokenHint.Error():  http.StatusBadRequest,
+	error_list.ErrEndOIDCSession.Error():      http.StatusInternalServerError,
+
+	error_list.ErrAuthorizeOAuthDevice.Error(): http.StatusInternalServerError,
+	error_list.ErrInvalidUserCode.Error():      http.StatusBadRequest,
+	error_list.ErrIntrospectToken.Error():      http.StatusInternalServerError,
+
+	error_list.ErrListRoles.Error():        http.StatusInternalServerError,
+	error_list.ErrListProfileRoles.Error(): http.StatusInternalServerError,
+	error_list.ErrAssignRole.Error():       http.StatusInternalServerError,
+	error_list.ErrUnassignRole.Error():     http.StatusInternalServerError,
+	error_list.ErrRoleNotFound.Error():     http.StatusNotFound,
+	error_list.ErrRoleNotAssigned.Error():  http.StatusNotFound,
 }
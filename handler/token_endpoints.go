@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/generated"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) RefreshToken(ctx echo.Context) error {
+	var req generated.RefreshTokenRequest
+
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	refreshReq := entity.RefreshTokenRequest{
+		RefreshToken: req.RefreshToken,
+	}
+	err = s.validate(refreshReq)
+	if err != nil {
+		return s.sendValidationErrorResponse(ctx, err)
+	}
+
+	result, err := s.authService.RefreshToken(ctx.Request().Context(), refreshReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.RefreshTokenResponse{
+		Token:        result.Token,
+		RefreshToken: result.RefreshToken,
+		ExpiresIn:    result.ExpiresIn,
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) Logout(ctx echo.Context, params generated.LogoutParams) error {
+	claims, ok := ctx.Get(constant.TokenClaimsContextKey).(entity.TokenClaims)
+	if !ok {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	var req generated.LogoutRequest
+	err := ctx.Bind(&req)
+	if err != nil {
+		return s.sendErrorResponse(ctx, error_list.ErrInvalidRequest)
+	}
+
+	logoutReq := entity.LogoutRequest{
+		ProfileId: claims.ProfileId,
+		SessionId: claims.SessionId,
+		TokenId:   claims.TokenId,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	if req.RefreshToken != nil {
+		logoutReq.RefreshToken = *req.RefreshToken
+	}
+
+	err = s.authService.Logout(ctx.Request().Context(), logoutReq)
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.LogoutResponse{
+		Message: "Success logout",
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) GetJSONWebKeySet(ctx echo.Context) error {
+	result, err := s.signingKeyService.GetJSONWebKeySet(ctx.Request().Context())
+	if err != nil {
+		return s.sendErrorResponse(ctx, err)
+	}
+
+	resp := generated.JSONWebKeySet{
+		Keys: make([]generated.JSONWebKey, 0, len(result.Keys)),
+	}
+	for _, key := range result.Keys {
+		resp.Keys = append(resp.Keys, generated.JSONWebKey{
+			Kty: key.KeyType,
+			Kid: key.KeyId,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			N:   optionalString(key.N),
+			E:   optionalString(key.E),
+			Crv: optionalString(key.Curve),
+			X:   optionalString(key.X),
+			Y:   optionalString(key.Y),
+		})
+	}
+
+	ctx.Response().Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(constant.JWKSCacheMaxAge.Seconds())))
+
+	return ctx.JSON(http.StatusOK, resp)
+}
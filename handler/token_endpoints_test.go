@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/entity"
+	"sawitpro/generated"
+	"sawitpro/helper"
+	"sawitpro/mocks"
+	"sawitpro/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_RefreshToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+
+	type fields struct {
+		authService     service.AuthServiceInterface
+		validatorHelper helper.ValidatorHelperInterface
+	}
+	type args struct {
+		req generated.RefreshTokenRequest
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		args       args
+		want       generated.RefreshTokenResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success refresh token",
+			fields: fields{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want: generated.RefreshTokenResponse{
+				Token:        "token-2",
+				RefreshToken: "refresh-token-2",
+				ExpiresIn:    900,
+			},
+			wantErr:    false,
+			errResp:    nil,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				}).Return(nil)
+				mockAuthService.EXPECT().RefreshToken(gomock.Any(), entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				}).Return(entity.RefreshTokenResponse{
+					Token:        "token-2",
+					RefreshToken: "refresh-token-2",
+					ExpiresIn:    900,
+				}, nil)
+			},
+		},
+		{
+			name: "error reused refresh token",
+			fields: fields{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    generated.RefreshTokenResponse{},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error refresh token reuse detected",
+			},
+			statusCode: http.StatusUnauthorized,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				}).Return(nil)
+				mockAuthService.EXPECT().RefreshToken(gomock.Any(), entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				}).Return(entity.RefreshTokenResponse{}, errors.New("error refresh token reuse detected"))
+			},
+		},
+		{
+			name: "error invalid payload",
+			fields: fields{
+				authService:     mockAuthService,
+				validatorHelper: mockValidatorHelper,
+			},
+			args: args{
+				req: generated.RefreshTokenRequest{},
+			},
+			want:    generated.RefreshTokenResponse{},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "invalid payload at refresh token",
+			},
+			statusCode: http.StatusBadRequest,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidateStruct(entity.RefreshTokenRequest{}).Return(errors.New("invalid payload at refresh token"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService:     tt.fields.authService,
+				validatorHelper: tt.fields.validatorHelper,
+			}
+
+			e := echo.New()
+
+			e.POST("/token/refresh", s.RefreshToken)
+
+			requestBody, _ := json.Marshal(tt.args.req)
+
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	expiresAt := time.Now().Add(time.Minute)
+	refreshToken := "refresh-token-1"
+
+	type args struct {
+		req    generated.LogoutRequest
+		claims interface{}
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       generated.LogoutResponse
+		wantErr    bool
+		errResp    *generated.ErrorResponse
+		statusCode int
+		mock       func()
+	}{
+		{
+			name: "success logout",
+			args: args{
+				req: generated.LogoutRequest{
+					RefreshToken: &refreshToken,
+				},
+				claims: entity.TokenClaims{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			want: generated.LogoutResponse{
+				Message: "Success logout",
+			},
+			wantErr:    false,
+			statusCode: http.StatusOK,
+			mock: func() {
+				mockAuthService.EXPECT().Logout(gomock.Any(), entity.LogoutRequest{
+					ProfileId:    "profile-id-1",
+					SessionId:    "session-id-1",
+					TokenId:      "token-id-1",
+					ExpiresAt:    expiresAt,
+					RefreshToken: "refresh-token-1",
+				}).Return(nil)
+			},
+		},
+		{
+			name: "error when logout",
+			args: args{
+				req: generated.LogoutRequest{},
+				claims: entity.TokenClaims{
+					ProfileId: "profile-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error when logging out",
+			},
+			statusCode: http.StatusInternalServerError,
+			mock: func() {
+				mockAuthService.EXPECT().Logout(gomock.Any(), entity.LogoutRequest{
+					ProfileId: "profile-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				}).Return(errors.New("error when logging out"))
+			},
+		},
+		{
+			name: "error missing token claims",
+			args: args{
+				req:    generated.LogoutRequest{},
+				claims: nil,
+			},
+			wantErr: true,
+			errResp: &generated.ErrorResponse{
+				Message: "error invalid request",
+			},
+			statusCode: http.StatusBadRequest,
+			mock:       func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			s := &Server{
+				authService: mockAuthService,
+			}
+
+			wrapper := func(ctx echo.Context) error {
+				ctx.Set("token_claims", tt.args.claims)
+				return s.Logout(ctx, generated.LogoutParams{})
+			}
+
+			e := echo.New()
+
+			e.POST("/logout", wrapper)
+
+			requestBody, _ := json.Marshal(tt.args.req)
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader(string(requestBody)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			var expectBody []byte
+
+			if tt.wantErr {
+				expectBody, _ = json.Marshal(tt.errResp)
+			} else {
+				expectBody, _ = json.Marshal(tt.want)
+			}
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, strings.TrimSpace(string(expectBody)), strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestServer_GetJSONWebKeySet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigningKeyService := mocks.NewMockSigningKeyServiceInterface(ctrl)
+	mockSigningKeyService.EXPECT().GetJSONWebKeySet(gomock.Any()).Return(entity.JSONWebKeySet{
+		Keys: []entity.JSONWebKey{
+			{
+				KeyType:   "EC",
+				KeyId:     "key-1",
+				Use:       "sig",
+				Algorithm: "ES256",
+				Curve:     "P-256",
+				X:         "x-coordinate",
+				Y:         "y-coordinate",
+			},
+		},
+	}, nil)
+
+	s := &Server{
+		signingKeyService: mockSigningKeyService,
+	}
+
+	e := echo.New()
+	e.GET("/.well-known/jwks.json", s.GetJSONWebKeySet)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=300", rec.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"keys":[{"kty":"EC","kid":"key-1","use":"sig","alg":"ES256","crv":"P-256","x":"x-coordinate","y":"y-coordinate"}]}`, rec.Body.String())
+}
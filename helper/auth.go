@@ -2,69 +2,352 @@ package helper
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
 	"sawitpro/constant"
+	"sawitpro/entity"
 	"sawitpro/error_list"
+	"sawitpro/pkg/authn"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 )
 
+// signingAlgorithms pins the algorithms accepted in the alg header, anything
+// else (notably none and the HMAC family) is rejected before key lookup.
+var signingAlgorithms = []string{
+	constant.SigningAlgorithmRS256,
+	constant.SigningAlgorithmES256,
+	constant.SigningAlgorithmEdDSA,
+}
+
 type authHelper struct {
+	keyRing        KeyRingInterface
+	passwordHasher PasswordHasherInterface
+	issuer         string
+	audience       string
+	verifier       *authn.Verifier
 }
 
-func NewAuthHelper() authHelper {
-	return authHelper{}
+type AuthHelperOptions struct {
+	KeyRing        KeyRingInterface
+	PasswordHasher PasswordHasherInterface
+	Issuer         string
+	Audience       string
+	Leeway         time.Duration
 }
 
-func (hlp authHelper) HashPassword(ctx context.Context, password string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func NewAuthHelper(opts AuthHelperOptions) authHelper {
+	// access tokens are verified the way the services accepting them do,
+	// a helper configured without keys only hashes and refuses any token
+	verifier, _ := authn.NewVerifier(authn.VerifierOptions{
+		Issuer:                      opts.Issuer,
+		Audience:                    opts.Audience,
+		Leeway:                      opts.Leeway,
+		KeySet:                      opts.KeyRing,
+		AllowPasswordChangeRequired: true,
+	})
 
-	return string(hashed), err
+	return authHelper{
+		keyRing:        opts.KeyRing,
+		passwordHasher: opts.PasswordHasher,
+		issuer:         opts.Issuer,
+		audience:       opts.Audience,
+		verifier:       verifier,
+	}
+}
+
+func (hlp authHelper) HashPassword(ctx context.Context, password string) (string, error) {
+	return hlp.passwordHasher.HashPassword(ctx, password)
 }
 
 func (hlp authHelper) VerifyPassword(ctx context.Context, plainPassword string, hashedPassword string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
+	return hlp.passwordHasher.VerifyPassword(ctx, plainPassword, hashedPassword)
+}
+
+// PasswordNeedsRehash reports whether a verified password should be hashed
+// again, because its hash is of an older algorithm or weaker parameters.
+func (hlp authHelper) PasswordNeedsRehash(ctx context.Context, hashedPassword string) bool {
+	return hlp.passwordHasher.NeedsRehash(ctx, hashedPassword)
+}
+
+func (hlp authHelper) GenerateToken(ctx context.Context, request entity.GenerateTokenRequest) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss": hlp.issuer,
+		"aud": hlp.audience,
+		"sub": request.ProfileId,
+		"sid": request.SessionId,
+		"jti": uuid.NewString(),
+		"iat": jwt.NewNumericDate(now),
+		"nbf": jwt.NewNumericDate(now),
+		"exp": jwt.NewNumericDate(now.Add(constant.AccessTokenDuration)),
+	}
+
+	// a client acting on its own is the subject, there is no session
+	if request.ProfileId == "" && request.ClientId != "" {
+		claims["sub"] = request.ClientId
+		delete(claims, "sid")
+	}
+
+	if request.PasswordChangeRequired {
+		claims[constant.PasswordChangeRequiredJwtField] = true
+	}
+
+	if request.ClientId != "" {
+		claims[constant.ClientIdJwtField] = request.ClientId
+	}
+
+	// a client token always states its scopes, even none, a login states
+	// those its roles grant
+	if request.ClientId != "" || len(request.Scopes) > 0 {
+		claims[constant.ScopeJwtField] = strings.Join(request.Scopes, " ")
+	}
+
+	if len(request.Roles) > 0 {
+		claims[constant.RolesJwtField] = request.Roles
+	}
+
+	return hlp.keyRing.SignToken(ctx, claims)
+}
+
+func (hlp authHelper) VerifyToken(ctx context.Context, token string) (entity.TokenClaims, error) {
+	var res = entity.TokenClaims{}
+
+	if hlp.verifier == nil {
+		return res, error_list.ErrInvalidToken
+	}
+
+	claims, err := hlp.verifier.Verify(ctx, token)
 	if err != nil {
-		if err == bcrypt.ErrMismatchedHashAndPassword {
-			return error_list.ErrPasswordNotMatch
-		}
+		return res, verifyError(err)
+	}
 
-		return err
+	res = entity.TokenClaims{
+		ProfileId:              claims.ProfileId,
+		SessionId:              claims.SessionId,
+		TokenId:                claims.TokenId,
+		ExpiresAt:              claims.ExpiresAt,
+		PasswordChangeRequired: claims.PasswordChangeRequired,
+		ClientId:               claims.ClientId,
+		Scopes:                 claims.Scopes,
+		Roles:                  claims.Roles,
 	}
 
-	return nil
+	return res, nil
 }
 
-func (hlp authHelper) GenerateToken(ctx context.Context, profileId string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		constant.ProfileIdJwtField: profileId,
-	})
+// GenerateIDToken signs the OpenID Connect ID token telling the client who
+// signed in. Its audience is the client, so it is never accepted in place of
+// an access token.
+func (hlp authHelper) GenerateIDToken(ctx context.Context, request entity.GenerateIDTokenRequest) (string, error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		"iss": hlp.issuer,
+		"aud": request.ClientId,
+		"sub": request.UserInfo.ProfileId,
+		"iat": jwt.NewNumericDate(now),
+		"exp": jwt.NewNumericDate(now.Add(constant.IDTokenDuration)),
+	}
+
+	if request.SessionId != "" {
+		claims["sid"] = request.SessionId
+	}
 
-	return token.SignedString([]byte(constant.EnvJWTSecretKey))
+	if request.Nonce != "" {
+		claims["nonce"] = request.Nonce
+	}
+
+	if request.UserInfo.Name != nil {
+		claims["name"] = *request.UserInfo.Name
+	}
+
+	if request.UserInfo.PhoneNumber != nil {
+		claims["phone_number"] = *request.UserInfo.PhoneNumber
+	}
+
+	if request.UserInfo.PhoneNumberVerified != nil {
+		claims["phone_number_verified"] = *request.UserInfo.PhoneNumberVerified
+	}
+
+	return hlp.keyRing.SignToken(ctx, claims)
 }
 
-func (hlp authHelper) VerifyToken(ctx context.Context, token string) (string, error) {
-	jwtToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(constant.EnvJWTSecretKey), nil
-	})
+// VerifyIDToken checks an ID token this service issued, as presented back by
+// a client to log the user out. It may have expired by then, which is
+// accepted as the spec asks.
+func (hlp authHelper) VerifyIDToken(ctx context.Context, token string) (entity.IDTokenClaims, error) {
+	var res = entity.IDTokenClaims{}
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, error_list.ErrUnknownSigningKey
+		}
+
+		return hlp.keyRing.VerificationKey(ctx, kid, token.Method.Alg())
+	}
+
+	// the caller only needs to know the hint is not one of ours
+	jwtToken, err := jwt.Parse(
+		token,
+		keyFunc,
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithoutClaimsValidation(),
+	)
 	if err != nil {
-		return "", error_list.ErrInvalidToken
+		return res, error_list.ErrInvalidToken
 	}
 
 	claims, claimsExist := jwtToken.Claims.(jwt.MapClaims)
 	if !claimsExist {
-		return "", error_list.ErrInvalidToken
+		return res, error_list.ErrInvalidToken
+	}
+
+	issuer, err := claims.GetIssuer()
+	if err != nil || issuer != hlp.issuer {
+		return res, error_list.ErrTokenInvalidIssuer
+	}
+
+	// an access token is signed by the same keys, only its audience tells
+	// it apart
+	audience, err := claims.GetAudience()
+	if err != nil || len(audience) != 1 || audience[0] == "" || audience[0] == hlp.audience {
+		return res, error_list.ErrTokenInvalidAudience
+	}
+
+	profileId, err := claims.GetSubject()
+	if err != nil || profileId == "" {
+		return res, error_list.ErrTokenMalformed
+	}
+
+	sessionId, _ := claims["sid"].(string)
+
+	res = entity.IDTokenClaims{
+		ProfileId: profileId,
+		ClientId:  audience[0],
+		SessionId: sessionId,
+	}
+
+	return res, nil
+}
+
+func (hlp authHelper) GenerateRefreshToken(ctx context.Context) (string, error) {
+	buf := make([]byte, 32)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashToken returns the digest stored in place of opaque tokens, so a leaked
+// database dump cannot be replayed against the API.
+func (hlp authHelper) HashToken(ctx context.Context, token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// CodeChallenge derives the S256 PKCE challenge of a code verifier, the
+// unpadded base64url of its SHA-256 digest.
+func (hlp authHelper) CodeChallenge(ctx context.Context, codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// GenerateOneTimeCode returns a uniformly random numeric code, zero padded to
+// constant.OneTimeCodeLength digits.
+func (hlp authHelper) GenerateOneTimeCode(ctx context.Context) (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < constant.OneTimeCodeLength; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", constant.OneTimeCodeLength, n), nil
+}
+
+// GenerateRecoveryCode returns a random code from the Crockford base32
+// alphabet, which leaves out letters easily mistaken for digits, split into
+// groups by dashes for reading it off paper.
+func (hlp authHelper) GenerateRecoveryCode(ctx context.Context) (string, error) {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	buf := make([]byte, constant.RecoveryCodeLength)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
 	}
 
-	profileId, profileIdExists := claims[constant.ProfileIdJwtField]
-	if !profileIdExists {
-		return "", error_list.ErrInvalidToken
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%constant.RecoveryCodeGroupSize == 0 {
+			code.WriteByte('-')
+		}
+		// 256 is a multiple of 32, so every symbol is equally likely
+		code.WriteByte(alphabet[int(b)%len(alphabet)])
 	}
 
-	profileIdStr, ok := profileId.(string)
-	if !ok {
-		return "", error_list.ErrInvalidToken
+	return code.String(), nil
+}
+
+// GenerateUserCode returns a random code of consonants for a user to type in
+// the code a device shows. Without vowels no words are spelled, and without
+// digits nothing is mistaken for a letter.
+func (hlp authHelper) GenerateUserCode(ctx context.Context) (string, error) {
+	const alphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+	var code strings.Builder
+	for i := 0; i < constant.OAuthUserCodeLength; i++ {
+		if i > 0 && i%constant.OAuthUserCodeGroupSize == 0 {
+			code.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+
+		code.WriteByte(alphabet[n.Int64()])
 	}
 
-	return profileIdStr, nil
+	return code.String(), nil
+}
+
+// verifyError narrows the errors of package authn down to the ones we report
+// to clients. An unknown key is a signature we cannot trust either.
+func verifyError(err error) error {
+	switch err {
+	case authn.ErrTokenExpired:
+		return error_list.ErrTokenExpired
+	case authn.ErrTokenNotValidYet:
+		return error_list.ErrTokenNotValidYet
+	case authn.ErrTokenInvalidAudience:
+		return error_list.ErrTokenInvalidAudience
+	case authn.ErrTokenInvalidIssuer:
+		return error_list.ErrTokenInvalidIssuer
+	case authn.ErrTokenMalformed:
+		return error_list.ErrTokenMalformed
+	case authn.ErrTokenSignatureInvalid, authn.ErrUnknownSigningKey, authn.ErrUnsupportedSigningAlgorithm:
+		return error_list.ErrTokenSignatureInvalid
+	default:
+		return error_list.ErrInvalidToken
+	}
 }
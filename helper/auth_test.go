@@ -0,0 +1,349 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/pkg/authn"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestKeyRing returns a ring holding a single active key.
+func newTestKeyRing(t *testing.T, algorithm string) keyRing {
+	t.Helper()
+
+	ring, err := NewKeyRing(KeyRingOptions{
+		Algorithm: algorithm,
+		Secret:    "secret",
+	})
+	assert.NoError(t, err)
+
+	key, err := ring.GenerateKey(context.TODO())
+	assert.NoError(t, err)
+	key.ActivatesAt = time.Now().Add(-time.Minute)
+
+	err = ring.LoadKeys(context.TODO(), []entity.SigningKey{key})
+	assert.NoError(t, err)
+
+	return ring
+}
+
+func Test_authHelper_VerifyToken(t *testing.T) {
+	ring := newTestKeyRing(t, constant.SigningAlgorithmES256)
+	otherRing := newTestKeyRing(t, constant.SigningAlgorithmES256)
+
+	hlp := NewAuthHelper(AuthHelperOptions{
+		KeyRing:  ring,
+		Issuer:   "sawitpro",
+		Audience: "sawitpro-api",
+		Leeway:   30 * time.Second,
+	})
+
+	now := time.Now()
+	expiresAt := now.Add(time.Minute).Truncate(time.Second)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": "sawitpro",
+			"aud": "sawitpro-api",
+			"sub": "profile-id-1",
+			"sid": "session-id-1",
+			"jti": "token-id-1",
+			"iat": jwt.NewNumericDate(now),
+			"nbf": jwt.NewNumericDate(now),
+			"exp": jwt.NewNumericDate(expiresAt),
+		}
+	}
+
+	sign := func(claims jwt.MapClaims) string {
+		token, err := ring.SignToken(context.TODO(), claims)
+		assert.NoError(t, err)
+
+		return token
+	}
+
+	withClaim := func(name string, value interface{}) string {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+
+		return sign(claims)
+	}
+
+	// kidOf lends the key id of a token of the ring to a token signed
+	// otherwise, so the key is found and only the signature decides
+	kidOf := func(token string) string {
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		assert.NoError(t, err)
+
+		return parsed.Header["kid"].(string)
+	}
+	kid := kidOf(sign(validClaims()))
+
+	withMethod := func(method jwt.SigningMethod, key interface{}) string {
+		token := jwt.NewWithClaims(method, validClaims())
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(key)
+		assert.NoError(t, err)
+
+		return signed
+	}
+
+	signedByOtherRing := func() string {
+		token, err := otherRing.SignToken(context.TODO(), validClaims())
+		assert.NoError(t, err)
+
+		return token
+	}
+
+	signedByOtherKey := func() string {
+		parts := strings.Split(signedByOtherRing(), ".")
+		parts[0] = encodeBase64URL([]byte(fmt.Sprintf(`{"alg":"ES256","kid":"%s","typ":"JWT"}`, kid)))
+
+		return strings.Join(parts, ".")
+	}
+
+	tamperedPayload := func() string {
+		parts := strings.Split(sign(validClaims()), ".")
+		parts[1] = strings.Split(withClaim("sub", "profile-id-2"), ".")[1]
+
+		return strings.Join(parts, ".")
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    entity.TokenClaims
+		wantErr error
+	}{
+		{
+			name:  "success token of a user",
+			token: sign(validClaims()),
+			want: entity.TokenClaims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				Scopes:    authn.LoginScopes(),
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "success token restricted to changing the password",
+			token: withClaim("pwd_change", true),
+			want: entity.TokenClaims{
+				ProfileId:              "profile-id-1",
+				SessionId:              "session-id-1",
+				TokenId:                "token-id-1",
+				ExpiresAt:              expiresAt,
+				PasswordChangeRequired: true,
+				Scopes:                 authn.LoginScopes(),
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "success expired within the leeway",
+			token: withClaim("exp", jwt.NewNumericDate(now.Add(-10*time.Second).Truncate(time.Second))),
+			want: entity.TokenClaims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: now.Add(-10 * time.Second).Truncate(time.Second),
+				Scopes:    authn.LoginScopes(),
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "error wrong alg",
+			token:   withMethod(jwt.SigningMethodHS256, []byte("secret")),
+			wantErr: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "error alg none",
+			token:   withMethod(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType),
+			wantErr: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "error wrong issuer",
+			token:   withClaim("iss", "someone-else"),
+			wantErr: error_list.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "error wrong audience",
+			token:   withClaim("aud", "someone-else"),
+			wantErr: error_list.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "error expired",
+			token:   withClaim("exp", jwt.NewNumericDate(now.Add(-time.Minute))),
+			wantErr: error_list.ErrTokenExpired,
+		},
+		{
+			name:    "error without expiry",
+			token:   withClaim("exp", nil),
+			wantErr: error_list.ErrInvalidToken,
+		},
+		{
+			name:    "error not valid yet",
+			token:   withClaim("nbf", jwt.NewNumericDate(now.Add(time.Minute))),
+			wantErr: error_list.ErrTokenNotValidYet,
+		},
+		{
+			name:    "error issued in the future",
+			token:   withClaim("iat", jwt.NewNumericDate(now.Add(time.Minute))),
+			wantErr: error_list.ErrTokenNotValidYet,
+		},
+		{
+			name:    "error bad signature",
+			token:   signedByOtherKey(),
+			wantErr: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "error tampered payload",
+			token:   tamperedPayload(),
+			wantErr: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "error unknown key",
+			token:   signedByOtherRing(),
+			wantErr: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name:    "error malformed",
+			token:   "not-a-token",
+			wantErr: error_list.ErrTokenMalformed,
+		},
+		{
+			name:    "error without subject",
+			token:   withClaim("sub", nil),
+			wantErr: error_list.ErrInvalidToken,
+		},
+		{
+			name: "error legacy profile id claim without subject",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "sub")
+				claims["profile_id"] = "profile-id-1"
+
+				return sign(claims)
+			}(),
+			wantErr: error_list.ErrInvalidToken,
+		},
+		{
+			name:    "error without session of a user",
+			token:   withClaim("sid", nil),
+			wantErr: error_list.ErrInvalidToken,
+		},
+		{
+			name:    "error malformed password change claim",
+			token:   withClaim("pwd_change", "false"),
+			wantErr: error_list.ErrTokenMalformed,
+		},
+		{
+			name:    "error client without scope claim",
+			token:   withClaim("client_id", "client-id-1"),
+			wantErr: error_list.ErrTokenMalformed,
+		},
+		{
+			name:    "error malformed roles claim",
+			token:   withClaim("roles", "admin"),
+			wantErr: error_list.ErrTokenMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hlp.VerifyToken(context.TODO(), tt.token)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authHelper_VerifyToken_withoutKeys(t *testing.T) {
+	ring := newTestKeyRing(t, constant.SigningAlgorithmES256)
+
+	token, err := NewAuthHelper(AuthHelperOptions{
+		KeyRing:  ring,
+		Issuer:   "sawitpro",
+		Audience: "sawitpro-api",
+	}).GenerateToken(context.TODO(), entity.GenerateTokenRequest{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	})
+	assert.NoError(t, err)
+
+	// as the admin command builds it, to hash secrets only
+	got, err := NewAuthHelper(AuthHelperOptions{}).VerifyToken(context.TODO(), token)
+	assert.Equal(t, entity.TokenClaims{}, got)
+	assert.Equal(t, error_list.ErrInvalidToken, err)
+}
+
+func Test_verifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{
+			name: "expired",
+			err:  authn.ErrTokenExpired,
+			want: error_list.ErrTokenExpired,
+		},
+		{
+			name: "not valid yet",
+			err:  authn.ErrTokenNotValidYet,
+			want: error_list.ErrTokenNotValidYet,
+		},
+		{
+			name: "invalid audience",
+			err:  authn.ErrTokenInvalidAudience,
+			want: error_list.ErrTokenInvalidAudience,
+		},
+		{
+			name: "invalid issuer",
+			err:  authn.ErrTokenInvalidIssuer,
+			want: error_list.ErrTokenInvalidIssuer,
+		},
+		{
+			name: "malformed",
+			err:  authn.ErrTokenMalformed,
+			want: error_list.ErrTokenMalformed,
+		},
+		{
+			name: "signature invalid",
+			err:  authn.ErrTokenSignatureInvalid,
+			want: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "unknown key",
+			err:  authn.ErrUnknownSigningKey,
+			want: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "algorithm of another key",
+			err:  authn.ErrUnsupportedSigningAlgorithm,
+			want: error_list.ErrTokenSignatureInvalid,
+		},
+		{
+			name: "anything else",
+			err:  authn.ErrInvalidToken,
+			want: error_list.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, verifyError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+	"sawitpro/error_list"
+	"strconv"
+	"strings"
+)
+
+const (
+	breachedPasswordIndexMagic = "PWNIDX1\n"
+
+	// hashes are grouped by their first five hex digits, the prefix the
+	// Pwned Passwords range API is queried with
+	breachedPasswordPrefixSize  = 5
+	breachedPasswordPrefixCount = 1 << (4 * breachedPasswordPrefixSize)
+
+	// the magic followed by where the range of every prefix starts in the
+	// body, and one more offset for where the last range ends
+	breachedPasswordHeaderSize = len(breachedPasswordIndexMagic) + 8*(breachedPasswordPrefixCount+1)
+)
+
+// breachedPasswordIndex looks passwords up in an index file built by
+// cmd/breachindex. The body of the file holds, prefix after prefix, exactly
+// what the range API answers for the prefix: one SUFFIX:COUNT line per
+// hash. Only the offsets of the ranges are kept in memory, eight MiB however
+// large the corpus, and a lookup reads the single range it needs.
+type breachedPasswordIndex struct {
+	file    *os.File
+	offsets []uint64
+}
+
+func NewBreachedPasswordIndex(path string) (breachedPasswordIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return breachedPasswordIndex{}, err
+	}
+
+	header := make([]byte, breachedPasswordHeaderSize)
+
+	_, err = io.ReadFull(file, header)
+	if err != nil || string(header[:len(breachedPasswordIndexMagic)]) != breachedPasswordIndexMagic {
+		file.Close()
+		return breachedPasswordIndex{}, error_list.ErrMalformedBreachedPasswordIndex
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return breachedPasswordIndex{}, err
+	}
+
+	offsets := make([]uint64, breachedPasswordPrefixCount+1)
+	for i := range offsets {
+		offsets[i] = binary.BigEndian.Uint64(header[len(breachedPasswordIndexMagic)+8*i:])
+
+		// ranges follow each other, a corrupt or truncated file would have
+		// lookups read backwards or past its end
+		if i > 0 && offsets[i] < offsets[i-1] {
+			file.Close()
+			return breachedPasswordIndex{}, error_list.ErrMalformedBreachedPasswordIndex
+		}
+	}
+
+	if offsets[0] != 0 || offsets[breachedPasswordPrefixCount] != uint64(info.Size())-uint64(breachedPasswordHeaderSize) {
+		file.Close()
+		return breachedPasswordIndex{}, error_list.ErrMalformedBreachedPasswordIndex
+	}
+
+	return breachedPasswordIndex{
+		file:    file,
+		offsets: offsets,
+	}, nil
+}
+
+func (idx breachedPasswordIndex) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	prefix := int(sum[0])<<12 | int(sum[1])<<4 | int(sum[2])>>4
+	start, end := idx.offsets[prefix], idx.offsets[prefix+1]
+	if start == end {
+		return false, nil
+	}
+
+	body := make([]byte, end-start)
+
+	_, err := idx.file.ReadAt(body, int64(breachedPasswordHeaderSize)+int64(start))
+	if err != nil {
+		return false, err
+	}
+
+	suffix := []byte(hash[breachedPasswordPrefixSize:] + ":")
+	for _, line := range bytes.Split(body, []byte("\r\n")) {
+		if bytes.HasPrefix(line, suffix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// noBreachedPasswordChecker is used when no index is configured, it lets
+// every password through.
+type noBreachedPasswordChecker struct{}
+
+func NewNoBreachedPasswordChecker() noBreachedPasswordChecker {
+	return noBreachedPasswordChecker{}
+}
+
+func (checker noBreachedPasswordChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	return false, nil
+}
+
+// BuildBreachedPasswordIndex writes the index of a dump of SHA-1 hashes to
+// dst and returns how many hashes it holds. The dump has one HASH:COUNT line
+// per hash ordered by hash, as the Pwned Passwords downloader writes it.
+func BuildBreachedPasswordIndex(src io.Reader, dst io.WriterAt) (int64, error) {
+	offsets := make([]uint64, breachedPasswordPrefixCount+1)
+	body := bufio.NewWriter(&offsetWriter{
+		dst:    dst,
+		offset: int64(breachedPasswordHeaderSize),
+	})
+
+	var count int64
+	var position uint64
+	var nextPrefix int
+	var previousHash string
+
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		hash, hits, found := strings.Cut(line, ":")
+		hash = strings.ToUpper(hash)
+		if !found || len(hash) != 2*sha1.Size || hits == "" {
+			return count, error_list.ErrMalformedBreachedPasswordDump
+		}
+
+		_, err := hex.DecodeString(hash)
+		if err != nil {
+			return count, error_list.ErrMalformedBreachedPasswordDump
+		}
+
+		_, err = strconv.ParseUint(hits, 10, 64)
+		if err != nil {
+			return count, error_list.ErrMalformedBreachedPasswordDump
+		}
+
+		// ordered input is what lets the ranges be written in one pass
+		if hash <= previousHash {
+			return count, error_list.ErrUnorderedBreachedPasswordDump
+		}
+		previousHash = hash
+
+		prefix, _ := strconv.ParseUint(hash[:breachedPasswordPrefixSize], 16, 32)
+		for ; nextPrefix <= int(prefix); nextPrefix++ {
+			offsets[nextPrefix] = position
+		}
+
+		n, err := body.WriteString(hash[breachedPasswordPrefixSize:] + ":" + hits + "\r\n")
+		if err != nil {
+			return count, err
+		}
+
+		position += uint64(n)
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return count, err
+	}
+
+	for ; nextPrefix <= breachedPasswordPrefixCount; nextPrefix++ {
+		offsets[nextPrefix] = position
+	}
+
+	err := body.Flush()
+	if err != nil {
+		return count, err
+	}
+
+	header := make([]byte, breachedPasswordHeaderSize)
+	copy(header, breachedPasswordIndexMagic)
+	for i, offset := range offsets {
+		binary.BigEndian.PutUint64(header[len(breachedPasswordIndexMagic)+8*i:], offset)
+	}
+
+	_, err = dst.WriteAt(header, 0)
+
+	return count, err
+}
+
+// offsetWriter turns sequential writes into positioned ones, so the body can
+// be streamed behind the header that is only known at the end.
+type offsetWriter struct {
+	dst    io.WriterAt
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.dst.WriteAt(p, w.offset)
+	w.offset += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,258 @@
+package helper
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sawitpro/error_list"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// writeBreachedPasswordIndex builds the index of the given HASH:COUNT lines,
+// ordering them as the dump would be, and returns the path of the file.
+func writeBreachedPasswordIndex(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	sort.Strings(lines)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "breached-passwords.idx"))
+	assert.NoError(t, err)
+	defer file.Close()
+
+	_, err = BuildBreachedPasswordIndex(strings.NewReader(strings.Join(lines, "\n")), file)
+	assert.NoError(t, err)
+
+	return file.Name()
+}
+
+func TestBuildBreachedPasswordIndex(t *testing.T) {
+	tests := []struct {
+		name      string
+		dump      string
+		wantCount int64
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			dump:      sha1Hex("password") + ":9545824\r\n" + sha1Hex("123456") + ":37359195\r\n",
+			wantCount: 2,
+			wantErr:   nil,
+		},
+		{
+			name:      "success lowercase hashes and blank lines",
+			dump:      strings.ToLower(sha1Hex("password")) + ":9545824\n\n" + strings.ToLower(sha1Hex("123456")) + ":37359195\n",
+			wantCount: 2,
+			wantErr:   nil,
+		},
+		{
+			name:      "success empty dump",
+			dump:      "",
+			wantCount: 0,
+			wantErr:   nil,
+		},
+		{
+			name:      "error line without count",
+			dump:      sha1Hex("password") + "\n",
+			wantCount: 0,
+			wantErr:   error_list.ErrMalformedBreachedPasswordDump,
+		},
+		{
+			name:      "error hash too short",
+			dump:      sha1Hex("password")[:39] + ":1\n",
+			wantCount: 0,
+			wantErr:   error_list.ErrMalformedBreachedPasswordDump,
+		},
+		{
+			name:      "error hash not in hex",
+			dump:      "Z" + sha1Hex("password")[1:] + ":1\n",
+			wantCount: 0,
+			wantErr:   error_list.ErrMalformedBreachedPasswordDump,
+		},
+		{
+			name:      "error count not a number",
+			dump:      sha1Hex("password") + ":1\n" + sha1Hex("123456") + ":many\n",
+			wantCount: 1,
+			wantErr:   error_list.ErrMalformedBreachedPasswordDump,
+		},
+		{
+			name:      "error not ordered",
+			dump:      sha1Hex("123456") + ":37359195\n" + sha1Hex("password") + ":9545824\n",
+			wantCount: 1,
+			wantErr:   error_list.ErrUnorderedBreachedPasswordDump,
+		},
+		{
+			name:      "error duplicate hash",
+			dump:      sha1Hex("password") + ":1\n" + sha1Hex("password") + ":1\n",
+			wantCount: 1,
+			wantErr:   error_list.ErrUnorderedBreachedPasswordDump,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := os.Create(filepath.Join(t.TempDir(), "breached-passwords.idx"))
+			assert.NoError(t, err)
+			defer file.Close()
+
+			got, err := BuildBreachedPasswordIndex(strings.NewReader(tt.dump), file)
+			assert.Equal(t, tt.wantCount, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestNewBreachedPasswordIndex(t *testing.T) {
+	valid, err := os.ReadFile(writeBreachedPasswordIndex(t, sha1Hex("password")+":9545824"))
+	assert.NoError(t, err)
+
+	// corrupt returns a copy of the valid index changed by fn
+	corrupt := func(fn func(b []byte) []byte) []byte {
+		return fn(append([]byte(nil), valid...))
+	}
+
+	offsetAt := func(b []byte, prefix int) []byte {
+		return b[len(breachedPasswordIndexMagic)+8*prefix:]
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr error
+	}{
+		{
+			name:    "success",
+			content: valid,
+			wantErr: nil,
+		},
+		{
+			name:    "error empty file",
+			content: []byte{},
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name:    "error header cut short",
+			content: valid[:breachedPasswordHeaderSize-1],
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name: "error other magic",
+			content: corrupt(func(b []byte) []byte {
+				copy(b, "PWNIDX2\n")
+				return b
+			}),
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name:    "error body cut short",
+			content: valid[:len(valid)-1],
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name:    "error body longer than its ranges",
+			content: append(append([]byte(nil), valid...), "\r\n"...),
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name: "error range ending before it starts",
+			content: corrupt(func(b []byte) []byte {
+				binary.BigEndian.PutUint64(offsetAt(b, 1), 1<<40)
+				return b
+			}),
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+		{
+			name: "error first range not at the start of the body",
+			content: corrupt(func(b []byte) []byte {
+				binary.BigEndian.PutUint64(offsetAt(b, 0), 1)
+				return b
+			}),
+			wantErr: error_list.ErrMalformedBreachedPasswordIndex,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "breached-passwords.idx")
+			assert.NoError(t, os.WriteFile(path, tt.content, 0600))
+
+			idx, err := NewBreachedPasswordIndex(path)
+			assert.Equal(t, tt.wantErr, err)
+			if err == nil {
+				idx.file.Close()
+			}
+		})
+	}
+
+	t.Run("error missing file", func(t *testing.T) {
+		_, err := NewBreachedPasswordIndex(filepath.Join(t.TempDir(), "missing.idx"))
+		assert.True(t, errors.Is(err, fs.ErrNotExist))
+	})
+}
+
+func Test_breachedPasswordIndex_IsBreached(t *testing.T) {
+	// a hash sharing the range of "password", so the lookup has to tell the
+	// suffixes apart
+	hash := sha1Hex("password")
+	neighbour := hash[:len(hash)-1] + "0"
+
+	idx, err := NewBreachedPasswordIndex(writeBreachedPasswordIndex(t,
+		sha1Hex("123456")+":37359195",
+		hash+":9545824",
+		neighbour+":1",
+		sha1Hex("qwerty")+":10556095",
+	))
+	assert.NoError(t, err)
+	defer idx.file.Close()
+
+	tests := []struct {
+		name     string
+		password string
+		want     bool
+	}{
+		{
+			name:     "breached",
+			password: "password",
+			want:     true,
+		},
+		{
+			name:     "breached 123456",
+			password: "123456",
+			want:     true,
+		},
+		{
+			name:     "breached qwerty",
+			password: "qwerty",
+			want:     true,
+		},
+		{
+			name:     "not breached",
+			password: "correct horse battery staple",
+			want:     false,
+		},
+		{
+			name:     "not breached differing only in case",
+			password: "Password",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idx.IsBreached(context.TODO(), tt.password)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
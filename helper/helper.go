@@ -1,14 +1,62 @@
 package helper
 
-import "context"
+import (
+	"context"
+	"crypto"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type AuthHelperInterface interface {
 	HashPassword(ctx context.Context, password string) (string, error)
 	VerifyPassword(ctx context.Context, plainPassword string, hashedPassword string) error
-	GenerateToken(ctx context.Context, profileId string) (string, error)
-	VerifyToken(ctx context.Context, token string) (string, error)
+	PasswordNeedsRehash(ctx context.Context, hashedPassword string) bool
+	GenerateToken(ctx context.Context, request entity.GenerateTokenRequest) (string, error)
+	VerifyToken(ctx context.Context, token string) (entity.TokenClaims, error)
+	GenerateIDToken(ctx context.Context, request entity.GenerateIDTokenRequest) (string, error)
+	VerifyIDToken(ctx context.Context, token string) (entity.IDTokenClaims, error)
+	GenerateRefreshToken(ctx context.Context) (string, error)
+	HashToken(ctx context.Context, token string) string
+	CodeChallenge(ctx context.Context, codeVerifier string) string
+	GenerateOneTimeCode(ctx context.Context) (string, error)
+	GenerateRecoveryCode(ctx context.Context) (string, error)
+	GenerateUserCode(ctx context.Context) (string, error)
+}
+
+type PasswordHasherInterface interface {
+	HashPassword(ctx context.Context, password string) (string, error)
+	VerifyPassword(ctx context.Context, plainPassword string, hashedPassword string) error
+	NeedsRehash(ctx context.Context, hashedPassword string) bool
+}
+
+type BreachedPasswordCheckerInterface interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+type TOTPHelperInterface interface {
+	GenerateSecret(ctx context.Context) (string, error)
+	EncryptSecret(ctx context.Context, secret string) (string, error)
+	DecryptSecret(ctx context.Context, encrypted string) (string, error)
+	KeyURI(ctx context.Context, accountName string, secret string) string
+	MatchCode(ctx context.Context, secret string, code string, now time.Time) (int64, bool)
+}
+
+type SMSSenderInterface interface {
+	SendSMS(ctx context.Context, phoneNumber string, message string) error
 }
 
 type ValidatorHelperInterface interface {
 	ValidateStruct(s interface{}) error
+	ValidatePassword(password string, personalInfo ...string) error
+}
+
+type KeyRingInterface interface {
+	Algorithm(ctx context.Context) string
+	GenerateKey(ctx context.Context) (entity.SigningKey, error)
+	LoadKeys(ctx context.Context, keys []entity.SigningKey) error
+	SignToken(ctx context.Context, claims jwt.Claims) (string, error)
+	VerificationKey(ctx context.Context, keyId string, algorithm string) (crypto.PublicKey, error)
+	JSONWebKeys(ctx context.Context) []entity.JSONWebKey
 }
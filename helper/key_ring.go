@@ -0,0 +1,257 @@
+package helper
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type keyRingKey struct {
+	id          string
+	algorithm   string
+	privateKey  crypto.Signer
+	activatesAt time.Time
+	retiring    bool
+}
+
+// keyRing holds the signing keys loaded from the database. Private keys are
+// kept encrypted at rest with a key derived from the configured secret.
+type keyRing struct {
+	mu        *sync.RWMutex
+	algorithm string
+	box       secretBox
+	keys      *[]keyRingKey
+}
+
+type KeyRingOptions struct {
+	Algorithm string
+	Secret    string
+}
+
+func NewKeyRing(opts KeyRingOptions) (keyRing, error) {
+	algorithm := opts.Algorithm
+	if algorithm == "" {
+		algorithm = constant.DefaultSigningAlgorithm
+	}
+
+	switch algorithm {
+	case constant.SigningAlgorithmRS256, constant.SigningAlgorithmES256, constant.SigningAlgorithmEdDSA:
+	default:
+		return keyRing{}, error_list.ErrUnsupportedSigningAlgorithm
+	}
+
+	if opts.Secret == "" {
+		return keyRing{}, error_list.ErrMissingKeySecret
+	}
+
+	box, err := newSecretBox(opts.Secret)
+	if err != nil {
+		return keyRing{}, err
+	}
+
+	return keyRing{
+		mu:        &sync.RWMutex{},
+		algorithm: algorithm,
+		box:       box,
+		keys:      &[]keyRingKey{},
+	}, nil
+}
+
+func (ring keyRing) Algorithm(ctx context.Context) string {
+	return ring.algorithm
+}
+
+func (ring keyRing) GenerateKey(ctx context.Context) (entity.SigningKey, error) {
+	var privateKey crypto.Signer
+	var err error
+
+	switch ring.algorithm {
+	case constant.SigningAlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case constant.SigningAlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case constant.SigningAlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = error_list.ErrUnsupportedSigningAlgorithm
+	}
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	encrypted, err := ring.box.seal(pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: der,
+	}))
+	if err != nil {
+		return entity.SigningKey{}, err
+	}
+
+	return entity.SigningKey{
+		Id:         uuid.NewString(),
+		Algorithm:  ring.algorithm,
+		PrivateKey: encrypted,
+	}, nil
+}
+
+// LoadKeys replaces the keys held by the ring. Keys without a retirement date
+// are candidates for signing, retiring keys are only kept for verification.
+func (ring keyRing) LoadKeys(ctx context.Context, keys []entity.SigningKey) error {
+	loaded := make([]keyRingKey, 0, len(keys))
+
+	for _, key := range keys {
+		decrypted, err := ring.box.open(key.PrivateKey)
+		if err != nil {
+			return err
+		}
+
+		block, _ := pem.Decode(decrypted)
+		if block == nil {
+			return error_list.ErrUnknownSigningKey
+		}
+
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+
+		privateKey, ok := parsed.(crypto.Signer)
+		if !ok {
+			return error_list.ErrUnsupportedSigningAlgorithm
+		}
+
+		loaded = append(loaded, keyRingKey{
+			id:          key.Id,
+			algorithm:   key.Algorithm,
+			privateKey:  privateKey,
+			activatesAt: key.ActivatesAt,
+			retiring:    key.RetiresAt != nil,
+		})
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].activatesAt.Before(loaded[j].activatesAt)
+	})
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	*ring.keys = loaded
+
+	return nil
+}
+
+func (ring keyRing) SignToken(ctx context.Context, claims jwt.Claims) (string, error) {
+	key, err := ring.signingKey(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.algorithm), claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.privateKey)
+}
+
+// VerificationKey resolves the public key of a kid header, so the ring is the
+// authn.KeySet of our own tokens. It refuses tokens whose alg header does not
+// match that key.
+func (ring keyRing) VerificationKey(ctx context.Context, keyId string, algorithm string) (crypto.PublicKey, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	for _, key := range *ring.keys {
+		if key.id != keyId {
+			continue
+		}
+
+		if algorithm != key.algorithm {
+			return nil, error_list.ErrUnsupportedSigningAlgorithm
+		}
+
+		return key.privateKey.Public(), nil
+	}
+
+	return nil, error_list.ErrUnknownSigningKey
+}
+
+func (ring keyRing) JSONWebKeys(ctx context.Context) []entity.JSONWebKey {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	res := make([]entity.JSONWebKey, 0, len(*ring.keys))
+	for _, key := range *ring.keys {
+		res = append(res, toJSONWebKey(key))
+	}
+
+	return res
+}
+
+// signingKey picks the newest key that is already active. Pending keys are
+// published ahead of time but must not sign until their activation time.
+func (ring keyRing) signingKey(now time.Time) (keyRingKey, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	keys := *ring.keys
+	for i := len(keys) - 1; i >= 0; i-- {
+		if !keys[i].retiring && !keys[i].activatesAt.After(now) {
+			return keys[i], nil
+		}
+	}
+
+	return keyRingKey{}, error_list.ErrNoSigningKey
+}
+
+func toJSONWebKey(key keyRingKey) entity.JSONWebKey {
+	jwk := entity.JSONWebKey{
+		KeyId:     key.id,
+		Use:       "sig",
+		Algorithm: key.algorithm,
+	}
+
+	switch publicKey := key.privateKey.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeBase64URL(publicKey.N.Bytes())
+		jwk.E = encodeBase64URL(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = publicKey.Curve.Params().Name
+		jwk.X = encodeBase64URL(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeBase64URL(publicKey.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeBase64URL(publicKey)
+	}
+
+	return jwk
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,289 @@
+package helper
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewKeyRing(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          KeyRingOptions
+		wantAlgorithm string
+		wantErr       error
+	}{
+		{
+			name:          "success default algorithm",
+			opts:          KeyRingOptions{Secret: "secret"},
+			wantAlgorithm: constant.DefaultSigningAlgorithm,
+			wantErr:       nil,
+		},
+		{
+			name:          "success EdDSA",
+			opts:          KeyRingOptions{Algorithm: constant.SigningAlgorithmEdDSA, Secret: "secret"},
+			wantAlgorithm: constant.SigningAlgorithmEdDSA,
+			wantErr:       nil,
+		},
+		{
+			name:    "error symmetric algorithm",
+			opts:    KeyRingOptions{Algorithm: "HS256", Secret: "secret"},
+			wantErr: error_list.ErrUnsupportedSigningAlgorithm,
+		},
+		{
+			name:    "error without secret",
+			opts:    KeyRingOptions{Algorithm: constant.SigningAlgorithmES256},
+			wantErr: error_list.ErrMissingKeySecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKeyRing(tt.opts)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantAlgorithm, got.Algorithm(context.TODO()))
+		})
+	}
+}
+
+func Test_keyRing_SignToken(t *testing.T) {
+	ring, err := NewKeyRing(KeyRingOptions{
+		Algorithm: constant.SigningAlgorithmES256,
+		Secret:    "secret",
+	})
+	assert.NoError(t, err)
+
+	now := time.Now()
+
+	// key generates a key of the ring activating at the given offset from
+	// now, retiring when retires is set
+	key := func(activatesIn time.Duration, retires bool) entity.SigningKey {
+		key, err := ring.GenerateKey(context.TODO())
+		assert.NoError(t, err)
+
+		key.ActivatesAt = now.Add(activatesIn)
+		if retires {
+			retiresAt := now.Add(constant.SigningKeyRetirementOverlap)
+			key.RetiresAt = &retiresAt
+		}
+
+		return key
+	}
+
+	older := key(-2*constant.SigningKeyRotationInterval, false)
+	active := key(-constant.SigningKeyRotationInterval, false)
+	pending := key(constant.SigningKeyPublishLeadTime, false)
+	retiring := key(-2*constant.SigningKeyRotationInterval, true)
+
+	tests := []struct {
+		name       string
+		keys       []entity.SigningKey
+		wantKeyId  string
+		wantErr    error
+		wantKeyIds []string
+	}{
+		{
+			name:       "success the only key",
+			keys:       []entity.SigningKey{active},
+			wantKeyId:  active.Id,
+			wantKeyIds: []string{active.Id},
+		},
+		{
+			name:       "success the newest active key",
+			keys:       []entity.SigningKey{active, older},
+			wantKeyId:  active.Id,
+			wantKeyIds: []string{older.Id, active.Id},
+		},
+		{
+			name:       "success pending key is published ahead but does not sign",
+			keys:       []entity.SigningKey{pending, active},
+			wantKeyId:  active.Id,
+			wantKeyIds: []string{active.Id, pending.Id},
+		},
+		{
+			name:       "success retiring key is still published for its overlap but does not sign",
+			keys:       []entity.SigningKey{active, retiring},
+			wantKeyId:  active.Id,
+			wantKeyIds: []string{retiring.Id, active.Id},
+		},
+		{
+			name:       "error only a pending key",
+			keys:       []entity.SigningKey{pending},
+			wantErr:    error_list.ErrNoSigningKey,
+			wantKeyIds: []string{pending.Id},
+		},
+		{
+			name:       "error only a retiring key",
+			keys:       []entity.SigningKey{retiring},
+			wantErr:    error_list.ErrNoSigningKey,
+			wantKeyIds: []string{retiring.Id},
+		},
+		{
+			name:       "error without keys",
+			keys:       []entity.SigningKey{},
+			wantErr:    error_list.ErrNoSigningKey,
+			wantKeyIds: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ring.LoadKeys(context.TODO(), tt.keys)
+			assert.NoError(t, err)
+
+			token, err := ring.SignToken(context.TODO(), jwt.MapClaims{"sub": "profile-id-1"})
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.wantErr == nil {
+				parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantKeyId, parsed.Header["kid"])
+			}
+
+			gotKeyIds := []string{}
+			for _, jwk := range ring.JSONWebKeys(context.TODO()) {
+				gotKeyIds = append(gotKeyIds, jwk.KeyId)
+
+				// every published key still verifies the tokens it signed
+				_, err := ring.VerificationKey(context.TODO(), jwk.KeyId, constant.SigningAlgorithmES256)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantKeyIds, gotKeyIds)
+		})
+	}
+}
+
+func Test_keyRing_VerificationKey(t *testing.T) {
+	ring := newTestKeyRing(t, constant.SigningAlgorithmES256)
+	keyId := ring.JSONWebKeys(context.TODO())[0].KeyId
+
+	tests := []struct {
+		name      string
+		keyId     string
+		algorithm string
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			keyId:     keyId,
+			algorithm: constant.SigningAlgorithmES256,
+			wantErr:   nil,
+		},
+		{
+			name:      "error unknown kid",
+			keyId:     "unknown-key-id",
+			algorithm: constant.SigningAlgorithmES256,
+			wantErr:   error_list.ErrUnknownSigningKey,
+		},
+		{
+			name:      "error alg of another key type",
+			keyId:     keyId,
+			algorithm: constant.SigningAlgorithmRS256,
+			wantErr:   error_list.ErrUnsupportedSigningAlgorithm,
+		},
+		{
+			name:      "error symmetric alg",
+			keyId:     keyId,
+			algorithm: "HS256",
+			wantErr:   error_list.ErrUnsupportedSigningAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ring.VerificationKey(context.TODO(), tt.keyId, tt.algorithm)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.IsType(t, &ecdsa.PublicKey{}, got)
+			} else {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
+
+func Test_keyRing_LoadKeys(t *testing.T) {
+	t.Run("success every algorithm", func(t *testing.T) {
+		tests := []struct {
+			algorithm   string
+			wantKeyType string
+			wantPublic  interface{}
+		}{
+			{algorithm: constant.SigningAlgorithmRS256, wantKeyType: "RSA", wantPublic: &rsa.PublicKey{}},
+			{algorithm: constant.SigningAlgorithmES256, wantKeyType: "EC", wantPublic: &ecdsa.PublicKey{}},
+			{algorithm: constant.SigningAlgorithmEdDSA, wantKeyType: "OKP", wantPublic: ed25519.PublicKey{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.algorithm, func(t *testing.T) {
+				ring := newTestKeyRing(t, tt.algorithm)
+
+				jwks := ring.JSONWebKeys(context.TODO())
+				assert.Len(t, jwks, 1)
+				assert.Equal(t, tt.wantKeyType, jwks[0].KeyType)
+				assert.Equal(t, tt.algorithm, jwks[0].Algorithm)
+
+				publicKey, err := ring.VerificationKey(context.TODO(), jwks[0].KeyId, tt.algorithm)
+				assert.NoError(t, err)
+				assert.IsType(t, tt.wantPublic, publicKey)
+
+				token, err := ring.SignToken(context.TODO(), jwt.MapClaims{"sub": "profile-id-1"})
+				assert.NoError(t, err)
+
+				_, err = jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+					return publicKey, nil
+				}, jwt.WithValidMethods([]string{tt.algorithm}))
+				assert.NoError(t, err)
+			})
+		}
+	})
+
+	ring, err := NewKeyRing(KeyRingOptions{
+		Algorithm: constant.SigningAlgorithmES256,
+		Secret:    "secret",
+	})
+	assert.NoError(t, err)
+
+	otherRing, err := NewKeyRing(KeyRingOptions{
+		Algorithm: constant.SigningAlgorithmES256,
+		Secret:    "another secret",
+	})
+	assert.NoError(t, err)
+
+	key, err := otherRing.GenerateKey(context.TODO())
+	assert.NoError(t, err)
+
+	t.Run("error key encrypted with another secret", func(t *testing.T) {
+		err := ring.LoadKeys(context.TODO(), []entity.SigningKey{key})
+		assert.Error(t, err)
+	})
+
+	t.Run("error private key that is not a key", func(t *testing.T) {
+		encrypted, err := ring.box.seal([]byte("not a key"))
+		assert.NoError(t, err)
+
+		err = ring.LoadKeys(context.TODO(), []entity.SigningKey{{
+			Id:         "key-id-1",
+			Algorithm:  constant.SigningAlgorithmES256,
+			PrivateKey: encrypted,
+		}})
+		assert.Equal(t, error_list.ErrUnknownSigningKey, err)
+	})
+
+	t.Run("error keeps the loaded keys", func(t *testing.T) {
+		loaded, err := ring.GenerateKey(context.TODO())
+		assert.NoError(t, err)
+		assert.NoError(t, ring.LoadKeys(context.TODO(), []entity.SigningKey{loaded}))
+
+		err = ring.LoadKeys(context.TODO(), []entity.SigningKey{key})
+		assert.Error(t, err)
+
+		_, err = ring.VerificationKey(context.TODO(), loaded.Id, constant.SigningAlgorithmES256)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,247 @@
+package helper
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// argon2idHash is a decoded hash in the PHC string format,
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+// with the salt and key in unpadded base64.
+type argon2idHash struct {
+	version int
+	params  argon2idParams
+	salt    []byte
+	key     []byte
+}
+
+// passwordHasher hashes new passwords with the configured algorithm and
+// verifies hashes of every supported one, so stored hashes keep working
+// after the algorithm or its parameters change. bcrypt hashes are kept in
+// their own modular crypt format, which the PHC format grew out of.
+type passwordHasher struct {
+	algorithm  string
+	argon2id   argon2idParams
+	bcryptCost int
+}
+
+type PasswordHasherOptions struct {
+	// Algorithm hashes new passwords, argon2id when empty
+	Algorithm string
+	// Argon2idMemory is in KiB, zero parameters take the defaults
+	Argon2idMemory      uint32
+	Argon2idIterations  uint32
+	Argon2idParallelism uint8
+	BcryptCost          int
+}
+
+func NewPasswordHasher(opts PasswordHasherOptions) (passwordHasher, error) {
+	hasher := passwordHasher{
+		algorithm: opts.Algorithm,
+		argon2id: argon2idParams{
+			memory:      opts.Argon2idMemory,
+			iterations:  opts.Argon2idIterations,
+			parallelism: opts.Argon2idParallelism,
+		},
+		bcryptCost: opts.BcryptCost,
+	}
+
+	if hasher.algorithm == "" {
+		hasher.algorithm = constant.DefaultPasswordHashAlgorithm
+	}
+	if hasher.argon2id.memory == 0 {
+		hasher.argon2id.memory = constant.DefaultArgon2idMemory
+	}
+	if hasher.argon2id.iterations == 0 {
+		hasher.argon2id.iterations = constant.DefaultArgon2idIterations
+	}
+	if hasher.argon2id.parallelism == 0 {
+		hasher.argon2id.parallelism = constant.DefaultArgon2idParallelism
+	}
+	if hasher.bcryptCost == 0 {
+		hasher.bcryptCost = constant.DefaultBcryptCost
+	}
+
+	switch hasher.algorithm {
+	case constant.PasswordHashAlgorithmArgon2id, constant.PasswordHashAlgorithmBcrypt:
+	default:
+		return passwordHasher{}, error_list.ErrUnsupportedPasswordHashAlgorithm
+	}
+
+	// argon2 needs at least eight KiB per lane
+	if hasher.argon2id.memory < 8*uint32(hasher.argon2id.parallelism) ||
+		hasher.bcryptCost < bcrypt.MinCost || hasher.bcryptCost > bcrypt.MaxCost {
+		return passwordHasher{}, error_list.ErrInvalidPasswordHashParams
+	}
+
+	return hasher, nil
+}
+
+func (hlp passwordHasher) HashPassword(ctx context.Context, password string) (string, error) {
+	switch hlp.algorithm {
+	case constant.PasswordHashAlgorithmArgon2id:
+		salt := make([]byte, constant.Argon2idSaltSize)
+
+		_, err := rand.Read(salt)
+		if err != nil {
+			return "", err
+		}
+
+		return encodeArgon2idHash(argon2idHash{
+			version: argon2.Version,
+			params:  hlp.argon2id,
+			salt:    salt,
+			key:     deriveArgon2idKey(password, salt, hlp.argon2id, constant.Argon2idKeySize),
+		}), nil
+	case constant.PasswordHashAlgorithmBcrypt:
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), hlp.bcryptCost)
+
+		return string(hashed), err
+	default:
+		return "", error_list.ErrUnsupportedPasswordHashAlgorithm
+	}
+}
+
+func (hlp passwordHasher) VerifyPassword(ctx context.Context, plainPassword string, hashedPassword string) error {
+	switch passwordHashAlgorithm(hashedPassword) {
+	case constant.PasswordHashAlgorithmArgon2id:
+		hash, err := decodeArgon2idHash(hashedPassword)
+		if err != nil {
+			return err
+		}
+
+		key := deriveArgon2idKey(plainPassword, hash.salt, hash.params, uint32(len(hash.key)))
+		if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+			return error_list.ErrPasswordNotMatch
+		}
+
+		return nil
+	case constant.PasswordHashAlgorithmBcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
+		if err != nil {
+			if err == bcrypt.ErrMismatchedHashAndPassword {
+				return error_list.ErrPasswordNotMatch
+			}
+
+			return err
+		}
+
+		return nil
+	default:
+		return error_list.ErrUnsupportedPasswordHashAlgorithm
+	}
+}
+
+// NeedsRehash reports whether the hash was produced by another algorithm
+// than the configured one or with weaker parameters. Parallelism does not
+// count, it changes how the work is spread and not how much of it there is.
+func (hlp passwordHasher) NeedsRehash(ctx context.Context, hashedPassword string) bool {
+	algorithm := passwordHashAlgorithm(hashedPassword)
+	if algorithm == "" {
+		// nothing to upgrade from, it cannot have been verified either
+		return false
+	}
+
+	if algorithm != hlp.algorithm {
+		return true
+	}
+
+	switch algorithm {
+	case constant.PasswordHashAlgorithmArgon2id:
+		hash, err := decodeArgon2idHash(hashedPassword)
+		if err != nil {
+			return false
+		}
+
+		return hash.params.memory < hlp.argon2id.memory ||
+			hash.params.iterations < hlp.argon2id.iterations ||
+			len(hash.salt) < constant.Argon2idSaltSize ||
+			len(hash.key) < constant.Argon2idKeySize
+	case constant.PasswordHashAlgorithmBcrypt:
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		if err != nil {
+			return false
+		}
+
+		return cost < hlp.bcryptCost
+	default:
+		return false
+	}
+}
+
+// passwordHashAlgorithm tells the algorithm from the identifier the hash
+// starts with, empty when it is not a supported one.
+func passwordHashAlgorithm(hashedPassword string) string {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return constant.PasswordHashAlgorithmArgon2id
+	case strings.HasPrefix(hashedPassword, "$2a$"),
+		strings.HasPrefix(hashedPassword, "$2b$"),
+		strings.HasPrefix(hashedPassword, "$2y$"):
+		return constant.PasswordHashAlgorithmBcrypt
+	default:
+		return ""
+	}
+}
+
+func deriveArgon2idKey(password string, salt []byte, params argon2idParams, keySize uint32) []byte {
+	return argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, keySize)
+}
+
+func encodeArgon2idHash(hash argon2idHash) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		hash.version,
+		hash.params.memory,
+		hash.params.iterations,
+		hash.params.parallelism,
+		base64.RawStdEncoding.EncodeToString(hash.salt),
+		base64.RawStdEncoding.EncodeToString(hash.key),
+	)
+}
+
+func decodeArgon2idHash(hashedPassword string) (argon2idHash, error) {
+	var hash argon2idHash
+
+	// the leading $ leaves an empty first part
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != constant.PasswordHashAlgorithmArgon2id {
+		return hash, error_list.ErrMalformedPasswordHash
+	}
+
+	_, err := fmt.Sscanf(parts[2], "v=%d", &hash.version)
+	if err != nil || hash.version != argon2.Version {
+		return hash, error_list.ErrMalformedPasswordHash
+	}
+
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.params.memory, &hash.params.iterations, &hash.params.parallelism)
+	if err != nil || hash.params.iterations == 0 || hash.params.parallelism == 0 {
+		return hash, error_list.ErrMalformedPasswordHash
+	}
+
+	hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return hash, error_list.ErrMalformedPasswordHash
+	}
+
+	hash.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash.key) == 0 {
+		return hash, error_list.ErrMalformedPasswordHash
+	}
+
+	return hash, nil
+}
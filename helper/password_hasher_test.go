@@ -0,0 +1,378 @@
+package helper
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// referenceArgon2idHash is "password" hashed with the salt "somesalt" by the
+// argon2 reference implementation.
+const referenceArgon2idHash = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+func bcryptHash(t *testing.T, password string, cost int) string {
+	t.Helper()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	assert.NoError(t, err)
+
+	return string(hashed)
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    PasswordHasherOptions
+		want    passwordHasher
+		wantErr error
+	}{
+		{
+			name: "success defaults",
+			opts: PasswordHasherOptions{},
+			want: passwordHasher{
+				algorithm: constant.PasswordHashAlgorithmArgon2id,
+				argon2id: argon2idParams{
+					memory:      constant.DefaultArgon2idMemory,
+					iterations:  constant.DefaultArgon2idIterations,
+					parallelism: constant.DefaultArgon2idParallelism,
+				},
+				bcryptCost: constant.DefaultBcryptCost,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success bcrypt",
+			opts: PasswordHasherOptions{
+				Algorithm:  constant.PasswordHashAlgorithmBcrypt,
+				BcryptCost: 12,
+			},
+			want: passwordHasher{
+				algorithm: constant.PasswordHashAlgorithmBcrypt,
+				argon2id: argon2idParams{
+					memory:      constant.DefaultArgon2idMemory,
+					iterations:  constant.DefaultArgon2idIterations,
+					parallelism: constant.DefaultArgon2idParallelism,
+				},
+				bcryptCost: 12,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error unsupported algorithm",
+			opts: PasswordHasherOptions{
+				Algorithm: "scrypt",
+			},
+			want:    passwordHasher{},
+			wantErr: error_list.ErrUnsupportedPasswordHashAlgorithm,
+		},
+		{
+			name: "error argon2id memory below eight KiB per lane",
+			opts: PasswordHasherOptions{
+				Argon2idMemory:      31,
+				Argon2idParallelism: 4,
+			},
+			want:    passwordHasher{},
+			wantErr: error_list.ErrInvalidPasswordHashParams,
+		},
+		{
+			name: "error bcrypt cost too low",
+			opts: PasswordHasherOptions{
+				BcryptCost: bcrypt.MinCost - 1,
+			},
+			want:    passwordHasher{},
+			wantErr: error_list.ErrInvalidPasswordHashParams,
+		},
+		{
+			name: "error bcrypt cost too high",
+			opts: PasswordHasherOptions{
+				BcryptCost: bcrypt.MaxCost + 1,
+			},
+			want:    passwordHasher{},
+			wantErr: error_list.ErrInvalidPasswordHashParams,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewPasswordHasher(tt.opts)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_passwordHasher_HashPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       PasswordHasherOptions
+		wantPrefix string
+	}{
+		{
+			name: "argon2id",
+			opts: PasswordHasherOptions{
+				Algorithm:           constant.PasswordHashAlgorithmArgon2id,
+				Argon2idMemory:      64,
+				Argon2idIterations:  1,
+				Argon2idParallelism: 2,
+			},
+			wantPrefix: "$argon2id$v=19$m=64,t=1,p=2$",
+		},
+		{
+			name: "bcrypt",
+			opts: PasswordHasherOptions{
+				Algorithm:  constant.PasswordHashAlgorithmBcrypt,
+				BcryptCost: bcrypt.MinCost,
+			},
+			wantPrefix: "$2a$04$",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := NewPasswordHasher(tt.opts)
+			assert.NoError(t, err)
+
+			hashed, err := hasher.HashPassword(context.TODO(), "correct horse")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hashed, tt.wantPrefix), hashed)
+
+			// salted, the same password never hashes the same twice
+			again, err := hasher.HashPassword(context.TODO(), "correct horse")
+			assert.NoError(t, err)
+			assert.NotEqual(t, hashed, again)
+
+			assert.NoError(t, hasher.VerifyPassword(context.TODO(), "correct horse", hashed))
+			assert.Equal(t, error_list.ErrPasswordNotMatch, hasher.VerifyPassword(context.TODO(), "correct horsE", hashed))
+			assert.False(t, hasher.NeedsRehash(context.TODO(), hashed))
+		})
+	}
+}
+
+func Test_passwordHasher_VerifyPassword(t *testing.T) {
+	// verification follows the hash, whatever the configured algorithm
+	hasher, err := NewPasswordHasher(PasswordHasherOptions{})
+	assert.NoError(t, err)
+
+	legacyHash := bcryptHash(t, "password", bcrypt.MinCost)
+
+	tests := []struct {
+		name           string
+		plainPassword  string
+		hashedPassword string
+		wantErr        error
+	}{
+		{
+			name:           "success argon2id reference hash",
+			plainPassword:  "password",
+			hashedPassword: referenceArgon2idHash,
+			wantErr:        nil,
+		},
+		{
+			name:           "success bcrypt legacy hash",
+			plainPassword:  "password",
+			hashedPassword: legacyHash,
+			wantErr:        nil,
+		},
+		{
+			name:           "success bcrypt legacy hash of the 2y variant",
+			plainPassword:  "password",
+			hashedPassword: "$2y$" + strings.TrimPrefix(legacyHash, "$2a$"),
+			wantErr:        nil,
+		},
+		{
+			name:           "error argon2id wrong password",
+			plainPassword:  "Password",
+			hashedPassword: referenceArgon2idHash,
+			wantErr:        error_list.ErrPasswordNotMatch,
+		},
+		{
+			name:           "error bcrypt wrong password",
+			plainPassword:  "Password",
+			hashedPassword: legacyHash,
+			wantErr:        error_list.ErrPasswordNotMatch,
+		},
+		{
+			name:           "error argon2id truncated",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id without key",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id other version",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=16$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id without iterations",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$m=65536,t=0,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id without parallelism",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$m=65536,t=2,p=0$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id unreadable parameters",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$memory=65536$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error argon2id salt not in base64",
+			plainPassword:  "password",
+			hashedPassword: "$argon2id$v=19$m=65536,t=2,p=1$some salt$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrMalformedPasswordHash,
+		},
+		{
+			name:           "error bcrypt truncated",
+			plainPassword:  "password",
+			hashedPassword: legacyHash[:30],
+			wantErr:        bcrypt.ErrHashTooShort,
+		},
+		{
+			name:           "error unsupported algorithm",
+			plainPassword:  "password",
+			hashedPassword: "$argon2i$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			wantErr:        error_list.ErrUnsupportedPasswordHashAlgorithm,
+		},
+		{
+			name:           "error plain text",
+			plainPassword:  "password",
+			hashedPassword: "password",
+			wantErr:        error_list.ErrUnsupportedPasswordHashAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := hasher.VerifyPassword(context.TODO(), tt.plainPassword, tt.hashedPassword)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_passwordHasher_NeedsRehash(t *testing.T) {
+	argon2idHasher, err := NewPasswordHasher(PasswordHasherOptions{
+		Algorithm:           constant.PasswordHashAlgorithmArgon2id,
+		Argon2idMemory:      1024,
+		Argon2idIterations:  2,
+		Argon2idParallelism: 1,
+	})
+	assert.NoError(t, err)
+
+	bcryptHasher, err := NewPasswordHasher(PasswordHasherOptions{
+		Algorithm:  constant.PasswordHashAlgorithmBcrypt,
+		BcryptCost: bcrypt.MinCost + 1,
+	})
+	assert.NoError(t, err)
+
+	current, err := argon2idHasher.HashPassword(context.TODO(), "password")
+	assert.NoError(t, err)
+
+	withParams := func(params string) string {
+		return strings.Replace(current, "m=1024,t=2,p=1", params, 1)
+	}
+
+	tests := []struct {
+		name           string
+		hasher         passwordHasher
+		hashedPassword string
+		want           bool
+	}{
+		{
+			name:           "argon2id same parameters",
+			hasher:         argon2idHasher,
+			hashedPassword: current,
+			want:           false,
+		},
+		{
+			name:           "argon2id stronger parameters",
+			hasher:         argon2idHasher,
+			hashedPassword: withParams("m=2048,t=3,p=1"),
+			want:           false,
+		},
+		{
+			name:           "argon2id other parallelism",
+			hasher:         argon2idHasher,
+			hashedPassword: withParams("m=1024,t=2,p=4"),
+			want:           false,
+		},
+		{
+			name:           "argon2id less memory",
+			hasher:         argon2idHasher,
+			hashedPassword: withParams("m=512,t=2,p=1"),
+			want:           true,
+		},
+		{
+			name:           "argon2id fewer iterations",
+			hasher:         argon2idHasher,
+			hashedPassword: withParams("m=1024,t=1,p=1"),
+			want:           true,
+		},
+		{
+			name:           "argon2id short salt",
+			hasher:         argon2idHasher,
+			hashedPassword: referenceArgon2idHash,
+			want:           true,
+		},
+		{
+			name:           "argon2id short key",
+			hasher:         argon2idHasher,
+			hashedPassword: current[:len(current)-11],
+			want:           true,
+		},
+		{
+			name:           "bcrypt hash when argon2id is configured",
+			hasher:         argon2idHasher,
+			hashedPassword: bcryptHash(t, "password", bcrypt.MinCost),
+			want:           true,
+		},
+		{
+			name:           "bcrypt lower cost",
+			hasher:         bcryptHasher,
+			hashedPassword: bcryptHash(t, "password", bcrypt.MinCost),
+			want:           true,
+		},
+		{
+			name:           "bcrypt same cost",
+			hasher:         bcryptHasher,
+			hashedPassword: bcryptHash(t, "password", bcrypt.MinCost+1),
+			want:           false,
+		},
+		{
+			name:           "argon2id hash when bcrypt is configured",
+			hasher:         bcryptHasher,
+			hashedPassword: current,
+			want:           true,
+		},
+		{
+			name:           "malformed argon2id",
+			hasher:         argon2idHasher,
+			hashedPassword: "$argon2id$v=19$m=1024,t=2,p=1$c29tZXNhbHQ",
+			want:           false,
+		},
+		{
+			name:           "unsupported algorithm",
+			hasher:         argon2idHasher,
+			hashedPassword: "password",
+			want:           false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.hasher.NeedsRehash(context.TODO(), tt.hashedPassword))
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the set of rules new passwords are checked against.
+// Loaded from a JSON file, fields left out keep their default.
+type PasswordPolicy struct {
+	MinLength int `json:"min_length"`
+	MaxLength int `json:"max_length"`
+
+	RequireUpper   bool `json:"require_upper"`
+	RequireLower   bool `json:"require_lower"`
+	RequireDigit   bool `json:"require_digit"`
+	RequireSpecial bool `json:"require_special"`
+	// SpecialCharacters are the only characters allowed besides letters and
+	// digits
+	SpecialCharacters string `json:"special_characters"`
+
+	// ForbidPersonalInfo rejects passwords containing the name or phone
+	// number of their owner
+	ForbidPersonalInfo  bool     `json:"forbid_personal_info"`
+	ForbiddenSubstrings []string `json:"forbidden_substrings"`
+
+	// MinEntropyBits is checked against a rough estimate, the length times
+	// the bits per character of the character classes used
+	MinEntropyBits float64 `json:"min_entropy_bits"`
+}
+
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:          constant.DefaultPasswordMinLength,
+		MaxLength:          constant.DefaultPasswordMaxLength,
+		RequireUpper:       true,
+		RequireDigit:       true,
+		RequireSpecial:     true,
+		SpecialCharacters:  constant.DefaultPasswordSpecialCharacters,
+		ForbidPersonalInfo: true,
+		MinEntropyBits:     constant.DefaultPasswordMinEntropyBits,
+	}
+}
+
+func LoadPasswordPolicy(path string) (PasswordPolicy, error) {
+	policy := DefaultPasswordPolicy()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+
+	err = json.Unmarshal(content, &policy)
+	if err != nil {
+		return policy, err
+	}
+
+	if policy.MinLength < 1 || policy.MaxLength < policy.MinLength || policy.MinEntropyBits < 0 {
+		return policy, error_list.ErrInvalidPasswordPolicy
+	}
+
+	return policy, nil
+}
+
+// Check returns every rule the password breaks, personalInfo being the
+// name, phone number and such of its owner.
+func (policy PasswordPolicy) Check(password string, personalInfo ...string) []string {
+	var violations []string
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", policy.MinLength))
+	}
+	if length > policy.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial, hasOther bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsDigit(char):
+			hasDigit = true
+		case strings.ContainsRune(policy.SpecialCharacters, char):
+			hasSpecial = true
+		default:
+			hasOther = true
+		}
+	}
+
+	if policy.RequireUpper && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if policy.RequireLower && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if policy.RequireSpecial && !hasSpecial {
+		violations = append(violations, fmt.Sprintf("must contain one of %s", policy.SpecialCharacters))
+	}
+	if hasOther {
+		violations = append(violations, fmt.Sprintf("must only contain letters, digits and %s", policy.SpecialCharacters))
+	}
+
+	lowered := strings.ToLower(password)
+	for _, substring := range policy.ForbiddenSubstrings {
+		if substring != "" && strings.Contains(lowered, strings.ToLower(substring)) {
+			violations = append(violations, fmt.Sprintf("must not contain %q", substring))
+		}
+	}
+
+	if policy.ForbidPersonalInfo {
+		for _, token := range personalInfoTokens(personalInfo) {
+			if strings.Contains(lowered, token) {
+				violations = append(violations, "must not contain your name or phone number")
+				break
+			}
+		}
+	}
+
+	if policy.MinEntropyBits > 0 {
+		pool := 0
+		if hasUpper {
+			pool += 26
+		}
+		if hasLower {
+			pool += 26
+		}
+		if hasDigit {
+			pool += 10
+		}
+		if hasSpecial {
+			pool += utf8.RuneCountInString(policy.SpecialCharacters)
+		}
+
+		if pool < 2 || float64(length)*math.Log2(float64(pool)) < policy.MinEntropyBits {
+			violations = append(violations, "is too easy to guess, make it longer or mix more kinds of characters")
+		}
+	}
+
+	return violations
+}
+
+// personalInfoTokens splits the personal info into the lowercased words and
+// numbers a password could reuse. Numbers also give their last digits, the
+// part of a phone number that is usually reused without the country code.
+func personalInfoTokens(personalInfo []string) []string {
+	var tokens []string
+
+	for _, info := range personalInfo {
+		words := strings.FieldsFunc(strings.ToLower(info), func(char rune) bool {
+			return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+		})
+
+		for _, word := range words {
+			if utf8.RuneCountInString(word) < constant.PasswordPersonalInfoMinTokenLength {
+				continue
+			}
+			tokens = append(tokens, word)
+
+			if len(word) > constant.PasswordPersonalInfoDigits && strings.IndexFunc(word, func(char rune) bool {
+				return !unicode.IsDigit(char)
+			}) < 0 {
+				tokens = append(tokens, word[len(word)-constant.PasswordPersonalInfoDigits:])
+			}
+		}
+	}
+
+	return tokens
+}
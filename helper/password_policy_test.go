@@ -0,0 +1,260 @@
+package helper
+
+import (
+	"os"
+	"path/filepath"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    func(policy *PasswordPolicy)
+		wantErr error
+	}{
+		{
+			name:    "success defaults kept",
+			content: `{}`,
+			want:    func(policy *PasswordPolicy) {},
+			wantErr: nil,
+		},
+		{
+			name:    "success fields given",
+			content: `{"min_length": 12, "require_lower": true, "forbidden_substrings": ["sawit"]}`,
+			want: func(policy *PasswordPolicy) {
+				policy.MinLength = 12
+				policy.RequireLower = true
+				policy.ForbiddenSubstrings = []string{"sawit"}
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "error without minimum length",
+			content: `{"min_length": 0}`,
+			wantErr: error_list.ErrInvalidPasswordPolicy,
+		},
+		{
+			name:    "error maximum below the minimum length",
+			content: `{"min_length": 12, "max_length": 8}`,
+			wantErr: error_list.ErrInvalidPasswordPolicy,
+		},
+		{
+			name:    "error negative entropy",
+			content: `{"min_entropy_bits": -1}`,
+			wantErr: error_list.ErrInvalidPasswordPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "password-policy.json")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := LoadPasswordPolicy(path)
+			assert.Equal(t, tt.wantErr, err)
+
+			if tt.want != nil {
+				want := DefaultPasswordPolicy()
+				tt.want(&want)
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+
+	t.Run("error not json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "password-policy.json")
+		assert.NoError(t, os.WriteFile(path, []byte("min_length: 12"), 0600))
+
+		_, err := LoadPasswordPolicy(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	// policy checks the length only, each case turns on the rule it is about
+	policy := func(fn func(policy *PasswordPolicy)) PasswordPolicy {
+		p := PasswordPolicy{
+			MinLength:         1,
+			MaxLength:         64,
+			SpecialCharacters: constant.DefaultPasswordSpecialCharacters,
+		}
+		fn(&p)
+
+		return p
+	}
+
+	tests := []struct {
+		name         string
+		policy       PasswordPolicy
+		password     string
+		personalInfo []string
+		want         []string
+	}{
+		{
+			name:     "success rules off",
+			policy:   policy(func(p *PasswordPolicy) {}),
+			password: "a",
+			want:     nil,
+		},
+		{
+			name:     "min length",
+			policy:   policy(func(p *PasswordPolicy) { p.MinLength = 10 }),
+			password: "Ab1!",
+			want:     []string{"must be at least 10 characters"},
+		},
+		{
+			name:     "min length counted in characters",
+			policy:   policy(func(p *PasswordPolicy) { p.MinLength = 4 }),
+			password: "Äbçd",
+			want:     nil,
+		},
+		{
+			name:     "max length",
+			policy:   policy(func(p *PasswordPolicy) { p.MaxLength = 8 }),
+			password: "Abcdefgh1!",
+			want:     []string{"must be at most 8 characters"},
+		},
+		{
+			name:     "require upper",
+			policy:   policy(func(p *PasswordPolicy) { p.RequireUpper = true }),
+			password: "abc1!",
+			want:     []string{"must contain an uppercase letter"},
+		},
+		{
+			name:     "require lower",
+			policy:   policy(func(p *PasswordPolicy) { p.RequireLower = true }),
+			password: "ABC1!",
+			want:     []string{"must contain a lowercase letter"},
+		},
+		{
+			name:     "require digit",
+			policy:   policy(func(p *PasswordPolicy) { p.RequireDigit = true }),
+			password: "Abc!",
+			want:     []string{"must contain a digit"},
+		},
+		{
+			name:     "require special",
+			policy:   policy(func(p *PasswordPolicy) { p.RequireSpecial = true }),
+			password: "Abc1",
+			want:     []string{"must contain one of " + constant.DefaultPasswordSpecialCharacters},
+		},
+		{
+			name:     "character outside the allowed ones",
+			policy:   policy(func(p *PasswordPolicy) {}),
+			password: "Abc 1!",
+			want:     []string{"must only contain letters, digits and " + constant.DefaultPasswordSpecialCharacters},
+		},
+		{
+			name:     "forbidden substring in another case",
+			policy:   policy(func(p *PasswordPolicy) { p.ForbiddenSubstrings = []string{"", "sawit"} }),
+			password: "MySawitPass1!",
+			want:     []string{`must not contain "sawit"`},
+		},
+		{
+			name:         "personal info name",
+			policy:       policy(func(p *PasswordPolicy) { p.ForbidPersonalInfo = true }),
+			password:     "Santoso1!",
+			personalInfo: []string{"Budi Santoso"},
+			want:         []string{"must not contain your name or phone number"},
+		},
+		{
+			name:         "personal info last digits of the phone number",
+			policy:       policy(func(p *PasswordPolicy) { p.ForbidPersonalInfo = true }),
+			password:     "Pass456789!",
+			personalInfo: []string{"+628123456789"},
+			want:         []string{"must not contain your name or phone number"},
+		},
+		{
+			name:         "personal info reported once",
+			policy:       policy(func(p *PasswordPolicy) { p.ForbidPersonalInfo = true }),
+			password:     "BudiSantoso1!",
+			personalInfo: []string{"Budi Santoso"},
+			want:         []string{"must not contain your name or phone number"},
+		},
+		{
+			name:         "personal info words too short to count",
+			policy:       policy(func(p *PasswordPolicy) { p.ForbidPersonalInfo = true }),
+			password:     "Albert1!",
+			personalInfo: []string{"Al Bo"},
+			want:         nil,
+		},
+		{
+			name:         "personal info allowed",
+			policy:       policy(func(p *PasswordPolicy) {}),
+			password:     "Santoso1!",
+			personalInfo: []string{"Budi Santoso"},
+			want:         nil,
+		},
+		{
+			name:     "entropy too low",
+			policy:   policy(func(p *PasswordPolicy) { p.MinEntropyBits = 50 }),
+			password: "abcdefgh",
+			want:     []string{"is too easy to guess, make it longer or mix more kinds of characters"},
+		},
+		{
+			name:     "entropy enough from length alone",
+			policy:   policy(func(p *PasswordPolicy) { p.MinEntropyBits = 50 }),
+			password: "abcdefghijk",
+			want:     nil,
+		},
+		{
+			name: "entropy of a single possible character",
+			policy: policy(func(p *PasswordPolicy) {
+				p.SpecialCharacters = "!"
+				p.MinEntropyBits = 1
+			}),
+			password: "!!!!!!!!!!!!",
+			want:     []string{"is too easy to guess, make it longer or mix more kinds of characters"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Check(tt.password, tt.personalInfo...))
+		})
+	}
+}
+
+func Test_validatorHelper_ValidatePassword(t *testing.T) {
+	vald := NewValidatorHelper(ValidatorHelperOptions{
+		PasswordPolicy: DefaultPasswordPolicy(),
+	})
+
+	tests := []struct {
+		name         string
+		password     string
+		personalInfo []string
+		wantErr      error
+	}{
+		{
+			name:         "success",
+			password:     "Kebun-Sawit-2024",
+			personalInfo: []string{"Budi Santoso", "+628123456789"},
+			wantErr:      nil,
+		},
+		{
+			name:         "error every violation at once",
+			password:     "budi",
+			personalInfo: []string{"Budi Santoso", "+628123456789"},
+			wantErr: error_list.PasswordPolicyError{
+				Violations: []string{
+					"must be at least 10 characters",
+					"must contain an uppercase letter",
+					"must contain a digit",
+					"must contain one of " + constant.DefaultPasswordSpecialCharacters,
+					"must not contain your name or phone number",
+					"is too easy to guess, make it longer or mix more kinds of characters",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vald.ValidatePassword(tt.password, tt.personalInfo...)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"sawitpro/error_list"
+)
+
+// secretBox encrypts secrets kept at rest with AES-GCM, under a key derived
+// from a configured secret. The nonce is stored in front of the ciphertext.
+type secretBox struct {
+	aead cipher.AEAD
+}
+
+func newSecretBox(secret string) (secretBox, error) {
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return secretBox{}, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return secretBox{}, err
+	}
+
+	return secretBox{
+		aead: aead,
+	}, nil
+}
+
+func (box secretBox) seal(plain []byte) (string, error) {
+	nonce := make([]byte, box.aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := box.aead.Seal(nonce, nonce, plain, nil)
+
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (box secretBox) open(encrypted string) ([]byte, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := box.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, error_list.ErrMalformedSecret
+	}
+
+	return box.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
@@ -0,0 +1,127 @@
+package helper
+
+import (
+	"encoding/base64"
+	"sawitpro/error_list"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_secretBox_open(t *testing.T) {
+	box, err := newSecretBox("secret")
+	assert.NoError(t, err)
+
+	otherBox, err := newSecretBox("another secret")
+	assert.NoError(t, err)
+
+	sealed, err := box.seal([]byte("plain"))
+	assert.NoError(t, err)
+
+	// tamper flips a bit of the decoded sealed secret at index, a negative
+	// one counting from its end
+	tamper := func(index int) string {
+		raw, err := base64.StdEncoding.DecodeString(sealed)
+		assert.NoError(t, err)
+
+		if index < 0 {
+			index += len(raw)
+		}
+		raw[index] ^= 0x01
+
+		return base64.StdEncoding.EncodeToString(raw)
+	}
+
+	truncate := func(size int) string {
+		raw, err := base64.StdEncoding.DecodeString(sealed)
+		assert.NoError(t, err)
+
+		return base64.StdEncoding.EncodeToString(raw[:size])
+	}
+
+	tests := []struct {
+		name      string
+		box       secretBox
+		encrypted string
+		want      []byte
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name:      "success",
+			box:       box,
+			encrypted: sealed,
+			want:      []byte("plain"),
+		},
+		{
+			name:      "error tampered nonce",
+			box:       box,
+			encrypted: tamper(0),
+			wantErr:   true,
+		},
+		{
+			name:      "error tampered ciphertext",
+			box:       box,
+			encrypted: tamper(box.aead.NonceSize()),
+			wantErr:   true,
+		},
+		{
+			name:      "error tampered tag",
+			box:       box,
+			encrypted: tamper(-1),
+			wantErr:   true,
+		},
+		{
+			name:      "error tag cut off",
+			box:       box,
+			encrypted: truncate(box.aead.NonceSize() + len("plain")),
+			wantErr:   true,
+		},
+		{
+			name:      "error shorter than the nonce",
+			box:       box,
+			encrypted: truncate(box.aead.NonceSize() - 1),
+			wantErr:   true,
+			wantErrIs: error_list.ErrMalformedSecret,
+		},
+		{
+			name:      "error not base64",
+			box:       box,
+			encrypted: "not base64!",
+			wantErr:   true,
+		},
+		{
+			name:      "error sealed under another secret",
+			box:       otherBox,
+			encrypted: sealed,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.box.open(tt.encrypted)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.wantErrIs != nil {
+				assert.Equal(t, tt.wantErrIs, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_secretBox_seal(t *testing.T) {
+	box, err := newSecretBox("secret")
+	assert.NoError(t, err)
+
+	sealed, err := box.seal([]byte("plain"))
+	assert.NoError(t, err)
+
+	// a fresh nonce every time
+	again, err := box.seal([]byte("plain"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, sealed, again)
+
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	assert.NoError(t, err)
+	assert.Len(t, raw, box.aead.NonceSize()+len("plain")+box.aead.Overhead())
+}
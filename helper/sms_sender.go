@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// logSMSSender writes messages to the standard logger instead of sending
+// them, for local development.
+type logSMSSender struct{}
+
+func NewLogSMSSender() logSMSSender {
+	return logSMSSender{}
+}
+
+func (sender logSMSSender) SendSMS(ctx context.Context, phoneNumber string, message string) error {
+	log.Printf("sms to %s: %s\n", phoneNumber, message)
+
+	return nil
+}
+
+// fileSMSSender appends messages as JSON lines to a file instead of sending
+// them, so tests and scripts can read the codes back.
+type fileSMSSender struct {
+	mu   *sync.Mutex
+	path string
+}
+
+type outboxMessage struct {
+	PhoneNumber string    `json:"phone_number"`
+	Message     string    `json:"message"`
+	SentAt      time.Time `json:"sent_at"`
+}
+
+func NewFileSMSSender(path string) fileSMSSender {
+	return fileSMSSender{
+		mu:   &sync.Mutex{},
+		path: path,
+	}
+}
+
+func (sender fileSMSSender) SendSMS(ctx context.Context, phoneNumber string, message string) error {
+	line, err := json.Marshal(outboxMessage{
+		PhoneNumber: phoneNumber,
+		Message:     message,
+		SentAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	file, err := os.OpenFile(sender.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,131 @@
+package helper
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var totpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpHelper implements the time-based one-time passwords of RFC 6238 with
+// the parameters authenticator apps assume by default: HMAC-SHA1, six digits
+// and a thirty second period.
+type totpHelper struct {
+	issuer string
+	box    secretBox
+}
+
+type TOTPHelperOptions struct {
+	Issuer string
+	// Secret encrypts the TOTP secrets before they are stored
+	Secret string
+}
+
+func NewTOTPHelper(opts TOTPHelperOptions) (totpHelper, error) {
+	if opts.Secret == "" {
+		return totpHelper{}, error_list.ErrMissingKeySecret
+	}
+
+	box, err := newSecretBox(opts.Secret)
+	if err != nil {
+		return totpHelper{}, err
+	}
+
+	return totpHelper{
+		issuer: opts.Issuer,
+		box:    box,
+	}, nil
+}
+
+// GenerateSecret returns a random secret in the unpadded base32 form
+// authenticator apps accept for manual entry.
+func (hlp totpHelper) GenerateSecret(ctx context.Context) (string, error) {
+	buf := make([]byte, constant.TOTPSecretSize)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	return totpSecretEncoding.EncodeToString(buf), nil
+}
+
+func (hlp totpHelper) EncryptSecret(ctx context.Context, secret string) (string, error) {
+	return hlp.box.seal([]byte(secret))
+}
+
+func (hlp totpHelper) DecryptSecret(ctx context.Context, encrypted string) (string, error) {
+	secret, err := hlp.box.open(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret), nil
+}
+
+// KeyURI returns the otpauth:// URI authenticator apps read from a QR code.
+func (hlp totpHelper) KeyURI(ctx context.Context, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", hlp.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(constant.TOTPDigits))
+	query.Set("period", strconv.Itoa(int(constant.TOTPPeriod/time.Second)))
+
+	// the plus of a phone number is escaped too, some apps read it as a space
+	label := strings.ReplaceAll(url.PathEscape(hlp.issuer+":"+accountName), "+", "%2B")
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// MatchCode looks for the time step, within the drift window around now, the
+// code was generated for. Callers remember the step to refuse the code when
+// it is presented again.
+func (hlp totpHelper) MatchCode(ctx context.Context, secret string, code string, now time.Time) (int64, bool) {
+	key, err := totpSecretEncoding.DecodeString(secret)
+	if err != nil || len(code) != constant.TOTPDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(constant.TOTPPeriod/time.Second)
+
+	for step := current - constant.TOTPDriftSteps; step <= current+constant.TOTPDriftSteps; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < constant.TOTPDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", constant.TOTPDigits, value%modulo)
+}
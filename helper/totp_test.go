@@ -0,0 +1,172 @@
+package helper
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/error_list"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfc6238Key is the HMAC-SHA1 key of the test vectors of RFC 6238, appendix B.
+var rfc6238Key = []byte("12345678901234567890")
+
+func newTestTOTPHelper(t *testing.T) totpHelper {
+	t.Helper()
+
+	hlp, err := NewTOTPHelper(TOTPHelperOptions{
+		Issuer: constant.TOTPIssuer,
+		Secret: "secret",
+	})
+	assert.NoError(t, err)
+
+	return hlp
+}
+
+func TestNewTOTPHelper(t *testing.T) {
+	_, err := NewTOTPHelper(TOTPHelperOptions{Issuer: constant.TOTPIssuer})
+	assert.Equal(t, error_list.ErrMissingKeySecret, err)
+}
+
+func Test_totpCode(t *testing.T) {
+	// the eight digit codes of the RFC cut down to the six digits we use
+	tests := []struct {
+		name string
+		time int64
+		want string
+	}{
+		{name: "59", time: 59, want: "287082"},
+		{name: "1111111109", time: 1111111109, want: "081804"},
+		{name: "1111111111", time: 1111111111, want: "050471"},
+		{name: "1234567890", time: 1234567890, want: "005924"},
+		{name: "2000000000", time: 2000000000, want: "279037"},
+		{name: "20000000000", time: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, totpCode(rfc6238Key, tt.time/int64(constant.TOTPPeriod/time.Second)))
+		})
+	}
+}
+
+func Test_totpHelper_MatchCode(t *testing.T) {
+	hlp := newTestTOTPHelper(t)
+	secret := totpSecretEncoding.EncodeToString(rfc6238Key)
+
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / int64(constant.TOTPPeriod/time.Second)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOk   bool
+	}{
+		{
+			name:     "success current step",
+			secret:   secret,
+			code:     "050471",
+			wantStep: step,
+			wantOk:   true,
+		},
+		{
+			name:     "success one step behind",
+			secret:   secret,
+			code:     totpCode(rfc6238Key, step-1),
+			wantStep: step - 1,
+			wantOk:   true,
+		},
+		{
+			name:     "success one step ahead",
+			secret:   secret,
+			code:     totpCode(rfc6238Key, step+1),
+			wantStep: step + 1,
+			wantOk:   true,
+		},
+		{
+			name:   "error two steps behind",
+			secret: secret,
+			code:   totpCode(rfc6238Key, step-2),
+		},
+		{
+			name:   "error two steps ahead",
+			secret: secret,
+			code:   totpCode(rfc6238Key, step+2),
+		},
+		{
+			name:   "error wrong code",
+			secret: secret,
+			code:   "000000",
+		},
+		{
+			name:   "error eight digits of the RFC",
+			secret: secret,
+			code:   "14050471",
+		},
+		{
+			name:   "error secret not in base32",
+			secret: "not base32!",
+			code:   "050471",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOk := hlp.MatchCode(context.TODO(), tt.secret, tt.code, now)
+			assert.Equal(t, tt.wantStep, gotStep)
+			assert.Equal(t, tt.wantOk, gotOk)
+		})
+	}
+}
+
+func Test_totpHelper_GenerateSecret(t *testing.T) {
+	hlp := newTestTOTPHelper(t)
+
+	secret, err := hlp.GenerateSecret(context.TODO())
+	assert.NoError(t, err)
+
+	key, err := totpSecretEncoding.DecodeString(secret)
+	assert.NoError(t, err)
+	assert.Len(t, key, constant.TOTPSecretSize)
+
+	other, err := hlp.GenerateSecret(context.TODO())
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+}
+
+func Test_totpHelper_EncryptSecret(t *testing.T) {
+	hlp := newTestTOTPHelper(t)
+	secret := totpSecretEncoding.EncodeToString(rfc6238Key)
+
+	encrypted, err := hlp.EncryptSecret(context.TODO(), secret)
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, secret)
+
+	// a fresh nonce every time
+	again, err := hlp.EncryptSecret(context.TODO(), secret)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	got, err := hlp.DecryptSecret(context.TODO(), encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, got)
+
+	// the secret can only be read back with the key it was encrypted with
+	otherHlp, err := NewTOTPHelper(TOTPHelperOptions{
+		Issuer: constant.TOTPIssuer,
+		Secret: "another secret",
+	})
+	assert.NoError(t, err)
+
+	_, err = otherHlp.DecryptSecret(context.TODO(), encrypted)
+	assert.Error(t, err)
+}
+
+func Test_totpHelper_KeyURI(t *testing.T) {
+	hlp := newTestTOTPHelper(t)
+
+	got := hlp.KeyURI(context.TODO(), "+628123456789", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	assert.Equal(t, "otpauth://totp/SawitPro:%2B628123456789?algorithm=SHA1&digits=6&issuer=SawitPro&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", got)
+}
@@ -1,31 +1,26 @@
 package helper
 
 import (
-	"sawitpro/constant"
-	"unicode"
+	"sawitpro/error_list"
 
 	"github.com/go-playground/validator"
 )
 
-const (
-	anyAlphaCapitalTag = "anyAlphaCapital"
-	anyNumericTag      = "anyNumeric"
-	anySpecialCharTag  = "anySpecialChar"
-)
-
 type validatorHelper struct {
-	goValidator *validator.Validate
+	goValidator    *validator.Validate
+	passwordPolicy PasswordPolicy
 }
 
-func NewValidatorHelper() validatorHelper {
-	goValidator := validator.New()
-
-	goValidator.RegisterValidation(anyAlphaCapitalTag, anyAlphaCapital)
-	goValidator.RegisterValidation(anyNumericTag, anyNumeric)
-	goValidator.RegisterValidation(anySpecialCharTag, anySpecialChar)
+type ValidatorHelperOptions struct {
+	// PasswordPolicy is what ValidatePassword checks against, callers that
+	// only validate structs can leave it empty.
+	PasswordPolicy PasswordPolicy
+}
 
+func NewValidatorHelper(opts ValidatorHelperOptions) validatorHelper {
 	return validatorHelper{
-		goValidator: goValidator,
+		goValidator:    validator.New(),
+		passwordPolicy: opts.PasswordPolicy,
 	}
 }
 
@@ -33,42 +28,15 @@ func (vald validatorHelper) ValidateStruct(s interface{}) error {
 	return vald.goValidator.Struct(s)
 }
 
-func anyAlphaCapital(fl validator.FieldLevel) bool {
-
-	str := fl.Field().String()
-
-	for _, char := range str {
-		if unicode.IsUpper(char) {
-			return true
-		}
-	}
-
-	return false
-}
-
-func anyNumeric(fl validator.FieldLevel) bool {
-
-	str := fl.Field().String()
-
-	for _, char := range str {
-		if unicode.IsDigit(char) {
-			return true
-		}
-	}
-
-	return false
-}
-
-func anySpecialChar(fl validator.FieldLevel) bool {
-
-	str := fl.Field().String()
-
-	for _, char := range str {
-		_, exists := constant.SpecialCharacter[char]
-		if exists {
-			return true
+// ValidatePassword checks a new password against the password policy and
+// reports every rule it breaks at once, so the user can fix them together.
+func (vald validatorHelper) ValidatePassword(password string, personalInfo ...string) error {
+	violations := vald.passwordPolicy.Check(password, personalInfo...)
+	if len(violations) > 0 {
+		return error_list.PasswordPolicyError{
+			Violations: violations,
 		}
 	}
 
-	return false
+	return nil
 }
@@ -6,8 +6,12 @@ package mocks
 
 import (
 	context "context"
+	crypto "crypto"
 	reflect "reflect"
+	entity "sawitpro/entity"
+	time "time"
 
+	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,19 +38,108 @@ func (m *MockAuthHelperInterface) EXPECT() *MockAuthHelperInterfaceMockRecorder
 	return m.recorder
 }
 
+// CodeChallenge mocks base method.
+func (m *MockAuthHelperInterface) CodeChallenge(ctx context.Context, codeVerifier string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CodeChallenge", ctx, codeVerifier)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CodeChallenge indicates an expected call of CodeChallenge.
+func (mr *MockAuthHelperInterfaceMockRecorder) CodeChallenge(ctx, codeVerifier interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CodeChallenge", reflect.TypeOf((*MockAuthHelperInterface)(nil).CodeChallenge), ctx, codeVerifier)
+}
+
+// GenerateIDToken mocks base method.
+func (m *MockAuthHelperInterface) GenerateIDToken(ctx context.Context, request entity.GenerateIDTokenRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateIDToken", ctx, request)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateIDToken indicates an expected call of GenerateIDToken.
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateIDToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateIDToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateIDToken), ctx, request)
+}
+
+// GenerateOneTimeCode mocks base method.
+func (m *MockAuthHelperInterface) GenerateOneTimeCode(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateOneTimeCode", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateOneTimeCode indicates an expected call of GenerateOneTimeCode.
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateOneTimeCode(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateOneTimeCode", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateOneTimeCode), ctx)
+}
+
+// GenerateRecoveryCode mocks base method.
+func (m *MockAuthHelperInterface) GenerateRecoveryCode(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRecoveryCode", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRecoveryCode indicates an expected call of GenerateRecoveryCode.
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateRecoveryCode(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRecoveryCode", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateRecoveryCode), ctx)
+}
+
+// GenerateRefreshToken mocks base method.
+func (m *MockAuthHelperInterface) GenerateRefreshToken(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRefreshToken", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRefreshToken indicates an expected call of GenerateRefreshToken.
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateRefreshToken(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRefreshToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateRefreshToken), ctx)
+}
+
 // GenerateToken mocks base method.
-func (m *MockAuthHelperInterface) GenerateToken(ctx context.Context, profileId string) (string, error) {
+func (m *MockAuthHelperInterface) GenerateToken(ctx context.Context, request entity.GenerateTokenRequest) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateToken", ctx, profileId)
+	ret := m.ctrl.Call(m, "GenerateToken", ctx, request)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateToken indicates an expected call of GenerateToken.
-func (mr *MockAuthHelperInterfaceMockRecorder) GenerateToken(ctx, profileId interface{}) *gomock.Call {
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateToken), ctx, request)
+}
+
+// GenerateUserCode mocks base method.
+func (m *MockAuthHelperInterface) GenerateUserCode(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateUserCode", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateUserCode indicates an expected call of GenerateUserCode.
+func (mr *MockAuthHelperInterfaceMockRecorder) GenerateUserCode(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateToken), ctx, profileId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateUserCode", reflect.TypeOf((*MockAuthHelperInterface)(nil).GenerateUserCode), ctx)
 }
 
 // HashPassword mocks base method.
@@ -64,6 +157,49 @@ func (mr *MockAuthHelperInterfaceMockRecorder) HashPassword(ctx, password interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockAuthHelperInterface)(nil).HashPassword), ctx, password)
 }
 
+// HashToken mocks base method.
+func (m *MockAuthHelperInterface) HashToken(ctx context.Context, token string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashToken", ctx, token)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// HashToken indicates an expected call of HashToken.
+func (mr *MockAuthHelperInterfaceMockRecorder) HashToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).HashToken), ctx, token)
+}
+
+// PasswordNeedsRehash mocks base method.
+func (m *MockAuthHelperInterface) PasswordNeedsRehash(ctx context.Context, hashedPassword string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PasswordNeedsRehash", ctx, hashedPassword)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// PasswordNeedsRehash indicates an expected call of PasswordNeedsRehash.
+func (mr *MockAuthHelperInterfaceMockRecorder) PasswordNeedsRehash(ctx, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PasswordNeedsRehash", reflect.TypeOf((*MockAuthHelperInterface)(nil).PasswordNeedsRehash), ctx, hashedPassword)
+}
+
+// VerifyIDToken mocks base method.
+func (m *MockAuthHelperInterface) VerifyIDToken(ctx context.Context, token string) (entity.IDTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyIDToken", ctx, token)
+	ret0, _ := ret[0].(entity.IDTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyIDToken indicates an expected call of VerifyIDToken.
+func (mr *MockAuthHelperInterfaceMockRecorder) VerifyIDToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyIDToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).VerifyIDToken), ctx, token)
+}
+
 // VerifyPassword mocks base method.
 func (m *MockAuthHelperInterface) VerifyPassword(ctx context.Context, plainPassword, hashedPassword string) error {
 	m.ctrl.T.Helper()
@@ -79,10 +215,10 @@ func (mr *MockAuthHelperInterfaceMockRecorder) VerifyPassword(ctx, plainPassword
 }
 
 // VerifyToken mocks base method.
-func (m *MockAuthHelperInterface) VerifyToken(ctx context.Context, token string) (string, error) {
+func (m *MockAuthHelperInterface) VerifyToken(ctx context.Context, token string) (entity.TokenClaims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "VerifyToken", ctx, token)
-	ret0, _ := ret[0].(string)
+	ret0, _ := ret[0].(entity.TokenClaims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -93,6 +229,244 @@ func (mr *MockAuthHelperInterfaceMockRecorder) VerifyToken(ctx, token interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockAuthHelperInterface)(nil).VerifyToken), ctx, token)
 }
 
+// MockPasswordHasherInterface is a mock of PasswordHasherInterface interface.
+type MockPasswordHasherInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordHasherInterfaceMockRecorder
+}
+
+// MockPasswordHasherInterfaceMockRecorder is the mock recorder for MockPasswordHasherInterface.
+type MockPasswordHasherInterfaceMockRecorder struct {
+	mock *MockPasswordHasherInterface
+}
+
+// NewMockPasswordHasherInterface creates a new mock instance.
+func NewMockPasswordHasherInterface(ctrl *gomock.Controller) *MockPasswordHasherInterface {
+	mock := &MockPasswordHasherInterface{ctrl: ctrl}
+	mock.recorder = &MockPasswordHasherInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordHasherInterface) EXPECT() *MockPasswordHasherInterfaceMockRecorder {
+	return m.recorder
+}
+
+// HashPassword mocks base method.
+func (m *MockPasswordHasherInterface) HashPassword(ctx context.Context, password string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HashPassword", ctx, password)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HashPassword indicates an expected call of HashPassword.
+func (mr *MockPasswordHasherInterfaceMockRecorder) HashPassword(ctx, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HashPassword", reflect.TypeOf((*MockPasswordHasherInterface)(nil).HashPassword), ctx, password)
+}
+
+// NeedsRehash mocks base method.
+func (m *MockPasswordHasherInterface) NeedsRehash(ctx context.Context, hashedPassword string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NeedsRehash", ctx, hashedPassword)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NeedsRehash indicates an expected call of NeedsRehash.
+func (mr *MockPasswordHasherInterfaceMockRecorder) NeedsRehash(ctx, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NeedsRehash", reflect.TypeOf((*MockPasswordHasherInterface)(nil).NeedsRehash), ctx, hashedPassword)
+}
+
+// VerifyPassword mocks base method.
+func (m *MockPasswordHasherInterface) VerifyPassword(ctx context.Context, plainPassword, hashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPassword", ctx, plainPassword, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPassword indicates an expected call of VerifyPassword.
+func (mr *MockPasswordHasherInterfaceMockRecorder) VerifyPassword(ctx, plainPassword, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPassword", reflect.TypeOf((*MockPasswordHasherInterface)(nil).VerifyPassword), ctx, plainPassword, hashedPassword)
+}
+
+// MockBreachedPasswordCheckerInterface is a mock of BreachedPasswordCheckerInterface interface.
+type MockBreachedPasswordCheckerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBreachedPasswordCheckerInterfaceMockRecorder
+}
+
+// MockBreachedPasswordCheckerInterfaceMockRecorder is the mock recorder for MockBreachedPasswordCheckerInterface.
+type MockBreachedPasswordCheckerInterfaceMockRecorder struct {
+	mock *MockBreachedPasswordCheckerInterface
+}
+
+// NewMockBreachedPasswordCheckerInterface creates a new mock instance.
+func NewMockBreachedPasswordCheckerInterface(ctrl *gomock.Controller) *MockBreachedPasswordCheckerInterface {
+	mock := &MockBreachedPasswordCheckerInterface{ctrl: ctrl}
+	mock.recorder = &MockBreachedPasswordCheckerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBreachedPasswordCheckerInterface) EXPECT() *MockBreachedPasswordCheckerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// IsBreached mocks base method.
+func (m *MockBreachedPasswordCheckerInterface) IsBreached(ctx context.Context, password string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBreached", ctx, password)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBreached indicates an expected call of IsBreached.
+func (mr *MockBreachedPasswordCheckerInterfaceMockRecorder) IsBreached(ctx, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBreached", reflect.TypeOf((*MockBreachedPasswordCheckerInterface)(nil).IsBreached), ctx, password)
+}
+
+// MockTOTPHelperInterface is a mock of TOTPHelperInterface interface.
+type MockTOTPHelperInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPHelperInterfaceMockRecorder
+}
+
+// MockTOTPHelperInterfaceMockRecorder is the mock recorder for MockTOTPHelperInterface.
+type MockTOTPHelperInterfaceMockRecorder struct {
+	mock *MockTOTPHelperInterface
+}
+
+// NewMockTOTPHelperInterface creates a new mock instance.
+func NewMockTOTPHelperInterface(ctrl *gomock.Controller) *MockTOTPHelperInterface {
+	mock := &MockTOTPHelperInterface{ctrl: ctrl}
+	mock.recorder = &MockTOTPHelperInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPHelperInterface) EXPECT() *MockTOTPHelperInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DecryptSecret mocks base method.
+func (m *MockTOTPHelperInterface) DecryptSecret(ctx context.Context, encrypted string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecryptSecret", ctx, encrypted)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecryptSecret indicates an expected call of DecryptSecret.
+func (mr *MockTOTPHelperInterfaceMockRecorder) DecryptSecret(ctx, encrypted interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecryptSecret", reflect.TypeOf((*MockTOTPHelperInterface)(nil).DecryptSecret), ctx, encrypted)
+}
+
+// EncryptSecret mocks base method.
+func (m *MockTOTPHelperInterface) EncryptSecret(ctx context.Context, secret string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EncryptSecret", ctx, secret)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncryptSecret indicates an expected call of EncryptSecret.
+func (mr *MockTOTPHelperInterfaceMockRecorder) EncryptSecret(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncryptSecret", reflect.TypeOf((*MockTOTPHelperInterface)(nil).EncryptSecret), ctx, secret)
+}
+
+// GenerateSecret mocks base method.
+func (m *MockTOTPHelperInterface) GenerateSecret(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSecret", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSecret indicates an expected call of GenerateSecret.
+func (mr *MockTOTPHelperInterfaceMockRecorder) GenerateSecret(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSecret", reflect.TypeOf((*MockTOTPHelperInterface)(nil).GenerateSecret), ctx)
+}
+
+// KeyURI mocks base method.
+func (m *MockTOTPHelperInterface) KeyURI(ctx context.Context, accountName, secret string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyURI", ctx, accountName, secret)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// KeyURI indicates an expected call of KeyURI.
+func (mr *MockTOTPHelperInterfaceMockRecorder) KeyURI(ctx, accountName, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyURI", reflect.TypeOf((*MockTOTPHelperInterface)(nil).KeyURI), ctx, accountName, secret)
+}
+
+// MatchCode mocks base method.
+func (m *MockTOTPHelperInterface) MatchCode(ctx context.Context, secret, code string, now time.Time) (int64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchCode", ctx, secret, code, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// MatchCode indicates an expected call of MatchCode.
+func (mr *MockTOTPHelperInterfaceMockRecorder) MatchCode(ctx, secret, code, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchCode", reflect.TypeOf((*MockTOTPHelperInterface)(nil).MatchCode), ctx, secret, code, now)
+}
+
+// MockSMSSenderInterface is a mock of SMSSenderInterface interface.
+type MockSMSSenderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSMSSenderInterfaceMockRecorder
+}
+
+// MockSMSSenderInterfaceMockRecorder is the mock recorder for MockSMSSenderInterface.
+type MockSMSSenderInterfaceMockRecorder struct {
+	mock *MockSMSSenderInterface
+}
+
+// NewMockSMSSenderInterface creates a new mock instance.
+func NewMockSMSSenderInterface(ctrl *gomock.Controller) *MockSMSSenderInterface {
+	mock := &MockSMSSenderInterface{ctrl: ctrl}
+	mock.recorder = &MockSMSSenderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSMSSenderInterface) EXPECT() *MockSMSSenderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SendSMS mocks base method.
+func (m *MockSMSSenderInterface) SendSMS(ctx context.Context, phoneNumber, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendSMS", ctx, phoneNumber, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendSMS indicates an expected call of SendSMS.
+func (mr *MockSMSSenderInterfaceMockRecorder) SendSMS(ctx, phoneNumber, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendSMS", reflect.TypeOf((*MockSMSSenderInterface)(nil).SendSMS), ctx, phoneNumber, message)
+}
+
 // MockValidatorHelperInterface is a mock of ValidatorHelperInterface interface.
 type MockValidatorHelperInterface struct {
 	ctrl     *gomock.Controller
@@ -116,6 +490,25 @@ func (m *MockValidatorHelperInterface) EXPECT() *MockValidatorHelperInterfaceMoc
 	return m.recorder
 }
 
+// ValidatePassword mocks base method.
+func (m *MockValidatorHelperInterface) ValidatePassword(password string, personalInfo ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{password}
+	for _, a := range personalInfo {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ValidatePassword", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidatePassword indicates an expected call of ValidatePassword.
+func (mr *MockValidatorHelperInterfaceMockRecorder) ValidatePassword(password interface{}, personalInfo ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{password}, personalInfo...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidatePassword", reflect.TypeOf((*MockValidatorHelperInterface)(nil).ValidatePassword), varargs...)
+}
+
 // ValidateStruct mocks base method.
 func (m *MockValidatorHelperInterface) ValidateStruct(s interface{}) error {
 	m.ctrl.T.Helper()
@@ -129,3 +522,113 @@ func (mr *MockValidatorHelperInterfaceMockRecorder) ValidateStruct(s interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateStruct", reflect.TypeOf((*MockValidatorHelperInterface)(nil).ValidateStruct), s)
 }
+
+// MockKeyRingInterface is a mock of KeyRingInterface interface.
+type MockKeyRingInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyRingInterfaceMockRecorder
+}
+
+// MockKeyRingInterfaceMockRecorder is the mock recorder for MockKeyRingInterface.
+type MockKeyRingInterfaceMockRecorder struct {
+	mock *MockKeyRingInterface
+}
+
+// NewMockKeyRingInterface creates a new mock instance.
+func NewMockKeyRingInterface(ctrl *gomock.Controller) *MockKeyRingInterface {
+	mock := &MockKeyRingInterface{ctrl: ctrl}
+	mock.recorder = &MockKeyRingInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyRingInterface) EXPECT() *MockKeyRingInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Algorithm mocks base method.
+func (m *MockKeyRingInterface) Algorithm(ctx context.Context) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Algorithm", ctx)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Algorithm indicates an expected call of Algorithm.
+func (mr *MockKeyRingInterfaceMockRecorder) Algorithm(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Algorithm", reflect.TypeOf((*MockKeyRingInterface)(nil).Algorithm), ctx)
+}
+
+// GenerateKey mocks base method.
+func (m *MockKeyRingInterface) GenerateKey(ctx context.Context) (entity.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateKey", ctx)
+	ret0, _ := ret[0].(entity.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateKey indicates an expected call of GenerateKey.
+func (mr *MockKeyRingInterfaceMockRecorder) GenerateKey(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateKey", reflect.TypeOf((*MockKeyRingInterface)(nil).GenerateKey), ctx)
+}
+
+// JSONWebKeys mocks base method.
+func (m *MockKeyRingInterface) JSONWebKeys(ctx context.Context) []entity.JSONWebKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JSONWebKeys", ctx)
+	ret0, _ := ret[0].([]entity.JSONWebKey)
+	return ret0
+}
+
+// JSONWebKeys indicates an expected call of JSONWebKeys.
+func (mr *MockKeyRingInterfaceMockRecorder) JSONWebKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JSONWebKeys", reflect.TypeOf((*MockKeyRingInterface)(nil).JSONWebKeys), ctx)
+}
+
+// LoadKeys mocks base method.
+func (m *MockKeyRingInterface) LoadKeys(ctx context.Context, keys []entity.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadKeys", ctx, keys)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadKeys indicates an expected call of LoadKeys.
+func (mr *MockKeyRingInterfaceMockRecorder) LoadKeys(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadKeys", reflect.TypeOf((*MockKeyRingInterface)(nil).LoadKeys), ctx, keys)
+}
+
+// SignToken mocks base method.
+func (m *MockKeyRingInterface) SignToken(ctx context.Context, claims jwt.Claims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignToken", ctx, claims)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignToken indicates an expected call of SignToken.
+func (mr *MockKeyRingInterfaceMockRecorder) SignToken(ctx, claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignToken", reflect.TypeOf((*MockKeyRingInterface)(nil).SignToken), ctx, claims)
+}
+
+// VerificationKey mocks base method.
+func (m *MockKeyRingInterface) VerificationKey(ctx context.Context, keyId, algorithm string) (crypto.PublicKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerificationKey", ctx, keyId, algorithm)
+	ret0, _ := ret[0].(crypto.PublicKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerificationKey indicates an expected call of VerificationKey.
+func (mr *MockKeyRingInterfaceMockRecorder) VerificationKey(ctx, keyId, algorithm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerificationKey", reflect.TypeOf((*MockKeyRingInterface)(nil).VerificationKey), ctx, keyId, algorithm)
+}
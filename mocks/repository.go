@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 	entity "sawitpro/entity"
 	repository "sawitpro/repository"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	sqlx "github.com/jmoiron/sqlx"
@@ -37,6 +38,50 @@ func (m *MockUserProfileRepositoryInterface) EXPECT() *MockUserProfileRepository
 	return m.recorder
 }
 
+// ConfirmPendingPhoneNumber mocks base method.
+func (m *MockUserProfileRepositoryInterface) ConfirmPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPendingPhoneNumber", ctx, tx, id, verifiedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmPendingPhoneNumber indicates an expected call of ConfirmPendingPhoneNumber.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) ConfirmPendingPhoneNumber(ctx, tx, id, verifiedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPendingPhoneNumber", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).ConfirmPendingPhoneNumber), ctx, tx, id, verifiedAt)
+}
+
+// DeleteProfileById mocks base method.
+func (m *MockUserProfileRepositoryInterface) DeleteProfileById(ctx context.Context, tx *sqlx.Tx, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfileById", ctx, tx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfileById indicates an expected call of DeleteProfileById.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) DeleteProfileById(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfileById", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).DeleteProfileById), ctx, tx, id)
+}
+
+// DeleteUnverifiedProfiles mocks base method.
+func (m *MockUserProfileRepositoryInterface) DeleteUnverifiedProfiles(ctx context.Context, tx *sqlx.Tx, createdBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUnverifiedProfiles", ctx, tx, createdBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUnverifiedProfiles indicates an expected call of DeleteUnverifiedProfiles.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) DeleteUnverifiedProfiles(ctx, tx, createdBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUnverifiedProfiles", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).DeleteUnverifiedProfiles), ctx, tx, createdBefore)
+}
+
 // GetProfileById mocks base method.
 func (m *MockUserProfileRepositoryInterface) GetProfileById(ctx context.Context, tx *sqlx.Tx, id string) (entity.UserProfile, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +112,21 @@ func (mr *MockUserProfileRepositoryInterfaceMockRecorder) GetProfileByPhoneNumbe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileByPhoneNumber", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).GetProfileByPhoneNumber), ctx, tx, phoneNumber)
 }
 
+// IncreaseFailedLoginCount mocks base method.
+func (m *MockUserProfileRepositoryInterface) IncreaseFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseFailedLoginCount", ctx, tx, profileId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncreaseFailedLoginCount indicates an expected call of IncreaseFailedLoginCount.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) IncreaseFailedLoginCount(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseFailedLoginCount", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).IncreaseFailedLoginCount), ctx, tx, profileId)
+}
+
 // IncreaseSuccessLoginCount mocks base method.
 func (m *MockUserProfileRepositoryInterface) IncreaseSuccessLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) error {
 	m.ctrl.T.Helper()
@@ -96,6 +156,62 @@ func (mr *MockUserProfileRepositoryInterfaceMockRecorder) InsertProfile(ctx, tx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProfile", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).InsertProfile), ctx, tx, user)
 }
 
+// LockProfile mocks base method.
+func (m *MockUserProfileRepositoryInterface) LockProfile(ctx context.Context, tx *sqlx.Tx, profileId string, lockedUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockProfile", ctx, tx, profileId, lockedUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockProfile indicates an expected call of LockProfile.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) LockProfile(ctx, tx, profileId, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockProfile", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).LockProfile), ctx, tx, profileId, lockedUntil)
+}
+
+// MarkPhoneVerified mocks base method.
+func (m *MockUserProfileRepositoryInterface) MarkPhoneVerified(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPhoneVerified", ctx, tx, id, verifiedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPhoneVerified indicates an expected call of MarkPhoneVerified.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) MarkPhoneVerified(ctx, tx, id, verifiedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPhoneVerified", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).MarkPhoneVerified), ctx, tx, id, verifiedAt)
+}
+
+// RehashPasswordById mocks base method.
+func (m *MockUserProfileRepositoryInterface) RehashPasswordById(ctx context.Context, tx *sqlx.Tx, id, oldHashedPassword, newHashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RehashPasswordById", ctx, tx, id, oldHashedPassword, newHashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RehashPasswordById indicates an expected call of RehashPasswordById.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) RehashPasswordById(ctx, tx, id, oldHashedPassword, newHashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehashPasswordById", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).RehashPasswordById), ctx, tx, id, oldHashedPassword, newHashedPassword)
+}
+
+// ResetFailedLoginCount mocks base method.
+func (m *MockUserProfileRepositoryInterface) ResetFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetFailedLoginCount", ctx, tx, profileId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetFailedLoginCount indicates an expected call of ResetFailedLoginCount.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) ResetFailedLoginCount(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLoginCount", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).ResetFailedLoginCount), ctx, tx, profileId)
+}
+
 // RunWithTransaction mocks base method.
 func (m *MockUserProfileRepositoryInterface) RunWithTransaction(ctx context.Context, handleFunc repository.TransactionHandleFunc) error {
 	m.ctrl.T.Helper()
@@ -110,6 +226,48 @@ func (mr *MockUserProfileRepositoryInterfaceMockRecorder) RunWithTransaction(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithTransaction", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).RunWithTransaction), ctx, handleFunc)
 }
 
+// SetMustChangePassword mocks base method.
+func (m *MockUserProfileRepositoryInterface) SetMustChangePassword(ctx context.Context, tx *sqlx.Tx, id string, mustChangePassword bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMustChangePassword", ctx, tx, id, mustChangePassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMustChangePassword indicates an expected call of SetMustChangePassword.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) SetMustChangePassword(ctx, tx, id, mustChangePassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMustChangePassword", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).SetMustChangePassword), ctx, tx, id, mustChangePassword)
+}
+
+// SetPendingPhoneNumber mocks base method.
+func (m *MockUserProfileRepositoryInterface) SetPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingPhoneNumber", ctx, tx, id, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingPhoneNumber indicates an expected call of SetPendingPhoneNumber.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) SetPendingPhoneNumber(ctx, tx, id, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingPhoneNumber", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).SetPendingPhoneNumber), ctx, tx, id, phoneNumber)
+}
+
+// UpdatePasswordById mocks base method.
+func (m *MockUserProfileRepositoryInterface) UpdatePasswordById(ctx context.Context, tx *sqlx.Tx, id, hashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordById", ctx, tx, id, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordById indicates an expected call of UpdatePasswordById.
+func (mr *MockUserProfileRepositoryInterfaceMockRecorder) UpdatePasswordById(ctx, tx, id, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordById", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).UpdatePasswordById), ctx, tx, id, hashedPassword)
+}
+
 // UpdateProfileById mocks base method.
 func (m *MockUserProfileRepositoryInterface) UpdateProfileById(ctx context.Context, tx *sqlx.Tx, id string, updateData entity.UserProfile) error {
 	m.ctrl.T.Helper()
@@ -123,3 +281,1473 @@ func (mr *MockUserProfileRepositoryInterfaceMockRecorder) UpdateProfileById(ctx,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfileById", reflect.TypeOf((*MockUserProfileRepositoryInterface)(nil).UpdateProfileById), ctx, tx, id, updateData)
 }
+
+// MockRefreshTokenRepositoryInterface is a mock of RefreshTokenRepositoryInterface interface.
+type MockRefreshTokenRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepositoryInterfaceMockRecorder
+}
+
+// MockRefreshTokenRepositoryInterfaceMockRecorder is the mock recorder for MockRefreshTokenRepositoryInterface.
+type MockRefreshTokenRepositoryInterfaceMockRecorder struct {
+	mock *MockRefreshTokenRepositoryInterface
+}
+
+// NewMockRefreshTokenRepositoryInterface creates a new mock instance.
+func NewMockRefreshTokenRepositoryInterface(ctrl *gomock.Controller) *MockRefreshTokenRepositoryInterface {
+	mock := &MockRefreshTokenRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefreshTokenRepositoryInterface) EXPECT() *MockRefreshTokenRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockRefreshTokenRepositoryInterface) GetRefreshTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockRefreshTokenRepositoryInterfaceMockRecorder) GetRefreshTokenByHash(ctx, tx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockRefreshTokenRepositoryInterface)(nil).GetRefreshTokenByHash), ctx, tx, tokenHash)
+}
+
+// InsertRefreshToken mocks base method.
+func (m *MockRefreshTokenRepositoryInterface) InsertRefreshToken(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRefreshToken", ctx, tx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertRefreshToken indicates an expected call of InsertRefreshToken.
+func (mr *MockRefreshTokenRepositoryInterfaceMockRecorder) InsertRefreshToken(ctx, tx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRefreshToken", reflect.TypeOf((*MockRefreshTokenRepositoryInterface)(nil).InsertRefreshToken), ctx, tx, token)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockRefreshTokenRepositoryInterface) MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", ctx, tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockRefreshTokenRepositoryInterfaceMockRecorder) MarkRefreshTokenUsed(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockRefreshTokenRepositoryInterface)(nil).MarkRefreshTokenUsed), ctx, tx, id)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockRefreshTokenRepositoryInterface) RevokeRefreshTokenFamily(ctx context.Context, tx *sqlx.Tx, familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", ctx, tx, familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockRefreshTokenRepositoryInterfaceMockRecorder) RevokeRefreshTokenFamily(ctx, tx, familyId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockRefreshTokenRepositoryInterface)(nil).RevokeRefreshTokenFamily), ctx, tx, familyId)
+}
+
+// MockSessionRepositoryInterface is a mock of SessionRepositoryInterface interface.
+type MockSessionRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryInterfaceMockRecorder
+}
+
+// MockSessionRepositoryInterfaceMockRecorder is the mock recorder for MockSessionRepositoryInterface.
+type MockSessionRepositoryInterfaceMockRecorder struct {
+	mock *MockSessionRepositoryInterface
+}
+
+// NewMockSessionRepositoryInterface creates a new mock instance.
+func NewMockSessionRepositoryInterface(ctrl *gomock.Controller) *MockSessionRepositoryInterface {
+	mock := &MockSessionRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepositoryInterface) EXPECT() *MockSessionRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteIdleSessions mocks base method.
+func (m *MockSessionRepositoryInterface) DeleteIdleSessions(ctx context.Context, tx *sqlx.Tx, lastSeenBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleSessions", ctx, tx, lastSeenBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdleSessions indicates an expected call of DeleteIdleSessions.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) DeleteIdleSessions(ctx, tx, lastSeenBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleSessions", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).DeleteIdleSessions), ctx, tx, lastSeenBefore)
+}
+
+// DeleteOtherSessions mocks base method.
+func (m *MockSessionRepositoryInterface) DeleteOtherSessions(ctx context.Context, tx *sqlx.Tx, profileId, keepId string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOtherSessions", ctx, tx, profileId, keepId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOtherSessions indicates an expected call of DeleteOtherSessions.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) DeleteOtherSessions(ctx, tx, profileId, keepId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherSessions", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).DeleteOtherSessions), ctx, tx, profileId, keepId)
+}
+
+// DeleteSession mocks base method.
+func (m *MockSessionRepositoryInterface) DeleteSession(ctx context.Context, tx *sqlx.Tx, profileId, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", ctx, tx, profileId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) DeleteSession(ctx, tx, profileId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).DeleteSession), ctx, tx, profileId, id)
+}
+
+// DeleteSessionsByProfileId mocks base method.
+func (m *MockSessionRepositoryInterface) DeleteSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionsByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSessionsByProfileId indicates an expected call of DeleteSessionsByProfileId.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) DeleteSessionsByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionsByProfileId", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).DeleteSessionsByProfileId), ctx, tx, profileId)
+}
+
+// GetSessionById mocks base method.
+func (m *MockSessionRepositoryInterface) GetSessionById(ctx context.Context, tx *sqlx.Tx, id string) (entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionById", ctx, tx, id)
+	ret0, _ := ret[0].(entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionById indicates an expected call of GetSessionById.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) GetSessionById(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionById", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).GetSessionById), ctx, tx, id)
+}
+
+// GetSessionsByProfileId mocks base method.
+func (m *MockSessionRepositoryInterface) GetSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionsByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionsByProfileId indicates an expected call of GetSessionsByProfileId.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) GetSessionsByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionsByProfileId", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).GetSessionsByProfileId), ctx, tx, profileId)
+}
+
+// InsertSession mocks base method.
+func (m *MockSessionRepositoryInterface) InsertSession(ctx context.Context, tx *sqlx.Tx, session entity.Session) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSession", ctx, tx, session)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertSession indicates an expected call of InsertSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) InsertSession(ctx, tx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).InsertSession), ctx, tx, session)
+}
+
+// TouchSession mocks base method.
+func (m *MockSessionRepositoryInterface) TouchSession(ctx context.Context, tx *sqlx.Tx, id string, lastSeenAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", ctx, tx, id, lastSeenAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) TouchSession(ctx, tx, id, lastSeenAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).TouchSession), ctx, tx, id, lastSeenAt)
+}
+
+// MockPersonalAccessTokenRepositoryInterface is a mock of PersonalAccessTokenRepositoryInterface interface.
+type MockPersonalAccessTokenRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalAccessTokenRepositoryInterfaceMockRecorder
+}
+
+// MockPersonalAccessTokenRepositoryInterfaceMockRecorder is the mock recorder for MockPersonalAccessTokenRepositoryInterface.
+type MockPersonalAccessTokenRepositoryInterfaceMockRecorder struct {
+	mock *MockPersonalAccessTokenRepositoryInterface
+}
+
+// NewMockPersonalAccessTokenRepositoryInterface creates a new mock instance.
+func NewMockPersonalAccessTokenRepositoryInterface(ctrl *gomock.Controller) *MockPersonalAccessTokenRepositoryInterface {
+	mock := &MockPersonalAccessTokenRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPersonalAccessTokenRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalAccessTokenRepositoryInterface) EXPECT() *MockPersonalAccessTokenRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeletePersonalAccessToken mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) DeletePersonalAccessToken(ctx context.Context, tx *sqlx.Tx, profileId, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessToken", ctx, tx, profileId, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePersonalAccessToken indicates an expected call of DeletePersonalAccessToken.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) DeletePersonalAccessToken(ctx, tx, profileId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessToken", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).DeletePersonalAccessToken), ctx, tx, profileId, id)
+}
+
+// DeletePersonalAccessTokensByProfileId mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) DeletePersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePersonalAccessTokensByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePersonalAccessTokensByProfileId indicates an expected call of DeletePersonalAccessTokensByProfileId.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) DeletePersonalAccessTokensByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePersonalAccessTokensByProfileId", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).DeletePersonalAccessTokensByProfileId), ctx, tx, profileId)
+}
+
+// GetPersonalAccessTokenByHash mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) GetPersonalAccessTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokenByHash", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(entity.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokenByHash indicates an expected call of GetPersonalAccessTokenByHash.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) GetPersonalAccessTokenByHash(ctx, tx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokenByHash", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).GetPersonalAccessTokenByHash), ctx, tx, tokenHash)
+}
+
+// GetPersonalAccessTokensByProfileId mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) GetPersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPersonalAccessTokensByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]entity.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPersonalAccessTokensByProfileId indicates an expected call of GetPersonalAccessTokensByProfileId.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) GetPersonalAccessTokensByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPersonalAccessTokensByProfileId", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).GetPersonalAccessTokensByProfileId), ctx, tx, profileId)
+}
+
+// InsertPersonalAccessToken mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) InsertPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, token entity.PersonalAccessToken) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPersonalAccessToken", ctx, tx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPersonalAccessToken indicates an expected call of InsertPersonalAccessToken.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) InsertPersonalAccessToken(ctx, tx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPersonalAccessToken", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).InsertPersonalAccessToken), ctx, tx, token)
+}
+
+// TouchPersonalAccessToken mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) TouchPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchPersonalAccessToken", ctx, tx, id, lastUsedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchPersonalAccessToken indicates an expected call of TouchPersonalAccessToken.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) TouchPersonalAccessToken(ctx, tx, id, lastUsedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchPersonalAccessToken", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).TouchPersonalAccessToken), ctx, tx, id, lastUsedAt)
+}
+
+// MockOAuthClientRepositoryInterface is a mock of OAuthClientRepositoryInterface interface.
+type MockOAuthClientRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthClientRepositoryInterfaceMockRecorder
+}
+
+// MockOAuthClientRepositoryInterfaceMockRecorder is the mock recorder for MockOAuthClientRepositoryInterface.
+type MockOAuthClientRepositoryInterfaceMockRecorder struct {
+	mock *MockOAuthClientRepositoryInterface
+}
+
+// NewMockOAuthClientRepositoryInterface creates a new mock instance.
+func NewMockOAuthClientRepositoryInterface(ctrl *gomock.Controller) *MockOAuthClientRepositoryInterface {
+	mock := &MockOAuthClientRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOAuthClientRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthClientRepositoryInterface) EXPECT() *MockOAuthClientRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetOAuthClientById mocks base method.
+func (m *MockOAuthClientRepositoryInterface) GetOAuthClientById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthClientById", ctx, tx, id)
+	ret0, _ := ret[0].(entity.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthClientById indicates an expected call of GetOAuthClientById.
+func (mr *MockOAuthClientRepositoryInterfaceMockRecorder) GetOAuthClientById(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthClientById", reflect.TypeOf((*MockOAuthClientRepositoryInterface)(nil).GetOAuthClientById), ctx, tx, id)
+}
+
+// InsertOAuthClient mocks base method.
+func (m *MockOAuthClientRepositoryInterface) InsertOAuthClient(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuthClient", ctx, tx, client)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOAuthClient indicates an expected call of InsertOAuthClient.
+func (mr *MockOAuthClientRepositoryInterfaceMockRecorder) InsertOAuthClient(ctx, tx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuthClient", reflect.TypeOf((*MockOAuthClientRepositoryInterface)(nil).InsertOAuthClient), ctx, tx, client)
+}
+
+// MockOAuthAuthorizationCodeRepositoryInterface is a mock of OAuthAuthorizationCodeRepositoryInterface interface.
+type MockOAuthAuthorizationCodeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder
+}
+
+// MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder is the mock recorder for MockOAuthAuthorizationCodeRepositoryInterface.
+type MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder struct {
+	mock *MockOAuthAuthorizationCodeRepositoryInterface
+}
+
+// NewMockOAuthAuthorizationCodeRepositoryInterface creates a new mock instance.
+func NewMockOAuthAuthorizationCodeRepositoryInterface(ctrl *gomock.Controller) *MockOAuthAuthorizationCodeRepositoryInterface {
+	mock := &MockOAuthAuthorizationCodeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthAuthorizationCodeRepositoryInterface) EXPECT() *MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOAuthAuthorizationCode mocks base method.
+func (m *MockOAuthAuthorizationCodeRepositoryInterface) ConsumeOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, codeHash string) (entity.OAuthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOAuthAuthorizationCode", ctx, tx, codeHash)
+	ret0, _ := ret[0].(entity.OAuthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeOAuthAuthorizationCode indicates an expected call of ConsumeOAuthAuthorizationCode.
+func (mr *MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder) ConsumeOAuthAuthorizationCode(ctx, tx, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOAuthAuthorizationCode", reflect.TypeOf((*MockOAuthAuthorizationCodeRepositoryInterface)(nil).ConsumeOAuthAuthorizationCode), ctx, tx, codeHash)
+}
+
+// DeleteExpiredOAuthAuthorizationCodes mocks base method.
+func (m *MockOAuthAuthorizationCodeRepositoryInterface) DeleteExpiredOAuthAuthorizationCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOAuthAuthorizationCodes", ctx, tx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredOAuthAuthorizationCodes indicates an expected call of DeleteExpiredOAuthAuthorizationCodes.
+func (mr *MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder) DeleteExpiredOAuthAuthorizationCodes(ctx, tx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOAuthAuthorizationCodes", reflect.TypeOf((*MockOAuthAuthorizationCodeRepositoryInterface)(nil).DeleteExpiredOAuthAuthorizationCodes), ctx, tx, now)
+}
+
+// InsertOAuthAuthorizationCode mocks base method.
+func (m *MockOAuthAuthorizationCodeRepositoryInterface) InsertOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthAuthorizationCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuthAuthorizationCode", ctx, tx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOAuthAuthorizationCode indicates an expected call of InsertOAuthAuthorizationCode.
+func (mr *MockOAuthAuthorizationCodeRepositoryInterfaceMockRecorder) InsertOAuthAuthorizationCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuthAuthorizationCode", reflect.TypeOf((*MockOAuthAuthorizationCodeRepositoryInterface)(nil).InsertOAuthAuthorizationCode), ctx, tx, code)
+}
+
+// MockOAuthDeviceCodeRepositoryInterface is a mock of OAuthDeviceCodeRepositoryInterface interface.
+type MockOAuthDeviceCodeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder
+}
+
+// MockOAuthDeviceCodeRepositoryInterfaceMockRecorder is the mock recorder for MockOAuthDeviceCodeRepositoryInterface.
+type MockOAuthDeviceCodeRepositoryInterfaceMockRecorder struct {
+	mock *MockOAuthDeviceCodeRepositoryInterface
+}
+
+// NewMockOAuthDeviceCodeRepositoryInterface creates a new mock instance.
+func NewMockOAuthDeviceCodeRepositoryInterface(ctrl *gomock.Controller) *MockOAuthDeviceCodeRepositoryInterface {
+	mock := &MockOAuthDeviceCodeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOAuthDeviceCodeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthDeviceCodeRepositoryInterface) EXPECT() *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DecideOAuthDeviceCode mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) DecideOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, userCode, status, profileId string, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideOAuthDeviceCode", ctx, tx, userCode, status, profileId, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideOAuthDeviceCode indicates an expected call of DecideOAuthDeviceCode.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) DecideOAuthDeviceCode(ctx, tx, userCode, status, profileId, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideOAuthDeviceCode", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).DecideOAuthDeviceCode), ctx, tx, userCode, status, profileId, now)
+}
+
+// DeleteExpiredOAuthDeviceCodes mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) DeleteExpiredOAuthDeviceCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOAuthDeviceCodes", ctx, tx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredOAuthDeviceCodes indicates an expected call of DeleteExpiredOAuthDeviceCodes.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) DeleteExpiredOAuthDeviceCodes(ctx, tx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOAuthDeviceCodes", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).DeleteExpiredOAuthDeviceCodes), ctx, tx, now)
+}
+
+// DeleteOAuthDeviceCode mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) DeleteOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuthDeviceCode", ctx, tx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuthDeviceCode indicates an expected call of DeleteOAuthDeviceCode.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) DeleteOAuthDeviceCode(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuthDeviceCode", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).DeleteOAuthDeviceCode), ctx, tx, id)
+}
+
+// GetOAuthDeviceCodeByHash mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) GetOAuthDeviceCodeByHash(ctx context.Context, tx *sqlx.Tx, deviceCodeHash string) (entity.OAuthDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthDeviceCodeByHash", ctx, tx, deviceCodeHash)
+	ret0, _ := ret[0].(entity.OAuthDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthDeviceCodeByHash indicates an expected call of GetOAuthDeviceCodeByHash.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) GetOAuthDeviceCodeByHash(ctx, tx, deviceCodeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthDeviceCodeByHash", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).GetOAuthDeviceCodeByHash), ctx, tx, deviceCodeHash)
+}
+
+// GetOAuthDeviceCodeByUserCode mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) GetOAuthDeviceCodeByUserCode(ctx context.Context, tx *sqlx.Tx, userCode string) (entity.OAuthDeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthDeviceCodeByUserCode", ctx, tx, userCode)
+	ret0, _ := ret[0].(entity.OAuthDeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthDeviceCodeByUserCode indicates an expected call of GetOAuthDeviceCodeByUserCode.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) GetOAuthDeviceCodeByUserCode(ctx, tx, userCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthDeviceCodeByUserCode", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).GetOAuthDeviceCodeByUserCode), ctx, tx, userCode)
+}
+
+// InsertOAuthDeviceCode mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) InsertOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthDeviceCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuthDeviceCode", ctx, tx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOAuthDeviceCode indicates an expected call of InsertOAuthDeviceCode.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) InsertOAuthDeviceCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuthDeviceCode", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).InsertOAuthDeviceCode), ctx, tx, code)
+}
+
+// PollOAuthDeviceCode mocks base method.
+func (m *MockOAuthDeviceCodeRepositoryInterface) PollOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string, polledAt time.Time, interval int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PollOAuthDeviceCode", ctx, tx, id, polledAt, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PollOAuthDeviceCode indicates an expected call of PollOAuthDeviceCode.
+func (mr *MockOAuthDeviceCodeRepositoryInterfaceMockRecorder) PollOAuthDeviceCode(ctx, tx, id, polledAt, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PollOAuthDeviceCode", reflect.TypeOf((*MockOAuthDeviceCodeRepositoryInterface)(nil).PollOAuthDeviceCode), ctx, tx, id, polledAt, interval)
+}
+
+// MockOAuthGrantRepositoryInterface is a mock of OAuthGrantRepositoryInterface interface.
+type MockOAuthGrantRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthGrantRepositoryInterfaceMockRecorder
+}
+
+// MockOAuthGrantRepositoryInterfaceMockRecorder is the mock recorder for MockOAuthGrantRepositoryInterface.
+type MockOAuthGrantRepositoryInterfaceMockRecorder struct {
+	mock *MockOAuthGrantRepositoryInterface
+}
+
+// NewMockOAuthGrantRepositoryInterface creates a new mock instance.
+func NewMockOAuthGrantRepositoryInterface(ctrl *gomock.Controller) *MockOAuthGrantRepositoryInterface {
+	mock := &MockOAuthGrantRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOAuthGrantRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthGrantRepositoryInterface) EXPECT() *MockOAuthGrantRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteOAuthGrant mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) DeleteOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId, clientId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuthGrant", ctx, tx, profileId, clientId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuthGrant indicates an expected call of DeleteOAuthGrant.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) DeleteOAuthGrant(ctx, tx, profileId, clientId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuthGrant", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).DeleteOAuthGrant), ctx, tx, profileId, clientId)
+}
+
+// DeleteOAuthGrantsByProfileId mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) DeleteOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuthGrantsByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOAuthGrantsByProfileId indicates an expected call of DeleteOAuthGrantsByProfileId.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) DeleteOAuthGrantsByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuthGrantsByProfileId", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).DeleteOAuthGrantsByProfileId), ctx, tx, profileId)
+}
+
+// GetOAuthGrant mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) GetOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId, clientId string) (entity.OAuthGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthGrant", ctx, tx, profileId, clientId)
+	ret0, _ := ret[0].(entity.OAuthGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthGrant indicates an expected call of GetOAuthGrant.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) GetOAuthGrant(ctx, tx, profileId, clientId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthGrant", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).GetOAuthGrant), ctx, tx, profileId, clientId)
+}
+
+// GetOAuthGrantById mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) GetOAuthGrantById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthGrantById", ctx, tx, id)
+	ret0, _ := ret[0].(entity.OAuthGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthGrantById indicates an expected call of GetOAuthGrantById.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) GetOAuthGrantById(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthGrantById", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).GetOAuthGrantById), ctx, tx, id)
+}
+
+// GetOAuthGrantsByProfileId mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) GetOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.OAuthGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthGrantsByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]entity.OAuthGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthGrantsByProfileId indicates an expected call of GetOAuthGrantsByProfileId.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) GetOAuthGrantsByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthGrantsByProfileId", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).GetOAuthGrantsByProfileId), ctx, tx, profileId)
+}
+
+// TouchOAuthGrant mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) TouchOAuthGrant(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchOAuthGrant", ctx, tx, id, lastUsedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchOAuthGrant indicates an expected call of TouchOAuthGrant.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) TouchOAuthGrant(ctx, tx, id, lastUsedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchOAuthGrant", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).TouchOAuthGrant), ctx, tx, id, lastUsedAt)
+}
+
+// UpsertOAuthGrant mocks base method.
+func (m *MockOAuthGrantRepositoryInterface) UpsertOAuthGrant(ctx context.Context, tx *sqlx.Tx, grant entity.OAuthGrant) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOAuthGrant", ctx, tx, grant)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertOAuthGrant indicates an expected call of UpsertOAuthGrant.
+func (mr *MockOAuthGrantRepositoryInterfaceMockRecorder) UpsertOAuthGrant(ctx, tx, grant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOAuthGrant", reflect.TypeOf((*MockOAuthGrantRepositoryInterface)(nil).UpsertOAuthGrant), ctx, tx, grant)
+}
+
+// MockRoleRepositoryInterface is a mock of RoleRepositoryInterface interface.
+type MockRoleRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryInterfaceMockRecorder
+}
+
+// MockRoleRepositoryInterfaceMockRecorder is the mock recorder for MockRoleRepositoryInterface.
+type MockRoleRepositoryInterfaceMockRecorder struct {
+	mock *MockRoleRepositoryInterface
+}
+
+// NewMockRoleRepositoryInterface creates a new mock instance.
+func NewMockRoleRepositoryInterface(ctrl *gomock.Controller) *MockRoleRepositoryInterface {
+	mock := &MockRoleRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepositoryInterface) EXPECT() *MockRoleRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AssignProfileRole mocks base method.
+func (m *MockRoleRepositoryInterface) AssignProfileRole(ctx context.Context, tx *sqlx.Tx, profileRole entity.ProfileRole) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignProfileRole", ctx, tx, profileRole)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignProfileRole indicates an expected call of AssignProfileRole.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) AssignProfileRole(ctx, tx, profileRole interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignProfileRole", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).AssignProfileRole), ctx, tx, profileRole)
+}
+
+// GetRoleByName mocks base method.
+func (m *MockRoleRepositoryInterface) GetRoleByName(ctx context.Context, tx *sqlx.Tx, name string) (entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoleByName", ctx, tx, name)
+	ret0, _ := ret[0].(entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoleByName indicates an expected call of GetRoleByName.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) GetRoleByName(ctx, tx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoleByName", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).GetRoleByName), ctx, tx, name)
+}
+
+// GetRoles mocks base method.
+func (m *MockRoleRepositoryInterface) GetRoles(ctx context.Context, tx *sqlx.Tx) ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRoles", ctx, tx)
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRoles indicates an expected call of GetRoles.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) GetRoles(ctx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRoles", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).GetRoles), ctx, tx)
+}
+
+// GetRolesByProfileId mocks base method.
+func (m *MockRoleRepositoryInterface) GetRolesByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByProfileId", ctx, tx, profileId)
+	ret0, _ := ret[0].([]entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesByProfileId indicates an expected call of GetRolesByProfileId.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) GetRolesByProfileId(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByProfileId", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).GetRolesByProfileId), ctx, tx, profileId)
+}
+
+// UnassignProfileRole mocks base method.
+func (m *MockRoleRepositoryInterface) UnassignProfileRole(ctx context.Context, tx *sqlx.Tx, profileId, roleId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignProfileRole", ctx, tx, profileId, roleId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassignProfileRole indicates an expected call of UnassignProfileRole.
+func (mr *MockRoleRepositoryInterfaceMockRecorder) UnassignProfileRole(ctx, tx, profileId, roleId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignProfileRole", reflect.TypeOf((*MockRoleRepositoryInterface)(nil).UnassignProfileRole), ctx, tx, profileId, roleId)
+}
+
+// MockOneTimeCodeRepositoryInterface is a mock of OneTimeCodeRepositoryInterface interface.
+type MockOneTimeCodeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOneTimeCodeRepositoryInterfaceMockRecorder
+}
+
+// MockOneTimeCodeRepositoryInterfaceMockRecorder is the mock recorder for MockOneTimeCodeRepositoryInterface.
+type MockOneTimeCodeRepositoryInterfaceMockRecorder struct {
+	mock *MockOneTimeCodeRepositoryInterface
+}
+
+// NewMockOneTimeCodeRepositoryInterface creates a new mock instance.
+func NewMockOneTimeCodeRepositoryInterface(ctrl *gomock.Controller) *MockOneTimeCodeRepositoryInterface {
+	mock := &MockOneTimeCodeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockOneTimeCodeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOneTimeCodeRepositoryInterface) EXPECT() *MockOneTimeCodeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConsumeOneTimeCodes mocks base method.
+func (m *MockOneTimeCodeRepositoryInterface) ConsumeOneTimeCodes(ctx context.Context, tx *sqlx.Tx, profileId, purpose string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeOneTimeCodes", ctx, tx, profileId, purpose)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeOneTimeCodes indicates an expected call of ConsumeOneTimeCodes.
+func (mr *MockOneTimeCodeRepositoryInterfaceMockRecorder) ConsumeOneTimeCodes(ctx, tx, profileId, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOneTimeCodes", reflect.TypeOf((*MockOneTimeCodeRepositoryInterface)(nil).ConsumeOneTimeCodes), ctx, tx, profileId, purpose)
+}
+
+// DeleteExpiredOneTimeCodes mocks base method.
+func (m *MockOneTimeCodeRepositoryInterface) DeleteExpiredOneTimeCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOneTimeCodes", ctx, tx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredOneTimeCodes indicates an expected call of DeleteExpiredOneTimeCodes.
+func (mr *MockOneTimeCodeRepositoryInterfaceMockRecorder) DeleteExpiredOneTimeCodes(ctx, tx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOneTimeCodes", reflect.TypeOf((*MockOneTimeCodeRepositoryInterface)(nil).DeleteExpiredOneTimeCodes), ctx, tx, now)
+}
+
+// GetActiveOneTimeCode mocks base method.
+func (m *MockOneTimeCodeRepositoryInterface) GetActiveOneTimeCode(ctx context.Context, tx *sqlx.Tx, profileId, purpose string) (entity.OneTimeCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveOneTimeCode", ctx, tx, profileId, purpose)
+	ret0, _ := ret[0].(entity.OneTimeCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveOneTimeCode indicates an expected call of GetActiveOneTimeCode.
+func (mr *MockOneTimeCodeRepositoryInterfaceMockRecorder) GetActiveOneTimeCode(ctx, tx, profileId, purpose interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveOneTimeCode", reflect.TypeOf((*MockOneTimeCodeRepositoryInterface)(nil).GetActiveOneTimeCode), ctx, tx, profileId, purpose)
+}
+
+// IncreaseOneTimeCodeAttempts mocks base method.
+func (m *MockOneTimeCodeRepositoryInterface) IncreaseOneTimeCodeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseOneTimeCodeAttempts", ctx, tx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseOneTimeCodeAttempts indicates an expected call of IncreaseOneTimeCodeAttempts.
+func (mr *MockOneTimeCodeRepositoryInterfaceMockRecorder) IncreaseOneTimeCodeAttempts(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseOneTimeCodeAttempts", reflect.TypeOf((*MockOneTimeCodeRepositoryInterface)(nil).IncreaseOneTimeCodeAttempts), ctx, tx, id)
+}
+
+// InsertOneTimeCode mocks base method.
+func (m *MockOneTimeCodeRepositoryInterface) InsertOneTimeCode(ctx context.Context, tx *sqlx.Tx, code entity.OneTimeCode) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOneTimeCode", ctx, tx, code)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOneTimeCode indicates an expected call of InsertOneTimeCode.
+func (mr *MockOneTimeCodeRepositoryInterfaceMockRecorder) InsertOneTimeCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOneTimeCode", reflect.TypeOf((*MockOneTimeCodeRepositoryInterface)(nil).InsertOneTimeCode), ctx, tx, code)
+}
+
+// MockRecoveryCodeRepositoryInterface is a mock of RecoveryCodeRepositoryInterface interface.
+type MockRecoveryCodeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecoveryCodeRepositoryInterfaceMockRecorder
+}
+
+// MockRecoveryCodeRepositoryInterfaceMockRecorder is the mock recorder for MockRecoveryCodeRepositoryInterface.
+type MockRecoveryCodeRepositoryInterfaceMockRecorder struct {
+	mock *MockRecoveryCodeRepositoryInterface
+}
+
+// NewMockRecoveryCodeRepositoryInterface creates a new mock instance.
+func NewMockRecoveryCodeRepositoryInterface(ctrl *gomock.Controller) *MockRecoveryCodeRepositoryInterface {
+	mock := &MockRecoveryCodeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRecoveryCodeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecoveryCodeRepositoryInterface) EXPECT() *MockRecoveryCodeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockRecoveryCodeRepositoryInterface) CountUnusedRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", ctx, tx, profileId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockRecoveryCodeRepositoryInterfaceMockRecorder) CountUnusedRecoveryCodes(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockRecoveryCodeRepositoryInterface)(nil).CountUnusedRecoveryCodes), ctx, tx, profileId)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockRecoveryCodeRepositoryInterface) DeleteRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", ctx, tx, profileId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockRecoveryCodeRepositoryInterfaceMockRecorder) DeleteRecoveryCodes(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockRecoveryCodeRepositoryInterface)(nil).DeleteRecoveryCodes), ctx, tx, profileId)
+}
+
+// InsertRecoveryCode mocks base method.
+func (m *MockRecoveryCodeRepositoryInterface) InsertRecoveryCode(ctx context.Context, tx *sqlx.Tx, code entity.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertRecoveryCode", ctx, tx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertRecoveryCode indicates an expected call of InsertRecoveryCode.
+func (mr *MockRecoveryCodeRepositoryInterfaceMockRecorder) InsertRecoveryCode(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertRecoveryCode", reflect.TypeOf((*MockRecoveryCodeRepositoryInterface)(nil).InsertRecoveryCode), ctx, tx, code)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockRecoveryCodeRepositoryInterface) UseRecoveryCode(ctx context.Context, tx *sqlx.Tx, profileId, codeHash string, usedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, tx, profileId, codeHash, usedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockRecoveryCodeRepositoryInterfaceMockRecorder) UseRecoveryCode(ctx, tx, profileId, codeHash, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockRecoveryCodeRepositoryInterface)(nil).UseRecoveryCode), ctx, tx, profileId, codeHash, usedAt)
+}
+
+// MockPasswordHistoryRepositoryInterface is a mock of PasswordHistoryRepositoryInterface interface.
+type MockPasswordHistoryRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordHistoryRepositoryInterfaceMockRecorder
+}
+
+// MockPasswordHistoryRepositoryInterfaceMockRecorder is the mock recorder for MockPasswordHistoryRepositoryInterface.
+type MockPasswordHistoryRepositoryInterfaceMockRecorder struct {
+	mock *MockPasswordHistoryRepositoryInterface
+}
+
+// NewMockPasswordHistoryRepositoryInterface creates a new mock instance.
+func NewMockPasswordHistoryRepositoryInterface(ctrl *gomock.Controller) *MockPasswordHistoryRepositoryInterface {
+	mock := &MockPasswordHistoryRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPasswordHistoryRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordHistoryRepositoryInterface) EXPECT() *MockPasswordHistoryRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetPasswordHistory mocks base method.
+func (m *MockPasswordHistoryRepositoryInterface) GetPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, limit int) ([]entity.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordHistory", ctx, tx, profileId, limit)
+	ret0, _ := ret[0].([]entity.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordHistory indicates an expected call of GetPasswordHistory.
+func (mr *MockPasswordHistoryRepositoryInterfaceMockRecorder) GetPasswordHistory(ctx, tx, profileId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordHistory", reflect.TypeOf((*MockPasswordHistoryRepositoryInterface)(nil).GetPasswordHistory), ctx, tx, profileId, limit)
+}
+
+// InsertPasswordHistory mocks base method.
+func (m *MockPasswordHistoryRepositoryInterface) InsertPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId, hashedPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPasswordHistory", ctx, tx, profileId, hashedPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPasswordHistory indicates an expected call of InsertPasswordHistory.
+func (mr *MockPasswordHistoryRepositoryInterfaceMockRecorder) InsertPasswordHistory(ctx, tx, profileId, hashedPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPasswordHistory", reflect.TypeOf((*MockPasswordHistoryRepositoryInterface)(nil).InsertPasswordHistory), ctx, tx, profileId, hashedPassword)
+}
+
+// PrunePasswordHistory mocks base method.
+func (m *MockPasswordHistoryRepositoryInterface) PrunePasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrunePasswordHistory", ctx, tx, profileId, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrunePasswordHistory indicates an expected call of PrunePasswordHistory.
+func (mr *MockPasswordHistoryRepositoryInterfaceMockRecorder) PrunePasswordHistory(ctx, tx, profileId, keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrunePasswordHistory", reflect.TypeOf((*MockPasswordHistoryRepositoryInterface)(nil).PrunePasswordHistory), ctx, tx, profileId, keep)
+}
+
+// MockTOTPCredentialRepositoryInterface is a mock of TOTPCredentialRepositoryInterface interface.
+type MockTOTPCredentialRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPCredentialRepositoryInterfaceMockRecorder
+}
+
+// MockTOTPCredentialRepositoryInterfaceMockRecorder is the mock recorder for MockTOTPCredentialRepositoryInterface.
+type MockTOTPCredentialRepositoryInterfaceMockRecorder struct {
+	mock *MockTOTPCredentialRepositoryInterface
+}
+
+// NewMockTOTPCredentialRepositoryInterface creates a new mock instance.
+func NewMockTOTPCredentialRepositoryInterface(ctrl *gomock.Controller) *MockTOTPCredentialRepositoryInterface {
+	mock := &MockTOTPCredentialRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTOTPCredentialRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPCredentialRepositoryInterface) EXPECT() *MockTOTPCredentialRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteTOTPCredential mocks base method.
+func (m *MockTOTPCredentialRepositoryInterface) DeleteTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTPCredential", ctx, tx, profileId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTOTPCredential indicates an expected call of DeleteTOTPCredential.
+func (mr *MockTOTPCredentialRepositoryInterfaceMockRecorder) DeleteTOTPCredential(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPCredential", reflect.TypeOf((*MockTOTPCredentialRepositoryInterface)(nil).DeleteTOTPCredential), ctx, tx, profileId)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockTOTPCredentialRepositoryInterface) EnableTOTP(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64, enabledAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx, tx, profileId, lastUsedStep, enabledAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockTOTPCredentialRepositoryInterfaceMockRecorder) EnableTOTP(ctx, tx, profileId, lastUsedStep, enabledAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockTOTPCredentialRepositoryInterface)(nil).EnableTOTP), ctx, tx, profileId, lastUsedStep, enabledAt)
+}
+
+// GetTOTPCredential mocks base method.
+func (m *MockTOTPCredentialRepositoryInterface) GetTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (entity.TOTPCredential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTPCredential", ctx, tx, profileId)
+	ret0, _ := ret[0].(entity.TOTPCredential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTOTPCredential indicates an expected call of GetTOTPCredential.
+func (mr *MockTOTPCredentialRepositoryInterfaceMockRecorder) GetTOTPCredential(ctx, tx, profileId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTPCredential", reflect.TypeOf((*MockTOTPCredentialRepositoryInterface)(nil).GetTOTPCredential), ctx, tx, profileId)
+}
+
+// SetPendingTOTPSecret mocks base method.
+func (m *MockTOTPCredentialRepositoryInterface) SetPendingTOTPSecret(ctx context.Context, tx *sqlx.Tx, profileId, pendingSecret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingTOTPSecret", ctx, tx, profileId, pendingSecret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingTOTPSecret indicates an expected call of SetPendingTOTPSecret.
+func (mr *MockTOTPCredentialRepositoryInterfaceMockRecorder) SetPendingTOTPSecret(ctx, tx, profileId, pendingSecret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingTOTPSecret", reflect.TypeOf((*MockTOTPCredentialRepositoryInterface)(nil).SetPendingTOTPSecret), ctx, tx, profileId, pendingSecret)
+}
+
+// UpdateTOTPLastUsedStep mocks base method.
+func (m *MockTOTPCredentialRepositoryInterface) UpdateTOTPLastUsedStep(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTOTPLastUsedStep", ctx, tx, profileId, lastUsedStep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTOTPLastUsedStep indicates an expected call of UpdateTOTPLastUsedStep.
+func (mr *MockTOTPCredentialRepositoryInterfaceMockRecorder) UpdateTOTPLastUsedStep(ctx, tx, profileId, lastUsedStep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPLastUsedStep", reflect.TypeOf((*MockTOTPCredentialRepositoryInterface)(nil).UpdateTOTPLastUsedStep), ctx, tx, profileId, lastUsedStep)
+}
+
+// MockMFAChallengeRepositoryInterface is a mock of MFAChallengeRepositoryInterface interface.
+type MockMFAChallengeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAChallengeRepositoryInterfaceMockRecorder
+}
+
+// MockMFAChallengeRepositoryInterfaceMockRecorder is the mock recorder for MockMFAChallengeRepositoryInterface.
+type MockMFAChallengeRepositoryInterfaceMockRecorder struct {
+	mock *MockMFAChallengeRepositoryInterface
+}
+
+// NewMockMFAChallengeRepositoryInterface creates a new mock instance.
+func NewMockMFAChallengeRepositoryInterface(ctrl *gomock.Controller) *MockMFAChallengeRepositoryInterface {
+	mock := &MockMFAChallengeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockMFAChallengeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAChallengeRepositoryInterface) EXPECT() *MockMFAChallengeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpiredMFAChallenges mocks base method.
+func (m *MockMFAChallengeRepositoryInterface) DeleteExpiredMFAChallenges(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredMFAChallenges", ctx, tx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredMFAChallenges indicates an expected call of DeleteExpiredMFAChallenges.
+func (mr *MockMFAChallengeRepositoryInterfaceMockRecorder) DeleteExpiredMFAChallenges(ctx, tx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredMFAChallenges", reflect.TypeOf((*MockMFAChallengeRepositoryInterface)(nil).DeleteExpiredMFAChallenges), ctx, tx, now)
+}
+
+// DeleteMFAChallenge mocks base method.
+func (m *MockMFAChallengeRepositoryInterface) DeleteMFAChallenge(ctx context.Context, tx *sqlx.Tx, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMFAChallenge", ctx, tx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMFAChallenge indicates an expected call of DeleteMFAChallenge.
+func (mr *MockMFAChallengeRepositoryInterfaceMockRecorder) DeleteMFAChallenge(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMFAChallenge", reflect.TypeOf((*MockMFAChallengeRepositoryInterface)(nil).DeleteMFAChallenge), ctx, tx, id)
+}
+
+// GetMFAChallengeByHash mocks base method.
+func (m *MockMFAChallengeRepositoryInterface) GetMFAChallengeByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFAChallengeByHash", ctx, tx, tokenHash)
+	ret0, _ := ret[0].(entity.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFAChallengeByHash indicates an expected call of GetMFAChallengeByHash.
+func (mr *MockMFAChallengeRepositoryInterfaceMockRecorder) GetMFAChallengeByHash(ctx, tx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFAChallengeByHash", reflect.TypeOf((*MockMFAChallengeRepositoryInterface)(nil).GetMFAChallengeByHash), ctx, tx, tokenHash)
+}
+
+// IncreaseMFAChallengeAttempts mocks base method.
+func (m *MockMFAChallengeRepositoryInterface) IncreaseMFAChallengeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncreaseMFAChallengeAttempts", ctx, tx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseMFAChallengeAttempts indicates an expected call of IncreaseMFAChallengeAttempts.
+func (mr *MockMFAChallengeRepositoryInterfaceMockRecorder) IncreaseMFAChallengeAttempts(ctx, tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseMFAChallengeAttempts", reflect.TypeOf((*MockMFAChallengeRepositoryInterface)(nil).IncreaseMFAChallengeAttempts), ctx, tx, id)
+}
+
+// InsertMFAChallenge mocks base method.
+func (m *MockMFAChallengeRepositoryInterface) InsertMFAChallenge(ctx context.Context, tx *sqlx.Tx, challenge entity.MFAChallenge) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMFAChallenge", ctx, tx, challenge)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMFAChallenge indicates an expected call of InsertMFAChallenge.
+func (mr *MockMFAChallengeRepositoryInterfaceMockRecorder) InsertMFAChallenge(ctx, tx, challenge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMFAChallenge", reflect.TypeOf((*MockMFAChallengeRepositoryInterface)(nil).InsertMFAChallenge), ctx, tx, challenge)
+}
+
+// MockRevokedTokenRepositoryInterface is a mock of RevokedTokenRepositoryInterface interface.
+type MockRevokedTokenRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevokedTokenRepositoryInterfaceMockRecorder
+}
+
+// MockRevokedTokenRepositoryInterfaceMockRecorder is the mock recorder for MockRevokedTokenRepositoryInterface.
+type MockRevokedTokenRepositoryInterfaceMockRecorder struct {
+	mock *MockRevokedTokenRepositoryInterface
+}
+
+// NewMockRevokedTokenRepositoryInterface creates a new mock instance.
+func NewMockRevokedTokenRepositoryInterface(ctrl *gomock.Controller) *MockRevokedTokenRepositoryInterface {
+	mock := &MockRevokedTokenRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRevokedTokenRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevokedTokenRepositoryInterface) EXPECT() *MockRevokedTokenRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockRevokedTokenRepositoryInterface) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, tokenId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockRevokedTokenRepositoryInterfaceMockRecorder) IsTokenRevoked(ctx, tokenId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockRevokedTokenRepositoryInterface)(nil).IsTokenRevoked), ctx, tokenId)
+}
+
+// PruneExpiredTokens mocks base method.
+func (m *MockRevokedTokenRepositoryInterface) PruneExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneExpiredTokens", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneExpiredTokens indicates an expected call of PruneExpiredTokens.
+func (mr *MockRevokedTokenRepositoryInterfaceMockRecorder) PruneExpiredTokens(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneExpiredTokens", reflect.TypeOf((*MockRevokedTokenRepositoryInterface)(nil).PruneExpiredTokens), ctx, now)
+}
+
+// RevokeToken mocks base method.
+func (m *MockRevokedTokenRepositoryInterface) RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, tokenId, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockRevokedTokenRepositoryInterfaceMockRecorder) RevokeToken(ctx, tokenId, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockRevokedTokenRepositoryInterface)(nil).RevokeToken), ctx, tokenId, expiresAt)
+}
+
+// MockRateLimitRepositoryInterface is a mock of RateLimitRepositoryInterface interface.
+type MockRateLimitRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitRepositoryInterfaceMockRecorder
+}
+
+// MockRateLimitRepositoryInterfaceMockRecorder is the mock recorder for MockRateLimitRepositoryInterface.
+type MockRateLimitRepositoryInterfaceMockRecorder struct {
+	mock *MockRateLimitRepositoryInterface
+}
+
+// NewMockRateLimitRepositoryInterface creates a new mock instance.
+func NewMockRateLimitRepositoryInterface(ctrl *gomock.Controller) *MockRateLimitRepositoryInterface {
+	mock := &MockRateLimitRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRateLimitRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitRepositoryInterface) EXPECT() *MockRateLimitRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteIdleRateLimitBuckets mocks base method.
+func (m *MockRateLimitRepositoryInterface) DeleteIdleRateLimitBuckets(ctx context.Context, updatedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleRateLimitBuckets", ctx, updatedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdleRateLimitBuckets indicates an expected call of DeleteIdleRateLimitBuckets.
+func (mr *MockRateLimitRepositoryInterfaceMockRecorder) DeleteIdleRateLimitBuckets(ctx, updatedBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleRateLimitBuckets", reflect.TypeOf((*MockRateLimitRepositoryInterface)(nil).DeleteIdleRateLimitBuckets), ctx, updatedBefore)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockRateLimitRepositoryInterface) TakeRateLimitToken(ctx context.Context, bucketKey string, capacity, refillPerSecond float64, now time.Time) (bool, entity.RateLimitBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", ctx, bucketKey, capacity, refillPerSecond, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(entity.RateLimitBucket)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockRateLimitRepositoryInterfaceMockRecorder) TakeRateLimitToken(ctx, bucketKey, capacity, refillPerSecond, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockRateLimitRepositoryInterface)(nil).TakeRateLimitToken), ctx, bucketKey, capacity, refillPerSecond, now)
+}
+
+// MockSigningKeyRepositoryInterface is a mock of SigningKeyRepositoryInterface interface.
+type MockSigningKeyRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningKeyRepositoryInterfaceMockRecorder
+}
+
+// MockSigningKeyRepositoryInterfaceMockRecorder is the mock recorder for MockSigningKeyRepositoryInterface.
+type MockSigningKeyRepositoryInterfaceMockRecorder struct {
+	mock *MockSigningKeyRepositoryInterface
+}
+
+// NewMockSigningKeyRepositoryInterface creates a new mock instance.
+func NewMockSigningKeyRepositoryInterface(ctrl *gomock.Controller) *MockSigningKeyRepositoryInterface {
+	mock := &MockSigningKeyRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockSigningKeyRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningKeyRepositoryInterface) EXPECT() *MockSigningKeyRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteRetiredSigningKeys mocks base method.
+func (m *MockSigningKeyRepositoryInterface) DeleteRetiredSigningKeys(ctx context.Context, tx *sqlx.Tx, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRetiredSigningKeys", ctx, tx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRetiredSigningKeys indicates an expected call of DeleteRetiredSigningKeys.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) DeleteRetiredSigningKeys(ctx, tx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetiredSigningKeys", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).DeleteRetiredSigningKeys), ctx, tx, now)
+}
+
+// GetSigningKeys mocks base method.
+func (m *MockSigningKeyRepositoryInterface) GetSigningKeys(ctx context.Context, tx *sqlx.Tx) ([]entity.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKeys", ctx, tx)
+	ret0, _ := ret[0].([]entity.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigningKeys indicates an expected call of GetSigningKeys.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) GetSigningKeys(ctx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKeys", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).GetSigningKeys), ctx, tx)
+}
+
+// InsertSigningKey mocks base method.
+func (m *MockSigningKeyRepositoryInterface) InsertSigningKey(ctx context.Context, tx *sqlx.Tx, key entity.SigningKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSigningKey", ctx, tx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSigningKey indicates an expected call of InsertSigningKey.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) InsertSigningKey(ctx, tx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSigningKey", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).InsertSigningKey), ctx, tx, key)
+}
+
+// LockSigningKeys mocks base method.
+func (m *MockSigningKeyRepositoryInterface) LockSigningKeys(ctx context.Context, tx *sqlx.Tx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockSigningKeys", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockSigningKeys indicates an expected call of LockSigningKeys.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) LockSigningKeys(ctx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSigningKeys", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).LockSigningKeys), ctx, tx)
+}
+
+// RetireSigningKey mocks base method.
+func (m *MockSigningKeyRepositoryInterface) RetireSigningKey(ctx context.Context, tx *sqlx.Tx, id string, retiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetireSigningKey", ctx, tx, id, retiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetireSigningKey indicates an expected call of RetireSigningKey.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) RetireSigningKey(ctx, tx, id, retiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetireSigningKey", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).RetireSigningKey), ctx, tx, id, retiresAt)
+}
+
+// RunWithTransaction mocks base method.
+func (m *MockSigningKeyRepositoryInterface) RunWithTransaction(ctx context.Context, handleFunc repository.TransactionHandleFunc) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunWithTransaction", ctx, handleFunc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunWithTransaction indicates an expected call of RunWithTransaction.
+func (mr *MockSigningKeyRepositoryInterfaceMockRecorder) RunWithTransaction(ctx, handleFunc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithTransaction", reflect.TypeOf((*MockSigningKeyRepositoryInterface)(nil).RunWithTransaction), ctx, handleFunc)
+}
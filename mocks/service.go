@@ -35,6 +35,120 @@ func (m *MockProfileServiceInterface) EXPECT() *MockProfileServiceInterfaceMockR
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockProfileServiceInterface) ChangePassword(ctx context.Context, request entity.ChangePasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockProfileServiceInterfaceMockRecorder) ChangePassword(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockProfileServiceInterface)(nil).ChangePassword), ctx, request)
+}
+
+// ConfirmPasswordReset mocks base method.
+func (m *MockProfileServiceInterface) ConfirmPasswordReset(ctx context.Context, request entity.ConfirmPasswordResetRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPasswordReset", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmPasswordReset indicates an expected call of ConfirmPasswordReset.
+func (mr *MockProfileServiceInterfaceMockRecorder) ConfirmPasswordReset(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPasswordReset", reflect.TypeOf((*MockProfileServiceInterface)(nil).ConfirmPasswordReset), ctx, request)
+}
+
+// ConfirmPhoneChange mocks base method.
+func (m *MockProfileServiceInterface) ConfirmPhoneChange(ctx context.Context, request entity.ConfirmPhoneChangeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmPhoneChange", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmPhoneChange indicates an expected call of ConfirmPhoneChange.
+func (mr *MockProfileServiceInterfaceMockRecorder) ConfirmPhoneChange(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmPhoneChange", reflect.TypeOf((*MockProfileServiceInterface)(nil).ConfirmPhoneChange), ctx, request)
+}
+
+// ConfirmTOTP mocks base method.
+func (m *MockProfileServiceInterface) ConfirmTOTP(ctx context.Context, request entity.ConfirmTOTPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTP", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmTOTP indicates an expected call of ConfirmTOTP.
+func (mr *MockProfileServiceInterfaceMockRecorder) ConfirmTOTP(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTP", reflect.TypeOf((*MockProfileServiceInterface)(nil).ConfirmTOTP), ctx, request)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockProfileServiceInterface) DisableTOTP(ctx context.Context, request entity.DisableTOTPRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockProfileServiceInterfaceMockRecorder) DisableTOTP(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockProfileServiceInterface)(nil).DisableTOTP), ctx, request)
+}
+
+// EnrollTOTP mocks base method.
+func (m *MockProfileServiceInterface) EnrollTOTP(ctx context.Context, request entity.EnrollTOTPRequest) (entity.EnrollTOTPResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollTOTP", ctx, request)
+	ret0, _ := ret[0].(entity.EnrollTOTPResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollTOTP indicates an expected call of EnrollTOTP.
+func (mr *MockProfileServiceInterfaceMockRecorder) EnrollTOTP(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollTOTP", reflect.TypeOf((*MockProfileServiceInterface)(nil).EnrollTOTP), ctx, request)
+}
+
+// ForcePasswordChange mocks base method.
+func (m *MockProfileServiceInterface) ForcePasswordChange(ctx context.Context, request entity.ForcePasswordChangeRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForcePasswordChange", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForcePasswordChange indicates an expected call of ForcePasswordChange.
+func (mr *MockProfileServiceInterfaceMockRecorder) ForcePasswordChange(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForcePasswordChange", reflect.TypeOf((*MockProfileServiceInterface)(nil).ForcePasswordChange), ctx, request)
+}
+
+// GenerateRecoveryCodes mocks base method.
+func (m *MockProfileServiceInterface) GenerateRecoveryCodes(ctx context.Context, request entity.GenerateRecoveryCodesRequest) (entity.GenerateRecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateRecoveryCodes", ctx, request)
+	ret0, _ := ret[0].(entity.GenerateRecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateRecoveryCodes indicates an expected call of GenerateRecoveryCodes.
+func (mr *MockProfileServiceInterfaceMockRecorder) GenerateRecoveryCodes(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRecoveryCodes", reflect.TypeOf((*MockProfileServiceInterface)(nil).GenerateRecoveryCodes), ctx, request)
+}
+
 // GetProfile mocks base method.
 func (m *MockProfileServiceInterface) GetProfile(ctx context.Context, request entity.GetProfileRequest) (entity.GetProfileResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +179,48 @@ func (mr *MockProfileServiceInterfaceMockRecorder) Login(ctx, request interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockProfileServiceInterface)(nil).Login), ctx, request)
 }
 
+// PruneMFAChallenges mocks base method.
+func (m *MockProfileServiceInterface) PruneMFAChallenges(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneMFAChallenges", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneMFAChallenges indicates an expected call of PruneMFAChallenges.
+func (mr *MockProfileServiceInterfaceMockRecorder) PruneMFAChallenges(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneMFAChallenges", reflect.TypeOf((*MockProfileServiceInterface)(nil).PruneMFAChallenges), ctx)
+}
+
+// PruneOneTimeCodes mocks base method.
+func (m *MockProfileServiceInterface) PruneOneTimeCodes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneOneTimeCodes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneOneTimeCodes indicates an expected call of PruneOneTimeCodes.
+func (mr *MockProfileServiceInterfaceMockRecorder) PruneOneTimeCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneOneTimeCodes", reflect.TypeOf((*MockProfileServiceInterface)(nil).PruneOneTimeCodes), ctx)
+}
+
+// PruneUnverifiedProfiles mocks base method.
+func (m *MockProfileServiceInterface) PruneUnverifiedProfiles(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneUnverifiedProfiles", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneUnverifiedProfiles indicates an expected call of PruneUnverifiedProfiles.
+func (mr *MockProfileServiceInterfaceMockRecorder) PruneUnverifiedProfiles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneUnverifiedProfiles", reflect.TypeOf((*MockProfileServiceInterface)(nil).PruneUnverifiedProfiles), ctx)
+}
+
 // Register mocks base method.
 func (m *MockProfileServiceInterface) Register(ctx context.Context, request entity.ProfileRegisterRequest) (entity.ProfileRegisterResponse, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +236,34 @@ func (mr *MockProfileServiceInterfaceMockRecorder) Register(ctx, request interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockProfileServiceInterface)(nil).Register), ctx, request)
 }
 
+// RequestPasswordReset mocks base method.
+func (m *MockProfileServiceInterface) RequestPasswordReset(ctx context.Context, request entity.RequestPasswordResetRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockProfileServiceInterfaceMockRecorder) RequestPasswordReset(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockProfileServiceInterface)(nil).RequestPasswordReset), ctx, request)
+}
+
+// SetNewPassword mocks base method.
+func (m *MockProfileServiceInterface) SetNewPassword(ctx context.Context, request entity.SetNewPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNewPassword", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNewPassword indicates an expected call of SetNewPassword.
+func (mr *MockProfileServiceInterfaceMockRecorder) SetNewPassword(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNewPassword", reflect.TypeOf((*MockProfileServiceInterface)(nil).SetNewPassword), ctx, request)
+}
+
 // UpdateProfile mocks base method.
 func (m *MockProfileServiceInterface) UpdateProfile(ctx context.Context, request entity.UpdateProfileRequest) error {
 	m.ctrl.T.Helper()
@@ -93,3 +277,672 @@ func (mr *MockProfileServiceInterfaceMockRecorder) UpdateProfile(ctx, request in
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockProfileServiceInterface)(nil).UpdateProfile), ctx, request)
 }
+
+// VerifyLoginMFA mocks base method.
+func (m *MockProfileServiceInterface) VerifyLoginMFA(ctx context.Context, request entity.VerifyLoginMFARequest) (entity.LoginResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyLoginMFA", ctx, request)
+	ret0, _ := ret[0].(entity.LoginResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyLoginMFA indicates an expected call of VerifyLoginMFA.
+func (mr *MockProfileServiceInterfaceMockRecorder) VerifyLoginMFA(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyLoginMFA", reflect.TypeOf((*MockProfileServiceInterface)(nil).VerifyLoginMFA), ctx, request)
+}
+
+// VerifyPhone mocks base method.
+func (m *MockProfileServiceInterface) VerifyPhone(ctx context.Context, request entity.VerifyPhoneRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyPhone", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyPhone indicates an expected call of VerifyPhone.
+func (mr *MockProfileServiceInterfaceMockRecorder) VerifyPhone(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyPhone", reflect.TypeOf((*MockProfileServiceInterface)(nil).VerifyPhone), ctx, request)
+}
+
+// MockAuthServiceInterface is a mock of AuthServiceInterface interface.
+type MockAuthServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthServiceInterfaceMockRecorder
+}
+
+// MockAuthServiceInterfaceMockRecorder is the mock recorder for MockAuthServiceInterface.
+type MockAuthServiceInterfaceMockRecorder struct {
+	mock *MockAuthServiceInterface
+}
+
+// NewMockAuthServiceInterface creates a new mock instance.
+func NewMockAuthServiceInterface(ctrl *gomock.Controller) *MockAuthServiceInterface {
+	mock := &MockAuthServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthServiceInterface) EXPECT() *MockAuthServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthServiceInterface) Authenticate(ctx context.Context, request entity.AuthenticateRequest) (entity.TokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, request)
+	ret0, _ := ret[0].(entity.TokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthServiceInterfaceMockRecorder) Authenticate(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthServiceInterface)(nil).Authenticate), ctx, request)
+}
+
+// CreatePersonalAccessToken mocks base method.
+func (m *MockAuthServiceInterface) CreatePersonalAccessToken(ctx context.Context, request entity.CreatePersonalAccessTokenRequest) (entity.CreatePersonalAccessTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePersonalAccessToken", ctx, request)
+	ret0, _ := ret[0].(entity.CreatePersonalAccessTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePersonalAccessToken indicates an expected call of CreatePersonalAccessToken.
+func (mr *MockAuthServiceInterfaceMockRecorder) CreatePersonalAccessToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePersonalAccessToken", reflect.TypeOf((*MockAuthServiceInterface)(nil).CreatePersonalAccessToken), ctx, request)
+}
+
+// IssueToken mocks base method.
+func (m *MockAuthServiceInterface) IssueToken(ctx context.Context, request entity.IssueTokenRequest) (entity.IssueTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueToken", ctx, request)
+	ret0, _ := ret[0].(entity.IssueTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueToken indicates an expected call of IssueToken.
+func (mr *MockAuthServiceInterfaceMockRecorder) IssueToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueToken", reflect.TypeOf((*MockAuthServiceInterface)(nil).IssueToken), ctx, request)
+}
+
+// ListPersonalAccessTokens mocks base method.
+func (m *MockAuthServiceInterface) ListPersonalAccessTokens(ctx context.Context, request entity.ListPersonalAccessTokensRequest) (entity.ListPersonalAccessTokensResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPersonalAccessTokens", ctx, request)
+	ret0, _ := ret[0].(entity.ListPersonalAccessTokensResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPersonalAccessTokens indicates an expected call of ListPersonalAccessTokens.
+func (mr *MockAuthServiceInterfaceMockRecorder) ListPersonalAccessTokens(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPersonalAccessTokens", reflect.TypeOf((*MockAuthServiceInterface)(nil).ListPersonalAccessTokens), ctx, request)
+}
+
+// ListSessions mocks base method.
+func (m *MockAuthServiceInterface) ListSessions(ctx context.Context, request entity.ListSessionsRequest) (entity.ListSessionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, request)
+	ret0, _ := ret[0].(entity.ListSessionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) ListSessions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).ListSessions), ctx, request)
+}
+
+// Logout mocks base method.
+func (m *MockAuthServiceInterface) Logout(ctx context.Context, request entity.LogoutRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthServiceInterfaceMockRecorder) Logout(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthServiceInterface)(nil).Logout), ctx, request)
+}
+
+// PruneIdleSessions mocks base method.
+func (m *MockAuthServiceInterface) PruneIdleSessions(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneIdleSessions", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneIdleSessions indicates an expected call of PruneIdleSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) PruneIdleSessions(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneIdleSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).PruneIdleSessions), ctx)
+}
+
+// PruneRevokedTokens mocks base method.
+func (m *MockAuthServiceInterface) PruneRevokedTokens(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRevokedTokens", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneRevokedTokens indicates an expected call of PruneRevokedTokens.
+func (mr *MockAuthServiceInterfaceMockRecorder) PruneRevokedTokens(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRevokedTokens", reflect.TypeOf((*MockAuthServiceInterface)(nil).PruneRevokedTokens), ctx)
+}
+
+// RefreshToken mocks base method.
+func (m *MockAuthServiceInterface) RefreshToken(ctx context.Context, request entity.RefreshTokenRequest) (entity.RefreshTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, request)
+	ret0, _ := ret[0].(entity.RefreshTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthServiceInterfaceMockRecorder) RefreshToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthServiceInterface)(nil).RefreshToken), ctx, request)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockAuthServiceInterface) RevokeAllSessions(ctx context.Context, request entity.RevokeAllSessionsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokeAllSessions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokeAllSessions), ctx, request)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockAuthServiceInterface) RevokeOtherSessions(ctx context.Context, request entity.RevokeOtherSessionsRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokeOtherSessions(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokeOtherSessions), ctx, request)
+}
+
+// RevokePersonalAccessToken mocks base method.
+func (m *MockAuthServiceInterface) RevokePersonalAccessToken(ctx context.Context, request entity.RevokePersonalAccessTokenRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokePersonalAccessToken", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePersonalAccessToken indicates an expected call of RevokePersonalAccessToken.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokePersonalAccessToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePersonalAccessToken", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokePersonalAccessToken), ctx, request)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthServiceInterface) RevokeSession(ctx context.Context, request entity.RevokeSessionRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokeSession(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokeSession), ctx, request)
+}
+
+// MockOAuthServiceInterface is a mock of OAuthServiceInterface interface.
+type MockOAuthServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthServiceInterfaceMockRecorder
+}
+
+// MockOAuthServiceInterfaceMockRecorder is the mock recorder for MockOAuthServiceInterface.
+type MockOAuthServiceInterfaceMockRecorder struct {
+	mock *MockOAuthServiceInterface
+}
+
+// NewMockOAuthServiceInterface creates a new mock instance.
+func NewMockOAuthServiceInterface(ctrl *gomock.Controller) *MockOAuthServiceInterface {
+	mock := &MockOAuthServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockOAuthServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthServiceInterface) EXPECT() *MockOAuthServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AuthorizeOAuthDevice mocks base method.
+func (m *MockOAuthServiceInterface) AuthorizeOAuthDevice(ctx context.Context, request entity.OAuthDeviceAuthorizationRequest) (entity.OAuthDeviceAuthorizationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeOAuthDevice", ctx, request)
+	ret0, _ := ret[0].(entity.OAuthDeviceAuthorizationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeOAuthDevice indicates an expected call of AuthorizeOAuthDevice.
+func (mr *MockOAuthServiceInterfaceMockRecorder) AuthorizeOAuthDevice(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeOAuthDevice", reflect.TypeOf((*MockOAuthServiceInterface)(nil).AuthorizeOAuthDevice), ctx, request)
+}
+
+// ConsentOAuthAuthorization mocks base method.
+func (m *MockOAuthServiceInterface) ConsentOAuthAuthorization(ctx context.Context, request entity.OAuthConsentRequest) (entity.OAuthConsentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsentOAuthAuthorization", ctx, request)
+	ret0, _ := ret[0].(entity.OAuthConsentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsentOAuthAuthorization indicates an expected call of ConsentOAuthAuthorization.
+func (mr *MockOAuthServiceInterfaceMockRecorder) ConsentOAuthAuthorization(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsentOAuthAuthorization", reflect.TypeOf((*MockOAuthServiceInterface)(nil).ConsentOAuthAuthorization), ctx, request)
+}
+
+// ConsentOAuthDevice mocks base method.
+func (m *MockOAuthServiceInterface) ConsentOAuthDevice(ctx context.Context, request entity.OAuthDeviceConsentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsentOAuthDevice", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsentOAuthDevice indicates an expected call of ConsentOAuthDevice.
+func (mr *MockOAuthServiceInterfaceMockRecorder) ConsentOAuthDevice(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsentOAuthDevice", reflect.TypeOf((*MockOAuthServiceInterface)(nil).ConsentOAuthDevice), ctx, request)
+}
+
+// EndOIDCSession mocks base method.
+func (m *MockOAuthServiceInterface) EndOIDCSession(ctx context.Context, request entity.OIDCLogoutRequest) (entity.OIDCLogoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EndOIDCSession", ctx, request)
+	ret0, _ := ret[0].(entity.OIDCLogoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EndOIDCSession indicates an expected call of EndOIDCSession.
+func (mr *MockOAuthServiceInterfaceMockRecorder) EndOIDCSession(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EndOIDCSession", reflect.TypeOf((*MockOAuthServiceInterface)(nil).EndOIDCSession), ctx, request)
+}
+
+// ExchangeOAuthToken mocks base method.
+func (m *MockOAuthServiceInterface) ExchangeOAuthToken(ctx context.Context, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeOAuthToken", ctx, request)
+	ret0, _ := ret[0].(entity.OAuthTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeOAuthToken indicates an expected call of ExchangeOAuthToken.
+func (mr *MockOAuthServiceInterfaceMockRecorder) ExchangeOAuthToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeOAuthToken", reflect.TypeOf((*MockOAuthServiceInterface)(nil).ExchangeOAuthToken), ctx, request)
+}
+
+// GetOAuthAuthorization mocks base method.
+func (m *MockOAuthServiceInterface) GetOAuthAuthorization(ctx context.Context, request entity.OAuthAuthorizationRequest) (entity.OAuthAuthorizationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthAuthorization", ctx, request)
+	ret0, _ := ret[0].(entity.OAuthAuthorizationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthAuthorization indicates an expected call of GetOAuthAuthorization.
+func (mr *MockOAuthServiceInterfaceMockRecorder) GetOAuthAuthorization(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthAuthorization", reflect.TypeOf((*MockOAuthServiceInterface)(nil).GetOAuthAuthorization), ctx, request)
+}
+
+// GetOAuthDevice mocks base method.
+func (m *MockOAuthServiceInterface) GetOAuthDevice(ctx context.Context, request entity.OAuthDeviceRequest) (entity.OAuthDeviceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOAuthDevice", ctx, request)
+	ret0, _ := ret[0].(entity.OAuthDeviceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOAuthDevice indicates an expected call of GetOAuthDevice.
+func (mr *MockOAuthServiceInterfaceMockRecorder) GetOAuthDevice(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOAuthDevice", reflect.TypeOf((*MockOAuthServiceInterface)(nil).GetOAuthDevice), ctx, request)
+}
+
+// GetOpenIDConfiguration mocks base method.
+func (m *MockOAuthServiceInterface) GetOpenIDConfiguration(ctx context.Context) (entity.OpenIDConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenIDConfiguration", ctx)
+	ret0, _ := ret[0].(entity.OpenIDConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenIDConfiguration indicates an expected call of GetOpenIDConfiguration.
+func (mr *MockOAuthServiceInterfaceMockRecorder) GetOpenIDConfiguration(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenIDConfiguration", reflect.TypeOf((*MockOAuthServiceInterface)(nil).GetOpenIDConfiguration), ctx)
+}
+
+// GetUserInfo mocks base method.
+func (m *MockOAuthServiceInterface) GetUserInfo(ctx context.Context, request entity.GetUserInfoRequest) (entity.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfo", ctx, request)
+	ret0, _ := ret[0].(entity.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfo indicates an expected call of GetUserInfo.
+func (mr *MockOAuthServiceInterfaceMockRecorder) GetUserInfo(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockOAuthServiceInterface)(nil).GetUserInfo), ctx, request)
+}
+
+// IntrospectToken mocks base method.
+func (m *MockOAuthServiceInterface) IntrospectToken(ctx context.Context, request entity.IntrospectTokenRequest) (entity.IntrospectTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IntrospectToken", ctx, request)
+	ret0, _ := ret[0].(entity.IntrospectTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IntrospectToken indicates an expected call of IntrospectToken.
+func (mr *MockOAuthServiceInterfaceMockRecorder) IntrospectToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IntrospectToken", reflect.TypeOf((*MockOAuthServiceInterface)(nil).IntrospectToken), ctx, request)
+}
+
+// ListOAuthGrants mocks base method.
+func (m *MockOAuthServiceInterface) ListOAuthGrants(ctx context.Context, request entity.ListOAuthGrantsRequest) (entity.ListOAuthGrantsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOAuthGrants", ctx, request)
+	ret0, _ := ret[0].(entity.ListOAuthGrantsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOAuthGrants indicates an expected call of ListOAuthGrants.
+func (mr *MockOAuthServiceInterfaceMockRecorder) ListOAuthGrants(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuthGrants", reflect.TypeOf((*MockOAuthServiceInterface)(nil).ListOAuthGrants), ctx, request)
+}
+
+// PruneOAuthAuthorizationCodes mocks base method.
+func (m *MockOAuthServiceInterface) PruneOAuthAuthorizationCodes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneOAuthAuthorizationCodes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneOAuthAuthorizationCodes indicates an expected call of PruneOAuthAuthorizationCodes.
+func (mr *MockOAuthServiceInterfaceMockRecorder) PruneOAuthAuthorizationCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneOAuthAuthorizationCodes", reflect.TypeOf((*MockOAuthServiceInterface)(nil).PruneOAuthAuthorizationCodes), ctx)
+}
+
+// PruneOAuthDeviceCodes mocks base method.
+func (m *MockOAuthServiceInterface) PruneOAuthDeviceCodes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneOAuthDeviceCodes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneOAuthDeviceCodes indicates an expected call of PruneOAuthDeviceCodes.
+func (mr *MockOAuthServiceInterfaceMockRecorder) PruneOAuthDeviceCodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneOAuthDeviceCodes", reflect.TypeOf((*MockOAuthServiceInterface)(nil).PruneOAuthDeviceCodes), ctx)
+}
+
+// RegisterOAuthClient mocks base method.
+func (m *MockOAuthServiceInterface) RegisterOAuthClient(ctx context.Context, request entity.RegisterOAuthClientRequest) (entity.RegisterOAuthClientResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOAuthClient", ctx, request)
+	ret0, _ := ret[0].(entity.RegisterOAuthClientResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterOAuthClient indicates an expected call of RegisterOAuthClient.
+func (mr *MockOAuthServiceInterfaceMockRecorder) RegisterOAuthClient(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOAuthClient", reflect.TypeOf((*MockOAuthServiceInterface)(nil).RegisterOAuthClient), ctx, request)
+}
+
+// RevokeOAuthGrant mocks base method.
+func (m *MockOAuthServiceInterface) RevokeOAuthGrant(ctx context.Context, request entity.RevokeOAuthGrantRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuthGrant", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuthGrant indicates an expected call of RevokeOAuthGrant.
+func (mr *MockOAuthServiceInterfaceMockRecorder) RevokeOAuthGrant(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuthGrant", reflect.TypeOf((*MockOAuthServiceInterface)(nil).RevokeOAuthGrant), ctx, request)
+}
+
+// MockRoleServiceInterface is a mock of RoleServiceInterface interface.
+type MockRoleServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleServiceInterfaceMockRecorder
+}
+
+// MockRoleServiceInterfaceMockRecorder is the mock recorder for MockRoleServiceInterface.
+type MockRoleServiceInterfaceMockRecorder struct {
+	mock *MockRoleServiceInterface
+}
+
+// NewMockRoleServiceInterface creates a new mock instance.
+func NewMockRoleServiceInterface(ctrl *gomock.Controller) *MockRoleServiceInterface {
+	mock := &MockRoleServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRoleServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleServiceInterface) EXPECT() *MockRoleServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRoleServiceInterface) AssignRole(ctx context.Context, request entity.AssignRoleRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRoleServiceInterfaceMockRecorder) AssignRole(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRoleServiceInterface)(nil).AssignRole), ctx, request)
+}
+
+// ListProfileRoles mocks base method.
+func (m *MockRoleServiceInterface) ListProfileRoles(ctx context.Context, request entity.ListProfileRolesRequest) (entity.ListProfileRolesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProfileRoles", ctx, request)
+	ret0, _ := ret[0].(entity.ListProfileRolesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProfileRoles indicates an expected call of ListProfileRoles.
+func (mr *MockRoleServiceInterfaceMockRecorder) ListProfileRoles(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProfileRoles", reflect.TypeOf((*MockRoleServiceInterface)(nil).ListProfileRoles), ctx, request)
+}
+
+// ListRoles mocks base method.
+func (m *MockRoleServiceInterface) ListRoles(ctx context.Context) (entity.ListRolesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].(entity.ListRolesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRoleServiceInterfaceMockRecorder) ListRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRoleServiceInterface)(nil).ListRoles), ctx)
+}
+
+// UnassignRole mocks base method.
+func (m *MockRoleServiceInterface) UnassignRole(ctx context.Context, request entity.UnassignRoleRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignRole", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignRole indicates an expected call of UnassignRole.
+func (mr *MockRoleServiceInterfaceMockRecorder) UnassignRole(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignRole", reflect.TypeOf((*MockRoleServiceInterface)(nil).UnassignRole), ctx, request)
+}
+
+// MockSigningKeyServiceInterface is a mock of SigningKeyServiceInterface interface.
+type MockSigningKeyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningKeyServiceInterfaceMockRecorder
+}
+
+// MockSigningKeyServiceInterfaceMockRecorder is the mock recorder for MockSigningKeyServiceInterface.
+type MockSigningKeyServiceInterfaceMockRecorder struct {
+	mock *MockSigningKeyServiceInterface
+}
+
+// NewMockSigningKeyServiceInterface creates a new mock instance.
+func NewMockSigningKeyServiceInterface(ctrl *gomock.Controller) *MockSigningKeyServiceInterface {
+	mock := &MockSigningKeyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockSigningKeyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningKeyServiceInterface) EXPECT() *MockSigningKeyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetJSONWebKeySet mocks base method.
+func (m *MockSigningKeyServiceInterface) GetJSONWebKeySet(ctx context.Context) (entity.JSONWebKeySet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJSONWebKeySet", ctx)
+	ret0, _ := ret[0].(entity.JSONWebKeySet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJSONWebKeySet indicates an expected call of GetJSONWebKeySet.
+func (mr *MockSigningKeyServiceInterfaceMockRecorder) GetJSONWebKeySet(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJSONWebKeySet", reflect.TypeOf((*MockSigningKeyServiceInterface)(nil).GetJSONWebKeySet), ctx)
+}
+
+// RotateSigningKeys mocks base method.
+func (m *MockSigningKeyServiceInterface) RotateSigningKeys(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSigningKeys", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateSigningKeys indicates an expected call of RotateSigningKeys.
+func (mr *MockSigningKeyServiceInterfaceMockRecorder) RotateSigningKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSigningKeys", reflect.TypeOf((*MockSigningKeyServiceInterface)(nil).RotateSigningKeys), ctx)
+}
+
+// MockRateLimitServiceInterface is a mock of RateLimitServiceInterface interface.
+type MockRateLimitServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRateLimitServiceInterfaceMockRecorder
+}
+
+// MockRateLimitServiceInterfaceMockRecorder is the mock recorder for MockRateLimitServiceInterface.
+type MockRateLimitServiceInterfaceMockRecorder struct {
+	mock *MockRateLimitServiceInterface
+}
+
+// NewMockRateLimitServiceInterface creates a new mock instance.
+func NewMockRateLimitServiceInterface(ctrl *gomock.Controller) *MockRateLimitServiceInterface {
+	mock := &MockRateLimitServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRateLimitServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRateLimitServiceInterface) EXPECT() *MockRateLimitServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// PruneRateLimitBuckets mocks base method.
+func (m *MockRateLimitServiceInterface) PruneRateLimitBuckets(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneRateLimitBuckets", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneRateLimitBuckets indicates an expected call of PruneRateLimitBuckets.
+func (mr *MockRateLimitServiceInterfaceMockRecorder) PruneRateLimitBuckets(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneRateLimitBuckets", reflect.TypeOf((*MockRateLimitServiceInterface)(nil).PruneRateLimitBuckets), ctx)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockRateLimitServiceInterface) TakeRateLimitToken(ctx context.Context, request entity.TakeRateLimitTokenRequest) (entity.TakeRateLimitTokenResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", ctx, request)
+	ret0, _ := ret[0].(entity.TakeRateLimitTokenResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockRateLimitServiceInterfaceMockRecorder) TakeRateLimitToken(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockRateLimitServiceInterface)(nil).TakeRateLimitToken), ctx, request)
+}
@@ -0,0 +1,129 @@
+// Package authn lets other Go services accept the access tokens issued by
+// sawitpro. It verifies the signature against the published JSON Web Key Set
+// and the claims the same way the issuing service does, and hands the
+// verified claims to handlers through the request context.
+//
+// Verification is local, a token revoked before it expires is still accepted
+// until it does. Callers that must honour revocation ask the introspection
+// endpoint instead.
+//
+//	keySet, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{
+//		URL: "https://sawitpro.example/.well-known/jwks.json",
+//	})
+//	...
+//	verifier, err := authn.NewVerifier(authn.VerifierOptions{
+//		Issuer:   "sawitpro",
+//		Audience: "sawitpro-api",
+//		Leeway:   30 * time.Second,
+//		KeySet:   keySet,
+//	})
+//	...
+//	e.Use(authn.EchoMiddleware(verifier))
+//
+// and in a handler
+//
+//	profileId, ok := authn.ProfileIdFromContext(ctx.Request().Context())
+//
+// Tests mint tokens with package authntest.
+package authn
+
+import (
+	"errors"
+	"time"
+)
+
+// the claims sawitpro issues besides the registered ones
+const (
+	sessionIdClaim              = "sid"
+	passwordChangeRequiredClaim = "pwd_change"
+	clientIdClaim               = "client_id"
+	scopeClaim                  = "scope"
+	rolesClaim                  = "roles"
+)
+
+// LoginScopes returns the scopes held by every login whatever its roles, a
+// login issued before logins carried a scope holds them and nothing more.
+func LoginScopes() []string {
+	return []string{"openid", "profile:read", "profile:write"}
+}
+
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmES256 = "ES256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// signingAlgorithms pins the algorithms accepted in the alg header, anything
+// else (notably none and the HMAC family) is rejected before key lookup.
+var signingAlgorithms = []string{
+	SigningAlgorithmRS256,
+	SigningAlgorithmES256,
+	SigningAlgorithmEdDSA,
+}
+
+var (
+	ErrNotAuthenticated = errors.New("error not authenticated")
+	ErrInvalidToken     = errors.New("error invalid token")
+
+	ErrTokenExpired          = errors.New("error token has expired")
+	ErrTokenNotValidYet      = errors.New("error token is not valid yet")
+	ErrTokenMalformed        = errors.New("error token is malformed")
+	ErrTokenSignatureInvalid = errors.New("error token signature is invalid")
+	ErrTokenInvalidIssuer    = errors.New("error token has an invalid issuer")
+	ErrTokenInvalidAudience  = errors.New("error token has an invalid audience")
+
+	// ErrPasswordChangeRequired is returned for a token only good for setting
+	// a new password on sawitpro itself
+	ErrPasswordChangeRequired = errors.New("error password change required")
+
+	ErrUnsupportedSigningAlgorithm = errors.New("error unsupported signing algorithm")
+	ErrUnknownSigningKey           = errors.New("error unknown signing key")
+	ErrMalformedKey                = errors.New("error json web key is malformed")
+	ErrFetchKeySet                 = errors.New("error when fetching key set")
+
+	ErrMissingIssuer    = errors.New("error issuer is not configured")
+	ErrMissingAudience  = errors.New("error audience is not configured")
+	ErrMissingKeySet    = errors.New("error key set is not configured")
+	ErrMissingKeySetURL = errors.New("error key set url is not configured")
+)
+
+// Claims are the verified claims of an access token. A token of a user has a
+// ProfileId and a SessionId, and grants the Scopes of every login and the
+// permissions of its Roles. A token issued to an OAuth client also has its
+// ClientId and only grants its Scopes, and a client acting on its own has no
+// ProfileId nor SessionId.
+type Claims struct {
+	ProfileId string
+	SessionId string
+	TokenId   string
+	ExpiresAt time.Time
+	// PasswordChangeRequired is only ever set by a Verifier allowing such
+	// tokens
+	PasswordChangeRequired bool
+	ClientId               string
+	Scopes                 []string
+	Roles                  []string
+}
+
+// HasScope reports whether the token grants the scope. Services should check
+// the permissions they need rather than role names, roles are renamed and
+// regrouped on sawitpro without them noticing.
+func (c Claims) HasScope(scope string) bool {
+	return contains(c.Scopes, scope)
+}
+
+// HasRole reports whether the user holds the role as of the issue of the
+// token.
+func (c Claims) HasRole(role string) bool {
+	return contains(c.Roles, role)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
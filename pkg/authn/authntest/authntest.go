@@ -0,0 +1,202 @@
+// Package authntest mints access tokens for the unit tests of services using
+// package authn, signed with a key of its own instead of the one of
+// sawitpro.
+package authntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sawitpro/pkg/authn"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultIssuer   = "sawitpro"
+	DefaultAudience = "sawitpro-api"
+
+	tokenDuration = 15 * time.Minute
+)
+
+// Issuer signs tokens like sawitpro does.
+type Issuer struct {
+	issuer     string
+	audience   string
+	keyId      string
+	privateKey *ecdsa.PrivateKey
+}
+
+type IssuerOptions struct {
+	// Issuer and Audience default to DefaultIssuer and DefaultAudience
+	Issuer   string
+	Audience string
+}
+
+func NewIssuer(t testing.TB, opts IssuerOptions) *Issuer {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("authntest: generate key: %v", err)
+	}
+
+	issuer := opts.Issuer
+	if issuer == "" {
+		issuer = DefaultIssuer
+	}
+
+	audience := opts.Audience
+	if audience == "" {
+		audience = DefaultAudience
+	}
+
+	return &Issuer{
+		issuer:     issuer,
+		audience:   audience,
+		keyId:      uuid.NewString(),
+		privateKey: privateKey,
+	}
+}
+
+// Token mints a valid token carrying the claims. A missing TokenId is
+// generated and a zero ExpiresAt is in the future, a ProfileId without a
+// SessionId gets one too, so authn.Claims{ProfileId: "profile-id-1"} is
+// enough for a token of a user. Such a token states no scope, like a login
+// issued before logins carried one, and verifies with the scopes of every
+// login.
+func (iss *Issuer) Token(t testing.TB, claims authn.Claims) string {
+	t.Helper()
+
+	now := time.Now()
+
+	expiresAt := claims.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(tokenDuration)
+	}
+
+	tokenId := claims.TokenId
+	if tokenId == "" {
+		tokenId = uuid.NewString()
+	}
+
+	mapClaims := jwt.MapClaims{
+		"iss": iss.issuer,
+		"aud": iss.audience,
+		"sub": claims.ProfileId,
+		"jti": tokenId,
+		"iat": jwt.NewNumericDate(now),
+		"nbf": jwt.NewNumericDate(now),
+		"exp": jwt.NewNumericDate(expiresAt),
+	}
+
+	// a client acting on its own is the subject, there is no session
+	if claims.ProfileId == "" && claims.ClientId != "" {
+		mapClaims["sub"] = claims.ClientId
+	} else {
+		sessionId := claims.SessionId
+		if sessionId == "" {
+			sessionId = uuid.NewString()
+		}
+		mapClaims["sid"] = sessionId
+	}
+
+	if claims.ClientId != "" {
+		mapClaims["client_id"] = claims.ClientId
+	}
+
+	// a client token always states its scopes, a login those its roles grant
+	if claims.ClientId != "" || len(claims.Scopes) > 0 {
+		mapClaims["scope"] = strings.Join(claims.Scopes, " ")
+	}
+
+	if len(claims.Roles) > 0 {
+		mapClaims["roles"] = claims.Roles
+	}
+
+	return iss.Sign(t, mapClaims)
+}
+
+// Sign signs the claims as they are, to mint the invalid tokens Token does
+// not.
+func (iss *Issuer) Sign(t testing.TB, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = iss.keyId
+
+	signed, err := token.SignedString(iss.privateKey)
+	if err != nil {
+		t.Fatalf("authntest: sign token: %v", err)
+	}
+
+	return signed
+}
+
+func (iss *Issuer) JSONWebKeySet() authn.JSONWebKeySet {
+	size := (iss.privateKey.Curve.Params().BitSize + 7) / 8
+
+	return authn.JSONWebKeySet{
+		Keys: []authn.JSONWebKey{
+			{
+				KeyType:   "EC",
+				KeyId:     iss.keyId,
+				Use:       "sig",
+				Algorithm: authn.SigningAlgorithmES256,
+				Curve:     "P-256",
+				X:         base64.RawURLEncoding.EncodeToString(iss.privateKey.X.FillBytes(make([]byte, size))),
+				Y:         base64.RawURLEncoding.EncodeToString(iss.privateKey.Y.FillBytes(make([]byte, size))),
+			},
+		},
+	}
+}
+
+func (iss *Issuer) KeySet() authn.StaticKeySet {
+	return authn.NewStaticKeySet(iss.JSONWebKeySet())
+}
+
+// Verifier accepts the tokens of the issuer.
+func (iss *Issuer) Verifier(t testing.TB) *authn.Verifier {
+	t.Helper()
+
+	verifier, err := authn.NewVerifier(authn.VerifierOptions{
+		Issuer:   iss.issuer,
+		Audience: iss.audience,
+		KeySet:   iss.KeySet(),
+	})
+	if err != nil {
+		t.Fatalf("authntest: new verifier: %v", err)
+	}
+
+	return verifier
+}
+
+// Server serves the key set of the issuer, for tests of a RemoteKeySet. It
+// is closed when the test ends.
+func (iss *Issuer) Server(t testing.TB) *httptest.Server {
+	t.Helper()
+
+	body, err := json.Marshal(iss.JSONWebKeySet())
+	if err != nil {
+		t.Fatalf("authntest: marshal key set: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(authn.DefaultKeySetMaxAge.Seconds())))
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
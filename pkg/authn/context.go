@@ -0,0 +1,39 @@
+package authn
+
+import "context"
+
+type claimsContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the claims, the middlewares call
+// it for every request they let through.
+func NewContext(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the token the request was
+// authenticated with.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(Claims)
+	return claims, ok
+}
+
+// ProfileIdFromContext returns the profile the request was made for. It is
+// false for an unauthenticated request and for a client acting on its own.
+func ProfileIdFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.ProfileId == "" {
+		return "", false
+	}
+
+	return claims.ProfileId, true
+}
+
+// ClientIdFromContext returns the OAuth client the token was issued to.
+func ClientIdFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || claims.ClientId == "" {
+		return "", false
+	}
+
+	return claims.ClientId, true
+}
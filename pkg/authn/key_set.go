@@ -0,0 +1,153 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// KeySet resolves the public key a token names in its kid header. It must
+// refuse a key whose algorithm is not the one in the alg header of the token.
+type KeySet interface {
+	VerificationKey(ctx context.Context, keyId string, algorithm string) (crypto.PublicKey, error)
+}
+
+// JSONWebKey is a public key as published on the jwks_uri of the issuer.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+type publicKey struct {
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// StaticKeySet is a KeySet of keys known upfront.
+type StaticKeySet struct {
+	keys map[string]publicKey
+}
+
+// NewStaticKeySet parses the keys of a key set. Keys not used for signatures
+// or of a type not issued by sawitpro are skipped, so that an issuer adding
+// them does not break its verifiers.
+func NewStaticKeySet(set JSONWebKeySet) StaticKeySet {
+	keys := make(map[string]publicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.KeyId] = publicKey{
+			algorithm: jwk.Algorithm,
+			key:       key,
+		}
+	}
+
+	return StaticKeySet{
+		keys: keys,
+	}
+}
+
+func (set StaticKeySet) VerificationKey(ctx context.Context, keyId string, algorithm string) (crypto.PublicKey, error) {
+	key, exists := set.keys[keyId]
+	if !exists {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if key.algorithm != algorithm {
+		return nil, ErrUnsupportedSigningAlgorithm
+	}
+
+	return key.key, nil
+}
+
+// PublicKey decodes the key for the algorithm it declares.
+func (jwk JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch {
+	case jwk.Algorithm == SigningAlgorithmRS256 && jwk.KeyType == "RSA":
+		n, err := decodeBase64URL(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBase64URL(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, ErrMalformedKey
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, nil
+	case jwk.Algorithm == SigningAlgorithmES256 && jwk.KeyType == "EC" && jwk.Curve == "P-256":
+		x, err := decodeBase64URL(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBase64URL(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, ErrMalformedKey
+		}
+
+		return key, nil
+	case jwk.Algorithm == SigningAlgorithmEdDSA && jwk.KeyType == "OKP" && jwk.Curve == "Ed25519":
+		x, err := decodeBase64URL(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, ErrMalformedKey
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrUnsupportedSigningAlgorithm
+	}
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrMalformedKey
+	}
+
+	return b, nil
+}
@@ -0,0 +1,86 @@
+package authn
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// BearerToken returns the token of the Authorization header.
+func BearerToken(req *http.Request) (string, error) {
+	authHdr := req.Header.Get("Authorization")
+
+	if authHdr == "" {
+		return "", ErrNotAuthenticated
+	}
+
+	prefix := "Bearer "
+	if !strings.HasPrefix(authHdr, prefix) {
+		return "", ErrNotAuthenticated
+	}
+	return strings.TrimPrefix(authHdr, prefix), nil
+}
+
+// Middleware refuses requests without a valid access token with a 401, or a
+// 503 when the keys of the issuer could not be fetched, and passes the claims
+// of the others on in the request context.
+func Middleware(verifier *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, err := verifier.verifyRequest(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(statusCode(err))
+				_ = json.NewEncoder(w).Encode(errorResponse{Message: err.Error()})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+		})
+	}
+}
+
+// EchoMiddleware is Middleware for Echo. The claims are in the context of
+// ctx.Request().
+func EchoMiddleware(verifier *Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			claims, err := verifier.verifyRequest(ctx.Request())
+			if err != nil {
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return ctx.JSON(statusCode(err), errorResponse{Message: err.Error()})
+			}
+
+			ctx.SetRequest(ctx.Request().WithContext(NewContext(ctx.Request().Context(), claims)))
+
+			return next(ctx)
+		}
+	}
+}
+
+func (v *Verifier) verifyRequest(req *http.Request) (Claims, error) {
+	token, err := BearerToken(req)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	return v.Verify(req.Context(), token)
+}
+
+// statusCode tells the caller whether it is their token or our fetching of
+// the keys that failed.
+func statusCode(err error) int {
+	if errors.Is(err, ErrFetchKeySet) {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,167 @@
+package authn_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/pkg/authn"
+	"sawitpro/pkg/authn/authntest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "success",
+			header:  "Bearer token-1",
+			want:    "token-1",
+			wantErr: nil,
+		},
+		{
+			name:    "error without header",
+			header:  "",
+			wantErr: authn.ErrNotAuthenticated,
+		},
+		{
+			name:    "error other scheme",
+			header:  "Basic dXNlcjpwYXNz",
+			wantErr: authn.ErrNotAuthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			got, err := authn.BearerToken(req)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	issuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+	otherIssuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+
+	tests := []struct {
+		name       string
+		header     string
+		want       string
+		statusCode int
+	}{
+		{
+			name:       "success token of a user",
+			header:     "Bearer " + issuer.Token(t, authn.Claims{ProfileId: "profile-id-1"}),
+			want:       "profile-id-1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "success token of a client acting on its own",
+			header:     "Bearer " + issuer.Token(t, authn.Claims{ClientId: "client-id-1"}),
+			want:       "no profile",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "error without token",
+			header:     "",
+			want:       `{"message":"error not authenticated"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:       "error token of another issuer",
+			header:     "Bearer " + otherIssuer.Token(t, authn.Claims{ProfileId: "profile-id-1"}),
+			want:       `{"message":"error unknown signing key"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := authn.Middleware(issuer.Verifier(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				profileId, ok := authn.ProfileIdFromContext(r.Context())
+				if !ok {
+					profileId = "no profile"
+				}
+
+				_, _ = w.Write([]byte(profileId))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, tt.want, strings.TrimSpace(rec.Body.String()))
+			if tt.statusCode == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestEchoMiddleware(t *testing.T) {
+	issuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+
+	tests := []struct {
+		name       string
+		header     string
+		want       string
+		statusCode int
+	}{
+		{
+			name:       "success token of an oauth client acting for a user",
+			header:     "Bearer " + issuer.Token(t, authn.Claims{ProfileId: "profile-id-1", ClientId: "client-id-1", Scopes: []string{"profile:read"}}),
+			want:       "profile-id-1 client-id-1 true",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "error malformed token",
+			header:     "Bearer not-a-token",
+			want:       `{"message":"error token is malformed"}`,
+			statusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(authn.EchoMiddleware(issuer.Verifier(t)))
+
+			e.GET("/", func(ctx echo.Context) error {
+				claims, _ := authn.ClaimsFromContext(ctx.Request().Context())
+				clientId, _ := authn.ClientIdFromContext(ctx.Request().Context())
+
+				return ctx.String(http.StatusOK, strings.Join([]string{
+					claims.ProfileId,
+					clientId,
+					strconv.FormatBool(claims.HasScope("profile:read")),
+				}, " "))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", tt.header)
+
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+			assert.Equal(t, tt.want, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultKeySetMaxAge is how long a key set is kept when the issuer did
+	// not say, sawitpro publishes its keys with the same max-age
+	DefaultKeySetMaxAge = 5 * time.Minute
+
+	// DefaultKeySetMinRefreshInterval keeps tokens naming unknown keys from
+	// making us fetch the key set on every request
+	DefaultKeySetMinRefreshInterval = time.Minute
+
+	keySetFetchTimeout = 10 * time.Second
+	keySetMaxSize      = 1 << 20
+)
+
+// RemoteKeySet is a KeySet fetched from the jwks_uri of the issuer. The keys
+// are cached for the max-age the issuer sends, and fetched again early when a
+// token names a key not seen yet, since a new key is published ahead of
+// signing with it. Cached keys are read while a fetch is in flight, and
+// concurrent requests for a fetch share a single one.
+type RemoteKeySet struct {
+	url                string
+	client             *http.Client
+	maxAge             time.Duration
+	minRefreshInterval time.Duration
+
+	fetches     *singleflight.Group
+	mu          *sync.RWMutex
+	keys        *StaticKeySet
+	expiresAt   time.Time
+	attemptedAt time.Time
+}
+
+type RemoteKeySetOptions struct {
+	URL        string
+	HTTPClient *http.Client
+	// MaxAge is used when the response has no Cache-Control max-age
+	MaxAge             time.Duration
+	MinRefreshInterval time.Duration
+}
+
+func NewRemoteKeySet(opts RemoteKeySetOptions) (*RemoteKeySet, error) {
+	if opts.URL == "" {
+		return nil, ErrMissingKeySetURL
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: keySetFetchTimeout}
+	}
+
+	maxAge := opts.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultKeySetMaxAge
+	}
+
+	minRefreshInterval := opts.MinRefreshInterval
+	if minRefreshInterval == 0 {
+		minRefreshInterval = DefaultKeySetMinRefreshInterval
+	}
+
+	return &RemoteKeySet{
+		url:                opts.URL,
+		client:             client,
+		maxAge:             maxAge,
+		minRefreshInterval: minRefreshInterval,
+		fetches:            &singleflight.Group{},
+		mu:                 &sync.RWMutex{},
+	}, nil
+}
+
+// VerificationKey resolves the key from the cached key set, fetching it
+// first when it is missing, stale or does not have the key. A failed fetch
+// keeps the keys fetched before in use.
+func (set *RemoteKeySet) VerificationKey(ctx context.Context, keyId string, algorithm string) (crypto.PublicKey, error) {
+	set.mu.RLock()
+	keys, fresh := set.keys, time.Now().Before(set.expiresAt)
+	set.mu.RUnlock()
+
+	if keys != nil && fresh {
+		key, err := keys.VerificationKey(ctx, keyId, algorithm)
+		if err != ErrUnknownSigningKey {
+			return key, err
+		}
+	}
+
+	keys, err := set.refresh(ctx)
+	if keys == nil {
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, ErrFetchKeySet
+	}
+
+	return keys.VerificationKey(ctx, keyId, algorithm)
+}
+
+// refresh fetches the key set, at most once per minimum refresh interval,
+// and returns the keys in use afterwards. Callers giving up wait no longer
+// than their ctx, the fetch itself is bounded by keySetFetchTimeout.
+func (set *RemoteKeySet) refresh(ctx context.Context) (*StaticKeySet, error) {
+	result := set.fetches.DoChan(set.url, func() (interface{}, error) {
+		now := time.Now()
+
+		set.mu.Lock()
+		if !set.attemptedAt.IsZero() && now.Sub(set.attemptedAt) < set.minRefreshInterval {
+			keys := set.keys
+			set.mu.Unlock()
+
+			return keys, nil
+		}
+		set.attemptedAt = now
+		set.mu.Unlock()
+
+		// shared by every caller waiting for it, so it must not end with
+		// the request of the one that started it
+		fetchCtx, cancel := context.WithTimeout(context.Background(), keySetFetchTimeout)
+		defer cancel()
+
+		keys, maxAge, err := set.fetch(fetchCtx)
+
+		set.mu.Lock()
+		defer set.mu.Unlock()
+
+		if err != nil {
+			return set.keys, err
+		}
+
+		set.keys = keys
+		set.expiresAt = now.Add(maxAge)
+
+		return keys, nil
+	})
+
+	select {
+	case res := <-result:
+		keys, _ := res.Val.(*StaticKeySet)
+		return keys, res.Err
+	case <-ctx.Done():
+		set.mu.RLock()
+		defer set.mu.RUnlock()
+
+		return set.keys, fmt.Errorf("%w: %v", ErrFetchKeySet, ctx.Err())
+	}
+}
+
+// fetch gets the key set and how long it may be cached.
+func (set *RemoteKeySet) fetch(ctx context.Context) (*StaticKeySet, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, set.url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeySet, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := set.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeySet, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("%w: unexpected status %d", ErrFetchKeySet, resp.StatusCode)
+	}
+
+	var jwks JSONWebKeySet
+	err = json.NewDecoder(io.LimitReader(resp.Body, keySetMaxSize)).Decode(&jwks)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrFetchKeySet, err)
+	}
+
+	keys := NewStaticKeySet(jwks)
+
+	return &keys, cacheMaxAge(resp.Header.Get("Cache-Control"), set.maxAge), nil
+}
+
+// cacheMaxAge reads the max-age directive, no-cache and no-store keep the
+// keys for no longer than the minimum refresh interval.
+func cacheMaxAge(cacheControl string, fallback time.Duration) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+
+		switch {
+		case directive == "no-cache", directive == "no-store":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(directive, "max-age="), 10, 64)
+			if err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	return fallback
+}
@@ -0,0 +1,280 @@
+package authn_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sawitpro/pkg/authn"
+	"sawitpro/pkg/authn/authntest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRemoteKeySet(t *testing.T) {
+	_, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{})
+	assert.Equal(t, authn.ErrMissingKeySetURL, err)
+}
+
+func TestRemoteKeySet_VerificationKey(t *testing.T) {
+	oldIssuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+	newIssuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+
+	keyIdOf := func(issuer *authntest.Issuer) string {
+		return issuer.JSONWebKeySet().Keys[0].KeyId
+	}
+
+	// serves the key set of the issuer in current, or fails when it is nil
+	newServer := func(t *testing.T, cacheControl string, current *atomic.Value, fetches *int32) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(fetches, 1)
+
+			issuer, _ := current.Load().(*authntest.Issuer)
+			if issuer == nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Cache-Control", cacheControl)
+			_ = json.NewEncoder(w).Encode(issuer.JSONWebKeySet())
+		}))
+		t.Cleanup(server.Close)
+
+		return server
+	}
+
+	t.Run("success keys are cached for their max-age", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store(oldIssuer)
+		server := newServer(t, "public, max-age=300", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			key, err := set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+			assert.NoError(t, err)
+			assert.NotNil(t, key)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("success unknown key is fetched again", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store(oldIssuer)
+		server := newServer(t, "public, max-age=300", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{
+			URL:                server.URL,
+			MinRefreshInterval: time.Nanosecond,
+		})
+		assert.NoError(t, err)
+
+		_, err = set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.NoError(t, err)
+
+		current.Store(newIssuer)
+
+		key, err := set.VerificationKey(context.TODO(), keyIdOf(newIssuer), authn.SigningAlgorithmES256)
+		assert.NoError(t, err)
+		assert.NotNil(t, key)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("error unknown key is not fetched again before the minimum interval", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store(oldIssuer)
+		server := newServer(t, "public, max-age=300", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err = set.VerificationKey(context.TODO(), "unknown-key-id", authn.SigningAlgorithmES256)
+			assert.Equal(t, authn.ErrUnknownSigningKey, err)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("error key of another algorithm", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store(oldIssuer)
+		server := newServer(t, "public, max-age=300", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		_, err = set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmRS256)
+		assert.Equal(t, authn.ErrUnsupportedSigningAlgorithm, err)
+	})
+
+	t.Run("success stale keys are kept when fetching fails", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store(oldIssuer)
+		server := newServer(t, "no-store", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{
+			URL:                server.URL,
+			MinRefreshInterval: time.Nanosecond,
+		})
+		assert.NoError(t, err)
+
+		_, err = set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.NoError(t, err)
+
+		current.Store((*authntest.Issuer)(nil))
+
+		key, err := set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.NoError(t, err)
+		assert.NotNil(t, key)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("error fetching fails without keys", func(t *testing.T) {
+		var current atomic.Value
+		var fetches int32
+		current.Store((*authntest.Issuer)(nil))
+		server := newServer(t, "public, max-age=300", &current, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		_, err = set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.True(t, errors.Is(err, authn.ErrFetchKeySet))
+	})
+
+	// serves the key set of the issuer, holding every fetch after the first
+	// skipped ones until release is closed, and telling of each on fetching
+	newSlowServer := func(t *testing.T, skip int32, fetches *int32) (*httptest.Server, chan struct{}, chan struct{}) {
+		fetching := make(chan struct{}, 16)
+		release := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(fetches, 1) > skip {
+				fetching <- struct{}{}
+				<-release
+			}
+
+			w.Header().Set("Cache-Control", "public, max-age=300")
+			_ = json.NewEncoder(w).Encode(oldIssuer.JSONWebKeySet())
+		}))
+		t.Cleanup(server.Close)
+		// before closing the server, which waits for the held fetches
+		t.Cleanup(func() {
+			select {
+			case <-release:
+			default:
+				close(release)
+			}
+		})
+
+		return server, fetching, release
+	}
+
+	t.Run("success cached keys are read while a fetch is in flight", func(t *testing.T) {
+		var fetches int32
+		server, fetching, release := newSlowServer(t, 1, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{
+			URL:                server.URL,
+			MinRefreshInterval: time.Nanosecond,
+		})
+		assert.NoError(t, err)
+
+		_, err = set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.NoError(t, err)
+
+		unknownDone := make(chan error)
+		go func() {
+			_, err := set.VerificationKey(context.TODO(), "unknown-key-id", authn.SigningAlgorithmES256)
+			unknownDone <- err
+		}()
+		<-fetching
+
+		knownDone := make(chan error)
+		go func() {
+			_, err := set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+			knownDone <- err
+		}()
+
+		select {
+		case err := <-knownDone:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("cached key was not read while the fetch was in flight")
+		}
+
+		close(release)
+		assert.Equal(t, authn.ErrUnknownSigningKey, <-unknownDone)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("success concurrent requests share a single fetch", func(t *testing.T) {
+		var fetches int32
+		server, fetching, release := newSlowServer(t, 0, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := set.VerificationKey(context.TODO(), keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+				errs <- err
+			}()
+		}
+		<-fetching
+
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&fetches))
+	})
+
+	t.Run("error request gives up on a hanging fetch", func(t *testing.T) {
+		var fetches int32
+		server, _, _ := newSlowServer(t, 0, &fetches)
+
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: server.URL})
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = set.VerificationKey(ctx, keyIdOf(oldIssuer), authn.SigningAlgorithmES256)
+		assert.True(t, errors.Is(err, authn.ErrFetchKeySet))
+	})
+
+	t.Run("success verifier with the key set of the issuer", func(t *testing.T) {
+		set, err := authn.NewRemoteKeySet(authn.RemoteKeySetOptions{URL: oldIssuer.Server(t).URL})
+		assert.NoError(t, err)
+
+		verifier, err := authn.NewVerifier(authn.VerifierOptions{
+			Issuer:   authntest.DefaultIssuer,
+			Audience: authntest.DefaultAudience,
+			KeySet:   set,
+		})
+		assert.NoError(t, err)
+
+		got, err := verifier.Verify(context.TODO(), oldIssuer.Token(t, authn.Claims{ProfileId: "profile-id-1"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "profile-id-1", got.ProfileId)
+	})
+}
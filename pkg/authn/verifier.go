@@ -0,0 +1,211 @@
+package authn
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Verifier checks access tokens of a single issuer and audience.
+type Verifier struct {
+	issuer                      string
+	audience                    string
+	leeway                      time.Duration
+	keySet                      KeySet
+	allowPasswordChangeRequired bool
+}
+
+type VerifierOptions struct {
+	// Issuer and Audience are the iss and aud the tokens must carry, those
+	// sawitpro is configured with
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew tolerated on exp, nbf and iat
+	Leeway time.Duration
+	// KeySet resolves the kid of a token, usually a RemoteKeySet of the
+	// issuer's jwks_uri
+	KeySet KeySet
+	// AllowPasswordChangeRequired accepts tokens restricted to changing the
+	// password, only sawitpro itself serves that operation
+	AllowPasswordChangeRequired bool
+}
+
+func NewVerifier(opts VerifierOptions) (*Verifier, error) {
+	if opts.Issuer == "" {
+		return nil, ErrMissingIssuer
+	}
+
+	if opts.Audience == "" {
+		return nil, ErrMissingAudience
+	}
+
+	if opts.KeySet == nil {
+		return nil, ErrMissingKeySet
+	}
+
+	return &Verifier{
+		issuer:                      opts.Issuer,
+		audience:                    opts.Audience,
+		leeway:                      opts.Leeway,
+		keySet:                      opts.KeySet,
+		allowPasswordChangeRequired: opts.AllowPasswordChangeRequired,
+	}, nil
+}
+
+// Verify returns the claims of a valid access token. A token restricted to
+// changing the password is refused unless the Verifier allows it, it is not
+// meant for any other service.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	var res = Claims{}
+
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrUnknownSigningKey
+		}
+
+		return v.keySet.VerificationKey(ctx, kid, token.Method.Alg())
+	}
+
+	jwtToken, err := jwt.Parse(
+		token,
+		keyFunc,
+		jwt.WithValidMethods(signingAlgorithms),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audience),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return res, tokenError(err)
+	}
+
+	claims, claimsExist := jwtToken.Claims.(jwt.MapClaims)
+	if !claimsExist {
+		return res, ErrInvalidToken
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return res, ErrTokenMalformed
+	}
+
+	if subject == "" {
+		return res, ErrInvalidToken
+	}
+
+	tokenId, ok := claims["jti"].(string)
+	if !ok || tokenId == "" {
+		return res, ErrInvalidToken
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return res, ErrTokenMalformed
+	}
+
+	// anything but an explicit true would lift the restriction, so a
+	// malformed value is refused rather than ignored
+	passwordChangeRequired := false
+	if value, exists := claims[passwordChangeRequiredClaim]; exists {
+		passwordChangeRequired, ok = value.(bool)
+		if !ok {
+			return res, ErrTokenMalformed
+		}
+	}
+
+	if passwordChangeRequired && !v.allowPasswordChangeRequired {
+		return res, ErrPasswordChangeRequired
+	}
+
+	var clientId string
+	if value, exists := claims[clientIdClaim]; exists {
+		clientId, ok = value.(string)
+		if !ok || clientId == "" {
+			return res, ErrTokenMalformed
+		}
+
+		if _, exists := claims[scopeClaim]; !exists {
+			return res, ErrTokenMalformed
+		}
+	}
+
+	// a malformed scope must not pass for the scopes of a login
+	scopes := LoginScopes()
+	if value, exists := claims[scopeClaim]; exists {
+		scope, ok := value.(string)
+		if !ok {
+			return res, ErrTokenMalformed
+		}
+		scopes = strings.Fields(scope)
+	}
+
+	var roles []string
+	if value, exists := claims[rolesClaim]; exists {
+		values, ok := value.([]interface{})
+		if !ok {
+			return res, ErrTokenMalformed
+		}
+
+		for _, v := range values {
+			role, ok := v.(string)
+			if !ok {
+				return res, ErrTokenMalformed
+			}
+			roles = append(roles, role)
+		}
+	}
+
+	// only a client acting on its own has no sid, and it is its own sub
+	profileId := subject
+	sessionId, ok := claims[sessionIdClaim].(string)
+	if _, exists := claims[sessionIdClaim]; !exists && clientId != "" && subject == clientId {
+		profileId = ""
+	} else if !ok || sessionId == "" {
+		return res, ErrInvalidToken
+	}
+
+	res = Claims{
+		ProfileId:              profileId,
+		SessionId:              sessionId,
+		TokenId:                tokenId,
+		ExpiresAt:              expiresAt.Time,
+		PasswordChangeRequired: passwordChangeRequired,
+		ClientId:               clientId,
+		Scopes:                 scopes,
+		Roles:                  roles,
+	}
+
+	return res, nil
+}
+
+// tokenError narrows the errors of the jwt package down to the ones we
+// report, most actionable first. Errors of the key set are kept as they are.
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, ErrFetchKeySet):
+		return ErrFetchKeySet
+	case errors.Is(err, ErrUnknownSigningKey):
+		return ErrUnknownSigningKey
+	case errors.Is(err, ErrUnsupportedSigningAlgorithm):
+		return ErrUnsupportedSigningAlgorithm
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return ErrTokenNotValidYet
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ErrTokenInvalidAudience
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrTokenInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return ErrTokenSignatureInvalid
+	default:
+		return ErrInvalidToken
+	}
+}
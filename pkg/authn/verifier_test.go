@@ -0,0 +1,326 @@
+package authn_test
+
+import (
+	"context"
+	"encoding/json"
+	"sawitpro/entity"
+	"sawitpro/helper"
+	"sawitpro/pkg/authn"
+	"sawitpro/pkg/authn/authntest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    authn.VerifierOptions
+		wantErr error
+	}{
+		{
+			name: "success",
+			opts: authn.VerifierOptions{
+				Issuer:   "sawitpro",
+				Audience: "sawitpro-api",
+				KeySet:   authn.StaticKeySet{},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "error missing issuer",
+			opts: authn.VerifierOptions{
+				Audience: "sawitpro-api",
+				KeySet:   authn.StaticKeySet{},
+			},
+			wantErr: authn.ErrMissingIssuer,
+		},
+		{
+			name: "error missing audience",
+			opts: authn.VerifierOptions{
+				Issuer: "sawitpro",
+				KeySet: authn.StaticKeySet{},
+			},
+			wantErr: authn.ErrMissingAudience,
+		},
+		{
+			name: "error missing key set",
+			opts: authn.VerifierOptions{
+				Issuer:   "sawitpro",
+				Audience: "sawitpro-api",
+			},
+			wantErr: authn.ErrMissingKeySet,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authn.NewVerifier(tt.opts)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	issuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+	otherIssuer := authntest.NewIssuer(t, authntest.IssuerOptions{})
+	verifier := issuer.Verifier(t)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Minute).Truncate(time.Second)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": authntest.DefaultIssuer,
+			"aud": authntest.DefaultAudience,
+			"sub": "profile-id-1",
+			"sid": "session-id-1",
+			"jti": "token-id-1",
+			"iat": jwt.NewNumericDate(now),
+			"exp": jwt.NewNumericDate(expiresAt),
+		}
+	}
+
+	withClaim := func(name string, value interface{}) string {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+
+		return issuer.Sign(t, claims)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    authn.Claims
+		wantErr error
+	}{
+		{
+			name:  "success token of a user issued before logins carried a scope",
+			token: issuer.Sign(t, validClaims()),
+			want: authn.Claims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				Scopes:    []string{"openid", "profile:read", "profile:write"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success token of a user with roles",
+			token: issuer.Token(t, authn.Claims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				Scopes:    []string{"openid", "profile:read", "profile:write", "roles:read"},
+				Roles:     []string{"auditor"},
+			}),
+			want: authn.Claims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				Scopes:    []string{"openid", "profile:read", "profile:write", "roles:read"},
+				Roles:     []string{"auditor"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success token of an oauth client acting for a user",
+			token: issuer.Token(t, authn.Claims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profile:read", "profile:write"},
+			}),
+			want: authn.Claims{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profile:read", "profile:write"},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success token of a client acting on its own",
+			token: issuer.Token(t, authn.Claims{
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profiles:read"},
+			}),
+			want: authn.Claims{
+				TokenId:   "token-id-1",
+				ExpiresAt: expiresAt,
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profiles:read"},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "error expired",
+			token:   withClaim("exp", jwt.NewNumericDate(now.Add(-time.Minute))),
+			wantErr: authn.ErrTokenExpired,
+		},
+		{
+			name:    "error without expiry",
+			token:   withClaim("exp", nil),
+			wantErr: authn.ErrInvalidToken,
+		},
+		{
+			name:    "error other issuer",
+			token:   withClaim("iss", "someone-else"),
+			wantErr: authn.ErrTokenInvalidIssuer,
+		},
+		{
+			name:    "error other audience",
+			token:   withClaim("aud", "someone-else"),
+			wantErr: authn.ErrTokenInvalidAudience,
+		},
+		{
+			name:    "error signed with an unknown key",
+			token:   otherIssuer.Sign(t, validClaims()),
+			wantErr: authn.ErrUnknownSigningKey,
+		},
+		{
+			name:    "error without subject",
+			token:   withClaim("sub", nil),
+			wantErr: authn.ErrInvalidToken,
+		},
+		{
+			name:    "error without session of a user",
+			token:   withClaim("sid", nil),
+			wantErr: authn.ErrInvalidToken,
+		},
+		{
+			name:    "error restricted to changing the password",
+			token:   withClaim("pwd_change", true),
+			wantErr: authn.ErrPasswordChangeRequired,
+		},
+		{
+			name:    "error malformed password change claim",
+			token:   withClaim("pwd_change", "false"),
+			wantErr: authn.ErrTokenMalformed,
+		},
+		{
+			name:    "error client without scope claim",
+			token:   withClaim("client_id", "client-id-1"),
+			wantErr: authn.ErrTokenMalformed,
+		},
+		{
+			name:    "error malformed scope claim",
+			token:   withClaim("scope", []string{"profile:read"}),
+			wantErr: authn.ErrTokenMalformed,
+		},
+		{
+			name:    "error malformed roles claim",
+			token:   withClaim("roles", "admin"),
+			wantErr: authn.ErrTokenMalformed,
+		},
+		{
+			name:    "error not a token",
+			token:   "not-a-token",
+			wantErr: authn.ErrTokenMalformed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(context.TODO(), tt.token)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	t.Run("error unsigned token", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		assert.NoError(t, err)
+
+		_, err = verifier.Verify(context.TODO(), token)
+		assert.Equal(t, authn.ErrTokenSignatureInvalid, err)
+	})
+
+	t.Run("success restricted to changing the password when allowed", func(t *testing.T) {
+		allowingVerifier, err := authn.NewVerifier(authn.VerifierOptions{
+			Issuer:                      authntest.DefaultIssuer,
+			Audience:                    authntest.DefaultAudience,
+			KeySet:                      issuer.KeySet(),
+			AllowPasswordChangeRequired: true,
+		})
+		assert.NoError(t, err)
+
+		got, err := allowingVerifier.Verify(context.TODO(), withClaim("pwd_change", true))
+		assert.NoError(t, err)
+		assert.True(t, got.PasswordChangeRequired)
+
+		got, err = allowingVerifier.Verify(context.TODO(), issuer.Sign(t, validClaims()))
+		assert.NoError(t, err)
+		assert.False(t, got.PasswordChangeRequired)
+	})
+}
+
+// TestVerifier_Verify_issuedTokens keeps the package in step with the tokens
+// sawitpro issues, for every signing algorithm it can be configured with.
+func TestVerifier_Verify_issuedTokens(t *testing.T) {
+	for _, algorithm := range []string{authn.SigningAlgorithmRS256, authn.SigningAlgorithmES256, authn.SigningAlgorithmEdDSA} {
+		t.Run(algorithm, func(t *testing.T) {
+			ring, err := helper.NewKeyRing(helper.KeyRingOptions{
+				Algorithm: algorithm,
+				Secret:    "secret",
+			})
+			assert.NoError(t, err)
+
+			key, err := ring.GenerateKey(context.TODO())
+			assert.NoError(t, err)
+			key.ActivatesAt = time.Now().Add(-time.Minute)
+
+			err = ring.LoadKeys(context.TODO(), []entity.SigningKey{key})
+			assert.NoError(t, err)
+
+			authHelper := helper.NewAuthHelper(helper.AuthHelperOptions{
+				KeyRing:  ring,
+				Issuer:   "sawitpro",
+				Audience: "sawitpro-api",
+			})
+
+			token, err := authHelper.GenerateToken(context.TODO(), entity.GenerateTokenRequest{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-1",
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profile:read"},
+			})
+			assert.NoError(t, err)
+
+			// through JSON, as a service fetching the key set sees it
+			published, err := json.Marshal(entity.JSONWebKeySet{Keys: ring.JSONWebKeys(context.TODO())})
+			assert.NoError(t, err)
+
+			var jwks authn.JSONWebKeySet
+			err = json.Unmarshal(published, &jwks)
+			assert.NoError(t, err)
+
+			verifier, err := authn.NewVerifier(authn.VerifierOptions{
+				Issuer:   "sawitpro",
+				Audience: "sawitpro-api",
+				KeySet:   authn.NewStaticKeySet(jwks),
+			})
+			assert.NoError(t, err)
+
+			got, err := verifier.Verify(context.TODO(), token)
+			assert.NoError(t, err)
+			assert.Equal(t, "profile-id-1", got.ProfileId)
+			assert.Equal(t, "session-id-1", got.SessionId)
+			assert.Equal(t, "client-id-1", got.ClientId)
+			assert.Equal(t, []string{"profile:read"}, got.Scopes)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type mfaChallengeRepository struct {
+	db *sqlx.DB
+}
+
+func NewMFAChallengeRepository(db *sqlx.DB) mfaChallengeRepository {
+	return mfaChallengeRepository{
+		db: db,
+	}
+}
+
+func (repo mfaChallengeRepository) InsertMFAChallenge(ctx context.Context, tx *sqlx.Tx, challenge entity.MFAChallenge) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertMFAChallenge,
+			challenge.ProfileId,
+			challenge.TokenHash,
+			challenge.ExpiresAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertMFAChallenge,
+			challenge.ProfileId,
+			challenge.TokenHash,
+			challenge.ExpiresAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+// GetMFAChallengeByHash returns the challenge issued under the token hash.
+// Inside a transaction the row stays locked until it ends, so concurrent
+// attempts against the same challenge are counted one by one.
+func (repo mfaChallengeRepository) GetMFAChallengeByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.MFAChallenge, error) {
+	var res entity.MFAChallenge
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetMFAChallengeByHash, tokenHash)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetMFAChallengeByHash, tokenHash)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.MFAChallenge{}, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo mfaChallengeRepository) IncreaseMFAChallengeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryIncreaseMFAChallengeAttempts, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryIncreaseMFAChallengeAttempts, id)
+	}
+
+	return err
+}
+
+func (repo mfaChallengeRepository) DeleteMFAChallenge(ctx context.Context, tx *sqlx.Tx, id string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryDeleteMFAChallenge, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryDeleteMFAChallenge, id)
+	}
+
+	return err
+}
+
+func (repo mfaChallengeRepository) DeleteExpiredMFAChallenges(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteExpiredMFAChallenges, now)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteExpiredMFAChallenges, now)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
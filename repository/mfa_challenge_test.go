@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mfaChallengeRepository_InsertMFAChallenge(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+
+	challenge := entity.MFAChallenge{
+		ProfileId: "profile-id-1",
+		TokenHash: "token-hash",
+		ExpiresAt: expiresAt,
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert mfa challenge",
+			want:    "challenge-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO mfa_challenge").WithArgs("profile-id-1", "token-hash", expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("challenge-id-1"))
+			},
+		},
+		{
+			name:    "error insert mfa challenge",
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO mfa_challenge").WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewMFAChallengeRepository(dbx)
+			got, err := repo.InsertMFAChallenge(context.TODO(), nil, challenge)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_mfaChallengeRepository_GetMFAChallengeByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "token_hash", "attempts", "expires_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.MFAChallenge
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get mfa challenge",
+			want: entity.MFAChallenge{
+				Id:        "challenge-id-1",
+				ProfileId: "profile-id-1",
+				TokenHash: "token-hash",
+				Attempts:  2,
+				ExpiresAt: expiresAt,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM mfa_challenge").WithArgs("token-hash").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("challenge-id-1", "profile-id-1", "token-hash", 2, expiresAt))
+			},
+		},
+		{
+			name:    "no mfa challenge",
+			want:    entity.MFAChallenge{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM mfa_challenge").WithArgs("token-hash").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:    "error get mfa challenge",
+			want:    entity.MFAChallenge{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM mfa_challenge").WithArgs("token-hash").
+					WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewMFAChallengeRepository(dbx)
+			got, err := repo.GetMFAChallengeByHash(context.TODO(), nil, "token-hash")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_mfaChallengeRepository_IncreaseMFAChallengeAttempts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE mfa_challenge SET attempts").WithArgs("challenge-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewMFAChallengeRepository(dbx)
+	err := repo.IncreaseMFAChallengeAttempts(context.TODO(), nil, "challenge-id-1")
+	assert.NoError(t, err)
+}
+
+func Test_mfaChallengeRepository_DeleteMFAChallenge(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM mfa_challenge WHERE id").WithArgs("challenge-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewMFAChallengeRepository(dbx)
+	err := repo.DeleteMFAChallenge(context.TODO(), nil, "challenge-id-1")
+	assert.NoError(t, err)
+}
+
+func Test_mfaChallengeRepository_DeleteExpiredMFAChallenges(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM mfa_challenge WHERE expires_at").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	repo := NewMFAChallengeRepository(dbx)
+	got, err := repo.DeleteExpiredMFAChallenges(context.TODO(), nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), got)
+}
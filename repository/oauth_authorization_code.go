@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oauthAuthorizationCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthAuthorizationCodeRepository(db *sqlx.DB) oauthAuthorizationCodeRepository {
+	return oauthAuthorizationCodeRepository{
+		db: db,
+	}
+}
+
+func (repo oauthAuthorizationCodeRepository) InsertOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthAuthorizationCode) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(
+			ctx,
+			queryInsertOAuthAuthorizationCode,
+			code.CodeHash,
+			code.ClientId,
+			code.ProfileId,
+			code.SessionId,
+			code.RedirectURI,
+			code.Scopes,
+			code.CodeChallenge,
+			code.Nonce,
+			code.ExpiresAt,
+			code.CreatedAt,
+		)
+	} else {
+		_, err = repo.db.ExecContext(
+			ctx,
+			queryInsertOAuthAuthorizationCode,
+			code.CodeHash,
+			code.ClientId,
+			code.ProfileId,
+			code.SessionId,
+			code.RedirectURI,
+			code.Scopes,
+			code.CodeChallenge,
+			code.Nonce,
+			code.ExpiresAt,
+			code.CreatedAt,
+		)
+	}
+
+	return err
+}
+
+// ConsumeOAuthAuthorizationCode deletes the code with the hash and returns
+// it, a zero code when there was none. A code can only be consumed once.
+func (repo oauthAuthorizationCodeRepository) ConsumeOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, codeHash string) (entity.OAuthAuthorizationCode, error) {
+	var res entity.OAuthAuthorizationCode
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryConsumeOAuthAuthorizationCode, codeHash)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryConsumeOAuthAuthorizationCode, codeHash)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo oauthAuthorizationCodeRepository) DeleteExpiredOAuthAuthorizationCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteExpiredOAuthAuthorizationCodes, now)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteExpiredOAuthAuthorizationCodes, now)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
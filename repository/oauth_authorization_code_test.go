@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthAuthorizationCodeRepository_InsertOAuthAuthorizationCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Minute)
+
+	mock.ExpectExec("INSERT INTO oauth_authorization_code").WithArgs(
+		"code-hash-1",
+		"client-id-1",
+		"profile-id-1",
+		"session-id-1",
+		"https://partner.example/callback",
+		"profile:read",
+		"challenge-1",
+		"nonce-1",
+		expiresAt,
+		now,
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewOAuthAuthorizationCodeRepository(dbx)
+	err := repo.InsertOAuthAuthorizationCode(context.TODO(), nil, entity.OAuthAuthorizationCode{
+		CodeHash:      "code-hash-1",
+		ClientId:      "client-id-1",
+		ProfileId:     "profile-id-1",
+		SessionId:     "session-id-1",
+		RedirectURI:   "https://partner.example/callback",
+		Scopes:        "profile:read",
+		CodeChallenge: "challenge-1",
+		Nonce:         "nonce-1",
+		ExpiresAt:     expiresAt,
+		CreatedAt:     now,
+	})
+	assert.NoError(t, err)
+}
+
+func Test_oauthAuthorizationCodeRepository_ConsumeOAuthAuthorizationCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Minute)
+
+	columns := []string{"id", "code_hash", "client_id", "profile_id", "session_id", "redirect_uri", "scopes", "code_challenge", "nonce", "expires_at", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthAuthorizationCode
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success consume oauth authorization code",
+			want: entity.OAuthAuthorizationCode{
+				Id:            "code-id-1",
+				CodeHash:      "code-hash-1",
+				ClientId:      "client-id-1",
+				ProfileId:     "profile-id-1",
+				SessionId:     "session-id-1",
+				RedirectURI:   "https://partner.example/callback",
+				Scopes:        "profile:read",
+				CodeChallenge: "challenge-1",
+				Nonce:         "nonce-1",
+				ExpiresAt:     expiresAt,
+				CreatedAt:     now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_authorization_code WHERE code_hash (.+) RETURNING").WithArgs("code-hash-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("code-id-1", "code-hash-1", "client-id-1", "profile-id-1", "session-id-1", "https://partner.example/callback", "profile:read", "challenge-1", "nonce-1", expiresAt, now),
+				)
+			},
+		},
+		{
+			name:    "oauth authorization code not found",
+			want:    entity.OAuthAuthorizationCode{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_authorization_code WHERE code_hash (.+) RETURNING").WithArgs("code-hash-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error consume oauth authorization code",
+			want:    entity.OAuthAuthorizationCode{},
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_authorization_code WHERE code_hash (.+) RETURNING").WithArgs("code-hash-1").WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthAuthorizationCodeRepository(dbx)
+			got, err := repo.ConsumeOAuthAuthorizationCode(context.TODO(), nil, "code-hash-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthAuthorizationCodeRepository_DeleteExpiredOAuthAuthorizationCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM oauth_authorization_code WHERE expires_at").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewOAuthAuthorizationCodeRepository(dbx)
+	got, err := repo.DeleteExpiredOAuthAuthorizationCodes(context.TODO(), nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+}
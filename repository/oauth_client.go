@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oauthClientRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthClientRepository(db *sqlx.DB) oauthClientRepository {
+	return oauthClientRepository{
+		db: db,
+	}
+}
+
+func (repo oauthClientRepository) InsertOAuthClient(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertOAuthClient,
+			client.Name,
+			client.SecretHash,
+			client.RedirectURIs,
+			client.PostLogoutRedirectURIs,
+			client.Scopes,
+			client.GrantTypes,
+			client.CreatedAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertOAuthClient,
+			client.Name,
+			client.SecretHash,
+			client.RedirectURIs,
+			client.PostLogoutRedirectURIs,
+			client.Scopes,
+			client.GrantTypes,
+			client.CreatedAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+func (repo oauthClientRepository) GetOAuthClientById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthClient, error) {
+	var res entity.OAuthClient
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetOAuthClientById, id)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetOAuthClientById, id)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
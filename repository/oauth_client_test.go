@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthClientRepository_InsertOAuthClient(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secretHash := "secret-hash-1"
+
+	client := entity.OAuthClient{
+		Name:                   "Partner App",
+		SecretHash:             &secretHash,
+		RedirectURIs:           "https://partner.example/callback",
+		PostLogoutRedirectURIs: "https://partner.example/signed-out",
+		Scopes:                 "profile:read",
+		GrantTypes:             "authorization_code refresh_token",
+		CreatedAt:              now,
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert oauth client",
+			want:    "client-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO oauth_client").WithArgs(
+					"Partner App",
+					&secretHash,
+					"https://partner.example/callback",
+					"https://partner.example/signed-out",
+					"profile:read",
+					"authorization_code refresh_token",
+					now,
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("client-id-1"))
+			},
+		},
+		{
+			name:    "error insert oauth client",
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO oauth_client").WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthClientRepository(dbx)
+			got, err := repo.InsertOAuthClient(context.TODO(), nil, client)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthClientRepository_GetOAuthClientById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "name", "secret_hash", "redirect_uris", "post_logout_redirect_uris", "scopes", "grant_types", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthClient
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get public oauth client",
+			want: entity.OAuthClient{
+				Id:           "client-id-1",
+				Name:         "Partner App",
+				RedirectURIs: "https://partner.example/callback",
+				Scopes:       "profile:read profile:write",
+				GrantTypes:   "authorization_code refresh_token",
+				CreatedAt:    now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_client WHERE id").WithArgs("client-id-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("client-id-1", "Partner App", nil, "https://partner.example/callback", "", "profile:read profile:write", "authorization_code refresh_token", now),
+				)
+			},
+		},
+		{
+			name:    "oauth client not found",
+			want:    entity.OAuthClient{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_client WHERE id").WithArgs("client-id-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error get oauth client",
+			want:    entity.OAuthClient{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_client WHERE id").WithArgs("client-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthClientRepository(dbx)
+			got, err := repo.GetOAuthClientById(context.TODO(), nil, "client-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
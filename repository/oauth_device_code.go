@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oauthDeviceCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthDeviceCodeRepository(db *sqlx.DB) oauthDeviceCodeRepository {
+	return oauthDeviceCodeRepository{
+		db: db,
+	}
+}
+
+func (repo oauthDeviceCodeRepository) InsertOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthDeviceCode) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(
+			ctx,
+			queryInsertOAuthDeviceCode,
+			code.DeviceCodeHash,
+			code.UserCode,
+			code.ClientId,
+			code.Scopes,
+			code.Interval,
+			code.ExpiresAt,
+			code.CreatedAt,
+		)
+	} else {
+		_, err = repo.db.ExecContext(
+			ctx,
+			queryInsertOAuthDeviceCode,
+			code.DeviceCodeHash,
+			code.UserCode,
+			code.ClientId,
+			code.Scopes,
+			code.Interval,
+			code.ExpiresAt,
+			code.CreatedAt,
+		)
+	}
+
+	return err
+}
+
+func (repo oauthDeviceCodeRepository) GetOAuthDeviceCodeByUserCode(ctx context.Context, tx *sqlx.Tx, userCode string) (entity.OAuthDeviceCode, error) {
+	var res entity.OAuthDeviceCode
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetOAuthDeviceCodeByUserCode, userCode)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetOAuthDeviceCodeByUserCode, userCode)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo oauthDeviceCodeRepository) GetOAuthDeviceCodeByHash(ctx context.Context, tx *sqlx.Tx, deviceCodeHash string) (entity.OAuthDeviceCode, error) {
+	var res entity.OAuthDeviceCode
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetOAuthDeviceCodeByHash, deviceCodeHash)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetOAuthDeviceCodeByHash, deviceCodeHash)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+// DecideOAuthDeviceCode records the answer of the user, it reports false when
+// the code was already decided or has expired.
+func (repo oauthDeviceCodeRepository) DecideOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, userCode string, status string, profileId string, now time.Time) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDecideOAuthDeviceCode, userCode, status, profileId, now)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDecideOAuthDeviceCode, userCode, status, profileId, now)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo oauthDeviceCodeRepository) PollOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string, polledAt time.Time, interval int64) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryPollOAuthDeviceCode, id, polledAt, interval)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryPollOAuthDeviceCode, id, polledAt, interval)
+	}
+
+	return err
+}
+
+// DeleteOAuthDeviceCode reports false when the code was already gone, which
+// keeps two polls from both redeeming an approved code.
+func (repo oauthDeviceCodeRepository) DeleteOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteOAuthDeviceCode, id)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteOAuthDeviceCode, id)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo oauthDeviceCodeRepository) DeleteExpiredOAuthDeviceCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteExpiredOAuthDeviceCodes, now)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteExpiredOAuthDeviceCodes, now)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthDeviceCodeRepository_InsertOAuthDeviceCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(10 * time.Minute)
+
+	mock.ExpectExec("INSERT INTO oauth_device_code").WithArgs(
+		"device-code-hash-1",
+		"BCDFGHJK",
+		"client-id-1",
+		"openid profile",
+		int64(5),
+		expiresAt,
+		now,
+	).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewOAuthDeviceCodeRepository(dbx)
+	err := repo.InsertOAuthDeviceCode(context.TODO(), nil, entity.OAuthDeviceCode{
+		DeviceCodeHash: "device-code-hash-1",
+		UserCode:       "BCDFGHJK",
+		ClientId:       "client-id-1",
+		Scopes:         "openid profile",
+		Interval:       5,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	})
+	assert.NoError(t, err)
+}
+
+func Test_oauthDeviceCodeRepository_GetOAuthDeviceCodeByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(10 * time.Minute)
+	profileId := "profile-id-1"
+
+	columns := []string{"id", "device_code_hash", "user_code", "client_id", "scopes", "status", "profile_id", "poll_interval", "last_polled_at", "expires_at", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthDeviceCode
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get oauth device code",
+			want: entity.OAuthDeviceCode{
+				Id:             "device-code-id-1",
+				DeviceCodeHash: "device-code-hash-1",
+				UserCode:       "BCDFGHJK",
+				ClientId:       "client-id-1",
+				Scopes:         "openid profile",
+				Status:         "approved",
+				ProfileId:      &profileId,
+				Interval:       5,
+				LastPolledAt:   &now,
+				ExpiresAt:      expiresAt,
+				CreatedAt:      now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_device_code WHERE device_code_hash").WithArgs("device-code-hash-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("device-code-id-1", "device-code-hash-1", "BCDFGHJK", "client-id-1", "openid profile", "approved", "profile-id-1", 5, now, expiresAt, now),
+				)
+			},
+		},
+		{
+			name:    "oauth device code not found",
+			want:    entity.OAuthDeviceCode{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_device_code WHERE device_code_hash").WithArgs("device-code-hash-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error get oauth device code",
+			want:    entity.OAuthDeviceCode{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_device_code WHERE device_code_hash").WithArgs("device-code-hash-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthDeviceCodeRepository(dbx)
+			got, err := repo.GetOAuthDeviceCodeByHash(context.TODO(), nil, "device-code-hash-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthDeviceCodeRepository_GetOAuthDeviceCodeByUserCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(10 * time.Minute)
+
+	mock.ExpectQuery("SELECT (.+) FROM oauth_device_code WHERE user_code").WithArgs("BCDFGHJK").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "device_code_hash", "user_code", "client_id", "scopes", "status", "profile_id", "poll_interval", "last_polled_at", "expires_at", "created_at"}).
+			AddRow("device-code-id-1", "device-code-hash-1", "BCDFGHJK", "client-id-1", "openid", "pending", nil, 5, nil, expiresAt, now),
+	)
+
+	repo := NewOAuthDeviceCodeRepository(dbx)
+	got, err := repo.GetOAuthDeviceCodeByUserCode(context.TODO(), nil, "BCDFGHJK")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.OAuthDeviceCode{
+		Id:             "device-code-id-1",
+		DeviceCodeHash: "device-code-hash-1",
+		UserCode:       "BCDFGHJK",
+		ClientId:       "client-id-1",
+		Scopes:         "openid",
+		Status:         "pending",
+		Interval:       5,
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+	}, got)
+}
+
+func Test_oauthDeviceCodeRepository_DecideOAuthDeviceCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success decide oauth device code",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE oauth_device_code SET status (.+) WHERE user_code (.+) AND status = 'pending'").
+					WithArgs("BCDFGHJK", "approved", "profile-id-1", now).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "oauth device code already decided",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE oauth_device_code SET status (.+) WHERE user_code (.+) AND status = 'pending'").
+					WithArgs("BCDFGHJK", "approved", "profile-id-1", now).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error decide oauth device code",
+			want:    false,
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE oauth_device_code SET status (.+) WHERE user_code (.+) AND status = 'pending'").
+					WithArgs("BCDFGHJK", "approved", "profile-id-1", now).WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthDeviceCodeRepository(dbx)
+			got, err := repo.DecideOAuthDeviceCode(context.TODO(), nil, "BCDFGHJK", "approved", "profile-id-1", now)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthDeviceCodeRepository_PollOAuthDeviceCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE oauth_device_code SET last_polled_at").WithArgs("device-code-id-1", now, int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewOAuthDeviceCodeRepository(dbx)
+	err := repo.PollOAuthDeviceCode(context.TODO(), nil, "device-code-id-1", now, 10)
+	assert.NoError(t, err)
+}
+
+func Test_oauthDeviceCodeRepository_DeleteOAuthDeviceCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM oauth_device_code WHERE id").WithArgs("device-code-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM oauth_device_code WHERE id").WithArgs("device-code-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewOAuthDeviceCodeRepository(dbx)
+	got, err := repo.DeleteOAuthDeviceCode(context.TODO(), nil, "device-code-id-1")
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = repo.DeleteOAuthDeviceCode(context.TODO(), nil, "device-code-id-1")
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func Test_oauthDeviceCodeRepository_DeleteExpiredOAuthDeviceCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM oauth_device_code WHERE expires_at").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewOAuthDeviceCodeRepository(dbx)
+	got, err := repo.DeleteExpiredOAuthDeviceCodes(context.TODO(), nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), got)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oauthGrantRepository struct {
+	db *sqlx.DB
+}
+
+func NewOAuthGrantRepository(db *sqlx.DB) oauthGrantRepository {
+	return oauthGrantRepository{
+		db: db,
+	}
+}
+
+// UpsertOAuthGrant records the grant of the profile to the client, replacing
+// the scopes of an earlier one, and returns its id.
+func (repo oauthGrantRepository) UpsertOAuthGrant(ctx context.Context, tx *sqlx.Tx, grant entity.OAuthGrant) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, queryUpsertOAuthGrant, grant.ProfileId, grant.ClientId, grant.Scopes, grant.CreatedAt).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(ctx, queryUpsertOAuthGrant, grant.ProfileId, grant.ClientId, grant.Scopes, grant.CreatedAt).Scan(&id)
+	}
+
+	return id, err
+}
+
+func (repo oauthGrantRepository) GetOAuthGrantById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthGrant, error) {
+	var res entity.OAuthGrant
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetOAuthGrantById, id)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetOAuthGrantById, id)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo oauthGrantRepository) GetOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId string, clientId string) (entity.OAuthGrant, error) {
+	var res entity.OAuthGrant
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetOAuthGrant, profileId, clientId)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetOAuthGrant, profileId, clientId)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo oauthGrantRepository) GetOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.OAuthGrant, error) {
+	var res []entity.OAuthGrant
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetOAuthGrantsByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetOAuthGrantsByProfileId, profileId)
+	}
+
+	return res, err
+}
+
+func (repo oauthGrantRepository) TouchOAuthGrant(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryTouchOAuthGrant, lastUsedAt, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryTouchOAuthGrant, lastUsedAt, id)
+	}
+
+	return err
+}
+
+// DeleteOAuthGrant deletes the grant of the profile to the client and
+// returns its id, empty when there was none.
+func (repo oauthGrantRepository) DeleteOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId string, clientId string) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, queryDeleteOAuthGrant, profileId, clientId).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(ctx, queryDeleteOAuthGrant, profileId, clientId).Scan(&id)
+	}
+
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return id, err
+}
+
+// DeleteOAuthGrantsByProfileId returns the ids of the deleted grants, the
+// refresh token families of the clients.
+func (repo oauthGrantRepository) DeleteOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error) {
+	var res []string
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryDeleteOAuthGrantsByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryDeleteOAuthGrantsByProfileId, profileId)
+	}
+
+	return res, err
+}
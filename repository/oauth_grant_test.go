@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthGrantRepository_UpsertOAuthGrant(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("INSERT INTO oauth_grant (.+) ON CONFLICT").WithArgs(
+		"profile-id-1",
+		"client-id-1",
+		"profile:read",
+		now,
+	).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("grant-id-1"))
+
+	repo := NewOAuthGrantRepository(dbx)
+	got, err := repo.UpsertOAuthGrant(context.TODO(), nil, entity.OAuthGrant{
+		ProfileId: "profile-id-1",
+		ClientId:  "client-id-1",
+		Scopes:    "profile:read",
+		CreatedAt: now,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "grant-id-1", got)
+}
+
+func Test_oauthGrantRepository_GetOAuthGrantById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "client_id", "client_name", "scopes", "created_at", "last_used_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthGrant
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get oauth grant",
+			want: entity.OAuthGrant{
+				Id:         "grant-id-1",
+				ProfileId:  "profile-id-1",
+				ClientId:   "client-id-1",
+				ClientName: "Partner App",
+				Scopes:     "profile:read",
+				CreatedAt:  now,
+				LastUsedAt: &now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_grant JOIN oauth_client (.+) WHERE oauth_grant.id").WithArgs("grant-id-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("grant-id-1", "profile-id-1", "client-id-1", "Partner App", "profile:read", now, now),
+				)
+			},
+		},
+		{
+			name:    "oauth grant not found",
+			want:    entity.OAuthGrant{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_grant JOIN oauth_client (.+) WHERE oauth_grant.id").WithArgs("grant-id-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error get oauth grant",
+			want:    entity.OAuthGrant{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM oauth_grant JOIN oauth_client (.+) WHERE oauth_grant.id").WithArgs("grant-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthGrantRepository(dbx)
+			got, err := repo.GetOAuthGrantById(context.TODO(), nil, "grant-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthGrantRepository_GetOAuthGrantsByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "client_id", "client_name", "scopes", "created_at", "last_used_at"}
+
+	mock.ExpectQuery("SELECT (.+) FROM oauth_grant JOIN oauth_client (.+) WHERE oauth_grant.profile_id").WithArgs("profile-id-1").WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("grant-id-1", "profile-id-1", "client-id-1", "Partner App", "profile:read", now, nil),
+	)
+
+	repo := NewOAuthGrantRepository(dbx)
+	got, err := repo.GetOAuthGrantsByProfileId(context.TODO(), nil, "profile-id-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.OAuthGrant{
+		{
+			Id:         "grant-id-1",
+			ProfileId:  "profile-id-1",
+			ClientId:   "client-id-1",
+			ClientName: "Partner App",
+			Scopes:     "profile:read",
+			CreatedAt:  now,
+		},
+	}, got)
+}
+
+func Test_oauthGrantRepository_DeleteOAuthGrant(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete oauth grant",
+			want:    "grant-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_grant").WithArgs("profile-id-1", "client-id-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}).AddRow("grant-id-1"),
+				)
+			},
+		},
+		{
+			name:    "oauth grant not found",
+			want:    "",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_grant").WithArgs("profile-id-1", "client-id-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error delete oauth grant",
+			want:    "",
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_grant").WithArgs("profile-id-1", "client-id-1").WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthGrantRepository(dbx)
+			got, err := repo.DeleteOAuthGrant(context.TODO(), nil, "profile-id-1", "client-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthGrantRepository_DeleteOAuthGrantsByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete grants",
+			want:    []string{"grant-id-1", "grant-id-2"},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_grant WHERE profile_id = \\$1 RETURNING id").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("grant-id-1").AddRow("grant-id-2"))
+			},
+		},
+		{
+			name:    "error delete grants",
+			want:    nil,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM oauth_grant WHERE profile_id = \\$1 RETURNING id").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOAuthGrantRepository(dbx)
+			got, err := repo.DeleteOAuthGrantsByProfileId(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
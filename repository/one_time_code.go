@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type oneTimeCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewOneTimeCodeRepository(db *sqlx.DB) oneTimeCodeRepository {
+	return oneTimeCodeRepository{
+		db: db,
+	}
+}
+
+func (repo oneTimeCodeRepository) InsertOneTimeCode(ctx context.Context, tx *sqlx.Tx, code entity.OneTimeCode) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertOneTimeCode,
+			code.ProfileId,
+			code.Purpose,
+			code.CodeHash,
+			code.ExpiresAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertOneTimeCode,
+			code.ProfileId,
+			code.Purpose,
+			code.CodeHash,
+			code.ExpiresAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+// GetActiveOneTimeCode returns the newest unconsumed code of the profile for
+// the purpose. Inside a transaction the row stays locked until it ends, so
+// concurrent attempts against the same code are counted one by one.
+func (repo oneTimeCodeRepository) GetActiveOneTimeCode(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string) (entity.OneTimeCode, error) {
+	var res entity.OneTimeCode
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetActiveOneTimeCode, profileId, purpose)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetActiveOneTimeCode, profileId, purpose)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.OneTimeCode{}, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo oneTimeCodeRepository) IncreaseOneTimeCodeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryIncreaseOneTimeCodeAttempts, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryIncreaseOneTimeCodeAttempts, id)
+	}
+
+	return err
+}
+
+// ConsumeOneTimeCodes marks every outstanding code of the profile for the
+// purpose as used, either because one of them was redeemed or because a new
+// one replaces them.
+func (repo oneTimeCodeRepository) ConsumeOneTimeCodes(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryConsumeOneTimeCodes, profileId, purpose)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryConsumeOneTimeCodes, profileId, purpose)
+	}
+
+	return err
+}
+
+func (repo oneTimeCodeRepository) DeleteExpiredOneTimeCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteExpiredOneTimeCodes, now)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteExpiredOneTimeCodes, now)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
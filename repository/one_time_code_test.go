@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oneTimeCodeRepository_InsertOneTimeCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	code := entity.OneTimeCode{
+		ProfileId: "profile-id-1",
+		Purpose:   "password_reset",
+		CodeHash:  "code-hash",
+		ExpiresAt: expiresAt,
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert one time code",
+			want:    "code-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO one_time_code").WithArgs(
+					"profile-id-1",
+					"password_reset",
+					"code-hash",
+					expiresAt,
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("code-id-1"))
+			},
+		},
+		{
+			name:    "error insert one time code",
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO one_time_code").WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOneTimeCodeRepository(dbx)
+			got, err := repo.InsertOneTimeCode(context.TODO(), nil, code)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oneTimeCodeRepository_GetActiveOneTimeCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "purpose", "code_hash", "expires_at", "attempts", "consumed_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.OneTimeCode
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get active one time code",
+			want: entity.OneTimeCode{
+				Id:        "code-id-1",
+				ProfileId: "profile-id-1",
+				Purpose:   "password_reset",
+				CodeHash:  "code-hash",
+				ExpiresAt: expiresAt,
+				Attempts:  1,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM one_time_code").WithArgs("profile-id-1", "password_reset").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("code-id-1", "profile-id-1", "password_reset", "code-hash", expiresAt, 1, nil))
+			},
+		},
+		{
+			name:    "no active one time code",
+			want:    entity.OneTimeCode{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM one_time_code").WithArgs("profile-id-1", "password_reset").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:    "error get active one time code",
+			want:    entity.OneTimeCode{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM one_time_code").WithArgs("profile-id-1", "password_reset").
+					WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewOneTimeCodeRepository(dbx)
+			got, err := repo.GetActiveOneTimeCode(context.TODO(), nil, "profile-id-1", "password_reset")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oneTimeCodeRepository_IncreaseOneTimeCodeAttempts(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE one_time_code SET attempts").WithArgs("code-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewOneTimeCodeRepository(dbx)
+	err := repo.IncreaseOneTimeCodeAttempts(context.TODO(), nil, "code-id-1")
+	assert.NoError(t, err)
+}
+
+func Test_oneTimeCodeRepository_ConsumeOneTimeCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE one_time_code SET consumed_at").WithArgs("profile-id-1", "password_reset").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewOneTimeCodeRepository(dbx)
+	err := repo.ConsumeOneTimeCodes(context.TODO(), nil, "profile-id-1", "password_reset")
+	assert.NoError(t, err)
+}
+
+func Test_oneTimeCodeRepository_DeleteExpiredOneTimeCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM one_time_code WHERE expires_at").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewOneTimeCodeRepository(dbx)
+	got, err := repo.DeleteExpiredOneTimeCodes(context.TODO(), nil, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"sawitpro/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type passwordHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewPasswordHistoryRepository(db *sqlx.DB) passwordHistoryRepository {
+	return passwordHistoryRepository{
+		db: db,
+	}
+}
+
+func (repo passwordHistoryRepository) InsertPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, hashedPassword string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryInsertPasswordHistory, profileId, hashedPassword)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryInsertPasswordHistory, profileId, hashedPassword)
+	}
+
+	return err
+}
+
+// GetPasswordHistory returns up to limit of the previous passwords of the
+// profile, newest first.
+func (repo passwordHistoryRepository) GetPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, limit int) ([]entity.PasswordHistory, error) {
+	var res []entity.PasswordHistory
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetPasswordHistory, profileId, limit)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetPasswordHistory, profileId, limit)
+	}
+
+	return res, err
+}
+
+// PrunePasswordHistory deletes all but the keep newest previous passwords of
+// the profile.
+func (repo passwordHistoryRepository) PrunePasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, keep int) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryPrunePasswordHistory, profileId, keep)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryPrunePasswordHistory, profileId, keep)
+	}
+
+	return err
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_passwordHistoryRepository_InsertPasswordHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("INSERT INTO password_history").WithArgs("profile-id-1", "hashed-password-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPasswordHistoryRepository(dbx)
+	err := repo.InsertPasswordHistory(context.TODO(), nil, "profile-id-1", "hashed-password-1")
+	assert.NoError(t, err)
+}
+
+func Test_passwordHistoryRepository_GetPasswordHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "password", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    []entity.PasswordHistory
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get password history",
+			want: []entity.PasswordHistory{
+				{
+					Id:        "history-id-1",
+					ProfileId: "profile-id-1",
+					Password:  "hashed-password-1",
+					CreatedAt: now,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM password_history WHERE profile_id").WithArgs("profile-id-1", 5).WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("history-id-1", "profile-id-1", "hashed-password-1", now),
+				)
+			},
+		},
+		{
+			name:    "error get password history",
+			want:    nil,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM password_history WHERE profile_id").WithArgs("profile-id-1", 5).WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPasswordHistoryRepository(dbx)
+			got, err := repo.GetPasswordHistory(context.TODO(), nil, "profile-id-1", 5)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_passwordHistoryRepository_PrunePasswordHistory(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM password_history WHERE profile_id").WithArgs("profile-id-1", 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewPasswordHistoryRepository(dbx)
+	err := repo.PrunePasswordHistory(context.TODO(), nil, "profile-id-1", 5)
+	assert.NoError(t, err)
+}
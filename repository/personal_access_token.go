@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type personalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewPersonalAccessTokenRepository(db *sqlx.DB) personalAccessTokenRepository {
+	return personalAccessTokenRepository{
+		db: db,
+	}
+}
+
+func (repo personalAccessTokenRepository) InsertPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, token entity.PersonalAccessToken) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertPersonalAccessToken,
+			token.ProfileId,
+			token.Name,
+			token.TokenHash,
+			token.Scopes,
+			token.ExpiresAt,
+			token.CreatedAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertPersonalAccessToken,
+			token.ProfileId,
+			token.Name,
+			token.TokenHash,
+			token.Scopes,
+			token.ExpiresAt,
+			token.CreatedAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+func (repo personalAccessTokenRepository) GetPersonalAccessTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.PersonalAccessToken, error) {
+	var res entity.PersonalAccessToken
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetPersonalAccessTokenByHash, tokenHash)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetPersonalAccessTokenByHash, tokenHash)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo personalAccessTokenRepository) GetPersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.PersonalAccessToken, error) {
+	var res []entity.PersonalAccessToken
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetPersonalAccessTokensByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetPersonalAccessTokensByProfileId, profileId)
+	}
+
+	return res, err
+}
+
+func (repo personalAccessTokenRepository) TouchPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryTouchPersonalAccessToken, lastUsedAt, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryTouchPersonalAccessToken, lastUsedAt, id)
+	}
+
+	return err
+}
+
+// DeletePersonalAccessToken only deletes the token when it belongs to the
+// given profile, and reports whether it did.
+func (repo personalAccessTokenRepository) DeletePersonalAccessToken(ctx context.Context, tx *sqlx.Tx, profileId string, id string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeletePersonalAccessToken, id, profileId)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeletePersonalAccessToken, id, profileId)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo personalAccessTokenRepository) DeletePersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeletePersonalAccessTokensByProfileId, profileId)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeletePersonalAccessTokensByProfileId, profileId)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
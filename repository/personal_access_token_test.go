@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_personalAccessTokenRepository_InsertPersonalAccessToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(24 * time.Hour)
+
+	token := entity.PersonalAccessToken{
+		ProfileId: "profile-id-1",
+		Name:      "backup script",
+		TokenHash: "token-hash-1",
+		Scopes:    "profile:read",
+		ExpiresAt: &expiresAt,
+		CreatedAt: now,
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert personal access token",
+			want:    "token-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO personal_access_token").WithArgs(
+					"profile-id-1",
+					"backup script",
+					"token-hash-1",
+					"profile:read",
+					&expiresAt,
+					now,
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("token-id-1"))
+			},
+		},
+		{
+			name:    "error insert personal access token",
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO personal_access_token").WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPersonalAccessTokenRepository(dbx)
+			got, err := repo.InsertPersonalAccessToken(context.TODO(), nil, token)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_personalAccessTokenRepository_GetPersonalAccessTokenByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "name", "token_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.PersonalAccessToken
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get personal access token",
+			want: entity.PersonalAccessToken{
+				Id:         "token-id-1",
+				ProfileId:  "profile-id-1",
+				Name:       "backup script",
+				TokenHash:  "token-hash-1",
+				Scopes:     "profile:read",
+				LastUsedAt: &now,
+				CreatedAt:  now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM personal_access_token WHERE token_hash").WithArgs("token-hash-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("token-id-1", "profile-id-1", "backup script", "token-hash-1", "profile:read", nil, now, now),
+				)
+			},
+		},
+		{
+			name:    "personal access token not found",
+			want:    entity.PersonalAccessToken{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM personal_access_token WHERE token_hash").WithArgs("token-hash-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name:    "error get personal access token",
+			want:    entity.PersonalAccessToken{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM personal_access_token WHERE token_hash").WithArgs("token-hash-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPersonalAccessTokenRepository(dbx)
+			got, err := repo.GetPersonalAccessTokenByHash(context.TODO(), nil, "token-hash-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_personalAccessTokenRepository_GetPersonalAccessTokensByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "name", "token_hash", "scopes", "expires_at", "last_used_at", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    []entity.PersonalAccessToken
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get personal access tokens",
+			want: []entity.PersonalAccessToken{
+				{
+					Id:        "token-id-1",
+					ProfileId: "profile-id-1",
+					Name:      "backup script",
+					TokenHash: "token-hash-1",
+					Scopes:    "profile:read profile:write",
+					ExpiresAt: &now,
+					CreatedAt: now,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM personal_access_token WHERE profile_id").WithArgs("profile-id-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("token-id-1", "profile-id-1", "backup script", "token-hash-1", "profile:read profile:write", now, nil, now),
+				)
+			},
+		},
+		{
+			name:    "error get personal access tokens",
+			want:    nil,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM personal_access_token WHERE profile_id").WithArgs("profile-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPersonalAccessTokenRepository(dbx)
+			got, err := repo.GetPersonalAccessTokensByProfileId(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_personalAccessTokenRepository_TouchPersonalAccessToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE personal_access_token SET last_used_at").WithArgs(now, "token-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPersonalAccessTokenRepository(dbx)
+	err := repo.TouchPersonalAccessToken(context.TODO(), nil, "token-id-1", now)
+	assert.NoError(t, err)
+}
+
+func Test_personalAccessTokenRepository_DeletePersonalAccessToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete personal access token",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM personal_access_token WHERE id").WithArgs("token-id-1", "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "personal access token of another profile is not deleted",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM personal_access_token WHERE id").WithArgs("token-id-1", "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error delete personal access token",
+			want:    false,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM personal_access_token WHERE id").WithArgs("token-id-1", "profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPersonalAccessTokenRepository(dbx)
+			got, err := repo.DeletePersonalAccessToken(context.TODO(), nil, "profile-id-1", "token-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_personalAccessTokenRepository_DeletePersonalAccessTokensByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete personal access tokens",
+			want:    2,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM personal_access_token WHERE profile_id").WithArgs("profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name:    "error delete personal access tokens",
+			want:    0,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM personal_access_token WHERE profile_id").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewPersonalAccessTokenRepository(dbx)
+			got, err := repo.DeletePersonalAccessTokensByProfileId(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -4,9 +4,9 @@ const (
 	queryInserProfile = `
 		INSERT INTO
 			user_profile
-			(full_name, phone_number, password, created_at, updated_at)
+			(full_name, phone_number, password, created_at, updated_at, password_changed_at)
 		VALUES
-			($1, $2 ,$3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+			($1, $2 ,$3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING id`
 
 	queryGetProfileById = `
@@ -14,7 +14,14 @@ const (
 			id, 
 			full_name, 
 			phone_number, 
-			password
+			password,
+			created_at,
+			phone_verified_at,
+			failed_login_count,
+			locked_until,
+			password_changed_at,
+			must_change_password,
+			pending_phone_number
 		FROM
 			user_profile
 		WHERE
@@ -24,10 +31,10 @@ const (
 		UPDATE
 			user_profile
 		SET
-			full_name = $1
-			phone_name = $2
+			full_name = $1,
+			updated_at = CURRENT_TIMESTAMP
 		WHERE
-			id = $3
+			id = $2
 		`
 
 	queryGetProfileByPhoneNumber = `
@@ -35,7 +42,14 @@ const (
 			id, 
 			full_name, 
 			phone_number, 
-			password
+			password,
+			created_at,
+			phone_verified_at,
+			failed_login_count,
+			locked_until,
+			password_changed_at,
+			must_change_password,
+			pending_phone_number
 		FROM
 			user_profile
 		WHERE
@@ -48,4 +62,867 @@ const (
 			success_count = success_count + 1
 		WHERE 
 			id = $1`
+
+	queryIncreaseFailedLoginCount = `
+		UPDATE
+			user_profile
+		SET
+			failed_login_count = failed_login_count + 1
+		WHERE
+			id = $1
+		RETURNING failed_login_count`
+
+	queryLockProfile = `
+		UPDATE
+			user_profile
+		SET
+			locked_until = $1
+		WHERE
+			id = $2`
+
+	queryResetFailedLoginCount = `
+		UPDATE
+			user_profile
+		SET
+			failed_login_count = 0,
+			locked_until = NULL
+		WHERE
+			id = $1`
+
+	queryUpdatePasswordById = `
+		UPDATE
+			user_profile
+		SET
+			password = $1,
+			password_changed_at = CURRENT_TIMESTAMP,
+			must_change_password = FALSE,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2`
+
+	// only replaces the hash it was computed from, a password changed in the
+	// meantime is left alone
+	queryRehashPasswordById = `
+		UPDATE
+			user_profile
+		SET
+			password = $3
+		WHERE
+			id = $1
+			AND password = $2`
+
+	querySetMustChangePassword = `
+		UPDATE
+			user_profile
+		SET
+			must_change_password = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2`
+
+	queryMarkPhoneVerified = `
+		UPDATE
+			user_profile
+		SET
+			phone_verified_at = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2`
+
+	querySetPendingPhoneNumber = `
+		UPDATE
+			user_profile
+		SET
+			pending_phone_number = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2`
+
+	// the number is verified by the change itself, its owner just entered
+	// the code sent to it
+	queryConfirmPendingPhoneNumber = `
+		UPDATE
+			user_profile
+		SET
+			phone_number = pending_phone_number,
+			pending_phone_number = NULL,
+			phone_verified_at = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $2
+			AND pending_phone_number IS NOT NULL`
+
+	queryDeleteProfileById = `
+		DELETE FROM
+			user_profile
+		WHERE
+			id = $1`
+
+	queryDeleteUnverifiedProfiles = `
+		DELETE FROM
+			user_profile
+		WHERE
+			phone_verified_at IS NULL
+			AND created_at < $1`
+
+	queryInsertRefreshToken = `
+		INSERT INTO
+			refresh_token
+			(profile_id, family_id, token_hash, expires_at, created_at)
+		VALUES
+			($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		RETURNING id`
+
+	queryGetRefreshTokenByHash = `
+		SELECT
+			id,
+			profile_id,
+			family_id,
+			token_hash,
+			expires_at,
+			used_at,
+			revoked_at
+		FROM
+			refresh_token
+		WHERE
+			token_hash = $1`
+
+	queryMarkRefreshTokenUsed = `
+		UPDATE
+			refresh_token
+		SET
+			used_at = CURRENT_TIMESTAMP
+		WHERE
+			id = $1
+			AND used_at IS NULL
+			AND revoked_at IS NULL`
+
+	queryRevokeRefreshTokenFamily = `
+		UPDATE
+			refresh_token
+		SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE
+			family_id = $1
+			AND revoked_at IS NULL`
+
+	queryInsertRevokedToken = `
+		INSERT INTO
+			revoked_token
+			(token_id, expires_at, revoked_at)
+		VALUES
+			($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (token_id) DO NOTHING`
+
+	queryIsTokenRevoked = `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					revoked_token
+				WHERE
+					token_id = $1
+			)`
+
+	queryDeleteExpiredRevokedTokens = `
+		DELETE FROM
+			revoked_token
+		WHERE
+			expires_at < $1`
+
+	queryLockSigningKeys = `
+		SELECT
+			pg_advisory_xact_lock(hashtext('signing_key'))`
+
+	queryInsertSigningKey = `
+		INSERT INTO
+			signing_key
+			(id, algorithm, private_key, activates_at, created_at)
+		VALUES
+			($1, $2, $3, $4, CURRENT_TIMESTAMP)`
+
+	queryGetSigningKeys = `
+		SELECT
+			id,
+			algorithm,
+			private_key,
+			activates_at,
+			retires_at
+		FROM
+			signing_key
+		ORDER BY
+			activates_at`
+
+	queryRetireSigningKey = `
+		UPDATE
+			signing_key
+		SET
+			retires_at = $1
+		WHERE
+			id = $2
+			AND retires_at IS NULL`
+
+	queryDeleteRetiredSigningKeys = `
+		DELETE FROM
+			signing_key
+		WHERE
+			retires_at < $1`
+
+	queryInsertSession = `
+		INSERT INTO
+			user_session
+			(profile_id, device_name, user_agent, ip_address, created_at, last_seen_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	queryGetSessionById = `
+		SELECT
+			id,
+			profile_id,
+			device_name,
+			user_agent,
+			ip_address,
+			created_at,
+			last_seen_at
+		FROM
+			user_session
+		WHERE
+			id = $1`
+
+	queryGetSessionsByProfileId = `
+		SELECT
+			id,
+			profile_id,
+			device_name,
+			user_agent,
+			ip_address,
+			created_at,
+			last_seen_at
+		FROM
+			user_session
+		WHERE
+			profile_id = $1
+		ORDER BY
+			last_seen_at DESC`
+
+	queryTouchSession = `
+		UPDATE
+			user_session
+		SET
+			last_seen_at = $1
+		WHERE
+			id = $2`
+
+	queryDeleteSession = `
+		DELETE FROM
+			user_session
+		WHERE
+			id = $1
+			AND profile_id = $2`
+
+	queryDeleteOtherSessions = `
+		DELETE FROM
+			user_session
+		WHERE
+			profile_id = $1
+			AND id <> $2
+		RETURNING id`
+
+	queryDeleteIdleSessions = `
+		DELETE FROM
+			user_session
+		WHERE
+			last_seen_at < $1`
+
+	queryDeleteSessionsByProfileId = `
+		DELETE FROM
+			user_session
+		WHERE
+			profile_id = $1
+		RETURNING id`
+
+	queryInsertPersonalAccessToken = `
+		INSERT INTO
+			personal_access_token
+			(profile_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	queryGetPersonalAccessTokenByHash = `
+		SELECT
+			id,
+			profile_id,
+			name,
+			token_hash,
+			scopes,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM
+			personal_access_token
+		WHERE
+			token_hash = $1`
+
+	queryGetPersonalAccessTokensByProfileId = `
+		SELECT
+			id,
+			profile_id,
+			name,
+			token_hash,
+			scopes,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM
+			personal_access_token
+		WHERE
+			profile_id = $1
+		ORDER BY
+			created_at DESC`
+
+	queryTouchPersonalAccessToken = `
+		UPDATE
+			personal_access_token
+		SET
+			last_used_at = $1
+		WHERE
+			id = $2`
+
+	queryDeletePersonalAccessToken = `
+		DELETE FROM
+			personal_access_token
+		WHERE
+			id = $1
+			AND profile_id = $2`
+
+	queryDeletePersonalAccessTokensByProfileId = `
+		DELETE FROM
+			personal_access_token
+		WHERE
+			profile_id = $1`
+
+	// the conflict update only applies, and so only returns the row, when a
+	// whole token is left after refilling
+	queryTakeRateLimitToken = `
+		INSERT INTO
+			rate_limit_bucket AS bucket
+			(bucket_key, tokens, updated_at)
+		VALUES
+			($1, $2::float8 - 1, $4::timestamp)
+		ON CONFLICT (bucket_key) DO UPDATE SET
+			tokens = LEAST($2::float8, bucket.tokens + GREATEST(EXTRACT(EPOCH FROM $4::timestamp - bucket.updated_at), 0) * $3::float8) - 1,
+			updated_at = $4::timestamp
+		WHERE
+			LEAST($2::float8, bucket.tokens + GREATEST(EXTRACT(EPOCH FROM $4::timestamp - bucket.updated_at), 0) * $3::float8) >= 1
+		RETURNING bucket_key, tokens, updated_at`
+
+	queryGetRateLimitBucket = `
+		SELECT
+			bucket_key,
+			tokens,
+			updated_at
+		FROM
+			rate_limit_bucket
+		WHERE
+			bucket_key = $1`
+
+	queryDeleteIdleRateLimitBuckets = `
+		DELETE FROM
+			rate_limit_bucket
+		WHERE
+			updated_at < $1`
+
+	queryInsertOneTimeCode = `
+		INSERT INTO
+			one_time_code
+			(profile_id, purpose, code_hash, expires_at, created_at)
+		VALUES
+			($1, $2, $3, $4, CURRENT_TIMESTAMP)
+		RETURNING id`
+
+	queryGetActiveOneTimeCode = `
+		SELECT
+			id,
+			profile_id,
+			purpose,
+			code_hash,
+			expires_at,
+			attempts,
+			consumed_at
+		FROM
+			one_time_code
+		WHERE
+			profile_id = $1
+			AND purpose = $2
+			AND consumed_at IS NULL
+		ORDER BY
+			created_at DESC
+		LIMIT 1
+		FOR UPDATE`
+
+	queryIncreaseOneTimeCodeAttempts = `
+		UPDATE
+			one_time_code
+		SET
+			attempts = attempts + 1
+		WHERE
+			id = $1`
+
+	queryConsumeOneTimeCodes = `
+		UPDATE
+			one_time_code
+		SET
+			consumed_at = CURRENT_TIMESTAMP
+		WHERE
+			profile_id = $1
+			AND purpose = $2
+			AND consumed_at IS NULL`
+
+	queryDeleteExpiredOneTimeCodes = `
+		DELETE FROM
+			one_time_code
+		WHERE
+			expires_at < $1`
+
+	queryGetTOTPCredential = `
+		SELECT
+			profile_id,
+			secret,
+			pending_secret,
+			last_used_step,
+			enabled_at
+		FROM
+			totp_credential
+		WHERE
+			profile_id = $1
+		FOR UPDATE`
+
+	querySetPendingTOTPSecret = `
+		INSERT INTO
+			totp_credential
+			(profile_id, pending_secret, created_at, updated_at)
+		VALUES
+			($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (profile_id) DO UPDATE SET
+			pending_secret = EXCLUDED.pending_secret,
+			updated_at = CURRENT_TIMESTAMP`
+
+	queryEnableTOTP = `
+		UPDATE
+			totp_credential
+		SET
+			secret = pending_secret,
+			pending_secret = NULL,
+			last_used_step = $2,
+			enabled_at = $3,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			profile_id = $1
+			AND pending_secret IS NOT NULL`
+
+	queryUpdateTOTPLastUsedStep = `
+		UPDATE
+			totp_credential
+		SET
+			last_used_step = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE
+			profile_id = $1`
+
+	queryDeleteTOTPCredential = `
+		DELETE FROM
+			totp_credential
+		WHERE
+			profile_id = $1`
+
+	queryInsertMFAChallenge = `
+		INSERT INTO
+			mfa_challenge
+			(profile_id, token_hash, expires_at, created_at)
+		VALUES
+			($1, $2, $3, CURRENT_TIMESTAMP)
+		RETURNING id`
+
+	queryGetMFAChallengeByHash = `
+		SELECT
+			id,
+			profile_id,
+			token_hash,
+			attempts,
+			expires_at
+		FROM
+			mfa_challenge
+		WHERE
+			token_hash = $1
+		FOR UPDATE`
+
+	queryIncreaseMFAChallengeAttempts = `
+		UPDATE
+			mfa_challenge
+		SET
+			attempts = attempts + 1
+		WHERE
+			id = $1`
+
+	queryDeleteMFAChallenge = `
+		DELETE FROM
+			mfa_challenge
+		WHERE
+			id = $1`
+
+	queryDeleteExpiredMFAChallenges = `
+		DELETE FROM
+			mfa_challenge
+		WHERE
+			expires_at < $1`
+
+	queryInsertRecoveryCode = `
+		INSERT INTO
+			recovery_code
+			(profile_id, code_hash, created_at)
+		VALUES
+			($1, $2, CURRENT_TIMESTAMP)`
+
+	queryUseRecoveryCode = `
+		UPDATE
+			recovery_code
+		SET
+			used_at = $3
+		WHERE
+			profile_id = $1
+			AND code_hash = $2
+			AND used_at IS NULL`
+
+	queryCountUnusedRecoveryCodes = `
+		SELECT
+			COUNT(*)
+		FROM
+			recovery_code
+		WHERE
+			profile_id = $1
+			AND used_at IS NULL`
+
+	queryDeleteRecoveryCodes = `
+		DELETE FROM
+			recovery_code
+		WHERE
+			profile_id = $1`
+
+	queryInsertPasswordHistory = `
+		INSERT INTO
+			password_history
+			(profile_id, "password", created_at)
+		VALUES
+			($1, $2, CURRENT_TIMESTAMP)`
+
+	queryGetPasswordHistory = `
+		SELECT
+			id,
+			profile_id,
+			"password",
+			created_at
+		FROM
+			password_history
+		WHERE
+			profile_id = $1
+		ORDER BY
+			created_at DESC
+		LIMIT $2`
+
+	queryPrunePasswordHistory = `
+		DELETE FROM
+			password_history
+		WHERE
+			profile_id = $1
+			AND id NOT IN (
+				SELECT
+					id
+				FROM
+					password_history
+				WHERE
+					profile_id = $1
+				ORDER BY
+					created_at DESC
+				LIMIT $2
+			)`
+
+	queryInsertOAuthClient = `
+		INSERT INTO
+			oauth_client
+			(name, secret_hash, redirect_uris, post_logout_redirect_uris, scopes, grant_types, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	queryGetOAuthClientById = `
+		SELECT
+			id,
+			name,
+			secret_hash,
+			redirect_uris,
+			post_logout_redirect_uris,
+			scopes,
+			grant_types,
+			created_at
+		FROM
+			oauth_client
+		WHERE
+			id = $1`
+
+	queryInsertOAuthAuthorizationCode = `
+		INSERT INTO
+			oauth_authorization_code
+			(code_hash, client_id, profile_id, session_id, redirect_uri, scopes, code_challenge, nonce, expires_at, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	// deleting the code as it is read keeps two exchanges from both
+	// redeeming it
+	queryConsumeOAuthAuthorizationCode = `
+		DELETE FROM
+			oauth_authorization_code
+		WHERE
+			code_hash = $1
+		RETURNING
+			id,
+			code_hash,
+			client_id,
+			profile_id,
+			session_id,
+			redirect_uri,
+			scopes,
+			code_challenge,
+			nonce,
+			expires_at,
+			created_at`
+
+	queryDeleteExpiredOAuthAuthorizationCodes = `
+		DELETE FROM
+			oauth_authorization_code
+		WHERE
+			expires_at < $1`
+
+	queryUpsertOAuthGrant = `
+		INSERT INTO
+			oauth_grant
+			(profile_id, client_id, scopes, created_at, last_used_at)
+		VALUES
+			($1, $2, $3, $4, $4)
+		ON CONFLICT (profile_id, client_id) DO UPDATE SET
+			scopes = EXCLUDED.scopes,
+			last_used_at = EXCLUDED.last_used_at
+		RETURNING id`
+
+	queryGetOAuthGrantById = `
+		SELECT
+			oauth_grant.id,
+			oauth_grant.profile_id,
+			oauth_grant.client_id,
+			oauth_client.name AS client_name,
+			oauth_grant.scopes,
+			oauth_grant.created_at,
+			oauth_grant.last_used_at
+		FROM
+			oauth_grant
+			JOIN oauth_client ON oauth_client.id = oauth_grant.client_id
+		WHERE
+			oauth_grant.id = $1`
+
+	queryGetOAuthGrant = `
+		SELECT
+			oauth_grant.id,
+			oauth_grant.profile_id,
+			oauth_grant.client_id,
+			oauth_client.name AS client_name,
+			oauth_grant.scopes,
+			oauth_grant.created_at,
+			oauth_grant.last_used_at
+		FROM
+			oauth_grant
+			JOIN oauth_client ON oauth_client.id = oauth_grant.client_id
+		WHERE
+			oauth_grant.profile_id = $1
+			AND oauth_grant.client_id = $2`
+
+	queryGetOAuthGrantsByProfileId = `
+		SELECT
+			oauth_grant.id,
+			oauth_grant.profile_id,
+			oauth_grant.client_id,
+			oauth_client.name AS client_name,
+			oauth_grant.scopes,
+			oauth_grant.created_at,
+			oauth_grant.last_used_at
+		FROM
+			oauth_grant
+			JOIN oauth_client ON oauth_client.id = oauth_grant.client_id
+		WHERE
+			oauth_grant.profile_id = $1
+		ORDER BY
+			oauth_grant.created_at DESC`
+
+	queryTouchOAuthGrant = `
+		UPDATE
+			oauth_grant
+		SET
+			last_used_at = $1
+		WHERE
+			id = $2`
+
+	queryDeleteOAuthGrant = `
+		DELETE FROM
+			oauth_grant
+		WHERE
+			profile_id = $1
+			AND client_id = $2
+		RETURNING id`
+
+	queryDeleteOAuthGrantsByProfileId = `
+		DELETE FROM
+			oauth_grant
+		WHERE
+			profile_id = $1
+		RETURNING id`
+
+	queryInsertOAuthDeviceCode = `
+		INSERT INTO
+			oauth_device_code
+			(device_code_hash, user_code, client_id, scopes, poll_interval, expires_at, created_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7)`
+
+	queryGetOAuthDeviceCodeByUserCode = `
+		SELECT
+			id,
+			device_code_hash,
+			user_code,
+			client_id,
+			scopes,
+			status,
+			profile_id,
+			poll_interval,
+			last_polled_at,
+			expires_at,
+			created_at
+		FROM
+			oauth_device_code
+		WHERE
+			user_code = $1`
+
+	queryGetOAuthDeviceCodeByHash = `
+		SELECT
+			id,
+			device_code_hash,
+			user_code,
+			client_id,
+			scopes,
+			status,
+			profile_id,
+			poll_interval,
+			last_polled_at,
+			expires_at,
+			created_at
+		FROM
+			oauth_device_code
+		WHERE
+			device_code_hash = $1`
+
+	// a code is only ever decided once, and not after it expired
+	queryDecideOAuthDeviceCode = `
+		UPDATE
+			oauth_device_code
+		SET
+			status = $2,
+			profile_id = $3
+		WHERE
+			user_code = $1
+			AND status = 'pending'
+			AND expires_at > $4`
+
+	queryPollOAuthDeviceCode = `
+		UPDATE
+			oauth_device_code
+		SET
+			last_polled_at = $2,
+			poll_interval = $3
+		WHERE
+			id = $1`
+
+	queryDeleteOAuthDeviceCode = `
+		DELETE FROM
+			oauth_device_code
+		WHERE
+			id = $1`
+
+	queryDeleteExpiredOAuthDeviceCodes = `
+		DELETE FROM
+			oauth_device_code
+		WHERE
+			expires_at < $1`
+
+	queryGetRoles = `
+		SELECT
+			role.id,
+			role.name,
+			role.description,
+			COALESCE(string_agg(role_permission.permission, ' ' ORDER BY role_permission.permission), '') AS permissions,
+			role.created_at
+		FROM
+			role
+			LEFT JOIN role_permission ON role_permission.role_id = role.id
+		GROUP BY
+			role.id
+		ORDER BY
+			role.name`
+
+	queryGetRoleByName = `
+		SELECT
+			role.id,
+			role.name,
+			role.description,
+			COALESCE(string_agg(role_permission.permission, ' ' ORDER BY role_permission.permission), '') AS permissions,
+			role.created_at
+		FROM
+			role
+			LEFT JOIN role_permission ON role_permission.role_id = role.id
+		WHERE
+			role.name = $1
+		GROUP BY
+			role.id`
+
+	queryGetRolesByProfileId = `
+		SELECT
+			role.id,
+			role.name,
+			role.description,
+			COALESCE(string_agg(role_permission.permission, ' ' ORDER BY role_permission.permission), '') AS permissions,
+			role.created_at
+		FROM
+			profile_role
+			JOIN role ON role.id = profile_role.role_id
+			LEFT JOIN role_permission ON role_permission.role_id = role.id
+		WHERE
+			profile_role.profile_id = $1
+		GROUP BY
+			role.id
+		ORDER BY
+			role.name`
+
+	queryAssignProfileRole = `
+		INSERT INTO
+			profile_role
+			(profile_id, role_id, assigned_by, assigned_at)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT (profile_id, role_id) DO NOTHING`
+
+	queryUnassignProfileRole = `
+		DELETE FROM
+			profile_role
+		WHERE
+			profile_id = $1
+			AND role_id = $2`
 )
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type rateLimitRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitRepository(db *sqlx.DB) rateLimitRepository {
+	return rateLimitRepository{
+		db: db,
+	}
+}
+
+func (repo rateLimitRepository) TakeRateLimitToken(ctx context.Context, bucketKey string, capacity float64, refillPerSecond float64, now time.Time) (bool, entity.RateLimitBucket, error) {
+	var bucket entity.RateLimitBucket
+
+	err := repo.db.GetContext(ctx, &bucket, queryTakeRateLimitToken, bucketKey, capacity, refillPerSecond, now)
+	if err == nil {
+		return true, bucket, nil
+	}
+
+	if err != sql.ErrNoRows {
+		return false, entity.RateLimitBucket{}, err
+	}
+
+	err = repo.db.GetContext(ctx, &bucket, queryGetRateLimitBucket, bucketKey)
+	if err != nil {
+		return false, entity.RateLimitBucket{}, err
+	}
+
+	return false, bucket, nil
+}
+
+func (repo rateLimitRepository) DeleteIdleRateLimitBuckets(ctx context.Context, updatedBefore time.Time) (int64, error) {
+	result, err := repo.db.ExecContext(ctx, queryDeleteIdleRateLimitBuckets, updatedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
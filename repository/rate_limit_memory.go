@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sawitpro/entity"
+	"sync"
+	"time"
+)
+
+// memoryRateLimitRepository keeps the buckets in process, which only limits
+// correctly when a single instance serves all traffic.
+type memoryRateLimitRepository struct {
+	mu      *sync.Mutex
+	buckets map[string]entity.RateLimitBucket
+}
+
+func NewMemoryRateLimitRepository() memoryRateLimitRepository {
+	return memoryRateLimitRepository{
+		mu:      &sync.Mutex{},
+		buckets: map[string]entity.RateLimitBucket{},
+	}
+}
+
+func (repo memoryRateLimitRepository) TakeRateLimitToken(ctx context.Context, bucketKey string, capacity float64, refillPerSecond float64, now time.Time) (bool, entity.RateLimitBucket, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	bucket, exists := repo.buckets[bucketKey]
+	if !exists {
+		bucket = entity.RateLimitBucket{
+			Key:       bucketKey,
+			Tokens:    capacity,
+			UpdatedAt: now,
+		}
+	}
+
+	elapsed := math.Max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+	bucket.Tokens = math.Min(capacity, bucket.Tokens+elapsed*refillPerSecond)
+	bucket.UpdatedAt = now
+
+	taken := bucket.Tokens >= 1
+	if taken {
+		bucket.Tokens--
+	}
+
+	repo.buckets[bucketKey] = bucket
+
+	return taken, bucket, nil
+}
+
+func (repo memoryRateLimitRepository) DeleteIdleRateLimitBuckets(ctx context.Context, updatedBefore time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var deleted int64
+	for bucketKey, bucket := range repo.buckets {
+		if bucket.UpdatedAt.Before(updatedBefore) {
+			delete(repo.buckets, bucketKey)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
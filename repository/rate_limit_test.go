@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitRepository_TakeRateLimitToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"bucket_key", "tokens", "updated_at"}
+
+	tests := []struct {
+		name       string
+		wantTaken  bool
+		wantBucket entity.RateLimitBucket
+		wantErr    error
+		mock       func()
+	}{
+		{
+			name:       "token taken",
+			wantTaken:  true,
+			wantBucket: entity.RateLimitBucket{Key: "login:ip:192.0.2.1", Tokens: 4, UpdatedAt: now},
+			wantErr:    nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_limit_bucket").WithArgs("login:ip:192.0.2.1", float64(5), 0.1, now).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("login:ip:192.0.2.1", 4, now))
+			},
+		},
+		{
+			name:       "bucket empty",
+			wantTaken:  false,
+			wantBucket: entity.RateLimitBucket{Key: "login:ip:192.0.2.1", Tokens: 0.5, UpdatedAt: now},
+			wantErr:    nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_limit_bucket").WithArgs("login:ip:192.0.2.1", float64(5), 0.1, now).
+					WillReturnRows(sqlmock.NewRows(columns))
+				mock.ExpectQuery("SELECT (.+) FROM rate_limit_bucket").WithArgs("login:ip:192.0.2.1").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow("login:ip:192.0.2.1", 0.5, now))
+			},
+		},
+		{
+			name:       "error take token",
+			wantTaken:  false,
+			wantBucket: entity.RateLimitBucket{},
+			wantErr:    errors.New("error upsert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO rate_limit_bucket").WithArgs("login:ip:192.0.2.1", float64(5), 0.1, now).
+					WillReturnError(errors.New("error upsert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRateLimitRepository(dbx)
+			taken, bucket, err := repo.TakeRateLimitToken(context.TODO(), "login:ip:192.0.2.1", 5, 0.1, now)
+			assert.Equal(t, tt.wantTaken, taken)
+			assert.Equal(t, tt.wantBucket, bucket)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_rateLimitRepository_DeleteIdleRateLimitBuckets(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	updatedBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM rate_limit_bucket WHERE updated_at").WithArgs(updatedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewRateLimitRepository(dbx)
+	got, err := repo.DeleteIdleRateLimitBuckets(context.TODO(), updatedBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), got)
+}
+
+func Test_memoryRateLimitRepository(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewMemoryRateLimitRepository()
+
+	for i := 0; i < 2; i++ {
+		taken, _, err := repo.TakeRateLimitToken(context.TODO(), "login:ip:192.0.2.1", 2, 0.5, now)
+		assert.NoError(t, err)
+		assert.True(t, taken)
+	}
+
+	taken, bucket, err := repo.TakeRateLimitToken(context.TODO(), "login:ip:192.0.2.1", 2, 0.5, now)
+	assert.NoError(t, err)
+	assert.False(t, taken)
+	assert.Equal(t, float64(0), bucket.Tokens)
+
+	// two seconds refill one token
+	taken, _, err = repo.TakeRateLimitToken(context.TODO(), "login:ip:192.0.2.1", 2, 0.5, now.Add(2*time.Second))
+	assert.NoError(t, err)
+	assert.True(t, taken)
+
+	deleted, err := repo.DeleteIdleRateLimitBuckets(context.TODO(), now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
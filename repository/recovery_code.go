@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type recoveryCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewRecoveryCodeRepository(db *sqlx.DB) recoveryCodeRepository {
+	return recoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (repo recoveryCodeRepository) InsertRecoveryCode(ctx context.Context, tx *sqlx.Tx, code entity.RecoveryCode) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryInsertRecoveryCode, code.ProfileId, code.CodeHash)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryInsertRecoveryCode, code.ProfileId, code.CodeHash)
+	}
+
+	return err
+}
+
+// UseRecoveryCode marks the unused code of the profile with the hash as used
+// and reports whether there was one. Checking and marking in one statement
+// keeps two logins from spending the same code.
+func (repo recoveryCodeRepository) UseRecoveryCode(ctx context.Context, tx *sqlx.Tx, profileId string, codeHash string, usedAt time.Time) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryUseRecoveryCode, profileId, codeHash, usedAt)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryUseRecoveryCode, profileId, codeHash, usedAt)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo recoveryCodeRepository) CountUnusedRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error) {
+	var count int
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &count, queryCountUnusedRecoveryCodes, profileId)
+	} else {
+		err = repo.db.GetContext(ctx, &count, queryCountUnusedRecoveryCodes, profileId)
+	}
+
+	return count, err
+}
+
+func (repo recoveryCodeRepository) DeleteRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryDeleteRecoveryCodes, profileId)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryDeleteRecoveryCodes, profileId)
+	}
+
+	return err
+}
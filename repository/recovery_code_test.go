@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_recoveryCodeRepository_InsertRecoveryCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("INSERT INTO recovery_code").WithArgs("profile-id-1", "code-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewRecoveryCodeRepository(dbx)
+	err := repo.InsertRecoveryCode(context.TODO(), nil, entity.RecoveryCode{
+		ProfileId: "profile-id-1",
+		CodeHash:  "code-hash",
+	})
+	assert.NoError(t, err)
+}
+
+func Test_recoveryCodeRepository_UseRecoveryCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	usedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success use recovery code",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE recovery_code SET used_at").WithArgs("profile-id-1", "code-hash", usedAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "unknown or used recovery code",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE recovery_code SET used_at").WithArgs("profile-id-1", "code-hash", usedAt).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error use recovery code",
+			want:    false,
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE recovery_code SET used_at").WithArgs("profile-id-1", "code-hash", usedAt).
+					WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRecoveryCodeRepository(dbx)
+			got, err := repo.UseRecoveryCode(context.TODO(), nil, "profile-id-1", "code-hash", usedAt)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_recoveryCodeRepository_CountUnusedRecoveryCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    int
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success count unused recovery codes",
+			want:    8,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT(.+) FROM recovery_code").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(8))
+			},
+		},
+		{
+			name:    "error count unused recovery codes",
+			want:    0,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT COUNT(.+) FROM recovery_code").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRecoveryCodeRepository(dbx)
+			got, err := repo.CountUnusedRecoveryCodes(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_recoveryCodeRepository_DeleteRecoveryCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM recovery_code").WithArgs("profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+
+	repo := NewRecoveryCodeRepository(dbx)
+	err := repo.DeleteRecoveryCodes(context.TODO(), nil, "profile-id-1")
+	assert.NoError(t, err)
+}
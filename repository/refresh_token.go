@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type refreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepository(db *sqlx.DB) refreshTokenRepository {
+	return refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (repo refreshTokenRepository) InsertRefreshToken(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertRefreshToken,
+			token.ProfileId,
+			token.FamilyId,
+			token.TokenHash,
+			token.ExpiresAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertRefreshToken,
+			token.ProfileId,
+			token.FamilyId,
+			token.TokenHash,
+			token.ExpiresAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+func (repo refreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.RefreshToken, error) {
+	var res entity.RefreshToken
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetRefreshTokenByHash, tokenHash)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetRefreshTokenByHash, tokenHash)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+// MarkRefreshTokenUsed reports false when the token had already been used or
+// revoked, which lets concurrent refreshes of the same token detect each other.
+func (repo refreshTokenRepository) MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryMarkRefreshTokenUsed, id)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryMarkRefreshTokenUsed, id)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo refreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, tx *sqlx.Tx, familyId string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryRevokeRefreshTokenFamily, familyId)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryRevokeRefreshTokenFamily, familyId)
+	}
+
+	return err
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_refreshTokenRepository_InsertRefreshToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		db *sqlx.DB
+	}
+	type args struct {
+		ctx   context.Context
+		tx    *sqlx.Tx
+		token entity.RefreshToken
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success insert refresh token",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx:  nil,
+				token: entity.RefreshToken{
+					ProfileId: "profile-id-1",
+					FamilyId:  "family-id-1",
+					TokenHash: "hash-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			want:    "refresh-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO refresh_token").WithArgs(
+					"profile-id-1",
+					"family-id-1",
+					"hash-1",
+					expiresAt,
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("refresh-id-1"))
+			},
+		},
+		{
+			name: "error insert refresh token with transaction",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx: func() *sqlx.Tx {
+					mock.ExpectBegin()
+					tx, _ := dbx.Beginx()
+					return tx
+				}(),
+				token: entity.RefreshToken{
+					ProfileId: "profile-id-1",
+					FamilyId:  "family-id-1",
+					TokenHash: "hash-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO refresh_token").WithArgs(
+					"profile-id-1",
+					"family-id-1",
+					"hash-1",
+					expiresAt,
+				).WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := refreshTokenRepository{
+				db: tt.fields.db,
+			}
+			got, err := repo.InsertRefreshToken(tt.args.ctx, tt.args.tx, tt.args.token)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_refreshTokenRepository_GetRefreshTokenByHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type fields struct {
+		db *sqlx.DB
+	}
+	type args struct {
+		ctx       context.Context
+		tx        *sqlx.Tx
+		tokenHash string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    entity.RefreshToken
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get refresh token",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tx:        nil,
+				tokenHash: "hash-1",
+			},
+			want: entity.RefreshToken{
+				Id:        "refresh-id-1",
+				ProfileId: "profile-id-1",
+				FamilyId:  "family-id-1",
+				TokenHash: "hash-1",
+				ExpiresAt: expiresAt,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("hash-1").WillReturnRows(
+					sqlmock.NewRows([]string{
+						"id",
+						"profile_id",
+						"family_id",
+						"token_hash",
+						"expires_at",
+						"used_at",
+						"revoked_at",
+					}).AddRow(
+						"refresh-id-1",
+						"profile-id-1",
+						"family-id-1",
+						"hash-1",
+						expiresAt,
+						nil,
+						nil,
+					),
+				)
+			},
+		},
+		{
+			name: "refresh token not found",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tx:        nil,
+				tokenHash: "hash-1",
+			},
+			want:    entity.RefreshToken{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("hash-1").WillReturnRows(
+					sqlmock.NewRows([]string{"id"}),
+				)
+			},
+		},
+		{
+			name: "error get refresh token",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:       context.TODO(),
+				tx:        nil,
+				tokenHash: "hash-1",
+			},
+			want:    entity.RefreshToken{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("hash-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := refreshTokenRepository{
+				db: tt.fields.db,
+			}
+			got, err := repo.GetRefreshTokenByHash(tt.args.ctx, tt.args.tx, tt.args.tokenHash)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_refreshTokenRepository_MarkRefreshTokenUsed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	type fields struct {
+		db *sqlx.DB
+	}
+	type args struct {
+		ctx context.Context
+		tx  *sqlx.Tx
+		id  string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "token marked as used",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx:  nil,
+				id:  "refresh-id-1",
+			},
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE refresh_token").WithArgs("refresh-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "token already used",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx:  nil,
+				id:  "refresh-id-1",
+			},
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE refresh_token").WithArgs("refresh-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "error update",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx:  nil,
+				id:  "refresh-id-1",
+			},
+			want:    false,
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE refresh_token").WithArgs("refresh-id-1").
+					WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := refreshTokenRepository{
+				db: tt.fields.db,
+			}
+			got, err := repo.MarkRefreshTokenUsed(tt.args.ctx, tt.args.tx, tt.args.id)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_refreshTokenRepository_RevokeRefreshTokenFamily(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	type fields struct {
+		db *sqlx.DB
+	}
+	type args struct {
+		ctx      context.Context
+		tx       *sqlx.Tx
+		familyId string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success revoke family",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:      context.TODO(),
+				tx:       nil,
+				familyId: "family-id-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE refresh_token").WithArgs("family-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name: "error revoke family",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:      context.TODO(),
+				tx:       nil,
+				familyId: "family-id-1",
+			},
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE refresh_token").WithArgs("family-id-1").
+					WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := refreshTokenRepository{
+				db: tt.fields.db,
+			}
+			err := repo.RevokeRefreshTokenFamily(tt.args.ctx, tt.args.tx, tt.args.familyId)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"sawitpro/entity"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -16,4 +17,145 @@ type UserProfileRepositoryInterface interface {
 	UpdateProfileById(ctx context.Context, tx *sqlx.Tx, id string, updateData entity.UserProfile) error
 	GetProfileByPhoneNumber(ctx context.Context, tx *sqlx.Tx, phoneNumber string) (entity.UserProfile, error)
 	IncreaseSuccessLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) error
+	IncreaseFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error)
+	LockProfile(ctx context.Context, tx *sqlx.Tx, profileId string, lockedUntil time.Time) error
+	ResetFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) error
+	UpdatePasswordById(ctx context.Context, tx *sqlx.Tx, id string, hashedPassword string) error
+	RehashPasswordById(ctx context.Context, tx *sqlx.Tx, id string, oldHashedPassword string, newHashedPassword string) error
+	SetMustChangePassword(ctx context.Context, tx *sqlx.Tx, id string, mustChangePassword bool) error
+	MarkPhoneVerified(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) error
+	SetPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id string, phoneNumber string) error
+	ConfirmPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) (bool, error)
+	DeleteProfileById(ctx context.Context, tx *sqlx.Tx, id string) error
+	DeleteUnverifiedProfiles(ctx context.Context, tx *sqlx.Tx, createdBefore time.Time) (int64, error)
+}
+
+type RefreshTokenRepositoryInterface interface {
+	InsertRefreshToken(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error)
+	GetRefreshTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id string) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, tx *sqlx.Tx, familyId string) error
+}
+
+type SessionRepositoryInterface interface {
+	InsertSession(ctx context.Context, tx *sqlx.Tx, session entity.Session) (string, error)
+	GetSessionById(ctx context.Context, tx *sqlx.Tx, id string) (entity.Session, error)
+	GetSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Session, error)
+	TouchSession(ctx context.Context, tx *sqlx.Tx, id string, lastSeenAt time.Time) error
+	DeleteSession(ctx context.Context, tx *sqlx.Tx, profileId string, id string) (bool, error)
+	DeleteOtherSessions(ctx context.Context, tx *sqlx.Tx, profileId string, keepId string) ([]string, error)
+	DeleteSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error)
+	DeleteIdleSessions(ctx context.Context, tx *sqlx.Tx, lastSeenBefore time.Time) (int64, error)
+}
+
+type PersonalAccessTokenRepositoryInterface interface {
+	InsertPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, token entity.PersonalAccessToken) (string, error)
+	GetPersonalAccessTokenByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.PersonalAccessToken, error)
+	GetPersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.PersonalAccessToken, error)
+	TouchPersonalAccessToken(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error
+	DeletePersonalAccessToken(ctx context.Context, tx *sqlx.Tx, profileId string, id string) (bool, error)
+	DeletePersonalAccessTokensByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) (int64, error)
+}
+
+type OAuthClientRepositoryInterface interface {
+	InsertOAuthClient(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error)
+	GetOAuthClientById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthClient, error)
+}
+
+type OAuthAuthorizationCodeRepositoryInterface interface {
+	InsertOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthAuthorizationCode) error
+	ConsumeOAuthAuthorizationCode(ctx context.Context, tx *sqlx.Tx, codeHash string) (entity.OAuthAuthorizationCode, error)
+	DeleteExpiredOAuthAuthorizationCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error)
+}
+
+type OAuthDeviceCodeRepositoryInterface interface {
+	InsertOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, code entity.OAuthDeviceCode) error
+	GetOAuthDeviceCodeByUserCode(ctx context.Context, tx *sqlx.Tx, userCode string) (entity.OAuthDeviceCode, error)
+	GetOAuthDeviceCodeByHash(ctx context.Context, tx *sqlx.Tx, deviceCodeHash string) (entity.OAuthDeviceCode, error)
+	DecideOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, userCode string, status string, profileId string, now time.Time) (bool, error)
+	PollOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string, polledAt time.Time, interval int64) error
+	DeleteOAuthDeviceCode(ctx context.Context, tx *sqlx.Tx, id string) (bool, error)
+	DeleteExpiredOAuthDeviceCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error)
+}
+
+type OAuthGrantRepositoryInterface interface {
+	UpsertOAuthGrant(ctx context.Context, tx *sqlx.Tx, grant entity.OAuthGrant) (string, error)
+	GetOAuthGrantById(ctx context.Context, tx *sqlx.Tx, id string) (entity.OAuthGrant, error)
+	GetOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId string, clientId string) (entity.OAuthGrant, error)
+	GetOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.OAuthGrant, error)
+	TouchOAuthGrant(ctx context.Context, tx *sqlx.Tx, id string, lastUsedAt time.Time) error
+	DeleteOAuthGrant(ctx context.Context, tx *sqlx.Tx, profileId string, clientId string) (string, error)
+	DeleteOAuthGrantsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error)
+}
+
+type RoleRepositoryInterface interface {
+	GetRoles(ctx context.Context, tx *sqlx.Tx) ([]entity.Role, error)
+	GetRoleByName(ctx context.Context, tx *sqlx.Tx, name string) (entity.Role, error)
+	GetRolesByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Role, error)
+	AssignProfileRole(ctx context.Context, tx *sqlx.Tx, profileRole entity.ProfileRole) (bool, error)
+	UnassignProfileRole(ctx context.Context, tx *sqlx.Tx, profileId string, roleId string) (bool, error)
+}
+
+type OneTimeCodeRepositoryInterface interface {
+	InsertOneTimeCode(ctx context.Context, tx *sqlx.Tx, code entity.OneTimeCode) (string, error)
+	GetActiveOneTimeCode(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string) (entity.OneTimeCode, error)
+	IncreaseOneTimeCodeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error
+	ConsumeOneTimeCodes(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string) error
+	DeleteExpiredOneTimeCodes(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error)
+}
+
+type RecoveryCodeRepositoryInterface interface {
+	InsertRecoveryCode(ctx context.Context, tx *sqlx.Tx, code entity.RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, tx *sqlx.Tx, profileId string, codeHash string, usedAt time.Time) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error)
+	DeleteRecoveryCodes(ctx context.Context, tx *sqlx.Tx, profileId string) error
+}
+
+type PasswordHistoryRepositoryInterface interface {
+	InsertPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, hashedPassword string) error
+	GetPasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, limit int) ([]entity.PasswordHistory, error)
+	PrunePasswordHistory(ctx context.Context, tx *sqlx.Tx, profileId string, keep int) error
+}
+
+type TOTPCredentialRepositoryInterface interface {
+	GetTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (entity.TOTPCredential, error)
+	SetPendingTOTPSecret(ctx context.Context, tx *sqlx.Tx, profileId string, pendingSecret string) error
+	EnableTOTP(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64, enabledAt time.Time) error
+	UpdateTOTPLastUsedStep(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64) error
+	DeleteTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (bool, error)
+}
+
+type MFAChallengeRepositoryInterface interface {
+	InsertMFAChallenge(ctx context.Context, tx *sqlx.Tx, challenge entity.MFAChallenge) (string, error)
+	GetMFAChallengeByHash(ctx context.Context, tx *sqlx.Tx, tokenHash string) (entity.MFAChallenge, error)
+	IncreaseMFAChallengeAttempts(ctx context.Context, tx *sqlx.Tx, id string) error
+	DeleteMFAChallenge(ctx context.Context, tx *sqlx.Tx, id string) error
+	DeleteExpiredMFAChallenges(ctx context.Context, tx *sqlx.Tx, now time.Time) (int64, error)
+}
+
+// RevokedTokenRepositoryInterface is implemented by both a Postgres and an
+// in-memory store, so unlike the other repositories it does not take a
+// transaction.
+type RevokedTokenRepositoryInterface interface {
+	RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, tokenId string) (bool, error)
+	PruneExpiredTokens(ctx context.Context, now time.Time) (int64, error)
+}
+
+// RateLimitRepositoryInterface is implemented by both a Postgres and an
+// in-memory store, so it does not take a transaction either.
+type RateLimitRepositoryInterface interface {
+	// TakeRateLimitToken refills the bucket up to now and takes a token from
+	// it if one is left. It reports whether it did and the bucket as stored.
+	TakeRateLimitToken(ctx context.Context, bucketKey string, capacity float64, refillPerSecond float64, now time.Time) (bool, entity.RateLimitBucket, error)
+	DeleteIdleRateLimitBuckets(ctx context.Context, updatedBefore time.Time) (int64, error)
+}
+
+type SigningKeyRepositoryInterface interface {
+	RunWithTransaction(ctx context.Context, handleFunc TransactionHandleFunc) error
+	LockSigningKeys(ctx context.Context, tx *sqlx.Tx) error
+	InsertSigningKey(ctx context.Context, tx *sqlx.Tx, key entity.SigningKey) error
+	GetSigningKeys(ctx context.Context, tx *sqlx.Tx) ([]entity.SigningKey, error)
+	RetireSigningKey(ctx context.Context, tx *sqlx.Tx, id string, retiresAt time.Time) error
+	DeleteRetiredSigningKeys(ctx context.Context, tx *sqlx.Tx, now time.Time) error
 }
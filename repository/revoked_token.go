@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type revokedTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRevokedTokenRepository(db *sqlx.DB) revokedTokenRepository {
+	return revokedTokenRepository{
+		db: db,
+	}
+}
+
+func (repo revokedTokenRepository) RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	_, err := repo.db.ExecContext(ctx, queryInsertRevokedToken, tokenId, expiresAt)
+
+	return err
+}
+
+func (repo revokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	var revoked bool
+
+	err := repo.db.GetContext(ctx, &revoked, queryIsTokenRevoked, tokenId)
+
+	return revoked, err
+}
+
+func (repo revokedTokenRepository) PruneExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	result, err := repo.db.ExecContext(ctx, queryDeleteExpiredRevokedTokens, now)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
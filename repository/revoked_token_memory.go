@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryRevokedTokenRepository keeps revocations in process memory. It is
+// meant for single instance deployments and tests, revocations are lost on
+// restart and are not shared between instances.
+type memoryRevokedTokenRepository struct {
+	mu     *sync.RWMutex
+	tokens map[string]time.Time
+}
+
+func NewMemoryRevokedTokenRepository() memoryRevokedTokenRepository {
+	return memoryRevokedTokenRepository{
+		mu:     &sync.RWMutex{},
+		tokens: map[string]time.Time{},
+	}
+}
+
+func (repo memoryRevokedTokenRepository) RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.tokens[tokenId] = expiresAt
+
+	return nil
+}
+
+func (repo memoryRevokedTokenRepository) IsTokenRevoked(ctx context.Context, tokenId string) (bool, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	_, revoked := repo.tokens[tokenId]
+
+	return revoked, nil
+}
+
+func (repo memoryRevokedTokenRepository) PruneExpiredTokens(ctx context.Context, now time.Time) (int64, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var pruned int64
+	for tokenId, expiresAt := range repo.tokens {
+		if expiresAt.Before(now) {
+			delete(repo.tokens, tokenId)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_revokedTokenRepository_RevokeToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	expiresAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke token",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("INSERT INTO revoked_token").WithArgs("token-id-1", expiresAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "error revoke token",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectExec("INSERT INTO revoked_token").WithArgs("token-id-1", expiresAt).
+					WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRevokedTokenRepository(dbx)
+			err := repo.RevokeToken(context.TODO(), "token-id-1", expiresAt)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_revokedTokenRepository_IsTokenRevoked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "token revoked",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("token-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:    "token not revoked",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("token-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+		},
+		{
+			name:    "error select",
+			want:    false,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT").WithArgs("token-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRevokedTokenRepository(dbx)
+			got, err := repo.IsTokenRevoked(context.TODO(), "token-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_revokedTokenRepository_PruneExpiredTokens(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			want:    3,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM revoked_token").WithArgs(now).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name:    "error prune",
+			want:    0,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM revoked_token").WithArgs(now).
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRevokedTokenRepository(dbx)
+			got, err := repo.PruneExpiredTokens(context.TODO(), now)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_memoryRevokedTokenRepository(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := NewMemoryRevokedTokenRepository()
+
+	_ = repo.RevokeToken(ctx, "expired-token", now.Add(-time.Minute))
+	_ = repo.RevokeToken(ctx, "live-token", now.Add(time.Minute))
+
+	revoked, err := repo.IsTokenRevoked(ctx, "live-token")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "unknown-token")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	pruned, err := repo.PruneExpiredTokens(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+
+	revoked, _ = repo.IsTokenRevoked(ctx, "expired-token")
+	assert.False(t, revoked)
+
+	revoked, _ = repo.IsTokenRevoked(ctx, "live-token")
+	assert.True(t, revoked)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type roleRepository struct {
+	db *sqlx.DB
+}
+
+func NewRoleRepository(db *sqlx.DB) roleRepository {
+	return roleRepository{
+		db: db,
+	}
+}
+
+func (repo roleRepository) GetRoles(ctx context.Context, tx *sqlx.Tx) ([]entity.Role, error) {
+	var res []entity.Role
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetRoles)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetRoles)
+	}
+
+	return res, err
+}
+
+func (repo roleRepository) GetRoleByName(ctx context.Context, tx *sqlx.Tx, name string) (entity.Role, error) {
+	var res entity.Role
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetRoleByName, name)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetRoleByName, name)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo roleRepository) GetRolesByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Role, error) {
+	var res []entity.Role
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetRolesByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetRolesByProfileId, profileId)
+	}
+
+	return res, err
+}
+
+// AssignProfileRole reports whether the profile did not hold the role yet.
+func (repo roleRepository) AssignProfileRole(ctx context.Context, tx *sqlx.Tx, profileRole entity.ProfileRole) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryAssignProfileRole, profileRole.ProfileId, profileRole.RoleId, profileRole.AssignedBy, profileRole.AssignedAt)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryAssignProfileRole, profileRole.ProfileId, profileRole.RoleId, profileRole.AssignedBy, profileRole.AssignedAt)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// UnassignProfileRole reports whether the profile held the role.
+func (repo roleRepository) UnassignProfileRole(ctx context.Context, tx *sqlx.Tx, profileId string, roleId string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryUnassignProfileRole, profileId, roleId)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryUnassignProfileRole, profileId, roleId)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_roleRepository_GetRoles(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "name", "description", "permissions", "created_at"}
+
+	mock.ExpectQuery("SELECT (.+) FROM role LEFT JOIN role_permission (.+) GROUP BY role.id ORDER BY role.name").WillReturnRows(
+		sqlmock.NewRows(columns).
+			AddRow("role-id-1", "admin", "Manages who holds which role", "roles:read roles:write", now).
+			AddRow("role-id-2", "support", "Answers customers", "", now),
+	)
+
+	repo := NewRoleRepository(dbx)
+	got, err := repo.GetRoles(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Role{
+		{
+			Id:          "role-id-1",
+			Name:        "admin",
+			Description: "Manages who holds which role",
+			Permissions: "roles:read roles:write",
+			CreatedAt:   now,
+		},
+		{
+			Id:          "role-id-2",
+			Name:        "support",
+			Description: "Answers customers",
+			CreatedAt:   now,
+		},
+	}, got)
+}
+
+func Test_roleRepository_GetRoleByName(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "name", "description", "permissions", "created_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.Role
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get role",
+			want: entity.Role{
+				Id:          "role-id-1",
+				Name:        "admin",
+				Description: "Manages who holds which role",
+				Permissions: "roles:read roles:write",
+				CreatedAt:   now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM role LEFT JOIN role_permission (.+) WHERE role.name").WithArgs("admin").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("role-id-1", "admin", "Manages who holds which role", "roles:read roles:write", now),
+				)
+			},
+		},
+		{
+			name:    "role not found",
+			want:    entity.Role{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM role LEFT JOIN role_permission (.+) WHERE role.name").WithArgs("admin").WillReturnRows(
+					sqlmock.NewRows(columns),
+				)
+			},
+		},
+		{
+			name:    "error get role",
+			want:    entity.Role{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM role LEFT JOIN role_permission (.+) WHERE role.name").WithArgs("admin").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewRoleRepository(dbx)
+			got, err := repo.GetRoleByName(context.TODO(), nil, "admin")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_roleRepository_GetRolesByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "name", "description", "permissions", "created_at"}
+
+	mock.ExpectQuery("SELECT (.+) FROM profile_role JOIN role (.+) WHERE profile_role.profile_id").WithArgs("profile-id-1").WillReturnRows(
+		sqlmock.NewRows(columns).AddRow("role-id-1", "admin", "Manages who holds which role", "roles:read roles:write", now),
+	)
+
+	repo := NewRoleRepository(dbx)
+	got, err := repo.GetRolesByProfileId(context.TODO(), nil, "profile-id-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Role{
+		{
+			Id:          "role-id-1",
+			Name:        "admin",
+			Description: "Manages who holds which role",
+			Permissions: "roles:read roles:write",
+			CreatedAt:   now,
+		},
+	}, got)
+}
+
+func Test_roleRepository_AssignProfileRole(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assignedBy := "profile-id-2"
+
+	profileRole := entity.ProfileRole{
+		ProfileId:  "profile-id-1",
+		RoleId:     "role-id-1",
+		AssignedBy: &assignedBy,
+		AssignedAt: now,
+	}
+
+	mock.ExpectExec("INSERT INTO profile_role (.+) ON CONFLICT (.+) DO NOTHING").WithArgs("profile-id-1", "role-id-1", &assignedBy, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO profile_role (.+) ON CONFLICT (.+) DO NOTHING").WithArgs("profile-id-1", "role-id-1", &assignedBy, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewRoleRepository(dbx)
+	got, err := repo.AssignProfileRole(context.TODO(), nil, profileRole)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = repo.AssignProfileRole(context.TODO(), nil, profileRole)
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
+
+func Test_roleRepository_UnassignProfileRole(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM profile_role WHERE profile_id (.+) AND role_id").WithArgs("profile-id-1", "role-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM profile_role WHERE profile_id (.+) AND role_id").WithArgs("profile-id-1", "role-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewRoleRepository(dbx)
+	got, err := repo.UnassignProfileRole(context.TODO(), nil, "profile-id-1", "role-id-1")
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = repo.UnassignProfileRole(context.TODO(), nil, "profile-id-1", "role-id-1")
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type sessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) sessionRepository {
+	return sessionRepository{
+		db: db,
+	}
+}
+
+func (repo sessionRepository) InsertSession(ctx context.Context, tx *sqlx.Tx, session entity.Session) (string, error) {
+	var id string
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(
+			ctx,
+			queryInsertSession,
+			session.ProfileId,
+			session.DeviceName,
+			session.UserAgent,
+			session.IpAddress,
+			session.CreatedAt,
+			session.LastSeenAt,
+		).Scan(&id)
+	} else {
+		err = repo.db.QueryRowContext(
+			ctx,
+			queryInsertSession,
+			session.ProfileId,
+			session.DeviceName,
+			session.UserAgent,
+			session.IpAddress,
+			session.CreatedAt,
+			session.LastSeenAt,
+		).Scan(&id)
+	}
+
+	return id, err
+}
+
+func (repo sessionRepository) GetSessionById(ctx context.Context, tx *sqlx.Tx, id string) (entity.Session, error) {
+	var res entity.Session
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetSessionById, id)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetSessionById, id)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return res, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (repo sessionRepository) GetSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]entity.Session, error) {
+	var res []entity.Session
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetSessionsByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetSessionsByProfileId, profileId)
+	}
+
+	return res, err
+}
+
+func (repo sessionRepository) TouchSession(ctx context.Context, tx *sqlx.Tx, id string, lastSeenAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryTouchSession, lastSeenAt, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryTouchSession, lastSeenAt, id)
+	}
+
+	return err
+}
+
+// DeleteSession only deletes the session when it belongs to the given
+// profile, and reports whether it did.
+func (repo sessionRepository) DeleteSession(ctx context.Context, tx *sqlx.Tx, profileId string, id string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteSession, id, profileId)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteSession, id, profileId)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteOtherSessions deletes every session of the profile except keepId and
+// returns the ids of the deleted ones.
+func (repo sessionRepository) DeleteOtherSessions(ctx context.Context, tx *sqlx.Tx, profileId string, keepId string) ([]string, error) {
+	var res []string
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryDeleteOtherSessions, profileId, keepId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryDeleteOtherSessions, profileId, keepId)
+	}
+
+	return res, err
+}
+
+// DeleteSessionsByProfileId deletes every session of the profile and returns
+// the ids of the deleted ones.
+func (repo sessionRepository) DeleteSessionsByProfileId(ctx context.Context, tx *sqlx.Tx, profileId string) ([]string, error) {
+	var res []string
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryDeleteSessionsByProfileId, profileId)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryDeleteSessionsByProfileId, profileId)
+	}
+
+	return res, err
+}
+
+func (repo sessionRepository) DeleteIdleSessions(ctx context.Context, tx *sqlx.Tx, lastSeenBefore time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteIdleSessions, lastSeenBefore)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteIdleSessions, lastSeenBefore)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
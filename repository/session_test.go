@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sessionRepository_InsertSession(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	session := entity.Session{
+		ProfileId:  "profile-id-1",
+		DeviceName: "Pixel 8",
+		UserAgent:  "okhttp/4.12.0",
+		IpAddress:  "192.0.2.1",
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert session",
+			want:    "session-id-1",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO user_session").WithArgs(
+					"profile-id-1",
+					"Pixel 8",
+					"okhttp/4.12.0",
+					"192.0.2.1",
+					now,
+					now,
+				).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session-id-1"))
+			},
+		},
+		{
+			name:    "error insert session",
+			want:    "",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectQuery("INSERT INTO user_session").WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.InsertSession(context.TODO(), nil, session)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_sessionRepository_GetSessionById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "device_name", "user_agent", "ip_address", "created_at", "last_seen_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.Session
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get session",
+			want: entity.Session{
+				Id:         "session-id-1",
+				ProfileId:  "profile-id-1",
+				DeviceName: "Pixel 8",
+				UserAgent:  "okhttp/4.12.0",
+				IpAddress:  "192.0.2.1",
+				CreatedAt:  now,
+				LastSeenAt: now,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM user_session WHERE id").WithArgs("session-id-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("session-id-1", "profile-id-1", "Pixel 8", "okhttp/4.12.0", "192.0.2.1", now, now),
+				)
+			},
+		},
+		{
+			name:    "session not found",
+			want:    entity.Session{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM user_session WHERE id").WithArgs("session-id-1").WillReturnRows(
+					sqlmock.NewRows(columns),
+				)
+			},
+		},
+		{
+			name:    "error get session",
+			want:    entity.Session{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM user_session WHERE id").WithArgs("session-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.GetSessionById(context.TODO(), nil, "session-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_sessionRepository_GetSessionsByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "profile_id", "device_name", "user_agent", "ip_address", "created_at", "last_seen_at"}
+
+	tests := []struct {
+		name    string
+		want    []entity.Session
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get sessions",
+			want: []entity.Session{
+				{
+					Id:         "session-id-1",
+					ProfileId:  "profile-id-1",
+					DeviceName: "Pixel 8",
+					UserAgent:  "okhttp/4.12.0",
+					IpAddress:  "192.0.2.1",
+					CreatedAt:  now,
+					LastSeenAt: now,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM user_session WHERE profile_id").WithArgs("profile-id-1").WillReturnRows(
+					sqlmock.NewRows(columns).AddRow("session-id-1", "profile-id-1", "Pixel 8", "okhttp/4.12.0", "192.0.2.1", now, now),
+				)
+			},
+		},
+		{
+			name:    "error get sessions",
+			want:    nil,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM user_session WHERE profile_id").WithArgs("profile-id-1").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.GetSessionsByProfileId(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_sessionRepository_TouchSession(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE user_session SET last_seen_at").WithArgs(now, "session-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewSessionRepository(dbx)
+	err := repo.TouchSession(context.TODO(), nil, "session-id-1", now)
+	assert.NoError(t, err)
+}
+
+func Test_sessionRepository_DeleteSession(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete session",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM user_session WHERE id").WithArgs("session-id-1", "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "session of another profile is not deleted",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM user_session WHERE id").WithArgs("session-id-1", "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error delete session",
+			want:    false,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM user_session WHERE id").WithArgs("session-id-1", "profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.DeleteSession(context.TODO(), nil, "profile-id-1", "session-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_sessionRepository_DeleteOtherSessions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete other sessions",
+			want:    []string{"session-id-2", "session-id-3"},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM user_session WHERE profile_id").WithArgs("profile-id-1", "session-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session-id-2").AddRow("session-id-3"))
+			},
+		},
+		{
+			name:    "error delete other sessions",
+			want:    nil,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM user_session WHERE profile_id").WithArgs("profile-id-1", "session-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.DeleteOtherSessions(context.TODO(), nil, "profile-id-1", "session-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_sessionRepository_DeleteIdleSessions(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	lastSeenBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM user_session WHERE last_seen_at").WithArgs(lastSeenBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := NewSessionRepository(dbx)
+	got, err := repo.DeleteIdleSessions(context.TODO(), nil, lastSeenBefore)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), got)
+}
+
+func Test_sessionRepository_DeleteSessionsByProfileId(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    []string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete sessions",
+			want:    []string{"session-id-1", "session-id-2"},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM user_session WHERE profile_id").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("session-id-1").AddRow("session-id-2"))
+			},
+		},
+		{
+			name:    "error delete sessions",
+			want:    nil,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectQuery("DELETE FROM user_session WHERE profile_id").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSessionRepository(dbx)
+			got, err := repo.DeleteSessionsByProfileId(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
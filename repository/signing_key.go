@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type signingKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewSigningKeyRepository(db *sqlx.DB) signingKeyRepository {
+	return signingKeyRepository{
+		db: db,
+	}
+}
+
+// LockSigningKeys serializes rotations across instances for the lifetime of
+// the transaction, so two instances never mint a replacement key at once.
+func (repo signingKeyRepository) LockSigningKeys(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, queryLockSigningKeys)
+
+	return err
+}
+
+func (repo signingKeyRepository) InsertSigningKey(ctx context.Context, tx *sqlx.Tx, key entity.SigningKey) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(
+			ctx,
+			queryInsertSigningKey,
+			key.Id,
+			key.Algorithm,
+			key.PrivateKey,
+			key.ActivatesAt,
+		)
+	} else {
+		_, err = repo.db.ExecContext(
+			ctx,
+			queryInsertSigningKey,
+			key.Id,
+			key.Algorithm,
+			key.PrivateKey,
+			key.ActivatesAt,
+		)
+	}
+
+	return err
+}
+
+func (repo signingKeyRepository) GetSigningKeys(ctx context.Context, tx *sqlx.Tx) ([]entity.SigningKey, error) {
+	var res []entity.SigningKey
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &res, queryGetSigningKeys)
+	} else {
+		err = repo.db.SelectContext(ctx, &res, queryGetSigningKeys)
+	}
+
+	return res, err
+}
+
+func (repo signingKeyRepository) RetireSigningKey(ctx context.Context, tx *sqlx.Tx, id string, retiresAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryRetireSigningKey, retiresAt, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryRetireSigningKey, retiresAt, id)
+	}
+
+	return err
+}
+
+func (repo signingKeyRepository) DeleteRetiredSigningKeys(ctx context.Context, tx *sqlx.Tx, now time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryDeleteRetiredSigningKeys, now)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryDeleteRetiredSigningKeys, now)
+	}
+
+	return err
+}
+
+func (repo signingKeyRepository) RunWithTransaction(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) error {
+	tx, err := repo.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	err = handleFunc(tx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
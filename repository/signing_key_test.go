@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_signingKeyRepository_InsertSigningKey(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	activatesAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success insert signing key",
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("INSERT INTO signing_key").WithArgs("key-1", "RS256", "encrypted", activatesAt).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "error insert signing key",
+			wantErr: errors.New("error insert"),
+			mock: func() {
+				mock.ExpectExec("INSERT INTO signing_key").WithArgs("key-1", "RS256", "encrypted", activatesAt).
+					WillReturnError(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSigningKeyRepository(dbx)
+			err := repo.InsertSigningKey(context.TODO(), nil, entity.SigningKey{
+				Id:          "key-1",
+				Algorithm:   "RS256",
+				PrivateKey:  "encrypted",
+				ActivatesAt: activatesAt,
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_signingKeyRepository_GetSigningKeys(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	activatesAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	retiresAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    []entity.SigningKey
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get signing keys",
+			want: []entity.SigningKey{
+				{
+					Id:          "key-1",
+					Algorithm:   "RS256",
+					PrivateKey:  "encrypted-1",
+					ActivatesAt: activatesAt,
+					RetiresAt:   &retiresAt,
+				},
+				{
+					Id:          "key-2",
+					Algorithm:   "RS256",
+					PrivateKey:  "encrypted-2",
+					ActivatesAt: retiresAt,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT").WillReturnRows(
+					sqlmock.NewRows([]string{
+						"id",
+						"algorithm",
+						"private_key",
+						"activates_at",
+						"retires_at",
+					}).
+						AddRow("key-1", "RS256", "encrypted-1", activatesAt, retiresAt).
+						AddRow("key-2", "RS256", "encrypted-2", retiresAt, nil),
+				)
+			},
+		},
+		{
+			name:    "error get signing keys",
+			want:    nil,
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT").WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewSigningKeyRepository(dbx)
+			got, err := repo.GetSigningKeys(context.TODO(), nil)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_signingKeyRepository_RetireSigningKey(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	retiresAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	tx, _ := dbx.Beginx()
+
+	mock.ExpectExec("UPDATE signing_key").WithArgs(retiresAt, "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewSigningKeyRepository(dbx)
+	err := repo.RetireSigningKey(context.TODO(), tx, "key-1", retiresAt)
+	assert.Nil(t, err)
+}
+
+func Test_signingKeyRepository_DeleteRetiredSigningKeys(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("DELETE FROM signing_key").WithArgs(now).
+		WillReturnError(errors.New("error delete"))
+
+	repo := NewSigningKeyRepository(dbx)
+	err := repo.DeleteRetiredSigningKeys(context.TODO(), nil, now)
+	assert.Equal(t, errors.New("error delete"), err)
+}
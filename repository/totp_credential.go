@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sawitpro/entity"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type totpCredentialRepository struct {
+	db *sqlx.DB
+}
+
+func NewTOTPCredentialRepository(db *sqlx.DB) totpCredentialRepository {
+	return totpCredentialRepository{
+		db: db,
+	}
+}
+
+// GetTOTPCredential returns the TOTP credential of the profile. Inside a
+// transaction the row stays locked until it ends, so the same code cannot be
+// accepted twice by concurrent logins.
+func (repo totpCredentialRepository) GetTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (entity.TOTPCredential, error) {
+	var res entity.TOTPCredential
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &res, queryGetTOTPCredential, profileId)
+	} else {
+		err = repo.db.GetContext(ctx, &res, queryGetTOTPCredential, profileId)
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entity.TOTPCredential{}, nil
+		}
+
+		return res, err
+	}
+
+	return res, nil
+}
+
+// SetPendingTOTPSecret starts an enrollment, replacing any enrollment that
+// was never confirmed. An active secret is left as it is.
+func (repo totpCredentialRepository) SetPendingTOTPSecret(ctx context.Context, tx *sqlx.Tx, profileId string, pendingSecret string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, querySetPendingTOTPSecret, profileId, pendingSecret)
+	} else {
+		_, err = repo.db.ExecContext(ctx, querySetPendingTOTPSecret, profileId, pendingSecret)
+	}
+
+	return err
+}
+
+// EnableTOTP makes the pending secret the active one.
+func (repo totpCredentialRepository) EnableTOTP(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64, enabledAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryEnableTOTP, profileId, lastUsedStep, enabledAt)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryEnableTOTP, profileId, lastUsedStep, enabledAt)
+	}
+
+	return err
+}
+
+func (repo totpCredentialRepository) UpdateTOTPLastUsedStep(ctx context.Context, tx *sqlx.Tx, profileId string, lastUsedStep int64) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryUpdateTOTPLastUsedStep, profileId, lastUsedStep)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryUpdateTOTPLastUsedStep, profileId, lastUsedStep)
+	}
+
+	return err
+}
+
+func (repo totpCredentialRepository) DeleteTOTPCredential(ctx context.Context, tx *sqlx.Tx, profileId string) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteTOTPCredential, profileId)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteTOTPCredential, profileId)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_totpCredentialRepository_GetTOTPCredential(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	enabledAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := "encrypted-secret"
+
+	columns := []string{"profile_id", "secret", "pending_secret", "last_used_step", "enabled_at"}
+
+	tests := []struct {
+		name    string
+		want    entity.TOTPCredential
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get totp credential",
+			want: entity.TOTPCredential{
+				ProfileId:    "profile-id-1",
+				Secret:       &secret,
+				LastUsedStep: 57000000,
+				EnabledAt:    &enabledAt,
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("profile-id-1", "encrypted-secret", nil, 57000000, enabledAt))
+			},
+		},
+		{
+			name:    "no totp credential",
+			want:    entity.TOTPCredential{},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:    "error get totp credential",
+			want:    entity.TOTPCredential{},
+			wantErr: errors.New("error select"),
+			mock: func() {
+				mock.ExpectQuery("SELECT (.+) FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewTOTPCredentialRepository(dbx)
+			got, err := repo.GetTOTPCredential(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_totpCredentialRepository_SetPendingTOTPSecret(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("INSERT INTO totp_credential").WithArgs("profile-id-1", "encrypted-secret").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewTOTPCredentialRepository(dbx)
+	err := repo.SetPendingTOTPSecret(context.TODO(), nil, "profile-id-1", "encrypted-secret")
+	assert.NoError(t, err)
+}
+
+func Test_totpCredentialRepository_EnableTOTP(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	enabledAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE totp_credential SET secret = pending_secret").WithArgs("profile-id-1", int64(57000000), enabledAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewTOTPCredentialRepository(dbx)
+	err := repo.EnableTOTP(context.TODO(), nil, "profile-id-1", 57000000, enabledAt)
+	assert.NoError(t, err)
+}
+
+func Test_totpCredentialRepository_UpdateTOTPLastUsedStep(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE totp_credential SET last_used_step").WithArgs("profile-id-1", int64(57000001)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewTOTPCredentialRepository(dbx)
+	err := repo.UpdateTOTPLastUsedStep(context.TODO(), nil, "profile-id-1", 57000001)
+	assert.NoError(t, err)
+}
+
+func Test_totpCredentialRepository_DeleteTOTPCredential(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete totp credential",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "no totp credential",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error delete totp credential",
+			want:    false,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM totp_credential").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewTOTPCredentialRepository(dbx)
+			got, err := repo.DeleteTOTPCredential(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"sawitpro/entity"
+	"time"
 
 	_ "github.com/jackc/pgx/stdlib"
 	"github.com/jmoiron/sqlx"
@@ -56,7 +57,7 @@ func (repo userProfileRepository) GetProfileById(ctx context.Context, tx *sqlx.T
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return res, nil
+			return entity.UserProfile{}, nil
 		}
 
 		return res, err
@@ -77,7 +78,7 @@ func (repo userProfileRepository) GetProfileByPhoneNumber(ctx context.Context, t
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return res, nil
+			return entity.UserProfile{}, nil
 		}
 
 		return res, err
@@ -94,7 +95,6 @@ func (repo userProfileRepository) UpdateProfileById(ctx context.Context, tx *sql
 			ctx,
 			queryUpdateProfileById,
 			updateData.FullName,
-			updateData.PhoneNumber,
 			id,
 		)
 	} else {
@@ -102,7 +102,6 @@ func (repo userProfileRepository) UpdateProfileById(ctx context.Context, tx *sql
 			ctx,
 			queryUpdateProfileById,
 			updateData.FullName,
-			updateData.PhoneNumber,
 			id,
 		)
 	}
@@ -130,6 +129,174 @@ func (repo userProfileRepository) IncreaseSuccessLoginCount(ctx context.Context,
 	return err
 }
 
+// IncreaseFailedLoginCount records a failed login of the profile and returns
+// the number of consecutive failures so far.
+func (repo userProfileRepository) IncreaseFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) (int, error) {
+	var count int
+	var err error
+
+	if tx != nil {
+		err = tx.QueryRowContext(ctx, queryIncreaseFailedLoginCount, profileId).Scan(&count)
+	} else {
+		err = repo.db.QueryRowContext(ctx, queryIncreaseFailedLoginCount, profileId).Scan(&count)
+	}
+
+	return count, err
+}
+
+func (repo userProfileRepository) LockProfile(ctx context.Context, tx *sqlx.Tx, profileId string, lockedUntil time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryLockProfile, lockedUntil, profileId)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryLockProfile, lockedUntil, profileId)
+	}
+
+	return err
+}
+
+// ResetFailedLoginCount forgets the failed logins of the profile and lifts any
+// lock they caused.
+func (repo userProfileRepository) ResetFailedLoginCount(ctx context.Context, tx *sqlx.Tx, profileId string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryResetFailedLoginCount, profileId)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryResetFailedLoginCount, profileId)
+	}
+
+	return err
+}
+
+func (repo userProfileRepository) UpdatePasswordById(ctx context.Context, tx *sqlx.Tx, id string, hashedPassword string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(
+			ctx,
+			queryUpdatePasswordById,
+			hashedPassword,
+			id,
+		)
+	} else {
+		_, err = repo.db.ExecContext(
+			ctx,
+			queryUpdatePasswordById,
+			hashedPassword,
+			id,
+		)
+	}
+
+	return err
+}
+
+// RehashPasswordById swaps a hash for a stronger one of the same password.
+// updated_at is kept, the profile itself did not change.
+func (repo userProfileRepository) RehashPasswordById(ctx context.Context, tx *sqlx.Tx, id string, oldHashedPassword string, newHashedPassword string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryRehashPasswordById, id, oldHashedPassword, newHashedPassword)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryRehashPasswordById, id, oldHashedPassword, newHashedPassword)
+	}
+
+	return err
+}
+
+// SetMustChangePassword flags the profile to replace its password on the next
+// login, or clears the flag. Setting a new password clears it as well.
+func (repo userProfileRepository) SetMustChangePassword(ctx context.Context, tx *sqlx.Tx, id string, mustChangePassword bool) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, querySetMustChangePassword, mustChangePassword, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, querySetMustChangePassword, mustChangePassword, id)
+	}
+
+	return err
+}
+
+func (repo userProfileRepository) MarkPhoneVerified(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryMarkPhoneVerified, verifiedAt, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryMarkPhoneVerified, verifiedAt, id)
+	}
+
+	return err
+}
+
+func (repo userProfileRepository) SetPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id string, phoneNumber string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, querySetPendingPhoneNumber, phoneNumber, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, querySetPendingPhoneNumber, phoneNumber, id)
+	}
+
+	return err
+}
+
+func (repo userProfileRepository) ConfirmPendingPhoneNumber(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) (bool, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryConfirmPendingPhoneNumber, verifiedAt, id)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryConfirmPendingPhoneNumber, verifiedAt, id)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo userProfileRepository) DeleteProfileById(ctx context.Context, tx *sqlx.Tx, id string) error {
+	var err error
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, queryDeleteProfileById, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, queryDeleteProfileById, id)
+	}
+
+	return err
+}
+
+// DeleteUnverifiedProfiles removes registrations that were never verified and
+// were created before createdBefore, freeing their phone numbers.
+func (repo userProfileRepository) DeleteUnverifiedProfiles(ctx context.Context, tx *sqlx.Tx, createdBefore time.Time) (int64, error) {
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, queryDeleteUnverifiedProfiles, createdBefore)
+	} else {
+		result, err = repo.db.ExecContext(ctx, queryDeleteUnverifiedProfiles, createdBefore)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 func (repo userProfileRepository) RunWithTransaction(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) error {
 	tx, err := repo.db.Beginx()
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"sawitpro/entity"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	_ "github.com/jackc/pgx/stdlib"
@@ -49,7 +50,7 @@ func Test_userProfileRepository_UpdateProfileById(t *testing.T) {
 			},
 			wantErr: nil,
 			mock: func() {
-				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "+62867", "id1").
+				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "id1").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 		},
@@ -73,7 +74,7 @@ func Test_userProfileRepository_UpdateProfileById(t *testing.T) {
 			},
 			wantErr: nil,
 			mock: func() {
-				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "+62867", "id1").
+				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "id1").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 		},
@@ -93,7 +94,7 @@ func Test_userProfileRepository_UpdateProfileById(t *testing.T) {
 			},
 			wantErr: errors.New("error update"),
 			mock: func() {
-				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "+62867", "id1").WillReturnError(errors.New("error update"))
+				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "id1").WillReturnError(errors.New("error update"))
 			},
 		},
 		{
@@ -116,7 +117,7 @@ func Test_userProfileRepository_UpdateProfileById(t *testing.T) {
 			},
 			wantErr: errors.New("error update"),
 			mock: func() {
-				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "+62867", "id1").WillReturnError(errors.New("error update"))
+				mock.ExpectExec("UPDATE user_profile").WithArgs("jonathan", "id1").WillReturnError(errors.New("error update"))
 			},
 		},
 	}
@@ -486,6 +487,8 @@ func Test_userProfileRepository_GetProfileById(t *testing.T) {
 func Test_userProfileRepository_GetProfileByPhoneNumber(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	dbx := sqlx.NewDb(db, "pgx")
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	verifiedAt := time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC)
 
 	type fields struct {
 		db *sqlx.DB
@@ -514,10 +517,12 @@ func Test_userProfileRepository_GetProfileByPhoneNumber(t *testing.T) {
 				phoneNumber: "+621234",
 			},
 			want: entity.UserProfile{
-				Id:          "profile-id-1",
-				FullName:    "phala",
-				PhoneNumber: "+621234",
-				Password:    "qwerty",
+				Id:              "profile-id-1",
+				FullName:        "phala",
+				PhoneNumber:     "+621234",
+				Password:        "qwerty",
+				CreatedAt:       createdAt,
+				PhoneVerifiedAt: &verifiedAt,
 			},
 			wantErr: nil,
 			mock: func() {
@@ -529,11 +534,15 @@ func Test_userProfileRepository_GetProfileByPhoneNumber(t *testing.T) {
 						"full_name",
 						"phone_number",
 						"password",
+						"created_at",
+						"phone_verified_at",
 					}).AddRow(
 						"profile-id-1",
 						"phala",
 						"+621234",
 						"qwerty",
+						createdAt,
+						verifiedAt,
 					),
 				)
 			},
@@ -781,6 +790,303 @@ func Test_userProfileRepository_IncreaseSuccessLoginCount(t *testing.T) {
 	}
 }
 
+func Test_userProfileRepository_IncreaseFailedLoginCount(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	tests := []struct {
+		name    string
+		want    int
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success increase failed login count",
+			want:    4,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectQuery("UPDATE user_profile SET failed_login_count").WithArgs("profile-id-1").
+					WillReturnRows(sqlmock.NewRows([]string{"failed_login_count"}).AddRow(4))
+			},
+		},
+		{
+			name:    "error increase failed login count",
+			want:    0,
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectQuery("UPDATE user_profile SET failed_login_count").WithArgs("profile-id-1").
+					WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewUserProfileRepository(dbx)
+			got, err := repo.IncreaseFailedLoginCount(context.TODO(), nil, "profile-id-1")
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_userProfileRepository_LockProfile(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	lockedUntil := time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE user_profile SET locked_until").WithArgs(lockedUntil, "profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.LockProfile(context.TODO(), nil, "profile-id-1", lockedUntil)
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_ResetFailedLoginCount(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE user_profile SET failed_login_count = 0, locked_until = NULL").WithArgs("profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.ResetFailedLoginCount(context.TODO(), nil, "profile-id-1")
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_UpdatePasswordById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	type fields struct {
+		db *sqlx.DB
+	}
+	type args struct {
+		ctx            context.Context
+		tx             *sqlx.Tx
+		id             string
+		hashedPassword string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success update",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx:            context.TODO(),
+				tx:             nil,
+				id:             "profile-id-1",
+				hashedPassword: "hashed-password-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE user_profile SET password").WithArgs(
+					"hashed-password-1",
+					"profile-id-1",
+				).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "got update error with transaction",
+			fields: fields{
+				db: dbx,
+			},
+			args: args{
+				ctx: context.TODO(),
+				tx: func() *sqlx.Tx {
+					mock.ExpectBegin()
+					tx, _ := dbx.Beginx()
+					return tx
+				}(),
+				id:             "profile-id-1",
+				hashedPassword: "hashed-password-1",
+			},
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE user_profile SET password").WithArgs(
+					"hashed-password-1",
+					"profile-id-1",
+				).WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt.mock()
+
+		t.Run(tt.name, func(t *testing.T) {
+			repo := userProfileRepository{
+				db: tt.fields.db,
+			}
+			err := repo.UpdatePasswordById(tt.args.ctx, tt.args.tx, tt.args.id, tt.args.hashedPassword)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_userProfileRepository_RehashPasswordById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE user_profile SET password").WithArgs("profile-id-1", "old-hash", "new-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.RehashPasswordById(context.TODO(), nil, "profile-id-1", "old-hash", "new-hash")
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_SetMustChangePassword(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE user_profile SET must_change_password").WithArgs(true, "profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.SetMustChangePassword(context.TODO(), nil, "profile-id-1", true)
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_MarkPhoneVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE user_profile SET phone_verified_at").WithArgs(verifiedAt, "profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.MarkPhoneVerified(context.TODO(), nil, "profile-id-1", verifiedAt)
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_SetPendingPhoneNumber(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("UPDATE user_profile SET pending_phone_number").WithArgs("+6281234567891", "profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.SetPendingPhoneNumber(context.TODO(), nil, "profile-id-1", "+6281234567891")
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_ConfirmPendingPhoneNumber(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    bool
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success confirm pending phone number",
+			want:    true,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE user_profile SET phone_number = pending_phone_number").WithArgs(verifiedAt, "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "no pending phone number",
+			want:    false,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("UPDATE user_profile SET phone_number = pending_phone_number").WithArgs(verifiedAt, "profile-id-1").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "error confirm pending phone number",
+			want:    false,
+			wantErr: errors.New("error update"),
+			mock: func() {
+				mock.ExpectExec("UPDATE user_profile SET phone_number = pending_phone_number").WithArgs(verifiedAt, "profile-id-1").
+					WillReturnError(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewUserProfileRepository(dbx)
+			got, err := repo.ConfirmPendingPhoneNumber(context.TODO(), nil, "profile-id-1", verifiedAt)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Nil(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func Test_userProfileRepository_DeleteProfileById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+
+	mock.ExpectExec("DELETE FROM user_profile WHERE id").WithArgs("profile-id-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewUserProfileRepository(dbx)
+	err := repo.DeleteProfileById(context.TODO(), nil, "profile-id-1")
+	assert.NoError(t, err)
+}
+
+func Test_userProfileRepository_DeleteUnverifiedProfiles(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	dbx := sqlx.NewDb(db, "pgx")
+	createdBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success delete unverified profiles",
+			want:    2,
+			wantErr: nil,
+			mock: func() {
+				mock.ExpectExec("DELETE FROM user_profile WHERE phone_verified_at IS NULL").WithArgs(createdBefore).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name:    "error delete unverified profiles",
+			want:    0,
+			wantErr: errors.New("error delete"),
+			mock: func() {
+				mock.ExpectExec("DELETE FROM user_profile WHERE phone_verified_at IS NULL").WithArgs(createdBefore).
+					WillReturnError(errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			repo := NewUserProfileRepository(dbx)
+			got, err := repo.DeleteUnverifiedProfiles(context.TODO(), nil, createdBefore)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
 func Test_userProfileRepository_RunWithTransaction(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	dbx := sqlx.NewDb(db, "pgx")
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/helper"
+	"sawitpro/repository"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type authService struct {
+	profileRepository             repository.UserProfileRepositoryInterface
+	refreshTokenRepository        repository.RefreshTokenRepositoryInterface
+	revokedTokenRepository        repository.RevokedTokenRepositoryInterface
+	sessionRepository             repository.SessionRepositoryInterface
+	personalAccessTokenRepository repository.PersonalAccessTokenRepositoryInterface
+	oauthGrantRepository          repository.OAuthGrantRepositoryInterface
+	oauthClientRepository         repository.OAuthClientRepositoryInterface
+	roleRepository                repository.RoleRepositoryInterface
+	authhelper                    helper.AuthHelperInterface
+}
+
+type AuthServiceDeps struct {
+	ProfileRepository             repository.UserProfileRepositoryInterface
+	RefreshTokenRepository        repository.RefreshTokenRepositoryInterface
+	RevokedTokenRepository        repository.RevokedTokenRepositoryInterface
+	SessionRepository             repository.SessionRepositoryInterface
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepositoryInterface
+	OAuthGrantRepository          repository.OAuthGrantRepositoryInterface
+	OAuthClientRepository         repository.OAuthClientRepositoryInterface
+	RoleRepository                repository.RoleRepositoryInterface
+	Authhelper                    helper.AuthHelperInterface
+}
+
+func NewAuthService(deps AuthServiceDeps) authService {
+	return authService{
+		profileRepository:             deps.ProfileRepository,
+		refreshTokenRepository:        deps.RefreshTokenRepository,
+		revokedTokenRepository:        deps.RevokedTokenRepository,
+		sessionRepository:             deps.SessionRepository,
+		personalAccessTokenRepository: deps.PersonalAccessTokenRepository,
+		oauthGrantRepository:          deps.OAuthGrantRepository,
+		oauthClientRepository:         deps.OAuthClientRepository,
+		roleRepository:                deps.RoleRepository,
+		authhelper:                    deps.Authhelper,
+	}
+}
+
+func (a authService) IssueToken(ctx context.Context, request entity.IssueTokenRequest) (entity.IssueTokenResponse, error) {
+	var res = entity.IssueTokenResponse{}
+
+	roles, err := a.roleRepository.GetRolesByProfileId(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrIssueToken
+	}
+
+	var sessionId, refreshToken string
+
+	err = a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		now := time.Now().UTC()
+
+		var err error
+		sessionId, err = a.sessionRepository.InsertSession(ctx, tx, entity.Session{
+			ProfileId:  request.ProfileId,
+			DeviceName: request.DeviceName,
+			UserAgent:  request.UserAgent,
+			IpAddress:  request.IpAddress,
+			CreatedAt:  now,
+			LastSeenAt: now,
+		})
+		if err != nil {
+			return error_list.ErrIssueToken
+		}
+
+		// a login held until the password is replaced gets no refresh token,
+		// its access token is all the restricted session ever has
+		if request.PasswordChangeRequired {
+			return nil
+		}
+
+		// every login starts a new family named after its session, rotations
+		// keep the family of the token they replace
+		refreshToken, err = a.createRefreshToken(ctx, tx, request.ProfileId, sessionId)
+		if err != nil {
+			return error_list.ErrIssueToken
+		}
+
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	roleNames, scopes := roleClaims(roles)
+
+	token, err := a.authhelper.GenerateToken(ctx, entity.GenerateTokenRequest{
+		ProfileId:              request.ProfileId,
+		SessionId:              sessionId,
+		PasswordChangeRequired: request.PasswordChangeRequired,
+		Scopes:                 scopes,
+		Roles:                  roleNames,
+	})
+	if err != nil {
+		return res, error_list.ErrIssueToken
+	}
+
+	res = entity.IssueTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(constant.AccessTokenDuration.Seconds()),
+	}
+
+	return res, nil
+}
+
+func (a authService) RefreshToken(ctx context.Context, request entity.RefreshTokenRequest) (entity.RefreshTokenResponse, error) {
+	var res = entity.RefreshTokenResponse{}
+
+	tokenHash := a.authhelper.HashToken(ctx, request.RefreshToken)
+
+	storedToken, err := a.refreshTokenRepository.GetRefreshTokenByHash(ctx, nil, tokenHash)
+	if err != nil {
+		return res, error_list.ErrRefreshToken
+	}
+
+	if storedToken.Id == "" || storedToken.RevokedAt != nil {
+		return res, error_list.ErrInvalidRefreshToken
+	}
+
+	if storedToken.UsedAt != nil {
+		return res, a.revokeReusedFamily(ctx, storedToken)
+	}
+
+	if time.Now().After(storedToken.ExpiresAt) {
+		return res, error_list.ErrInvalidRefreshToken
+	}
+
+	session, err := a.sessionRepository.GetSessionById(ctx, nil, storedToken.FamilyId)
+	if err != nil {
+		return res, error_list.ErrRefreshToken
+	}
+
+	if session.Id == "" {
+		return res, error_list.ErrInvalidRefreshToken
+	}
+
+	// roles are read again on every refresh, so a change reaches the
+	// session within the lifetime of an access token
+	roles, err := a.roleRepository.GetRolesByProfileId(ctx, nil, storedToken.ProfileId)
+	if err != nil {
+		return res, error_list.ErrRefreshToken
+	}
+
+	var refreshToken string
+
+	err = a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		marked, err := a.refreshTokenRepository.MarkRefreshTokenUsed(ctx, tx, storedToken.Id)
+		if err != nil {
+			return error_list.ErrRefreshToken
+		}
+
+		// another request rotated this token between our read and the update
+		if !marked {
+			return error_list.ErrRefreshTokenReused
+		}
+
+		refreshToken, err = a.createRefreshToken(ctx, tx, storedToken.ProfileId, storedToken.FamilyId)
+		if err != nil {
+			return error_list.ErrRefreshToken
+		}
+
+		err = a.sessionRepository.TouchSession(ctx, tx, session.Id, time.Now().UTC())
+		if err != nil {
+			return error_list.ErrRefreshToken
+		}
+
+		return nil
+	})
+	if err != nil {
+		if err == error_list.ErrRefreshTokenReused {
+			return res, a.revokeReusedFamily(ctx, storedToken)
+		}
+
+		return res, err
+	}
+
+	roleNames, scopes := roleClaims(roles)
+
+	token, err := a.authhelper.GenerateToken(ctx, entity.GenerateTokenRequest{
+		ProfileId: storedToken.ProfileId,
+		SessionId: session.Id,
+		Scopes:    scopes,
+		Roles:     roleNames,
+	})
+	if err != nil {
+		return res, error_list.ErrRefreshToken
+	}
+
+	res = entity.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(constant.AccessTokenDuration.Seconds()),
+	}
+
+	return res, nil
+}
+
+func (a authService) Authenticate(ctx context.Context, request entity.AuthenticateRequest) (entity.TokenClaims, error) {
+	if strings.HasPrefix(request.Token, constant.PersonalAccessTokenPrefix) {
+		return a.authenticatePersonalAccessToken(ctx, request.Token)
+	}
+
+	claims, err := a.authhelper.VerifyToken(ctx, request.Token)
+	if err != nil {
+		return entity.TokenClaims{}, err
+	}
+
+	revoked, err := a.revokedTokenRepository.IsTokenRevoked(ctx, claims.TokenId)
+	if err != nil {
+		return entity.TokenClaims{}, error_list.ErrAuthenticate
+	}
+
+	if revoked {
+		return entity.TokenClaims{}, error_list.ErrTokenRevoked
+	}
+
+	// a token a client was issued for itself lives as long as the client
+	// keeps the client credentials grant
+	if claims.ClientId != "" && claims.ProfileId == "" {
+		client, err := a.oauthClientRepository.GetOAuthClientById(ctx, nil, claims.ClientId)
+		if err != nil {
+			return entity.TokenClaims{}, error_list.ErrAuthenticate
+		}
+
+		if client.Id == "" || !containsString(strings.Fields(client.GrantTypes), constant.OAuthGrantTypeClientCredentials) {
+			return entity.TokenClaims{}, error_list.ErrOAuthClientRevoked
+		}
+
+		return claims, nil
+	}
+
+	// a token issued to an oauth client lives as long as the grant behind it
+	if claims.ClientId != "" {
+		grant, err := a.oauthGrantRepository.GetOAuthGrantById(ctx, nil, claims.SessionId)
+		if err != nil {
+			return entity.TokenClaims{}, error_list.ErrAuthenticate
+		}
+
+		if grant.Id == "" || grant.ProfileId != claims.ProfileId || grant.ClientId != claims.ClientId {
+			return entity.TokenClaims{}, error_list.ErrOAuthGrantRevoked
+		}
+
+		return claims, nil
+	}
+
+	session, err := a.sessionRepository.GetSessionById(ctx, nil, claims.SessionId)
+	if err != nil {
+		return entity.TokenClaims{}, error_list.ErrAuthenticate
+	}
+
+	if session.Id == "" || session.ProfileId != claims.ProfileId {
+		return entity.TokenClaims{}, error_list.ErrSessionEnded
+	}
+
+	now := time.Now().UTC()
+	if now.Sub(session.LastSeenAt) > constant.SessionLastSeenResolution {
+		// last seen is informational, failing to record it must not fail the request
+		_ = a.sessionRepository.TouchSession(ctx, nil, session.Id, now)
+	}
+
+	return claims, nil
+}
+
+func (a authService) Logout(ctx context.Context, request entity.LogoutRequest) error {
+	// the revocation only has to outlive the token itself
+	err := a.revokedTokenRepository.RevokeToken(ctx, request.TokenId, request.ExpiresAt)
+	if err != nil {
+		return error_list.ErrLogout
+	}
+
+	err = a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := a.endSession(ctx, tx, request.ProfileId, request.SessionId)
+		return err
+	})
+	if err != nil {
+		return error_list.ErrLogout
+	}
+
+	if request.RefreshToken == "" {
+		return nil
+	}
+
+	tokenHash := a.authhelper.HashToken(ctx, request.RefreshToken)
+
+	storedToken, err := a.refreshTokenRepository.GetRefreshTokenByHash(ctx, nil, tokenHash)
+	if err != nil {
+		return error_list.ErrLogout
+	}
+
+	// never let a caller revoke somebody else's refresh token
+	if storedToken.Id == "" || storedToken.ProfileId != request.ProfileId || storedToken.FamilyId == request.SessionId {
+		return nil
+	}
+
+	err = a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := a.endSession(ctx, tx, request.ProfileId, storedToken.FamilyId)
+		return err
+	})
+	if err != nil {
+		return error_list.ErrLogout
+	}
+
+	return nil
+}
+
+func (a authService) PruneRevokedTokens(ctx context.Context) error {
+	_, err := a.revokedTokenRepository.PruneExpiredTokens(ctx, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrPruneRevokedTokens
+	}
+
+	return nil
+}
+
+func (a authService) createRefreshToken(ctx context.Context, tx *sqlx.Tx, profileId string, familyId string) (string, error) {
+	refreshToken, err := a.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = a.refreshTokenRepository.InsertRefreshToken(ctx, tx, entity.RefreshToken{
+		ProfileId: profileId,
+		FamilyId:  familyId,
+		TokenHash: a.authhelper.HashToken(ctx, refreshToken),
+		ExpiresAt: time.Now().Add(constant.RefreshTokenDuration).UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// revokeReusedFamily is called when an already rotated refresh token is
+// presented again. Either the legitimate client or an attacker holds a copy,
+// and we cannot tell which, so the whole session is ended.
+func (a authService) revokeReusedFamily(ctx context.Context, storedToken entity.RefreshToken) error {
+	err := a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := a.endSession(ctx, tx, storedToken.ProfileId, storedToken.FamilyId)
+		return err
+	})
+	if err != nil {
+		return error_list.ErrRefreshToken
+	}
+
+	return error_list.ErrRefreshTokenReused
+}
+
+// endSession deletes the session, which rejects its access tokens from then
+// on, and revokes its refresh tokens. It reports false when the profile has
+// no such session.
+func (a authService) endSession(ctx context.Context, tx *sqlx.Tx, profileId string, sessionId string) (bool, error) {
+	deleted, err := a.sessionRepository.DeleteSession(ctx, tx, profileId, sessionId)
+	if err != nil {
+		return false, err
+	}
+
+	err = a.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, sessionId)
+	if err != nil {
+		return false, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,1115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/helper"
+	"sawitpro/mocks"
+	"sawitpro/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	type args struct {
+		deps AuthServiceDeps
+	}
+	tests := []struct {
+		name string
+		args args
+		want authService
+	}{
+		{
+			name: "return auth service instance",
+			args: args{
+				deps: AuthServiceDeps{
+					ProfileRepository:      mockProfileRepository,
+					RefreshTokenRepository: mockRefreshTokenRepository,
+					RevokedTokenRepository: mockRevokedTokenRepository,
+					SessionRepository:      mockSessionRepository,
+					Authhelper:             mockHelper,
+				},
+			},
+			want: authService{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAuthService(tt.args.deps)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_authService_IssueToken(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	type fields struct {
+		profileRepository      repository.UserProfileRepositoryInterface
+		refreshTokenRepository repository.RefreshTokenRepositoryInterface
+		sessionRepository      repository.SessionRepositoryInterface
+		roleRepository         repository.RoleRepositoryInterface
+		authhelper             helper.AuthHelperInterface
+	}
+	type args struct {
+		ctx     context.Context
+		request entity.IssueTokenRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    entity.IssueTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success issue token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId:  "profile-id-1",
+					DeviceName: "Pixel 8",
+					UserAgent:  "okhttp/4.12.0",
+					IpAddress:  "192.0.2.1",
+				},
+			},
+			want: entity.IssueTokenResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return([]entity.Role{
+					{
+						Id:          "role-id-1",
+						Name:        "admin",
+						Permissions: "roles:read roles:write",
+					},
+				}, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().InsertSession(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, session entity.Session) (string, error) {
+						assert.Equal(t, "profile-id-1", session.ProfileId)
+						assert.Equal(t, "Pixel 8", session.DeviceName)
+						assert.Equal(t, "okhttp/4.12.0", session.UserAgent)
+						assert.Equal(t, "192.0.2.1", session.IpAddress)
+						return "session-id-1", nil
+					},
+				)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error) {
+						assert.Equal(t, "profile-id-1", token.ProfileId)
+						assert.Equal(t, "hashed-refresh-token-1", token.TokenHash)
+						assert.Equal(t, "session-id-1", token.FamilyId)
+						return "refresh-id-1", nil
+					},
+				)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					Scopes:    []string{"openid", "profile:read", "profile:write", "roles:read", "roles:write"},
+					Roles:     []string{"admin"},
+				}).Return("token-1", nil)
+			},
+		},
+		{
+			name: "success issue restricted token without refresh token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId:              "profile-id-1",
+					PasswordChangeRequired: true,
+				},
+			},
+			want: entity.IssueTokenResponse{
+				Token:     "token-1",
+				ExpiresIn: 900,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().InsertSession(gomock.Any(), mockTx, gomock.Any()).Return("session-id-1", nil)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ProfileId:              "profile-id-1",
+					SessionId:              "session-id-1",
+					PasswordChangeRequired: true,
+					Scopes:                 []string{"openid", "profile:read", "profile:write"},
+				}).Return("token-1", nil)
+			},
+		},
+		{
+			name: "error when generate token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				},
+			},
+			want:    entity.IssueTokenResponse{},
+			wantErr: errors.New("error when issuing token"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().InsertSession(gomock.Any(), mockTx, gomock.Any()).Return("session-id-1", nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).Return("refresh-id-1", nil)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Return("", errors.New("error token"))
+			},
+		},
+		{
+			name: "error when insert session",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				},
+			},
+			want:    entity.IssueTokenResponse{},
+			wantErr: errors.New("error when issuing token"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().InsertSession(gomock.Any(), mockTx, gomock.Any()).Return("", errors.New("error insert"))
+			},
+		},
+		{
+			name: "error when insert refresh token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				},
+			},
+			want:    entity.IssueTokenResponse{},
+			wantErr: errors.New("error when issuing token"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().InsertSession(gomock.Any(), mockTx, gomock.Any()).Return("session-id-1", nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).Return("", errors.New("error insert"))
+			},
+		},
+		{
+			name: "error when get roles",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				},
+			},
+			want:    entity.IssueTokenResponse{},
+			wantErr: errors.New("error when issuing token"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      tt.fields.profileRepository,
+				refreshTokenRepository: tt.fields.refreshTokenRepository,
+				sessionRepository:      tt.fields.sessionRepository,
+				roleRepository:         tt.fields.roleRepository,
+				authhelper:             tt.fields.authhelper,
+			}
+			got, err := a.IssueToken(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_RefreshToken(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	usedAt := time.Now().Add(-time.Minute)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	type fields struct {
+		profileRepository      repository.UserProfileRepositoryInterface
+		refreshTokenRepository repository.RefreshTokenRepositoryInterface
+		sessionRepository      repository.SessionRepositoryInterface
+		roleRepository         repository.RoleRepositoryInterface
+		authhelper             helper.AuthHelperInterface
+	}
+	type args struct {
+		ctx     context.Context
+		request entity.RefreshTokenRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    entity.RefreshTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success rotate refresh token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want: entity.RefreshTokenResponse{
+				Token:        "token-2",
+				RefreshToken: "refresh-token-2",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(
+					entity.Session{
+						Id:        "session-id-1",
+						ProfileId: "profile-id-1",
+					}, nil,
+				)
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockRefreshTokenRepository.EXPECT().MarkRefreshTokenUsed(gomock.Any(), mockTx, "refresh-id-1").Return(true, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-2", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-2").Return("hashed-refresh-token-2")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error) {
+						assert.Equal(t, "session-id-1", token.FamilyId)
+						assert.Equal(t, "hashed-refresh-token-2", token.TokenHash)
+						return "refresh-id-2", nil
+					},
+				)
+				mockSessionRepository.EXPECT().TouchSession(gomock.Any(), mockTx, "session-id-1", gomock.Any()).Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					Scopes:    []string{"openid", "profile:read", "profile:write"},
+				}).Return("token-2", nil)
+			},
+		},
+		{
+			name: "error refresh token not found",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error invalid refresh token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{}, nil,
+				)
+			},
+		},
+		{
+			name: "error refresh token expired",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error invalid refresh token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(-time.Hour),
+					}, nil,
+				)
+			},
+		},
+		{
+			name: "error session has ended",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error invalid refresh token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(entity.Session{}, nil)
+			},
+		},
+		{
+			name: "reused refresh token ends the session",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error refresh token reuse detected"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(time.Hour),
+						UsedAt:    &usedAt,
+					}, nil,
+				)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+			},
+		},
+		{
+			name: "concurrent rotation ends the session",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error refresh token reuse detected"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(
+					entity.Session{
+						Id:        "session-id-1",
+						ProfileId: "profile-id-1",
+					}, nil,
+				)
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, nil)
+				mockRefreshTokenRepository.EXPECT().MarkRefreshTokenUsed(gomock.Any(), mockTx, "refresh-id-1").Return(false, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				).Times(2)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+			},
+		},
+		{
+			name: "error when get roles",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error when refreshing token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-1",
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(
+					entity.Session{
+						Id:        "session-id-1",
+						ProfileId: "profile-id-1",
+					}, nil,
+				)
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, errors.New("error select"))
+			},
+		},
+		{
+			name: "error when get refresh token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				roleRepository:         mockRoleRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.RefreshTokenRequest{
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			want:    entity.RefreshTokenResponse{},
+			wantErr: errors.New("error when refreshing token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{}, errors.New("error get"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      tt.fields.profileRepository,
+				refreshTokenRepository: tt.fields.refreshTokenRepository,
+				sessionRepository:      tt.fields.sessionRepository,
+				roleRepository:         tt.fields.roleRepository,
+				authhelper:             tt.fields.authhelper,
+			}
+			got, err := a.RefreshToken(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+		TokenId:   "token-id-1",
+		ExpiresAt: time.Now().Add(time.Minute),
+		Scopes:    []string{"openid", "profile:read", "profile:write"},
+	}
+
+	type fields struct {
+		revokedTokenRepository repository.RevokedTokenRepositoryInterface
+		sessionRepository      repository.SessionRepositoryInterface
+		authhelper             helper.AuthHelperInterface
+	}
+	type args struct {
+		ctx     context.Context
+		request entity.AuthenticateRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    entity.TokenClaims
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success authenticate",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(
+					entity.Session{
+						Id:         "session-id-1",
+						ProfileId:  "profile-id-1",
+						LastSeenAt: time.Now().UTC(),
+					}, nil,
+				)
+			},
+		},
+		{
+			name: "success authenticate records last seen",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(
+					entity.Session{
+						Id:         "session-id-1",
+						ProfileId:  "profile-id-1",
+						LastSeenAt: time.Now().Add(-time.Hour).UTC(),
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().TouchSession(gomock.Any(), nil, "session-id-1", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error session has ended",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error session has ended"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(entity.Session{}, nil)
+			},
+		},
+		{
+			name: "error revoked token",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error token has been revoked"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(true, nil)
+			},
+		},
+		{
+			name: "error invalid token",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error invalid token"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(entity.TokenClaims{}, errors.New("error invalid token"))
+			},
+		},
+		{
+			name: "error when check revocation",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error when authenticating token"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, errors.New("error select"))
+			},
+		},
+		{
+			name: "error when get session",
+			fields: fields{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.AuthenticateRequest{
+					Token: "token-1",
+				},
+			},
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error when authenticating token"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockSessionRepository.EXPECT().GetSessionById(gomock.Any(), nil, "session-id-1").Return(entity.Session{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				revokedTokenRepository: tt.fields.revokedTokenRepository,
+				sessionRepository:      tt.fields.sessionRepository,
+				authhelper:             tt.fields.authhelper,
+			}
+			got, err := a.Authenticate(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_Logout(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	type fields struct {
+		profileRepository      repository.UserProfileRepositoryInterface
+		refreshTokenRepository repository.RefreshTokenRepositoryInterface
+		revokedTokenRepository repository.RevokedTokenRepositoryInterface
+		sessionRepository      repository.SessionRepositoryInterface
+		authhelper             helper.AuthHelperInterface
+	}
+	type args struct {
+		ctx     context.Context
+		request entity.LogoutRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success logout without refresh token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LogoutRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().RevokeToken(gomock.Any(), "token-id-1", expiresAt).Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+			},
+		},
+		{
+			name: "success logout with refresh token of another session",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LogoutRequest{
+					ProfileId:    "profile-id-1",
+					SessionId:    "session-id-1",
+					TokenId:      "token-id-1",
+					ExpiresAt:    expiresAt,
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().RevokeToken(gomock.Any(), "token-id-1", expiresAt).Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				).Times(2)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-1",
+						FamilyId:  "session-id-2",
+					}, nil,
+				)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-2").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+			},
+		},
+		{
+			name: "refresh token of another profile is ignored",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LogoutRequest{
+					ProfileId:    "profile-id-1",
+					SessionId:    "session-id-1",
+					TokenId:      "token-id-1",
+					ExpiresAt:    expiresAt,
+					RefreshToken: "refresh-token-1",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().RevokeToken(gomock.Any(), "token-id-1", expiresAt).Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("hashed-refresh-token-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "hashed-refresh-token-1").Return(
+					entity.RefreshToken{
+						Id:        "refresh-id-1",
+						ProfileId: "profile-id-2",
+						FamilyId:  "session-id-2",
+					}, nil,
+				)
+			},
+		},
+		{
+			name: "error when revoke token",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LogoutRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: errors.New("error when logging out"),
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().RevokeToken(gomock.Any(), "token-id-1", expiresAt).Return(errors.New("error insert"))
+			},
+		},
+		{
+			name: "error when end session",
+			fields: fields{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				revokedTokenRepository: mockRevokedTokenRepository,
+				sessionRepository:      mockSessionRepository,
+				authhelper:             mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LogoutRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "session-id-1",
+					TokenId:   "token-id-1",
+					ExpiresAt: expiresAt,
+				},
+			},
+			wantErr: errors.New("error when logging out"),
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().RevokeToken(gomock.Any(), "token-id-1", expiresAt).Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(false, errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      tt.fields.profileRepository,
+				refreshTokenRepository: tt.fields.refreshTokenRepository,
+				revokedTokenRepository: tt.fields.revokedTokenRepository,
+				sessionRepository:      tt.fields.sessionRepository,
+				authhelper:             tt.fields.authhelper,
+			}
+			err := a.Logout(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_PruneRevokedTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().PruneExpiredTokens(gomock.Any(), gomock.Any()).Return(int64(2), nil)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning revoked tokens"),
+			mock: func() {
+				mockRevokedTokenRepository.EXPECT().PruneExpiredTokens(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				revokedTokenRepository: mockRevokedTokenRepository,
+			}
+			err := a.PruneRevokedTokens(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// LoginLockoutPolicy slows down password guessing against a single account.
+// Once DelayAfter consecutive logins have failed, every further failure locks
+// the account for BaseDelay, doubled for each failure since but never longer
+// than LockoutDuration when one is set. From LockoutAfter failures on, every
+// failure locks it for LockoutDuration. A threshold of zero turns its stage
+// off.
+type LoginLockoutPolicy struct {
+	DelayAfter      int
+	BaseDelay       time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+}
+
+// lockedUntil returns until when an account with failedLoginCount consecutive
+// failures stays locked, or the zero time when it is not locked.
+func (policy LoginLockoutPolicy) lockedUntil(failedLoginCount int, now time.Time) time.Time {
+	if policy.LockoutAfter > 0 && failedLoginCount >= policy.LockoutAfter {
+		return now.Add(policy.LockoutDuration)
+	}
+
+	if policy.DelayAfter <= 0 || failedLoginCount <= policy.DelayAfter {
+		return time.Time{}
+	}
+
+	delay := policy.BaseDelay
+	for i := policy.DelayAfter + 1; i < failedLoginCount; i++ {
+		// without a lockout duration to stop at, stop short of overflowing
+		if delay > math.MaxInt64/2 {
+			break
+		}
+
+		delay *= 2
+		if policy.LockoutDuration > 0 && delay >= policy.LockoutDuration {
+			delay = policy.LockoutDuration
+			break
+		}
+	}
+
+	return now.Add(delay)
+}
+
+func (p profileService) recordFailedLogin(ctx context.Context, profileId string) error {
+	return p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		failedLoginCount, err := p.profileRepository.IncreaseFailedLoginCount(ctx, tx, profileId)
+		if err != nil {
+			return err
+		}
+
+		lockedUntil := p.loginLockoutPolicy.lockedUntil(failedLoginCount, time.Now())
+		if lockedUntil.IsZero() {
+			return nil
+		}
+
+		return p.profileRepository.LockProfile(ctx, tx, profileId, lockedUntil.UTC())
+	})
+}
+
+// verifyCurrentPassword checks the password a signed in profile confirms a
+// sensitive change with. A wrong one counts as a failed login, so a stolen
+// token cannot be used to guess the password without limit.
+func (p profileService) verifyCurrentPassword(ctx context.Context, profile entity.UserProfile, password string) error {
+	if profile.LockedUntil != nil && time.Now().Before(*profile.LockedUntil) {
+		return error_list.ErrAccountLocked
+	}
+
+	err := p.authhelper.VerifyPassword(ctx, password, profile.Password)
+	if err != error_list.ErrPasswordNotMatch {
+		return err
+	}
+
+	err = p.recordFailedLogin(ctx, profile.Id)
+	if err != nil {
+		return err
+	}
+
+	return error_list.ErrCurrentPasswordNotMatch
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLockoutPolicy_lockedUntil(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := LoginLockoutPolicy{
+		DelayAfter:      3,
+		BaseDelay:       time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+	}
+
+	tests := []struct {
+		name             string
+		policy           LoginLockoutPolicy
+		failedLoginCount int
+		want             time.Time
+	}{
+		{
+			name:             "not locked below the delay threshold",
+			policy:           policy,
+			failedLoginCount: 3,
+			want:             time.Time{},
+		},
+		{
+			name:             "first delay",
+			policy:           policy,
+			failedLoginCount: 4,
+			want:             now.Add(time.Second),
+		},
+		{
+			name:             "delay doubles with each failure",
+			policy:           policy,
+			failedLoginCount: 7,
+			want:             now.Add(8 * time.Second),
+		},
+		{
+			name:             "locked out at the lockout threshold",
+			policy:           policy,
+			failedLoginCount: 10,
+			want:             now.Add(15 * time.Minute),
+		},
+		{
+			name: "delay never exceeds the lockout duration",
+			policy: LoginLockoutPolicy{
+				DelayAfter:      3,
+				BaseDelay:       time.Second,
+				LockoutDuration: time.Minute,
+			},
+			failedLoginCount: 100,
+			want:             now.Add(time.Minute),
+		},
+		{
+			name: "delay keeps doubling without a lockout duration",
+			policy: LoginLockoutPolicy{
+				DelayAfter: 3,
+				BaseDelay:  time.Second,
+			},
+			failedLoginCount: 14,
+			want:             now.Add(1024 * time.Second),
+		},
+		{
+			name: "delay stops doubling short of overflowing",
+			policy: LoginLockoutPolicy{
+				DelayAfter: 3,
+				BaseDelay:  time.Second,
+			},
+			failedLoginCount: 1000,
+			want:             now.Add(time.Second << 33),
+		},
+		{
+			name: "delays turned off",
+			policy: LoginLockoutPolicy{
+				LockoutAfter:    10,
+				LockoutDuration: 15 * time.Minute,
+			},
+			failedLoginCount: 9,
+			want:             time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.lockedUntil(tt.failedLoginCount, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/url"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/helper"
+	"sawitpro/repository"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// clientCredentialsScopes are only given to clients acting on their own
+var clientCredentialsScopes = []string{constant.ScopeProfilesRead, constant.ScopeTokensIntrospect}
+
+type oauthService struct {
+	profileRepository                repository.UserProfileRepositoryInterface
+	oauthClientRepository            repository.OAuthClientRepositoryInterface
+	oauthAuthorizationCodeRepository repository.OAuthAuthorizationCodeRepositoryInterface
+	oauthGrantRepository             repository.OAuthGrantRepositoryInterface
+	refreshTokenRepository           repository.RefreshTokenRepositoryInterface
+	authService                      AuthServiceInterface
+	authhelper                       helper.AuthHelperInterface
+	keyRing                          helper.KeyRingInterface
+	oauthDeviceCodeRepository        repository.OAuthDeviceCodeRepositoryInterface
+	issuer                           string
+	authorizationEndpoint            string
+	deviceVerificationURI            string
+}
+
+type OAuthServiceDeps struct {
+	ProfileRepository                repository.UserProfileRepositoryInterface
+	OAuthClientRepository            repository.OAuthClientRepositoryInterface
+	OAuthAuthorizationCodeRepository repository.OAuthAuthorizationCodeRepositoryInterface
+	OAuthGrantRepository             repository.OAuthGrantRepositoryInterface
+	RefreshTokenRepository           repository.RefreshTokenRepositoryInterface
+	OAuthDeviceCodeRepository        repository.OAuthDeviceCodeRepositoryInterface
+	// AuthService ends the login an OpenID Connect logout names
+	AuthService AuthServiceInterface
+	Authhelper  helper.AuthHelperInterface
+	KeyRing     helper.KeyRingInterface
+	// Issuer is the iss of issued tokens, the base of the endpoints in the
+	// OpenID Connect discovery document
+	Issuer string
+	// AuthorizationEndpoint is the page OpenID Connect clients send the user
+	// to, where the user consents
+	AuthorizationEndpoint string
+	// DeviceVerificationURI is the page a device tells the user to enter
+	// its user code at
+	DeviceVerificationURI string
+}
+
+func NewOAuthService(deps OAuthServiceDeps) oauthService {
+	return oauthService{
+		profileRepository:                deps.ProfileRepository,
+		oauthClientRepository:            deps.OAuthClientRepository,
+		oauthAuthorizationCodeRepository: deps.OAuthAuthorizationCodeRepository,
+		oauthGrantRepository:             deps.OAuthGrantRepository,
+		refreshTokenRepository:           deps.RefreshTokenRepository,
+		authService:                      deps.AuthService,
+		authhelper:                       deps.Authhelper,
+		keyRing:                          deps.KeyRing,
+		oauthDeviceCodeRepository:        deps.OAuthDeviceCodeRepository,
+		issuer:                           deps.Issuer,
+		authorizationEndpoint:            deps.AuthorizationEndpoint,
+		deviceVerificationURI:            deps.DeviceVerificationURI,
+	}
+}
+
+// RegisterOAuthClient returns the secret of a confidential client only this
+// once, only its hash is stored.
+func (o oauthService) RegisterOAuthClient(ctx context.Context, request entity.RegisterOAuthClientRequest) (entity.RegisterOAuthClientResponse, error) {
+	var res = entity.RegisterOAuthClientResponse{}
+
+	scopes := uniqueStrings(request.Scopes)
+
+	// scopes of a client acting on its own and of one acting for users
+	// never mix, a user could otherwise consent to read every profile
+	for _, scope := range scopes {
+		if containsString(clientCredentialsScopes, scope) != request.ClientCredentials {
+			return res, error_list.ErrInvalidOAuthScope
+		}
+	}
+
+	grantTypes := []string{constant.OAuthGrantTypeAuthorizationCode, constant.OAuthGrantTypeRefreshToken}
+	if request.ClientCredentials {
+		grantTypes = []string{constant.OAuthGrantTypeClientCredentials}
+	} else if request.Device {
+		// a device is given a login of the user, refreshed like any other
+		grantTypes = []string{constant.OAuthGrantTypeDeviceCode}
+	}
+
+	client := entity.OAuthClient{
+		Name:                   request.Name,
+		RedirectURIs:           strings.Join(request.RedirectURIs, " "),
+		PostLogoutRedirectURIs: strings.Join(request.PostLogoutRedirectURIs, " "),
+		Scopes:                 strings.Join(scopes, " "),
+		GrantTypes:             strings.Join(grantTypes, " "),
+		CreatedAt:              time.Now().UTC(),
+	}
+
+	var secret string
+	if request.Confidential || request.ClientCredentials {
+		var err error
+		secret, err = o.authhelper.GenerateRefreshToken(ctx)
+		if err != nil {
+			return res, error_list.ErrRegisterOAuthClient
+		}
+
+		secretHash := o.authhelper.HashToken(ctx, secret)
+		client.SecretHash = &secretHash
+	}
+
+	var err error
+	client.Id, err = o.oauthClientRepository.InsertOAuthClient(ctx, nil, client)
+	if err != nil {
+		return res, error_list.ErrRegisterOAuthClient
+	}
+
+	res = entity.RegisterOAuthClientResponse{
+		Client:       client,
+		ClientSecret: secret,
+	}
+
+	return res, nil
+}
+
+// GetOAuthAuthorization checks an authorization request and returns what the
+// user is asked to consent to.
+func (o oauthService) GetOAuthAuthorization(ctx context.Context, request entity.OAuthAuthorizationRequest) (entity.OAuthAuthorizationResponse, error) {
+	var res = entity.OAuthAuthorizationResponse{}
+
+	client, scopes, err := o.checkAuthorizationRequest(ctx, request)
+	if err != nil {
+		return res, err
+	}
+
+	res = entity.OAuthAuthorizationResponse{
+		ClientId:    client.Id,
+		ClientName:  client.Name,
+		RedirectURI: request.RedirectURI,
+		Scopes:      scopes,
+	}
+
+	return res, nil
+}
+
+// ConsentOAuthAuthorization records the answer of the user and returns where
+// to send them back to the client, with an authorization code when they
+// approved.
+func (o oauthService) ConsentOAuthAuthorization(ctx context.Context, request entity.OAuthConsentRequest) (entity.OAuthConsentResponse, error) {
+	var res = entity.OAuthConsentResponse{}
+
+	client, scopes, err := o.checkAuthorizationRequest(ctx, request.OAuthAuthorizationRequest)
+	if err != nil {
+		return res, err
+	}
+
+	// the redirect uri is known to be registered, only from here on are
+	// errors reported to the client rather than to the user
+	redirectTo, err := url.Parse(request.RedirectURI)
+	if err != nil {
+		return res, error_list.ErrInvalidRedirectURI
+	}
+
+	query := redirectTo.Query()
+	if request.State != "" {
+		query.Set("state", request.State)
+	}
+
+	if !request.Approved {
+		query.Set("error", "access_denied")
+		redirectTo.RawQuery = query.Encode()
+
+		return entity.OAuthConsentResponse{
+			RedirectTo: redirectTo.String(),
+		}, nil
+	}
+
+	code, err := o.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuth
+	}
+
+	now := time.Now().UTC()
+	err = o.oauthAuthorizationCodeRepository.InsertOAuthAuthorizationCode(ctx, nil, entity.OAuthAuthorizationCode{
+		CodeHash:      o.authhelper.HashToken(ctx, code),
+		ClientId:      client.Id,
+		ProfileId:     request.ProfileId,
+		SessionId:     request.SessionId,
+		RedirectURI:   request.RedirectURI,
+		Scopes:        strings.Join(scopes, " "),
+		CodeChallenge: request.CodeChallenge,
+		Nonce:         request.Nonce,
+		ExpiresAt:     now.Add(constant.OAuthAuthorizationCodeDuration),
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuth
+	}
+
+	query.Set("code", code)
+	redirectTo.RawQuery = query.Encode()
+
+	res = entity.OAuthConsentResponse{
+		RedirectTo: redirectTo.String(),
+	}
+
+	return res, nil
+}
+
+func (o oauthService) ExchangeOAuthToken(ctx context.Context, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	client, err := o.authenticateClient(ctx, request.ClientId, request.ClientSecret)
+	if err != nil {
+		return entity.OAuthTokenResponse{}, err
+	}
+
+	switch request.GrantType {
+	case constant.OAuthGrantTypeAuthorizationCode, constant.OAuthGrantTypeRefreshToken,
+		constant.OAuthGrantTypeClientCredentials, constant.OAuthGrantTypeDeviceCode:
+	default:
+		return entity.OAuthTokenResponse{}, error_list.ErrUnsupportedGrantType
+	}
+
+	if !containsString(strings.Fields(client.GrantTypes), request.GrantType) {
+		return entity.OAuthTokenResponse{}, error_list.ErrUnauthorizedOAuthClient
+	}
+
+	switch request.GrantType {
+	case constant.OAuthGrantTypeAuthorizationCode:
+		return o.exchangeAuthorizationCode(ctx, client, request)
+	case constant.OAuthGrantTypeRefreshToken:
+		return o.exchangeRefreshToken(ctx, client, request)
+	case constant.OAuthGrantTypeDeviceCode:
+		return o.exchangeDeviceCode(ctx, client, request)
+	default:
+		return o.exchangeClientCredentials(ctx, client, request)
+	}
+}
+
+func (o oauthService) ListOAuthGrants(ctx context.Context, request entity.ListOAuthGrantsRequest) (entity.ListOAuthGrantsResponse, error) {
+	var res = entity.ListOAuthGrantsResponse{}
+
+	grants, err := o.oauthGrantRepository.GetOAuthGrantsByProfileId(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrListOAuthGrants
+	}
+
+	res = entity.ListOAuthGrantsResponse{
+		Grants: grants,
+	}
+
+	return res, nil
+}
+
+// RevokeOAuthGrant takes back everything the user allowed the client. Its
+// access tokens are refused from then on, its refresh tokens revoked.
+func (o oauthService) RevokeOAuthGrant(ctx context.Context, request entity.RevokeOAuthGrantRequest) error {
+	var grantId string
+
+	err := o.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		grantId, err = o.oauthGrantRepository.DeleteOAuthGrant(ctx, tx, request.ProfileId, request.ClientId)
+		if err != nil {
+			return error_list.ErrRevokeOAuthGrant
+		}
+
+		if grantId == "" {
+			return nil
+		}
+
+		err = o.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, grantId)
+		if err != nil {
+			return error_list.ErrRevokeOAuthGrant
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if grantId == "" {
+		return error_list.ErrOAuthGrantNotFound
+	}
+
+	return nil
+}
+
+func (o oauthService) PruneOAuthAuthorizationCodes(ctx context.Context) error {
+	_, err := o.oauthAuthorizationCodeRepository.DeleteExpiredOAuthAuthorizationCodes(ctx, nil, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrPruneOAuthAuthorizationCodes
+	}
+
+	return nil
+}
+
+// checkAuthorizationRequest returns the client of the request and the scopes
+// it asks for.
+func (o oauthService) checkAuthorizationRequest(ctx context.Context, request entity.OAuthAuthorizationRequest) (entity.OAuthClient, []string, error) {
+	client, err := o.oauthClientRepository.GetOAuthClientById(ctx, nil, request.ClientId)
+	if err != nil {
+		return client, nil, error_list.ErrAuthorizeOAuth
+	}
+
+	if client.Id == "" || !containsString(strings.Fields(client.GrantTypes), constant.OAuthGrantTypeAuthorizationCode) {
+		return client, nil, error_list.ErrInvalidOAuthClient
+	}
+
+	// only an exact match, anything looser lets codes be sent elsewhere
+	if !containsString(strings.Fields(client.RedirectURIs), request.RedirectURI) {
+		return client, nil, error_list.ErrInvalidRedirectURI
+	}
+
+	scopes, err := requestedScopes(client, request.Scope)
+	if err != nil {
+		return client, nil, err
+	}
+
+	return client, scopes, nil
+}
+
+// requestedScopes returns the space separated scopes a request of the client
+// asks for, every scope of the client when it names none.
+func requestedScopes(client entity.OAuthClient, scope string) ([]string, error) {
+	allowed := strings.Fields(client.Scopes)
+
+	scopes := uniqueStrings(strings.Fields(scope))
+	if len(scopes) == 0 {
+		scopes = allowed
+	}
+
+	for _, scope := range scopes {
+		if !containsString(allowed, scope) {
+			return nil, error_list.ErrInvalidOAuthScope
+		}
+	}
+
+	return scopes, nil
+}
+
+// authenticateClient looks the client up, and checks the secret of a
+// confidential one. Public clients prove themselves with PKCE instead.
+func (o oauthService) authenticateClient(ctx context.Context, clientId string, clientSecret string) (entity.OAuthClient, error) {
+	client, err := o.oauthClientRepository.GetOAuthClientById(ctx, nil, clientId)
+	if err != nil {
+		return client, error_list.ErrOAuthToken
+	}
+
+	if client.Id == "" {
+		return client, error_list.ErrInvalidOAuthClient
+	}
+
+	if client.SecretHash != nil {
+		secretHash := o.authhelper.HashToken(ctx, clientSecret)
+		if clientSecret == "" || subtle.ConstantTimeCompare([]byte(secretHash), []byte(*client.SecretHash)) != 1 {
+			return client, error_list.ErrInvalidOAuthClient
+		}
+	}
+
+	return client, nil
+}
+
+func (o oauthService) exchangeAuthorizationCode(ctx context.Context, client entity.OAuthClient, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	var res = entity.OAuthTokenResponse{}
+
+	if request.Code == "" || request.RedirectURI == "" ||
+		len(request.CodeVerifier) < constant.OAuthCodeVerifierMinLength || len(request.CodeVerifier) > constant.OAuthCodeVerifierMaxLength {
+		return res, error_list.ErrInvalidOAuthRequest
+	}
+
+	code, err := o.oauthAuthorizationCodeRepository.ConsumeOAuthAuthorizationCode(ctx, nil, o.authhelper.HashToken(ctx, request.Code))
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if code.Id == "" || time.Now().After(code.ExpiresAt) || code.ClientId != client.Id || code.RedirectURI != request.RedirectURI {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	challenge := o.authhelper.CodeChallenge(ctx, request.CodeVerifier)
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	codeScopes := strings.Fields(code.Scopes)
+
+	res, err = o.grantTokens(ctx, code.ProfileId, client, codeScopes)
+	if err != nil {
+		return res, err
+	}
+
+	// only the sign in itself gets an ID token, refreshing does not
+	if containsString(codeScopes, constant.ScopeOpenId) {
+		res.IDToken, err = o.idToken(ctx, code, codeScopes)
+		if err != nil {
+			return entity.OAuthTokenResponse{}, err
+		}
+	}
+
+	return res, nil
+}
+
+func (o oauthService) exchangeRefreshToken(ctx context.Context, client entity.OAuthClient, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	var res = entity.OAuthTokenResponse{}
+
+	if request.RefreshToken == "" {
+		return res, error_list.ErrInvalidOAuthRequest
+	}
+
+	storedToken, err := o.refreshTokenRepository.GetRefreshTokenByHash(ctx, nil, o.authhelper.HashToken(ctx, request.RefreshToken))
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if storedToken.Id == "" || storedToken.RevokedAt != nil || time.Now().After(storedToken.ExpiresAt) {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	// the family of a refresh token issued to a login is a session, which
+	// is not found here
+	grant, err := o.oauthGrantRepository.GetOAuthGrantById(ctx, nil, storedToken.FamilyId)
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if grant.Id == "" || grant.ClientId != client.Id {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	if storedToken.UsedAt != nil {
+		return res, o.revokeReusedFamily(ctx, grant.Id)
+	}
+
+	var refreshToken string
+
+	err = o.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		marked, err := o.refreshTokenRepository.MarkRefreshTokenUsed(ctx, tx, storedToken.Id)
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		// another request rotated this token between our read and the update
+		if !marked {
+			return error_list.ErrRefreshTokenReused
+		}
+
+		refreshToken, err = o.createRefreshToken(ctx, tx, grant)
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		err = o.oauthGrantRepository.TouchOAuthGrant(ctx, tx, grant.Id, time.Now().UTC())
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		return nil
+	})
+	if err != nil {
+		if err == error_list.ErrRefreshTokenReused {
+			return res, o.revokeReusedFamily(ctx, grant.Id)
+		}
+
+		return res, err
+	}
+
+	return o.tokenResponse(ctx, grant, refreshToken)
+}
+
+// exchangeClientCredentials issues a token of the client itself, no user is
+// involved so there is neither a grant nor a refresh token.
+func (o oauthService) exchangeClientCredentials(ctx context.Context, client entity.OAuthClient, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	var res = entity.OAuthTokenResponse{}
+
+	// a public client proves nothing about who is asking
+	if client.SecretHash == nil {
+		return res, error_list.ErrUnauthorizedOAuthClient
+	}
+
+	scopes, err := requestedScopes(client, request.Scope)
+	if err != nil {
+		return res, err
+	}
+
+	token, err := o.authhelper.GenerateToken(ctx, entity.GenerateTokenRequest{
+		ClientId: client.Id,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	res = entity.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   constant.OAuthTokenTypeBearer,
+		ExpiresIn:   int64(constant.AccessTokenDuration.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}
+
+	return res, nil
+}
+
+// grantTokens adds the scopes the user just approved to what they allowed the
+// client, and issues tokens under that grant.
+func (o oauthService) grantTokens(ctx context.Context, profileId string, client entity.OAuthClient, approved []string) (entity.OAuthTokenResponse, error) {
+	var grant entity.OAuthGrant
+	var refreshToken string
+
+	err := o.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		grant, err = o.oauthGrantRepository.GetOAuthGrant(ctx, tx, profileId, client.Id)
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		// consenting again only ever adds to what the client was allowed
+		scopes := strings.Fields(grant.Scopes)
+		for _, scope := range approved {
+			if !containsString(scopes, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+
+		grant = entity.OAuthGrant{
+			ProfileId: profileId,
+			ClientId:  client.Id,
+			Scopes:    strings.Join(scopes, " "),
+			CreatedAt: time.Now().UTC(),
+		}
+		grant.Id, err = o.oauthGrantRepository.UpsertOAuthGrant(ctx, tx, grant)
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		refreshToken, err = o.createRefreshToken(ctx, tx, grant)
+		if err != nil {
+			return error_list.ErrOAuthToken
+		}
+
+		return nil
+	})
+	if err != nil {
+		return entity.OAuthTokenResponse{}, err
+	}
+
+	return o.tokenResponse(ctx, grant, refreshToken)
+}
+
+func (o oauthService) createRefreshToken(ctx context.Context, tx *sqlx.Tx, grant entity.OAuthGrant) (string, error) {
+	refreshToken, err := o.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = o.refreshTokenRepository.InsertRefreshToken(ctx, tx, entity.RefreshToken{
+		ProfileId: grant.ProfileId,
+		FamilyId:  grant.Id,
+		TokenHash: o.authhelper.HashToken(ctx, refreshToken),
+		ExpiresAt: time.Now().Add(constant.RefreshTokenDuration).UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// revokeReusedFamily is called when an already rotated refresh token is
+// presented again, like for logins every refresh token of the grant is
+// revoked. The grant itself stays, the user signs the client in again.
+func (o oauthService) revokeReusedFamily(ctx context.Context, grantId string) error {
+	err := o.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, nil, grantId)
+	if err != nil {
+		return error_list.ErrOAuthToken
+	}
+
+	return error_list.ErrInvalidOAuthGrant
+}
+
+func (o oauthService) tokenResponse(ctx context.Context, grant entity.OAuthGrant, refreshToken string) (entity.OAuthTokenResponse, error) {
+	scopes := strings.Fields(grant.Scopes)
+
+	token, err := o.authhelper.GenerateToken(ctx, entity.GenerateTokenRequest{
+		ProfileId: grant.ProfileId,
+		SessionId: grant.Id,
+		ClientId:  grant.ClientId,
+		Scopes:    scopes,
+	})
+	if err != nil {
+		return entity.OAuthTokenResponse{}, error_list.ErrOAuthToken
+	}
+
+	return entity.OAuthTokenResponse{
+		AccessToken:  token,
+		TokenType:    constant.OAuthTokenTypeBearer,
+		ExpiresIn:    int64(constant.AccessTokenDuration.Seconds()),
+		RefreshToken: refreshToken,
+		Scope:        strings.Join(scopes, " "),
+	}, nil
+}
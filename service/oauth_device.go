@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// AuthorizeOAuthDevice starts the sign in of a device. The device shows the
+// user code and polls the token endpoint with the device code until the user
+// has answered on another device.
+func (o oauthService) AuthorizeOAuthDevice(ctx context.Context, request entity.OAuthDeviceAuthorizationRequest) (entity.OAuthDeviceAuthorizationResponse, error) {
+	var res = entity.OAuthDeviceAuthorizationResponse{}
+
+	client, err := o.authenticateClient(ctx, request.ClientId, request.ClientSecret)
+	if err != nil {
+		if err == error_list.ErrOAuthToken {
+			return res, error_list.ErrAuthorizeOAuthDevice
+		}
+
+		return res, err
+	}
+
+	if !containsString(strings.Fields(client.GrantTypes), constant.OAuthGrantTypeDeviceCode) {
+		return res, error_list.ErrUnauthorizedOAuthClient
+	}
+
+	scopes, err := requestedScopes(client, request.Scope)
+	if err != nil {
+		return res, err
+	}
+
+	verificationURIComplete, err := url.Parse(o.deviceVerificationURI)
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	deviceCode, err := o.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	userCode, err := o.authhelper.GenerateUserCode(ctx)
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	now := time.Now().UTC()
+	interval := int64(constant.OAuthDevicePollInterval.Seconds())
+
+	err = o.oauthDeviceCodeRepository.InsertOAuthDeviceCode(ctx, nil, entity.OAuthDeviceCode{
+		DeviceCodeHash: o.authhelper.HashToken(ctx, deviceCode),
+		UserCode:       normalizeUserCode(userCode),
+		ClientId:       client.Id,
+		Scopes:         strings.Join(scopes, " "),
+		Interval:       interval,
+		ExpiresAt:      now.Add(constant.OAuthDeviceCodeDuration),
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	query := verificationURIComplete.Query()
+	query.Set("user_code", userCode)
+	verificationURIComplete.RawQuery = query.Encode()
+
+	res = entity.OAuthDeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         o.deviceVerificationURI,
+		VerificationURIComplete: verificationURIComplete.String(),
+		ExpiresIn:               int64(constant.OAuthDeviceCodeDuration.Seconds()),
+		Interval:                interval,
+	}
+
+	return res, nil
+}
+
+// GetOAuthDevice returns what the user entering a user code is asked to
+// consent to.
+func (o oauthService) GetOAuthDevice(ctx context.Context, request entity.OAuthDeviceRequest) (entity.OAuthDeviceResponse, error) {
+	var res = entity.OAuthDeviceResponse{}
+
+	code, err := o.oauthDeviceCodeRepository.GetOAuthDeviceCodeByUserCode(ctx, nil, normalizeUserCode(request.UserCode))
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	if code.Id == "" || code.Status != constant.OAuthDeviceCodeStatusPending || time.Now().After(code.ExpiresAt) {
+		return res, error_list.ErrInvalidUserCode
+	}
+
+	client, err := o.oauthClientRepository.GetOAuthClientById(ctx, nil, code.ClientId)
+	if err != nil {
+		return res, error_list.ErrAuthorizeOAuthDevice
+	}
+
+	if client.Id == "" {
+		return res, error_list.ErrInvalidUserCode
+	}
+
+	res = entity.OAuthDeviceResponse{
+		ClientId:   client.Id,
+		ClientName: client.Name,
+		Scopes:     strings.Fields(code.Scopes),
+	}
+
+	return res, nil
+}
+
+// ConsentOAuthDevice records the answer of the user, the device learns of it
+// on its next poll.
+func (o oauthService) ConsentOAuthDevice(ctx context.Context, request entity.OAuthDeviceConsentRequest) error {
+	status := constant.OAuthDeviceCodeStatusDenied
+	if request.Approved {
+		status = constant.OAuthDeviceCodeStatusApproved
+	}
+
+	decided, err := o.oauthDeviceCodeRepository.DecideOAuthDeviceCode(ctx, nil, normalizeUserCode(request.UserCode), status, request.ProfileId, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrAuthorizeOAuthDevice
+	}
+
+	if !decided {
+		return error_list.ErrInvalidUserCode
+	}
+
+	return nil
+}
+
+func (o oauthService) PruneOAuthDeviceCodes(ctx context.Context) error {
+	_, err := o.oauthDeviceCodeRepository.DeleteExpiredOAuthDeviceCodes(ctx, nil, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrPruneOAuthDeviceCodes
+	}
+
+	return nil
+}
+
+// exchangeDeviceCode answers a poll of a device. Once the user approved, the
+// device is signed in as the user like a password login would: it is one of
+// their sessions, refreshed at /token/refresh and signed out with the others.
+func (o oauthService) exchangeDeviceCode(ctx context.Context, client entity.OAuthClient, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error) {
+	var res = entity.OAuthTokenResponse{}
+
+	if request.DeviceCode == "" {
+		return res, error_list.ErrInvalidOAuthRequest
+	}
+
+	code, err := o.oauthDeviceCodeRepository.GetOAuthDeviceCodeByHash(ctx, nil, o.authhelper.HashToken(ctx, request.DeviceCode))
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if code.Id == "" || code.ClientId != client.Id {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	now := time.Now().UTC()
+	if now.After(code.ExpiresAt) {
+		return res, error_list.ErrExpiredDeviceCode
+	}
+
+	// a device polling too often has to wait longer from then on
+	interval := code.Interval
+	slowDown := code.LastPolledAt != nil && now.Before(code.LastPolledAt.Add(time.Duration(interval)*time.Second))
+	if slowDown {
+		interval += int64(constant.OAuthDeviceSlowDownStep.Seconds())
+	}
+
+	err = o.oauthDeviceCodeRepository.PollOAuthDeviceCode(ctx, nil, code.Id, now, interval)
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if slowDown {
+		return res, error_list.ErrSlowDown
+	}
+
+	switch code.Status {
+	case constant.OAuthDeviceCodeStatusPending:
+		return res, error_list.ErrAuthorizationPending
+	case constant.OAuthDeviceCodeStatusDenied:
+		_, err = o.oauthDeviceCodeRepository.DeleteOAuthDeviceCode(ctx, nil, code.Id)
+		if err != nil {
+			return res, error_list.ErrOAuthToken
+		}
+
+		return res, error_list.ErrOAuthAccessDenied
+	}
+
+	// deleting the code keeps two polls from both redeeming it
+	deleted, err := o.oauthDeviceCodeRepository.DeleteOAuthDeviceCode(ctx, nil, code.Id)
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	if !deleted || code.ProfileId == nil {
+		return res, error_list.ErrInvalidOAuthGrant
+	}
+
+	issued, err := o.authService.IssueToken(ctx, entity.IssueTokenRequest{
+		ProfileId:  *code.ProfileId,
+		DeviceName: client.Name,
+	})
+	if err != nil {
+		return res, error_list.ErrOAuthToken
+	}
+
+	res = entity.OAuthTokenResponse{
+		AccessToken:  issued.Token,
+		TokenType:    constant.OAuthTokenTypeBearer,
+		ExpiresIn:    issued.ExpiresIn,
+		RefreshToken: issued.RefreshToken,
+	}
+
+	return res, nil
+}
+
+// normalizeUserCode lets the user type the code in any case, with or without
+// the dash.
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, userCode)
+}
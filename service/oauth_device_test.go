@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthService_RegisterOAuthClient_device(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthClientRepository.EXPECT().InsertOAuthClient(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+			assert.Nil(t, client.SecretHash)
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", client.GrantTypes)
+			return "client-id-1", nil
+		},
+	)
+
+	o := oauthService{
+		oauthClientRepository: mockOAuthClientRepository,
+	}
+	got, err := o.RegisterOAuthClient(context.TODO(), entity.RegisterOAuthClientRequest{
+		Name:   "Weighing Kiosk",
+		Scopes: []string{"openid", "profile"},
+		Device: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "client-id-1", got.Client.Id)
+	assert.Empty(t, got.ClientSecret)
+}
+
+func Test_oauthService_AuthorizeOAuthDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthDeviceCodeRepository := mocks.NewMockOAuthDeviceCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	deviceClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		Scopes:     "openid profile profile:read",
+		GrantTypes: "urn:ietf:params:oauth:grant-type:device_code refresh_token",
+	}
+	webClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		Scopes:     "openid profile profile:read",
+		GrantTypes: "authorization_code refresh_token",
+	}
+
+	tests := []struct {
+		name    string
+		scope   string
+		want    entity.OAuthDeviceAuthorizationResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:  "success authorize device",
+			scope: "openid profile",
+			want: entity.OAuthDeviceAuthorizationResponse{
+				DeviceCode:              "device-code-1",
+				UserCode:                "BCDF-GHJK",
+				VerificationURI:         "https://sawitpro.example/device",
+				VerificationURIComplete: "https://sawitpro.example/device?user_code=BCDF-GHJK",
+				ExpiresIn:               600,
+				Interval:                5,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(deviceClient, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("device-code-1", nil)
+				mockHelper.EXPECT().GenerateUserCode(gomock.Any()).Return("BCDF-GHJK", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "device-code-1").Return("device-code-hash-1")
+				mockOAuthDeviceCodeRepository.EXPECT().InsertOAuthDeviceCode(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, code entity.OAuthDeviceCode) error {
+						assert.Equal(t, "device-code-hash-1", code.DeviceCodeHash)
+						assert.Equal(t, "BCDFGHJK", code.UserCode)
+						assert.Equal(t, "openid profile", code.Scopes)
+						assert.Equal(t, int64(5), code.Interval)
+						return nil
+					},
+				)
+			},
+		},
+		{
+			name:    "error client not registered for devices",
+			want:    entity.OAuthDeviceAuthorizationResponse{},
+			wantErr: errors.New("error grant type is not allowed for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(webClient, nil)
+			},
+		},
+		{
+			name:    "error scope not allowed",
+			scope:   "profile:write",
+			want:    entity.OAuthDeviceAuthorizationResponse{},
+			wantErr: errors.New("error scope is not allowed for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(deviceClient, nil)
+			},
+		},
+		{
+			name:    "error insert device code",
+			want:    entity.OAuthDeviceAuthorizationResponse{},
+			wantErr: errors.New("error when authorizing oauth device"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(deviceClient, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("device-code-1", nil)
+				mockHelper.EXPECT().GenerateUserCode(gomock.Any()).Return("BCDF-GHJK", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "device-code-1").Return("device-code-hash-1")
+				mockOAuthDeviceCodeRepository.EXPECT().InsertOAuthDeviceCode(gomock.Any(), nil, gomock.Any()).Return(errors.New("error db"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository:     mockOAuthClientRepository,
+				oauthDeviceCodeRepository: mockOAuthDeviceCodeRepository,
+				authhelper:                mockHelper,
+				deviceVerificationURI:     "https://sawitpro.example/device",
+			}
+			got, err := o.AuthorizeOAuthDevice(context.TODO(), entity.OAuthDeviceAuthorizationRequest{
+				ClientId: "client-id-1",
+				Scope:    tt.scope,
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_GetOAuthDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthDeviceCodeRepository := mocks.NewMockOAuthDeviceCodeRepositoryInterface(ctrl)
+
+	code := entity.OAuthDeviceCode{
+		Id:        "device-code-id-1",
+		UserCode:  "BCDFGHJK",
+		ClientId:  "client-id-1",
+		Scopes:    "openid profile",
+		Status:    "pending",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	expiredCode := code
+	expiredCode.ExpiresAt = time.Now().Add(-time.Second)
+	decidedCode := code
+	decidedCode.Status = "approved"
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthDeviceResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success get device",
+			want: entity.OAuthDeviceResponse{
+				ClientId:   "client-id-1",
+				ClientName: "Weighing Kiosk",
+				Scopes:     []string{"openid", "profile"},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByUserCode(gomock.Any(), nil, "BCDFGHJK").Return(code, nil)
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{
+					Id:   "client-id-1",
+					Name: "Weighing Kiosk",
+				}, nil)
+			},
+		},
+		{
+			name:    "error user code not found",
+			want:    entity.OAuthDeviceResponse{},
+			wantErr: errors.New("error invalid or expired user code"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByUserCode(gomock.Any(), nil, "BCDFGHJK").Return(entity.OAuthDeviceCode{}, nil)
+			},
+		},
+		{
+			name:    "error user code expired",
+			want:    entity.OAuthDeviceResponse{},
+			wantErr: errors.New("error invalid or expired user code"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByUserCode(gomock.Any(), nil, "BCDFGHJK").Return(expiredCode, nil)
+			},
+		},
+		{
+			name:    "error user code already decided",
+			want:    entity.OAuthDeviceResponse{},
+			wantErr: errors.New("error invalid or expired user code"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByUserCode(gomock.Any(), nil, "BCDFGHJK").Return(decidedCode, nil)
+			},
+		},
+		{
+			name:    "error get device code",
+			want:    entity.OAuthDeviceResponse{},
+			wantErr: errors.New("error when authorizing oauth device"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByUserCode(gomock.Any(), nil, "BCDFGHJK").Return(entity.OAuthDeviceCode{}, errors.New("error db"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository:     mockOAuthClientRepository,
+				oauthDeviceCodeRepository: mockOAuthDeviceCodeRepository,
+			}
+			// typed the way it was read off the kiosk
+			got, err := o.GetOAuthDevice(context.TODO(), entity.OAuthDeviceRequest{
+				ProfileId: "profile-id-1",
+				UserCode:  "bcdf-ghjk",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_ConsentOAuthDevice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthDeviceCodeRepository := mocks.NewMockOAuthDeviceCodeRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name     string
+		approved bool
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name:     "success approve device",
+			approved: true,
+			wantErr:  nil,
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().DecideOAuthDeviceCode(gomock.Any(), nil, "BCDFGHJK", "approved", "profile-id-1", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:     "success deny device",
+			approved: false,
+			wantErr:  nil,
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().DecideOAuthDeviceCode(gomock.Any(), nil, "BCDFGHJK", "denied", "profile-id-1", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:     "error user code already decided or expired",
+			approved: true,
+			wantErr:  errors.New("error invalid or expired user code"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().DecideOAuthDeviceCode(gomock.Any(), nil, "BCDFGHJK", "approved", "profile-id-1", gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name:     "error decide device code",
+			approved: true,
+			wantErr:  errors.New("error when authorizing oauth device"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().DecideOAuthDeviceCode(gomock.Any(), nil, "BCDFGHJK", "approved", "profile-id-1", gomock.Any()).Return(false, errors.New("error db"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthDeviceCodeRepository: mockOAuthDeviceCodeRepository,
+			}
+			err := o.ConsentOAuthDevice(context.TODO(), entity.OAuthDeviceConsentRequest{
+				OAuthDeviceRequest: entity.OAuthDeviceRequest{
+					ProfileId: "profile-id-1",
+					UserCode:  "BCDF-GHJK",
+				},
+				Approved: tt.approved,
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_ExchangeOAuthToken_deviceCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthDeviceCodeRepository := mocks.NewMockOAuthDeviceCodeRepositoryInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	client := entity.OAuthClient{
+		Id:         "client-id-1",
+		Name:       "weighing station 3",
+		GrantTypes: "urn:ietf:params:oauth:grant-type:device_code",
+	}
+
+	profileId := "profile-id-1"
+	lastPolledAt := time.Now().Add(-10 * time.Second)
+	pendingCode := entity.OAuthDeviceCode{
+		Id:           "device-code-id-1",
+		ClientId:     "client-id-1",
+		Scopes:       "openid profile",
+		Status:       "pending",
+		Interval:     5,
+		LastPolledAt: &lastPolledAt,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	approvedCode := pendingCode
+	approvedCode.Status = "approved"
+	approvedCode.ProfileId = &profileId
+	deniedCode := pendingCode
+	deniedCode.Status = "denied"
+	deniedCode.ProfileId = &profileId
+	expiredCode := pendingCode
+	expiredCode.ExpiresAt = time.Now().Add(-time.Second)
+	otherClientCode := pendingCode
+	otherClientCode.ClientId = "client-id-2"
+	recentlyPolledAt := time.Now().Add(-time.Second)
+	tooFastCode := pendingCode
+	tooFastCode.LastPolledAt = &recentlyPolledAt
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success approved device is signed in as the user",
+			want: entity.OAuthTokenResponse{
+				AccessToken:  "token-1",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: "refresh-token-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(approvedCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(5)).Return(nil)
+				mockOAuthDeviceCodeRepository.EXPECT().DeleteOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1").Return(true, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId:  "profile-id-1",
+					DeviceName: "weighing station 3",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+			},
+		},
+		{
+			name:    "error issue token",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error when issuing oauth token"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(approvedCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(5)).Return(nil)
+				mockOAuthDeviceCodeRepository.EXPECT().DeleteOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1").Return(true, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), gomock.Any()).Return(entity.IssueTokenResponse{}, errors.New("error when issuing token"))
+			},
+		},
+		{
+			name:    "error authorization pending",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error the user has not answered yet"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(pendingCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(5)).Return(nil)
+			},
+		},
+		{
+			name:    "error polling too often slows the device down",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error polling too often"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(tooFastCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(10)).Return(nil)
+			},
+		},
+		{
+			name:    "error user denied",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error the user denied the authorization"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(deniedCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(5)).Return(nil)
+				mockOAuthDeviceCodeRepository.EXPECT().DeleteOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1").Return(true, nil)
+			},
+		},
+		{
+			name:    "error device code expired",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error device code has expired"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(expiredCode, nil)
+			},
+		},
+		{
+			name:    "error device code of another client",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(otherClientCode, nil)
+			},
+		},
+		{
+			name:    "error approved code redeemed by another poll",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthDeviceCodeRepository.EXPECT().GetOAuthDeviceCodeByHash(gomock.Any(), nil, "device-code-hash-1").Return(approvedCode, nil)
+				mockOAuthDeviceCodeRepository.EXPECT().PollOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1", gomock.Any(), int64(5)).Return(nil)
+				mockOAuthDeviceCodeRepository.EXPECT().DeleteOAuthDeviceCode(gomock.Any(), nil, "device-code-id-1").Return(false, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			mockHelper.EXPECT().HashToken(gomock.Any(), "device-code-1").Return("device-code-hash-1")
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository:     mockOAuthClientRepository,
+				oauthDeviceCodeRepository: mockOAuthDeviceCodeRepository,
+				authService:               mockAuthService,
+				authhelper:                mockHelper,
+			}
+			got, err := o.ExchangeOAuthToken(context.TODO(), entity.OAuthTokenRequest{
+				GrantType:  "urn:ietf:params:oauth:grant-type:device_code",
+				DeviceCode: "device-code-1",
+				ClientId:   "client-id-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+)
+
+// IntrospectToken tells a service holding a token whether it is still good
+// and whose it is, checked the way our own middleware checks it. A token
+// that is malformed, expired or revoked is simply not active.
+func (o oauthService) IntrospectToken(ctx context.Context, request entity.IntrospectTokenRequest) (entity.IntrospectTokenResponse, error) {
+	// none of those becomes active again, the answer holds as long as any
+	inactive := entity.IntrospectTokenResponse{
+		CacheFor: constant.IntrospectionMaxAge,
+	}
+
+	claims, err := o.authService.Authenticate(ctx, entity.AuthenticateRequest{
+		Token: request.Token,
+	})
+	if err == error_list.ErrAuthenticate {
+		return entity.IntrospectTokenResponse{}, error_list.ErrIntrospectToken
+	}
+
+	// but one that is not valid yet will be, and when is not known here
+	if err == error_list.ErrTokenNotValidYet {
+		return entity.IntrospectTokenResponse{}, nil
+	}
+
+	if err != nil {
+		return inactive, nil
+	}
+
+	// such a token only lets its owner set a new password here
+	if claims.PasswordChangeRequired {
+		return inactive, nil
+	}
+
+	subject := claims.ProfileId
+	if subject == "" {
+		subject = claims.ClientId
+	}
+
+	res := entity.IntrospectTokenResponse{
+		Active:    true,
+		Subject:   subject,
+		ClientId:  claims.ClientId,
+		Scopes:    claims.Scopes,
+		TokenType: constant.OAuthTokenTypeBearer,
+		CacheFor:  constant.IntrospectionMaxAge,
+	}
+
+	if !claims.ExpiresAt.IsZero() {
+		expiresAt := claims.ExpiresAt
+		res.ExpiresAt = &expiresAt
+
+		if remaining := time.Until(expiresAt); remaining < res.CacheFor {
+			res.CacheFor = remaining
+		}
+	}
+
+	return res, nil
+}
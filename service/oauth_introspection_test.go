@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthService_IntrospectToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+	soonExpiresAt := time.Now().Add(30 * time.Second)
+
+	tests := []struct {
+		name    string
+		want    entity.IntrospectTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success token of an oauth client acting for a user",
+			want: entity.IntrospectTokenResponse{
+				Active:    true,
+				Subject:   "profile-id-1",
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profile:read"},
+				TokenType: "Bearer",
+				ExpiresAt: &expiresAt,
+				CacheFor:  time.Minute,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{
+					ProfileId: "profile-id-1",
+					SessionId: "grant-id-1",
+					ClientId:  "client-id-1",
+					Scopes:    []string{"profile:read"},
+					ExpiresAt: expiresAt,
+				}, nil)
+			},
+		},
+		{
+			name: "success token of a client acting on its own",
+			want: entity.IntrospectTokenResponse{
+				Active:    true,
+				Subject:   "client-id-1",
+				ClientId:  "client-id-1",
+				Scopes:    []string{"profiles:read"},
+				TokenType: "Bearer",
+				ExpiresAt: &expiresAt,
+				CacheFor:  time.Minute,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{
+					ClientId:  "client-id-1",
+					Scopes:    []string{"profiles:read"},
+					ExpiresAt: expiresAt,
+				}, nil)
+			},
+		},
+		{
+			name: "success personal access token that never expires",
+			want: entity.IntrospectTokenResponse{
+				Active:    true,
+				Subject:   "profile-id-1",
+				Scopes:    []string{"profile:read"},
+				TokenType: "Bearer",
+				CacheFor:  time.Minute,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{
+					ProfileId:             "profile-id-1",
+					PersonalAccessTokenId: "pat-id-1",
+					Scopes:                []string{"profile:read"},
+				}, nil)
+			},
+		},
+		{
+			name: "error revoked token is not active",
+			want: entity.IntrospectTokenResponse{
+				CacheFor: time.Minute,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{}, error_list.ErrTokenRevoked)
+			},
+		},
+		{
+			name:    "error token not valid yet is not active and not cached",
+			want:    entity.IntrospectTokenResponse{},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{}, error_list.ErrTokenNotValidYet)
+			},
+		},
+		{
+			name: "error token only allowed to change the password is not active",
+			want: entity.IntrospectTokenResponse{
+				CacheFor: time.Minute,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{
+					ProfileId:              "profile-id-1",
+					SessionId:              "session-id-1",
+					ExpiresAt:              expiresAt,
+					PasswordChangeRequired: true,
+				}, nil)
+			},
+		},
+		{
+			name:    "error authenticate",
+			want:    entity.IntrospectTokenResponse{},
+			wantErr: errors.New("error when introspecting token"),
+			mock: func() {
+				mockAuthService.EXPECT().Authenticate(gomock.Any(), entity.AuthenticateRequest{Token: "token-1"}).Return(entity.TokenClaims{}, error_list.ErrAuthenticate)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				authService: mockAuthService,
+			}
+			got, err := o.IntrospectToken(context.TODO(), entity.IntrospectTokenRequest{
+				Token: "token-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+
+	t.Run("success token about to expire is cached until it does", func(t *testing.T) {
+		mockAuthService.EXPECT().Authenticate(gomock.Any(), gomock.Any()).Return(entity.TokenClaims{
+			ProfileId: "profile-id-1",
+			SessionId: "session-id-1",
+			ExpiresAt: soonExpiresAt,
+		}, nil)
+
+		o := oauthService{
+			authService: mockAuthService,
+		}
+		got, err := o.IntrospectToken(context.TODO(), entity.IntrospectTokenRequest{
+			Token: "token-1",
+		})
+		assert.NoError(t, err)
+		assert.True(t, got.Active)
+		assert.LessOrEqual(t, got.CacheFor, 30*time.Second)
+	})
+}
@@ -0,0 +1,1078 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOAuthService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthAuthorizationCodeRepository := mocks.NewMockOAuthAuthorizationCodeRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockOAuthDeviceCodeRepository := mocks.NewMockOAuthDeviceCodeRepositoryInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockKeyRing := mocks.NewMockKeyRingInterface(ctrl)
+
+	got := NewOAuthService(OAuthServiceDeps{
+		ProfileRepository:                mockProfileRepository,
+		OAuthClientRepository:            mockOAuthClientRepository,
+		OAuthAuthorizationCodeRepository: mockOAuthAuthorizationCodeRepository,
+		OAuthGrantRepository:             mockOAuthGrantRepository,
+		RefreshTokenRepository:           mockRefreshTokenRepository,
+		OAuthDeviceCodeRepository:        mockOAuthDeviceCodeRepository,
+		AuthService:                      mockAuthService,
+		Authhelper:                       mockHelper,
+		KeyRing:                          mockKeyRing,
+		Issuer:                           "https://id.sawitpro.example",
+		AuthorizationEndpoint:            "https://sawitpro.example/consent",
+		DeviceVerificationURI:            "https://sawitpro.example/device",
+	})
+	assert.Equal(t, oauthService{
+		profileRepository:                mockProfileRepository,
+		oauthClientRepository:            mockOAuthClientRepository,
+		oauthAuthorizationCodeRepository: mockOAuthAuthorizationCodeRepository,
+		oauthGrantRepository:             mockOAuthGrantRepository,
+		refreshTokenRepository:           mockRefreshTokenRepository,
+		oauthDeviceCodeRepository:        mockOAuthDeviceCodeRepository,
+		authService:                      mockAuthService,
+		authhelper:                       mockHelper,
+		keyRing:                          mockKeyRing,
+		issuer:                           "https://id.sawitpro.example",
+		authorizationEndpoint:            "https://sawitpro.example/consent",
+		deviceVerificationURI:            "https://sawitpro.example/device",
+	}, got)
+}
+
+func Test_oauthService_RegisterOAuthClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	tests := []struct {
+		name         string
+		confidential bool
+		wantSecret   string
+		wantErr      error
+		mock         func()
+	}{
+		{
+			name:         "success register confidential client",
+			confidential: true,
+			wantSecret:   "secret-1",
+			wantErr:      nil,
+			mock: func() {
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("secret-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "secret-1").Return("secret-hash-1")
+				mockOAuthClientRepository.EXPECT().InsertOAuthClient(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+						assert.Equal(t, "secret-hash-1", *client.SecretHash)
+						assert.Equal(t, "https://partner.example/callback https://partner.example/other", client.RedirectURIs)
+						assert.Equal(t, "profile:read", client.Scopes)
+						return "client-id-1", nil
+					},
+				)
+			},
+		},
+		{
+			name:         "success register public client",
+			confidential: false,
+			wantSecret:   "",
+			wantErr:      nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().InsertOAuthClient(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+						assert.Nil(t, client.SecretHash)
+						return "client-id-1", nil
+					},
+				)
+			},
+		},
+		{
+			name:         "error insert oauth client",
+			confidential: false,
+			wantErr:      errors.New("error when registering oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().InsertOAuthClient(gomock.Any(), nil, gomock.Any()).Return("", errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository: mockOAuthClientRepository,
+				authhelper:            mockHelper,
+			}
+			got, err := o.RegisterOAuthClient(context.TODO(), entity.RegisterOAuthClientRequest{
+				Name:         "Partner App",
+				RedirectURIs: []string{"https://partner.example/callback", "https://partner.example/other"},
+				Scopes:       []string{"profile:read", "profile:read"},
+				Confidential: tt.confidential,
+			})
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Equal(t, entity.RegisterOAuthClientResponse{}, got)
+				return
+			}
+
+			assert.Equal(t, "client-id-1", got.Client.Id)
+			assert.Equal(t, tt.wantSecret, got.ClientSecret)
+		})
+	}
+}
+
+func Test_oauthService_GetOAuthAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+
+	client := entity.OAuthClient{
+		Id:           "client-id-1",
+		Name:         "Partner App",
+		RedirectURIs: "https://partner.example/callback",
+		Scopes:       "profile:read profile:write",
+		GrantTypes:   "authorization_code refresh_token",
+	}
+
+	tests := []struct {
+		name        string
+		scope       string
+		redirectURI string
+		want        entity.OAuthAuthorizationResponse
+		wantErr     error
+		mock        func()
+	}{
+		{
+			name:        "success every scope of the client",
+			scope:       "",
+			redirectURI: "https://partner.example/callback",
+			want: entity.OAuthAuthorizationResponse{
+				ClientId:    "client-id-1",
+				ClientName:  "Partner App",
+				RedirectURI: "https://partner.example/callback",
+				Scopes:      []string{"profile:read", "profile:write"},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name:        "success requested scope",
+			scope:       "profile:read profile:read",
+			redirectURI: "https://partner.example/callback",
+			want: entity.OAuthAuthorizationResponse{
+				ClientId:    "client-id-1",
+				ClientName:  "Partner App",
+				RedirectURI: "https://partner.example/callback",
+				Scopes:      []string{"profile:read"},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name:        "error unknown client",
+			redirectURI: "https://partner.example/callback",
+			want:        entity.OAuthAuthorizationResponse{},
+			wantErr:     errors.New("error invalid oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{}, nil)
+			},
+		},
+		{
+			name:        "error redirect uri not registered",
+			redirectURI: "https://partner.example/callback/../evil",
+			want:        entity.OAuthAuthorizationResponse{},
+			wantErr:     errors.New("error redirect uri is not registered for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name:        "error scope not allowed",
+			scope:       "profile:read admin",
+			redirectURI: "https://partner.example/callback",
+			want:        entity.OAuthAuthorizationResponse{},
+			wantErr:     errors.New("error scope is not allowed for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name:        "error get client",
+			redirectURI: "https://partner.example/callback",
+			want:        entity.OAuthAuthorizationResponse{},
+			wantErr:     errors.New("error when authorizing oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository: mockOAuthClientRepository,
+			}
+			got, err := o.GetOAuthAuthorization(context.TODO(), entity.OAuthAuthorizationRequest{
+				ProfileId:   "profile-id-1",
+				ClientId:    "client-id-1",
+				RedirectURI: tt.redirectURI,
+				Scope:       tt.scope,
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_ConsentOAuthAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthAuthorizationCodeRepository := mocks.NewMockOAuthAuthorizationCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	client := entity.OAuthClient{
+		Id:           "client-id-1",
+		Name:         "Partner App",
+		RedirectURIs: "https://partner.example/callback?app=1",
+		Scopes:       "profile:read profile:write",
+		GrantTypes:   "authorization_code refresh_token",
+	}
+
+	tests := []struct {
+		name     string
+		approved bool
+		want     url.Values
+		wantErr  error
+		mock     func()
+	}{
+		{
+			name:     "success approved",
+			approved: true,
+			want: url.Values{
+				"app":   {"1"},
+				"code":  {"code-1"},
+				"state": {"state-1"},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("code-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().InsertOAuthAuthorizationCode(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, code entity.OAuthAuthorizationCode) error {
+						assert.Equal(t, "code-hash-1", code.CodeHash)
+						assert.Equal(t, "profile-id-1", code.ProfileId)
+						assert.Equal(t, "session-id-1", code.SessionId)
+						assert.Equal(t, "profile:read", code.Scopes)
+						assert.Equal(t, "challenge-1", code.CodeChallenge)
+						assert.Equal(t, "nonce-1", code.Nonce)
+						assert.True(t, code.ExpiresAt.After(time.Now()))
+						return nil
+					},
+				)
+			},
+		},
+		{
+			name:     "success denied",
+			approved: false,
+			want: url.Values{
+				"app":   {"1"},
+				"error": {"access_denied"},
+				"state": {"state-1"},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name:     "error insert authorization code",
+			approved: true,
+			wantErr:  errors.New("error when authorizing oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("code-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().InsertOAuthAuthorizationCode(gomock.Any(), nil, gomock.Any()).Return(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository:            mockOAuthClientRepository,
+				oauthAuthorizationCodeRepository: mockOAuthAuthorizationCodeRepository,
+				authhelper:                       mockHelper,
+			}
+			got, err := o.ConsentOAuthAuthorization(context.TODO(), entity.OAuthConsentRequest{
+				OAuthAuthorizationRequest: entity.OAuthAuthorizationRequest{
+					ProfileId:     "profile-id-1",
+					SessionId:     "session-id-1",
+					ClientId:      "client-id-1",
+					RedirectURI:   "https://partner.example/callback?app=1",
+					Scope:         "profile:read",
+					State:         "state-1",
+					Nonce:         "nonce-1",
+					CodeChallenge: "challenge-1",
+				},
+				Approved: tt.approved,
+			})
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Equal(t, entity.OAuthConsentResponse{}, got)
+				return
+			}
+
+			redirectTo, err := url.Parse(got.RedirectTo)
+			assert.NoError(t, err)
+			assert.Equal(t, "partner.example", redirectTo.Host)
+			assert.Equal(t, "/callback", redirectTo.Path)
+			assert.Equal(t, tt.want, redirectTo.Query())
+		})
+	}
+}
+
+func Test_oauthService_ExchangeOAuthToken_authorizationCode(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthAuthorizationCodeRepository := mocks.NewMockOAuthAuthorizationCodeRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	secretHash := "secret-hash-1"
+	confidentialClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		SecretHash: &secretHash,
+		GrantTypes: "authorization_code refresh_token",
+	}
+	publicClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		GrantTypes: "authorization_code refresh_token",
+	}
+
+	verifier := strings.Repeat("v", 43)
+	code := entity.OAuthAuthorizationCode{
+		Id:            "code-id-1",
+		ClientId:      "client-id-1",
+		ProfileId:     "profile-id-1",
+		RedirectURI:   "https://partner.example/callback",
+		Scopes:        "profile:write",
+		CodeChallenge: "challenge-1",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	expiredCode := code
+	expiredCode.ExpiresAt = time.Now().Add(-time.Second)
+	openIdCode := code
+	openIdCode.SessionId = "session-id-1"
+	openIdCode.Scopes = "openid profile"
+	openIdCode.Nonce = "nonce-1"
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name         string
+		clientSecret string
+		codeVerifier string
+		want         entity.OAuthTokenResponse
+		wantErr      error
+		mock         func()
+	}{
+		{
+			name:         "success exchange code of a public client",
+			codeVerifier: verifier,
+			want: entity.OAuthTokenResponse{
+				AccessToken:  "token-1",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: "refresh-token-1",
+				Scope:        "profile:read profile:write",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(publicClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), nil, "code-hash-1").Return(code, nil)
+				mockHelper.EXPECT().CodeChallenge(gomock.Any(), verifier).Return("challenge-1")
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrant(gomock.Any(), mockTx, "profile-id-1", "client-id-1").Return(entity.OAuthGrant{
+					Id:     "grant-id-1",
+					Scopes: "profile:read",
+				}, nil)
+				mockOAuthGrantRepository.EXPECT().UpsertOAuthGrant(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, grant entity.OAuthGrant) (string, error) {
+						assert.Equal(t, "profile:read profile:write", grant.Scopes)
+						return "grant-id-1", nil
+					},
+				)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("refresh-hash-1")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, token entity.RefreshToken) (string, error) {
+						assert.Equal(t, "grant-id-1", token.FamilyId)
+						return "refresh-id-1", nil
+					},
+				)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "grant-id-1",
+					ClientId:  "client-id-1",
+					Scopes:    []string{"profile:read", "profile:write"},
+				}).Return("token-1", nil)
+			},
+		},
+		{
+			name:         "success exchange code granted openid issues an id token",
+			codeVerifier: verifier,
+			want: entity.OAuthTokenResponse{
+				AccessToken:  "token-1",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: "refresh-token-1",
+				Scope:        "openid profile",
+				IDToken:      "id-token-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(publicClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), nil, "code-hash-1").Return(openIdCode, nil)
+				mockHelper.EXPECT().CodeChallenge(gomock.Any(), verifier).Return("challenge-1")
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrant(gomock.Any(), mockTx, "profile-id-1", "client-id-1").Return(entity.OAuthGrant{}, nil)
+				mockOAuthGrantRepository.EXPECT().UpsertOAuthGrant(gomock.Any(), mockTx, gomock.Any()).Return("grant-id-1", nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("refresh-hash-1")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).Return("refresh-id-1", nil)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), gomock.Any()).Return("token-1", nil)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{
+					Id:              "profile-id-1",
+					FullName:        "Budi",
+					PhoneNumber:     "+6281234567890",
+					PhoneVerifiedAt: &verifiedAt,
+				}, nil)
+				name := "Budi"
+				mockHelper.EXPECT().GenerateIDToken(gomock.Any(), entity.GenerateIDTokenRequest{
+					ClientId:  "client-id-1",
+					SessionId: "session-id-1",
+					Nonce:     "nonce-1",
+					UserInfo: entity.UserInfo{
+						ProfileId: "profile-id-1",
+						Name:      &name,
+					},
+				}).Return("id-token-1", nil)
+			},
+		},
+		{
+			name:         "error wrong client secret",
+			clientSecret: "wrong-secret",
+			codeVerifier: verifier,
+			want:         entity.OAuthTokenResponse{},
+			wantErr:      errors.New("error invalid oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(confidentialClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "wrong-secret").Return("wrong-hash")
+			},
+		},
+		{
+			name:         "error missing code verifier",
+			codeVerifier: "",
+			want:         entity.OAuthTokenResponse{},
+			wantErr:      errors.New("error invalid oauth request"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(publicClient, nil)
+			},
+		},
+		{
+			name:         "error expired code",
+			codeVerifier: verifier,
+			want:         entity.OAuthTokenResponse{},
+			wantErr:      errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(publicClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), nil, "code-hash-1").Return(expiredCode, nil)
+			},
+		},
+		{
+			name:         "error code verifier does not match",
+			codeVerifier: verifier,
+			want:         entity.OAuthTokenResponse{},
+			wantErr:      errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(publicClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "code-1").Return("code-hash-1")
+				mockOAuthAuthorizationCodeRepository.EXPECT().ConsumeOAuthAuthorizationCode(gomock.Any(), nil, "code-hash-1").Return(code, nil)
+				mockHelper.EXPECT().CodeChallenge(gomock.Any(), verifier).Return("challenge-2")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				profileRepository:                mockProfileRepository,
+				oauthClientRepository:            mockOAuthClientRepository,
+				oauthAuthorizationCodeRepository: mockOAuthAuthorizationCodeRepository,
+				oauthGrantRepository:             mockOAuthGrantRepository,
+				refreshTokenRepository:           mockRefreshTokenRepository,
+				authhelper:                       mockHelper,
+			}
+			got, err := o.ExchangeOAuthToken(context.TODO(), entity.OAuthTokenRequest{
+				GrantType:    "authorization_code",
+				Code:         "code-1",
+				RedirectURI:  "https://partner.example/callback",
+				CodeVerifier: tt.codeVerifier,
+				ClientId:     "client-id-1",
+				ClientSecret: tt.clientSecret,
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_ExchangeOAuthToken_refreshToken(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	usedAt := time.Now().Add(-time.Minute)
+
+	client := entity.OAuthClient{
+		Id:         "client-id-1",
+		GrantTypes: "authorization_code refresh_token",
+	}
+	storedToken := entity.RefreshToken{
+		Id:        "refresh-id-1",
+		ProfileId: "profile-id-1",
+		FamilyId:  "grant-id-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	usedToken := storedToken
+	usedToken.UsedAt = &usedAt
+	grant := entity.OAuthGrant{
+		Id:        "grant-id-1",
+		ProfileId: "profile-id-1",
+		ClientId:  "client-id-1",
+		Scopes:    "profile:read",
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.OAuthTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success refresh",
+			want: entity.OAuthTokenResponse{
+				AccessToken:  "token-1",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: "refresh-token-2",
+				Scope:        "profile:read",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("refresh-hash-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "refresh-hash-1").Return(storedToken, nil)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrantById(gomock.Any(), nil, "grant-id-1").Return(grant, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockRefreshTokenRepository.EXPECT().MarkRefreshTokenUsed(gomock.Any(), mockTx, "refresh-id-1").Return(true, nil)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("refresh-token-2", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-2").Return("refresh-hash-2")
+				mockRefreshTokenRepository.EXPECT().InsertRefreshToken(gomock.Any(), mockTx, gomock.Any()).Return("refresh-id-2", nil)
+				mockOAuthGrantRepository.EXPECT().TouchOAuthGrant(gomock.Any(), mockTx, "grant-id-1", gomock.Any()).Return(nil)
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ProfileId: "profile-id-1",
+					SessionId: "grant-id-1",
+					ClientId:  "client-id-1",
+					Scopes:    []string{"profile:read"},
+				}).Return("token-1", nil)
+			},
+		},
+		{
+			name:    "error refresh token of a login",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("refresh-hash-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "refresh-hash-1").Return(storedToken, nil)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrantById(gomock.Any(), nil, "grant-id-1").Return(entity.OAuthGrant{}, nil)
+			},
+		},
+		{
+			name:    "error reused refresh token revokes family",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error invalid or expired authorization grant"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "refresh-token-1").Return("refresh-hash-1")
+				mockRefreshTokenRepository.EXPECT().GetRefreshTokenByHash(gomock.Any(), nil, "refresh-hash-1").Return(usedToken, nil)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrantById(gomock.Any(), nil, "grant-id-1").Return(grant, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), nil, "grant-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error unknown client",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error invalid oauth client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				profileRepository:      mockProfileRepository,
+				oauthClientRepository:  mockOAuthClientRepository,
+				oauthGrantRepository:   mockOAuthGrantRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				authhelper:             mockHelper,
+			}
+			got, err := o.ExchangeOAuthToken(context.TODO(), entity.OAuthTokenRequest{
+				GrantType:    "refresh_token",
+				RefreshToken: "refresh-token-1",
+				ClientId:     "client-id-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_ExchangeOAuthToken_unsupportedGrantType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{Id: "client-id-1"}, nil)
+
+	o := oauthService{
+		oauthClientRepository: mockOAuthClientRepository,
+	}
+	got, err := o.ExchangeOAuthToken(context.TODO(), entity.OAuthTokenRequest{
+		GrantType: "password",
+		ClientId:  "client-id-1",
+	})
+	assert.Equal(t, entity.OAuthTokenResponse{}, got)
+	assert.Equal(t, errors.New("error unsupported grant type"), err)
+}
+
+func Test_oauthService_RevokeOAuthGrant(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke oauth grant",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockOAuthGrantRepository.EXPECT().DeleteOAuthGrant(gomock.Any(), mockTx, "profile-id-1", "client-id-1").Return("grant-id-1", nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "grant-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error oauth grant not found",
+			wantErr: errors.New("error oauth grant not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockOAuthGrantRepository.EXPECT().DeleteOAuthGrant(gomock.Any(), mockTx, "profile-id-1", "client-id-1").Return("", nil)
+			},
+		},
+		{
+			name:    "error revoke refresh tokens",
+			wantErr: errors.New("error when revoking oauth grant"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockOAuthGrantRepository.EXPECT().DeleteOAuthGrant(gomock.Any(), mockTx, "profile-id-1", "client-id-1").Return("grant-id-1", nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "grant-id-1").Return(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				profileRepository:      mockProfileRepository,
+				oauthGrantRepository:   mockOAuthGrantRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+			}
+			err := o.RevokeOAuthGrant(context.TODO(), entity.RevokeOAuthGrantRequest{
+				ProfileId: "profile-id-1",
+				ClientId:  "client-id-1",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_Authenticate_oauthClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		ProfileId: "profile-id-1",
+		SessionId: "grant-id-1",
+		TokenId:   "token-id-1",
+		ClientId:  "client-id-1",
+		Scopes:    []string{"profile:read"},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.TokenClaims
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success authenticate oauth client token",
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrantById(gomock.Any(), nil, "grant-id-1").Return(entity.OAuthGrant{
+					Id:        "grant-id-1",
+					ProfileId: "profile-id-1",
+					ClientId:  "client-id-1",
+				}, nil)
+			},
+		},
+		{
+			name:    "error oauth grant revoked",
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error oauth grant has been revoked"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockOAuthGrantRepository.EXPECT().GetOAuthGrantById(gomock.Any(), nil, "grant-id-1").Return(entity.OAuthGrant{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				oauthGrantRepository:   mockOAuthGrantRepository,
+				authhelper:             mockHelper,
+			}
+			got, err := a.Authenticate(context.TODO(), entity.AuthenticateRequest{
+				Token: "token-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_RegisterOAuthClient_clientCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	tests := []struct {
+		name              string
+		clientCredentials bool
+		scopes            []string
+		wantErr           error
+		mock              func()
+	}{
+		{
+			name:              "success register client credentials client",
+			clientCredentials: true,
+			scopes:            []string{"profiles:read"},
+			wantErr:           nil,
+			mock: func() {
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("secret-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "secret-1").Return("secret-hash-1")
+				mockOAuthClientRepository.EXPECT().InsertOAuthClient(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, client entity.OAuthClient) (string, error) {
+						assert.Equal(t, "secret-hash-1", *client.SecretHash)
+						assert.Equal(t, "client_credentials", client.GrantTypes)
+						assert.Equal(t, "profiles:read", client.Scopes)
+						return "client-id-1", nil
+					},
+				)
+			},
+		},
+		{
+			name:              "error client credentials client asking for a scope of users",
+			clientCredentials: true,
+			scopes:            []string{"profiles:read", "profile:write"},
+			wantErr:           errors.New("error scope is not allowed for the client"),
+			mock:              func() {},
+		},
+		{
+			name:              "error client of users asking for a scope of services",
+			clientCredentials: false,
+			scopes:            []string{"profiles:read"},
+			wantErr:           errors.New("error scope is not allowed for the client"),
+			mock:              func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository: mockOAuthClientRepository,
+				authhelper:            mockHelper,
+			}
+			got, err := o.RegisterOAuthClient(context.TODO(), entity.RegisterOAuthClientRequest{
+				Name:              "Payment",
+				Scopes:            tt.scopes,
+				ClientCredentials: tt.clientCredentials,
+			})
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Equal(t, entity.RegisterOAuthClientResponse{}, got)
+				return
+			}
+
+			assert.Equal(t, "client-id-1", got.Client.Id)
+			assert.Equal(t, "secret-1", got.ClientSecret)
+		})
+	}
+}
+
+func Test_oauthService_ExchangeOAuthToken_clientCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	secretHash := "secret-hash-1"
+	serviceClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		SecretHash: &secretHash,
+		Scopes:     "profiles:read",
+		GrantTypes: "client_credentials",
+	}
+	userClient := entity.OAuthClient{
+		Id:         "client-id-1",
+		SecretHash: &secretHash,
+		Scopes:     "profile:read",
+		GrantTypes: "authorization_code refresh_token",
+	}
+
+	tests := []struct {
+		name    string
+		scope   string
+		want    entity.OAuthTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:  "success issue token of the client",
+			scope: "",
+			want: entity.OAuthTokenResponse{
+				AccessToken: "token-1",
+				TokenType:   "Bearer",
+				ExpiresIn:   900,
+				Scope:       "profiles:read",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(serviceClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "secret-1").Return("secret-hash-1")
+				mockHelper.EXPECT().GenerateToken(gomock.Any(), entity.GenerateTokenRequest{
+					ClientId: "client-id-1",
+					Scopes:   []string{"profiles:read"},
+				}).Return("token-1", nil)
+			},
+		},
+		{
+			name:    "error scope not allowed",
+			scope:   "profile:write",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error scope is not allowed for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(serviceClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "secret-1").Return("secret-hash-1")
+			},
+		},
+		{
+			name:    "error client signing users in",
+			scope:   "",
+			want:    entity.OAuthTokenResponse{},
+			wantErr: errors.New("error grant type is not allowed for the client"),
+			mock: func() {
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(userClient, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "secret-1").Return("secret-hash-1")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository: mockOAuthClientRepository,
+				authhelper:            mockHelper,
+			}
+			got, err := o.ExchangeOAuthToken(context.TODO(), entity.OAuthTokenRequest{
+				GrantType:    "client_credentials",
+				Scope:        tt.scope,
+				ClientId:     "client-id-1",
+				ClientSecret: "secret-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_Authenticate_clientCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRevokedTokenRepository := mocks.NewMockRevokedTokenRepositoryInterface(ctrl)
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	claims := entity.TokenClaims{
+		TokenId:  "token-id-1",
+		ClientId: "client-id-1",
+		Scopes:   []string{"profiles:read"},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.TokenClaims
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success authenticate token of a client",
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{
+					Id:         "client-id-1",
+					GrantTypes: "client_credentials",
+				}, nil)
+			},
+		},
+		{
+			name:    "error oauth client removed",
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error oauth client has been revoked"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyToken(gomock.Any(), "token-1").Return(claims, nil)
+				mockRevokedTokenRepository.EXPECT().IsTokenRevoked(gomock.Any(), "token-id-1").Return(false, nil)
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(entity.OAuthClient{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				revokedTokenRepository: mockRevokedTokenRepository,
+				oauthClientRepository:  mockOAuthClientRepository,
+				authhelper:             mockHelper,
+			}
+			got, err := a.Authenticate(context.TODO(), entity.AuthenticateRequest{
+				Token: "token-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
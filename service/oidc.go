@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"strings"
+)
+
+// GetUserInfo returns the claims of the profile the scopes release.
+func (o oauthService) GetUserInfo(ctx context.Context, request entity.GetUserInfoRequest) (entity.UserInfo, error) {
+	var res = entity.UserInfo{}
+
+	profile, err := o.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrGetUserInfo
+	}
+
+	if profile.Id == "" {
+		return res, error_list.ErrProfileNotFound
+	}
+
+	return userInfo(profile, request.Scopes), nil
+}
+
+// GetOpenIDConfiguration describes this service to OpenID Connect clients,
+// which configure themselves from it.
+func (o oauthService) GetOpenIDConfiguration(ctx context.Context) (entity.OpenIDConfiguration, error) {
+	return entity.OpenIDConfiguration{
+		Issuer:                      o.issuer,
+		AuthorizationEndpoint:       o.authorizationEndpoint,
+		TokenEndpoint:               o.issuer + constant.OAuthTokenPath,
+		UserInfoEndpoint:            o.issuer + constant.UserInfoPath,
+		JWKSURI:                     o.issuer + constant.JSONWebKeySetPath,
+		EndSessionEndpoint:          o.issuer + constant.OAuthLogoutPath,
+		DeviceAuthorizationEndpoint: o.issuer + constant.OAuthDeviceAuthorizationPath,
+		IntrospectionEndpoint:       o.issuer + constant.OAuthIntrospectPath,
+		ScopesSupported: []string{
+			constant.ScopeOpenId,
+			constant.ScopeProfile,
+			constant.ScopePhone,
+			constant.ScopeProfileRead,
+			constant.ScopeProfileWrite,
+			constant.ScopeProfilesRead,
+			constant.ScopeTokensIntrospect,
+		},
+		ResponseTypesSupported: []string{"code"},
+		GrantTypesSupported: []string{
+			constant.OAuthGrantTypeAuthorizationCode,
+			constant.OAuthGrantTypeRefreshToken,
+			constant.OAuthGrantTypeClientCredentials,
+			constant.OAuthGrantTypeDeviceCode,
+		},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{o.keyRing.Algorithm(ctx)},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{constant.OAuthCodeChallengeMethodS256},
+		ClaimsSupported:                   []string{"sub", "name", "phone_number", "phone_number_verified", "nonce", "sid"},
+	}, nil
+}
+
+// EndOIDCSession logs the user out of the login they signed in to the client
+// with, and returns where to send them back to when the client asked for it.
+func (o oauthService) EndOIDCSession(ctx context.Context, request entity.OIDCLogoutRequest) (entity.OIDCLogoutResponse, error) {
+	var res = entity.OIDCLogoutResponse{}
+
+	claims, err := o.authhelper.VerifyIDToken(ctx, request.IDTokenHint)
+	if err != nil {
+		return res, error_list.ErrInvalidIDTokenHint
+	}
+
+	if request.ClientId != "" && request.ClientId != claims.ClientId {
+		return res, error_list.ErrInvalidIDTokenHint
+	}
+
+	var redirectTo *url.URL
+	if request.PostLogoutRedirectURI != "" {
+		client, err := o.oauthClientRepository.GetOAuthClientById(ctx, nil, claims.ClientId)
+		if err != nil {
+			return res, error_list.ErrEndOIDCSession
+		}
+
+		// an open redirect otherwise, the same exact match as on sign in
+		if client.Id == "" || !containsString(strings.Fields(client.PostLogoutRedirectURIs), request.PostLogoutRedirectURI) {
+			return res, error_list.ErrInvalidRedirectURI
+		}
+
+		redirectTo, err = url.Parse(request.PostLogoutRedirectURI)
+		if err != nil {
+			return res, error_list.ErrInvalidRedirectURI
+		}
+	}
+
+	// the login may have ended already, which is what was asked for
+	if claims.SessionId != "" {
+		err = o.authService.RevokeSession(ctx, entity.RevokeSessionRequest{
+			ProfileId: claims.ProfileId,
+			SessionId: claims.SessionId,
+		})
+		if err != nil && err != error_list.ErrSessionNotFound {
+			return res, error_list.ErrEndOIDCSession
+		}
+	}
+
+	if redirectTo != nil {
+		if request.State != "" {
+			query := redirectTo.Query()
+			query.Set("state", request.State)
+			redirectTo.RawQuery = query.Encode()
+		}
+
+		res.RedirectTo = redirectTo.String()
+	}
+
+	return res, nil
+}
+
+func (o oauthService) idToken(ctx context.Context, code entity.OAuthAuthorizationCode, scopes []string) (string, error) {
+	profile, err := o.profileRepository.GetProfileById(ctx, nil, code.ProfileId)
+	if err != nil || profile.Id == "" {
+		return "", error_list.ErrOAuthToken
+	}
+
+	idToken, err := o.authhelper.GenerateIDToken(ctx, entity.GenerateIDTokenRequest{
+		ClientId:  code.ClientId,
+		SessionId: code.SessionId,
+		Nonce:     code.Nonce,
+		UserInfo:  userInfo(profile, scopes),
+	})
+	if err != nil {
+		return "", error_list.ErrOAuthToken
+	}
+
+	return idToken, nil
+}
+
+// userInfo releases name for the profile scope and the phone number for the
+// phone scope, the subject always.
+func userInfo(profile entity.UserProfile, scopes []string) entity.UserInfo {
+	info := entity.UserInfo{
+		ProfileId: profile.Id,
+	}
+
+	if containsString(scopes, constant.ScopeProfile) {
+		name := profile.FullName
+		info.Name = &name
+	}
+
+	if containsString(scopes, constant.ScopePhone) {
+		phoneNumber := profile.PhoneNumber
+		phoneNumberVerified := profile.PhoneVerifiedAt != nil
+		info.PhoneNumber = &phoneNumber
+		info.PhoneNumberVerified = &phoneNumberVerified
+	}
+
+	return info
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_oauthService_GetUserInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+
+	name := "Budi"
+	phoneNumber := "+6281234567890"
+	phoneNumberVerified := false
+
+	profile := entity.UserProfile{
+		Id:          "profile-id-1",
+		FullName:    "Budi",
+		PhoneNumber: "+6281234567890",
+	}
+
+	tests := []struct {
+		name    string
+		scopes  []string
+		want    entity.UserInfo
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:   "success with every scope",
+			scopes: []string{"openid", "profile", "phone"},
+			want: entity.UserInfo{
+				ProfileId:           "profile-id-1",
+				Name:                &name,
+				PhoneNumber:         &phoneNumber,
+				PhoneNumberVerified: &phoneNumberVerified,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+			},
+		},
+		{
+			name:   "success with openid only releases the subject",
+			scopes: []string{"openid"},
+			want: entity.UserInfo{
+				ProfileId: "profile-id-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			scopes:  []string{"openid"},
+			want:    entity.UserInfo{},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error get profile",
+			scopes:  []string{"openid"},
+			want:    entity.UserInfo{},
+			wantErr: errors.New("error when getting user info"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, errors.New("error db"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				profileRepository: mockProfileRepository,
+			}
+			got, err := o.GetUserInfo(context.TODO(), entity.GetUserInfoRequest{
+				ProfileId: "profile-id-1",
+				Scopes:    tt.scopes,
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_oauthService_GetOpenIDConfiguration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyRing := mocks.NewMockKeyRingInterface(ctrl)
+	mockKeyRing.EXPECT().Algorithm(gomock.Any()).Return("ES256")
+
+	o := oauthService{
+		keyRing:               mockKeyRing,
+		issuer:                "https://id.sawitpro.example",
+		authorizationEndpoint: "https://sawitpro.example/consent",
+	}
+	got, err := o.GetOpenIDConfiguration(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, "https://id.sawitpro.example", got.Issuer)
+	assert.Equal(t, "https://sawitpro.example/consent", got.AuthorizationEndpoint)
+	assert.Equal(t, "https://id.sawitpro.example/oauth/token", got.TokenEndpoint)
+	assert.Equal(t, "https://id.sawitpro.example/userinfo", got.UserInfoEndpoint)
+	assert.Equal(t, "https://id.sawitpro.example/.well-known/jwks.json", got.JWKSURI)
+	assert.Equal(t, "https://id.sawitpro.example/oauth/logout", got.EndSessionEndpoint)
+	assert.Equal(t, []string{"ES256"}, got.IDTokenSigningAlgValuesSupported)
+	assert.Contains(t, got.ScopesSupported, "openid")
+}
+
+func Test_oauthService_EndOIDCSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOAuthClientRepository := mocks.NewMockOAuthClientRepositoryInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	idTokenClaims := entity.IDTokenClaims{
+		ProfileId: "profile-id-1",
+		ClientId:  "client-id-1",
+		SessionId: "session-id-1",
+	}
+	client := entity.OAuthClient{
+		Id:                     "client-id-1",
+		PostLogoutRedirectURIs: "https://partner.example/logged-out",
+		CreatedAt:              time.Now(),
+	}
+	revokeReq := entity.RevokeSessionRequest{
+		ProfileId: "profile-id-1",
+		SessionId: "session-id-1",
+	}
+
+	tests := []struct {
+		name    string
+		request entity.OIDCLogoutRequest
+		want    entity.OIDCLogoutResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success logout with redirect",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint:           "id-token-1",
+				ClientId:              "client-id-1",
+				PostLogoutRedirectURI: "https://partner.example/logged-out",
+				State:                 "state-1",
+			},
+			want: entity.OIDCLogoutResponse{
+				RedirectTo: "https://partner.example/logged-out?state=state-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(idTokenClaims, nil)
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+				mockAuthService.EXPECT().RevokeSession(gomock.Any(), revokeReq).Return(nil)
+			},
+		},
+		{
+			name: "success logout of a session already ended",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint: "id-token-1",
+			},
+			want:    entity.OIDCLogoutResponse{},
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(idTokenClaims, nil)
+				mockAuthService.EXPECT().RevokeSession(gomock.Any(), revokeReq).Return(error_list.ErrSessionNotFound)
+			},
+		},
+		{
+			name: "error invalid id token",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint: "id-token-1",
+			},
+			want:    entity.OIDCLogoutResponse{},
+			wantErr: errors.New("error invalid id token hint"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(entity.IDTokenClaims{}, errors.New("error token signature is invalid"))
+			},
+		},
+		{
+			name: "error id token of another client",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint: "id-token-1",
+				ClientId:    "client-id-2",
+			},
+			want:    entity.OIDCLogoutResponse{},
+			wantErr: errors.New("error invalid id token hint"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(idTokenClaims, nil)
+			},
+		},
+		{
+			name: "error post logout redirect uri not registered",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint:           "id-token-1",
+				PostLogoutRedirectURI: "https://evil.example",
+			},
+			want:    entity.OIDCLogoutResponse{},
+			wantErr: errors.New("error redirect uri is not registered for the client"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(idTokenClaims, nil)
+				mockOAuthClientRepository.EXPECT().GetOAuthClientById(gomock.Any(), nil, "client-id-1").Return(client, nil)
+			},
+		},
+		{
+			name: "error revoke session",
+			request: entity.OIDCLogoutRequest{
+				IDTokenHint: "id-token-1",
+			},
+			want:    entity.OIDCLogoutResponse{},
+			wantErr: errors.New("error when ending openid connect session"),
+			mock: func() {
+				mockHelper.EXPECT().VerifyIDToken(gomock.Any(), "id-token-1").Return(idTokenClaims, nil)
+				mockAuthService.EXPECT().RevokeSession(gomock.Any(), revokeReq).Return(errors.New("error when revoking session"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			o := oauthService{
+				oauthClientRepository: mockOAuthClientRepository,
+				authService:           mockAuthService,
+				authhelper:            mockHelper,
+			}
+			got, err := o.EndOIDCSession(context.TODO(), tt.request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// issueOneTimeCode replaces any outstanding code of the profile for the
+// purpose with a new one and texts it to the profile's phone.
+func (p profileService) issueOneTimeCode(ctx context.Context, profile entity.UserProfile, purpose string, messageFormat string) error {
+	var code string
+
+	err := p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		code, err = p.createOneTimeCode(ctx, tx, profile.Id, purpose)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.sendOneTimeCode(ctx, profile.PhoneNumber, messageFormat, code)
+}
+
+// createOneTimeCode stores a new code of the profile for the purpose in place
+// of any outstanding one and returns it in plain text.
+func (p profileService) createOneTimeCode(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string) (string, error) {
+	code, err := p.authhelper.GenerateOneTimeCode(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.oneTimeCodeRepository.ConsumeOneTimeCodes(ctx, tx, profileId, purpose)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = p.oneTimeCodeRepository.InsertOneTimeCode(ctx, tx, entity.OneTimeCode{
+		ProfileId: profileId,
+		Purpose:   purpose,
+		CodeHash:  p.authhelper.HashToken(ctx, code),
+		ExpiresAt: time.Now().Add(constant.OneTimeCodeDuration).UTC(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// sendOneTimeCode texts code to phoneNumber. messageFormat receives the code
+// and its lifetime in minutes.
+func (p profileService) sendOneTimeCode(ctx context.Context, phoneNumber string, messageFormat string, code string) error {
+	message := fmt.Sprintf(messageFormat, code, int(constant.OneTimeCodeDuration.Minutes()))
+
+	return p.smsSender.SendSMS(ctx, phoneNumber, message)
+}
+
+// redeemOneTimeCode checks code against the active code of the profile for
+// the purpose and consumes it when it matches. A rejected code is reported
+// through codeErr rather than err: the failed attempt it records must be
+// committed, so the caller should not roll the transaction back for it.
+func (p profileService) redeemOneTimeCode(ctx context.Context, tx *sqlx.Tx, profileId string, purpose string, code string) (codeErr error, err error) {
+	activeCode, err := p.oneTimeCodeRepository.GetActiveOneTimeCode(ctx, tx, profileId, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	if activeCode.Id == "" || time.Now().After(activeCode.ExpiresAt) {
+		return error_list.ErrInvalidOneTimeCode, nil
+	}
+
+	if activeCode.Attempts >= constant.OneTimeCodeMaxAttempts {
+		return error_list.ErrOneTimeCodeAttemptsExceeded, nil
+	}
+
+	codeHash := p.authhelper.HashToken(ctx, code)
+	if subtle.ConstantTimeCompare([]byte(codeHash), []byte(activeCode.CodeHash)) != 1 {
+		err = p.oneTimeCodeRepository.IncreaseOneTimeCodeAttempts(ctx, tx, activeCode.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		return error_list.ErrInvalidOneTimeCode, nil
+	}
+
+	err = p.oneTimeCodeRepository.ConsumeOneTimeCodes(ctx, tx, profileId, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (p profileService) PruneOneTimeCodes(ctx context.Context) error {
+	_, err := p.oneTimeCodeRepository.DeleteExpiredOneTimeCodes(ctx, nil, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrPruneOneTimeCodes
+	}
+
+	return nil
+}
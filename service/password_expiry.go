@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// passwordChangeRequired reports whether the profile has to replace its
+// password before it may do anything else, because an admin flagged it or
+// because it is older than the configured maximum age.
+func (p profileService) passwordChangeRequired(profile entity.UserProfile, now time.Time) bool {
+	if profile.MustChangePassword {
+		return true
+	}
+
+	return p.passwordMaxAge > 0 && now.After(profile.PasswordChangedAt.Add(p.passwordMaxAge))
+}
+
+// SetNewPassword replaces the password of a login that was only let through
+// to do so. The login already proved the old password, or a recovery code,
+// so it is not asked for again. Every session ends, the restricted one
+// included, and the owner signs in again with the new password.
+func (p profileService) SetNewPassword(ctx context.Context, request entity.SetNewPasswordRequest) error {
+	profile, err := p.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return error_list.ErrSetNewPassword
+	}
+
+	if profile.Id == "" {
+		return error_list.ErrProfileNotFound
+	}
+
+	err = p.validatorHelper.ValidatePassword(request.NewPassword, profile.FullName, profile.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	breached, err := p.breachedPasswordChecker.IsBreached(ctx, request.NewPassword)
+	if err != nil {
+		return error_list.ErrSetNewPassword
+	}
+
+	if breached {
+		return error_list.ErrBreachedPassword
+	}
+
+	err = p.checkPasswordHistory(ctx, nil, profile, request.NewPassword)
+	if err != nil {
+		if err == error_list.ErrPasswordReused {
+			return err
+		}
+		return error_list.ErrSetNewPassword
+	}
+
+	hashedPassword, err := p.authhelper.HashPassword(ctx, request.NewPassword)
+	if err != nil {
+		return error_list.ErrSetNewPassword
+	}
+
+	err = p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := p.replacePassword(ctx, tx, profile, hashedPassword)
+		if err != nil {
+			return error_list.ErrSetNewPassword
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = p.authService.RevokeAllSessions(ctx, entity.RevokeAllSessionsRequest{
+		ProfileId: profile.Id,
+	})
+	if err != nil {
+		return error_list.ErrSetNewPassword
+	}
+
+	return nil
+}
+
+// ForcePasswordChange makes the owner of the phone number replace their
+// password on their next login. Every session ends, so nobody stays signed in
+// with the password in the meantime.
+func (p profileService) ForcePasswordChange(ctx context.Context, request entity.ForcePasswordChangeRequest) error {
+	profile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, nil, request.PhoneNumber)
+	if err != nil {
+		return error_list.ErrForcePasswordChange
+	}
+
+	if profile.Id == "" {
+		return error_list.ErrProfileNotFound
+	}
+
+	err = p.profileRepository.SetMustChangePassword(ctx, nil, profile.Id, true)
+	if err != nil {
+		return error_list.ErrForcePasswordChange
+	}
+
+	err = p.authService.RevokeAllSessions(ctx, entity.RevokeAllSessionsRequest{
+		ProfileId: profile.Id,
+	})
+	if err != nil {
+		return error_list.ErrForcePasswordChange
+	}
+
+	return nil
+}
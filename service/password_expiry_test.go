@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileService_passwordChangeRequired(t *testing.T) {
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		passwordMaxAge time.Duration
+		profile        entity.UserProfile
+		want           bool
+	}{
+		{
+			name:           "password within its age",
+			passwordMaxAge: 90 * 24 * time.Hour,
+			profile: entity.UserProfile{
+				PasswordChangedAt: now.Add(-89 * 24 * time.Hour),
+			},
+			want: false,
+		},
+		{
+			name:           "password older than its age",
+			passwordMaxAge: 90 * 24 * time.Hour,
+			profile: entity.UserProfile{
+				PasswordChangedAt: now.Add(-91 * 24 * time.Hour),
+			},
+			want: true,
+		},
+		{
+			name:           "passwords never expire",
+			passwordMaxAge: 0,
+			profile: entity.UserProfile{
+				PasswordChangedAt: now.Add(-1000 * 24 * time.Hour),
+			},
+			want: false,
+		},
+		{
+			name:           "password change forced by admin",
+			passwordMaxAge: 0,
+			profile: entity.UserProfile{
+				PasswordChangedAt:  now,
+				MustChangePassword: true,
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := profileService{
+				passwordMaxAge: tt.passwordMaxAge,
+			}
+			assert.Equal(t, tt.want, p.passwordChangeRequired(tt.profile, now))
+		})
+	}
+}
+
+func Test_profileService_SetNewPassword(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockPasswordHistoryRepository := mocks.NewMockPasswordHistoryRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+	mockBreachedPasswordChecker := mocks.NewMockBreachedPasswordCheckerInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	request := entity.SetNewPasswordRequest{
+		ProfileId:   "profile-id-1",
+		NewPassword: "67890B!",
+	}
+	profile := entity.UserProfile{
+		Id:                 "profile-id-1",
+		FullName:           "jonathan",
+		PhoneNumber:        "+62345",
+		Password:           "hashed-password-1",
+		MustChangePassword: true,
+	}
+
+	screenPassword := func() {
+		mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+		mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+		mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+		mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), nil, "profile-id-1", 5).Return(nil, nil)
+		mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+	}
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success set new password",
+			wantErr: nil,
+			mock: func() {
+				screenPassword()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().InsertPasswordHistory(gomock.Any(), mockTx, "profile-id-1", "hashed-password-1").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().PrunePasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), entity.RevokeAllSessionsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error when get profile",
+			wantErr: errors.New("error when setting new password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, errors.New("error select"))
+			},
+		},
+		{
+			name: "error password breaks the policy",
+			wantErr: error_list.PasswordPolicyError{
+				Violations: []string{"must contain a digit"},
+			},
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(error_list.PasswordPolicyError{
+					Violations: []string{"must contain a digit"},
+				})
+			},
+		},
+		{
+			name:    "error breached password",
+			wantErr: errors.New("error password has appeared in a data breach, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(true, nil)
+			},
+		},
+		{
+			name:    "error same as the expired password",
+			wantErr: errors.New("error password was used recently, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), nil, "profile-id-1", 5).Return(nil, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(nil)
+			},
+		},
+		{
+			name:    "error when update password",
+			wantErr: errors.New("error when setting new password"),
+			mock: func() {
+				screenPassword()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(errors.New("error update"))
+			},
+		},
+		{
+			name:    "error when revoke sessions",
+			wantErr: errors.New("error when setting new password"),
+			mock: func() {
+				screenPassword()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().InsertPasswordHistory(gomock.Any(), mockTx, "profile-id-1", "hashed-password-1").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().PrunePasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), gomock.Any()).Return(errors.New("error when revoking session"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:         mockProfileRepository,
+				passwordHistoryRepository: mockPasswordHistoryRepository,
+				authhelper:                mockHelper,
+				validatorHelper:           mockValidatorHelper,
+				breachedPasswordChecker:   mockBreachedPasswordChecker,
+				authService:               mockAuthService,
+				passwordHistorySize:       5,
+			}
+			err := p.SetNewPassword(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_ForcePasswordChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	request := entity.ForcePasswordChangeRequest{
+		PhoneNumber: "+62345",
+	}
+	profile := entity.UserProfile{
+		Id:          "profile-id-1",
+		PhoneNumber: "+62345",
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success force password change",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(profile, nil)
+				mockProfileRepository.EXPECT().SetMustChangePassword(gomock.Any(), nil, "profile-id-1", true).Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), entity.RevokeAllSessionsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error when flag profile",
+			wantErr: errors.New("error when forcing password change"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(profile, nil)
+				mockProfileRepository.EXPECT().SetMustChangePassword(gomock.Any(), nil, "profile-id-1", true).Return(errors.New("error update"))
+			},
+		},
+		{
+			name:    "error when revoke sessions",
+			wantErr: errors.New("error when forcing password change"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(profile, nil)
+				mockProfileRepository.EXPECT().SetMustChangePassword(gomock.Any(), nil, "profile-id-1", true).Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), gomock.Any()).Return(errors.New("error when revoking session"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository: mockProfileRepository,
+				authService:       mockAuthService,
+			}
+			err := p.ForcePasswordChange(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// checkPasswordHistory rejects a new password matching the current password
+// of the profile or one of the previous ones still kept. Hashes only verify,
+// so every one of them is tried in turn.
+func (p profileService) checkPasswordHistory(ctx context.Context, tx *sqlx.Tx, profile entity.UserProfile, newPassword string) error {
+	hashedPasswords := []string{profile.Password}
+
+	if p.passwordHistorySize > 0 {
+		history, err := p.passwordHistoryRepository.GetPasswordHistory(ctx, tx, profile.Id, p.passwordHistorySize)
+		if err != nil {
+			return err
+		}
+
+		for _, previous := range history {
+			hashedPasswords = append(hashedPasswords, previous.Password)
+		}
+	}
+
+	for _, hashedPassword := range hashedPasswords {
+		err := p.authhelper.VerifyPassword(ctx, newPassword, hashedPassword)
+		if err == nil {
+			return error_list.ErrPasswordReused
+		}
+
+		if err != error_list.ErrPasswordNotMatch {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replacePassword sets the new password of the profile and moves the one it
+// replaces into the history, pruning what no longer fits.
+func (p profileService) replacePassword(ctx context.Context, tx *sqlx.Tx, profile entity.UserProfile, hashedPassword string) error {
+	err := p.profileRepository.UpdatePasswordById(ctx, tx, profile.Id, hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	if p.passwordHistorySize <= 0 {
+		return nil
+	}
+
+	err = p.passwordHistoryRepository.InsertPasswordHistory(ctx, tx, profile.Id, profile.Password)
+	if err != nil {
+		return err
+	}
+
+	return p.passwordHistoryRepository.PrunePasswordHistory(ctx, tx, profile.Id, p.passwordHistorySize)
+}
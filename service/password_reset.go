@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const passwordResetMessageFormat = "Your sawitpro password reset code is %s. It expires in %d minutes, do not share it with anyone."
+
+func (p profileService) RequestPasswordReset(ctx context.Context, request entity.RequestPasswordResetRequest) error {
+	profile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, nil, request.PhoneNumber)
+	if err != nil {
+		return error_list.ErrRequestPasswordReset
+	}
+
+	// answer the same whether or not the number is registered, so the
+	// endpoint cannot be used to find out who has an account
+	if profile.Id == "" || profile.PhoneVerifiedAt == nil {
+		return nil
+	}
+
+	err = p.issueOneTimeCode(ctx, profile, constant.OneTimeCodePurposePasswordReset, passwordResetMessageFormat)
+	if err != nil {
+		return error_list.ErrRequestPasswordReset
+	}
+
+	return nil
+}
+
+func (p profileService) ConfirmPasswordReset(ctx context.Context, request entity.ConfirmPasswordResetRequest) error {
+	profile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, nil, request.PhoneNumber)
+	if err != nil {
+		return error_list.ErrResetPassword
+	}
+
+	if profile.Id == "" || profile.PhoneVerifiedAt == nil {
+		return error_list.ErrInvalidOneTimeCode
+	}
+
+	// rejected before the code is redeemed, so picking another password does
+	// not cost the owner an attempt
+	err = p.validatorHelper.ValidatePassword(request.NewPassword, profile.FullName, profile.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	breached, err := p.breachedPasswordChecker.IsBreached(ctx, request.NewPassword)
+	if err != nil {
+		return error_list.ErrResetPassword
+	}
+
+	if breached {
+		return error_list.ErrBreachedPassword
+	}
+
+	var codeErr error
+
+	err = p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		codeErr, err = p.redeemOneTimeCode(ctx, tx, profile.Id, constant.OneTimeCodePurposePasswordReset, request.Code)
+		if err != nil {
+			return error_list.ErrResetPassword
+		}
+
+		// commit the failed attempt, the code error is returned below
+		if codeErr != nil {
+			return nil
+		}
+
+		// only checked once the code is redeemed, so the endpoint cannot be
+		// used to guess passwords of the profile. A reused one rolls back, the
+		// code is left for the owner to pick another password with.
+		err = p.checkPasswordHistory(ctx, tx, profile, request.NewPassword)
+		if err != nil {
+			if err == error_list.ErrPasswordReused {
+				return err
+			}
+			return error_list.ErrResetPassword
+		}
+
+		hashedPassword, err := p.authhelper.HashPassword(ctx, request.NewPassword)
+		if err != nil {
+			return error_list.ErrResetPassword
+		}
+
+		err = p.replacePassword(ctx, tx, profile, hashedPassword)
+		if err != nil {
+			return error_list.ErrResetPassword
+		}
+
+		// the owner proved themselves, lift any lock from guesses at the old password
+		err = p.profileRepository.ResetFailedLoginCount(ctx, tx, profile.Id)
+		if err != nil {
+			return error_list.ErrResetPassword
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if codeErr != nil {
+		return codeErr
+	}
+
+	// the old password may be what leaked, so nobody stays signed in with it
+	err = p.authService.RevokeAllSessions(ctx, entity.RevokeAllSessionsRequest{
+		ProfileId: profile.Id,
+	})
+	if err != nil {
+		return error_list.ErrResetPassword
+	}
+
+	return nil
+}
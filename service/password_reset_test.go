@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileService_RequestPasswordReset(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockSMSSender := mocks.NewMockSMSSenderInterface(ctrl)
+
+	request := entity.RequestPasswordResetRequest{
+		PhoneNumber: "+62812345678",
+	}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := entity.UserProfile{
+		Id:              "profile-id-1",
+		FullName:        "jonathan",
+		PhoneNumber:     "+62812345678",
+		PhoneVerifiedAt: &verifiedAt,
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success request password reset",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, code entity.OneTimeCode) (string, error) {
+						assert.Equal(t, "profile-id-1", code.ProfileId)
+						assert.Equal(t, "password_reset", code.Purpose)
+						assert.Equal(t, "code-hash", code.CodeHash)
+						assert.WithinDuration(t, time.Now().Add(10*time.Minute), code.ExpiresAt, time.Minute)
+						return "code-id-1", nil
+					},
+				)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62812345678",
+					"Your sawitpro password reset code is 012345. It expires in 10 minutes, do not share it with anyone.").Return(nil)
+			},
+		},
+		{
+			name:    "unknown phone number is not revealed",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "unverified profile is not revealed",
+			wantErr: nil,
+			mock: func() {
+				unverifiedProfile := profile
+				unverifiedProfile.PhoneVerifiedAt = nil
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(unverifiedProfile, nil)
+			},
+		},
+		{
+			name:    "error when get profile",
+			wantErr: errors.New("error when requesting password reset"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(entity.UserProfile{}, errors.New("error select"))
+			},
+		},
+		{
+			name:    "error when insert one time code",
+			wantErr: errors.New("error when requesting password reset"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("", errors.New("error insert"))
+			},
+		},
+		{
+			name:    "error when send sms",
+			wantErr: errors.New("error when requesting password reset"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62812345678", gomock.Any()).Return(errors.New("error send"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			}
+			err := p.RequestPasswordReset(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_ConfirmPasswordReset(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+	mockBreachedPasswordChecker := mocks.NewMockBreachedPasswordCheckerInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockPasswordHistoryRepository := mocks.NewMockPasswordHistoryRepositoryInterface(ctrl)
+
+	request := entity.ConfirmPasswordResetRequest{
+		PhoneNumber: "+62812345678",
+		Code:        "012345",
+		NewPassword: "67890B!",
+	}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := entity.UserProfile{
+		Id:              "profile-id-1",
+		FullName:        "jonathan",
+		PhoneNumber:     "+62812345678",
+		Password:        "hashed-password-1",
+		PhoneVerifiedAt: &verifiedAt,
+	}
+	activeCode := entity.OneTimeCode{
+		Id:        "code-id-1",
+		ProfileId: "profile-id-1",
+		Purpose:   "password_reset",
+		CodeHash:  "code-hash",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+		Attempts:  1,
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+	checkPasswordHistory := func() {
+		mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil, nil)
+		mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success confirm password reset",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().InsertPasswordHistory(gomock.Any(), mockTx, "profile-id-1", "hashed-password-1").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().PrunePasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil)
+				mockProfileRepository.EXPECT().ResetFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), entity.RevokeAllSessionsRequest{
+					ProfileId: "profile-id-1",
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "error unknown phone number",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error unverified profile",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				unverifiedProfile := profile
+				unverifiedProfile.PhoneVerifiedAt = nil
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(unverifiedProfile, nil)
+			},
+		},
+		{
+			name: "error password breaks the policy",
+			wantErr: error_list.PasswordPolicyError{
+				Violations: []string{"must not contain your name or phone number"},
+			},
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(error_list.PasswordPolicyError{
+					Violations: []string{"must not contain your name or phone number"},
+				})
+			},
+		},
+		{
+			name:    "error breached password",
+			wantErr: errors.New("error password has appeared in a data breach, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(true, nil)
+			},
+		},
+		{
+			name:    "error when screen password",
+			wantErr: errors.New("error when resetting password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, errors.New("error read"))
+			},
+		},
+		{
+			name:    "error password used recently",
+			wantErr: errors.New("error password was used recently, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(nil)
+			},
+		},
+		{
+			name:    "error wrong code with a reused password tells nothing of the password",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("other-code-hash")
+				mockOneTimeCodeRepository.EXPECT().IncreaseOneTimeCodeAttempts(gomock.Any(), mockTx, "code-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error no active code",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(entity.OneTimeCode{}, nil)
+			},
+		},
+		{
+			name:    "error expired code",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				expiredCode := activeCode
+				expiredCode.ExpiresAt = time.Now().Add(-time.Minute)
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(expiredCode, nil)
+			},
+		},
+		{
+			name:    "error too many attempts",
+			wantErr: errors.New("error too many attempts for this code"),
+			mock: func() {
+				exhaustedCode := activeCode
+				exhaustedCode.Attempts = 5
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(exhaustedCode, nil)
+			},
+		},
+		{
+			name:    "error wrong code counts the attempt",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						err := handleFunc(mockTx)
+						assert.NoError(t, err)
+						return err
+					},
+				)
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("other-code-hash")
+				mockOneTimeCodeRepository.EXPECT().IncreaseOneTimeCodeAttempts(gomock.Any(), mockTx, "code-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error when get active code",
+			wantErr: errors.New("error when resetting password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(entity.OneTimeCode{}, errors.New("error select"))
+			},
+		},
+		{
+			name:    "error when update password",
+			wantErr: errors.New("error when resetting password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(errors.New("error update"))
+			},
+		},
+		{
+			name:    "error when revoke all sessions",
+			wantErr: errors.New("error when resetting password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62812345678").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "password_reset").Return(nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().InsertPasswordHistory(gomock.Any(), mockTx, "profile-id-1", "hashed-password-1").Return(nil)
+				mockPasswordHistoryRepository.EXPECT().PrunePasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil)
+				mockProfileRepository.EXPECT().ResetFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockAuthService.EXPECT().RevokeAllSessions(gomock.Any(), gomock.Any()).Return(errors.New("error when revoking session"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:         mockProfileRepository,
+				oneTimeCodeRepository:     mockOneTimeCodeRepository,
+				authhelper:                mockHelper,
+				validatorHelper:           mockValidatorHelper,
+				breachedPasswordChecker:   mockBreachedPasswordChecker,
+				authService:               mockAuthService,
+				passwordHistoryRepository: mockPasswordHistoryRepository,
+				passwordHistorySize:       5,
+			}
+			err := p.ConfirmPasswordReset(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_PruneOneTimeCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockOneTimeCodeRepository.EXPECT().DeleteExpiredOneTimeCodes(gomock.Any(), nil, gomock.Any()).Return(int64(3), nil)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning one time codes"),
+			mock: func() {
+				mockOneTimeCodeRepository.EXPECT().DeleteExpiredOneTimeCodes(gomock.Any(), nil, gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+			}
+			err := p.PruneOneTimeCodes(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"strings"
+	"time"
+)
+
+// CreatePersonalAccessToken returns the token only this once, only its hash
+// is stored.
+func (a authService) CreatePersonalAccessToken(ctx context.Context, request entity.CreatePersonalAccessTokenRequest) (entity.CreatePersonalAccessTokenResponse, error) {
+	var res = entity.CreatePersonalAccessTokenResponse{}
+
+	// as much randomness as a refresh token, the prefix tells it apart from a JWT
+	secret, err := a.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return res, error_list.ErrCreatePersonalAccessToken
+	}
+	token := constant.PersonalAccessTokenPrefix + secret
+
+	personalAccessToken := entity.PersonalAccessToken{
+		ProfileId: request.ProfileId,
+		Name:      request.Name,
+		TokenHash: a.authhelper.HashToken(ctx, token),
+		Scopes:    strings.Join(uniqueStrings(request.Scopes), " "),
+		CreatedAt: time.Now().UTC(),
+	}
+	if request.ExpiresAt != nil {
+		expiresAt := request.ExpiresAt.UTC()
+		personalAccessToken.ExpiresAt = &expiresAt
+	}
+
+	personalAccessToken.Id, err = a.personalAccessTokenRepository.InsertPersonalAccessToken(ctx, nil, personalAccessToken)
+	if err != nil {
+		return res, error_list.ErrCreatePersonalAccessToken
+	}
+
+	res = entity.CreatePersonalAccessTokenResponse{
+		PersonalAccessToken: personalAccessToken,
+		Token:               token,
+	}
+
+	return res, nil
+}
+
+func (a authService) ListPersonalAccessTokens(ctx context.Context, request entity.ListPersonalAccessTokensRequest) (entity.ListPersonalAccessTokensResponse, error) {
+	var res = entity.ListPersonalAccessTokensResponse{}
+
+	personalAccessTokens, err := a.personalAccessTokenRepository.GetPersonalAccessTokensByProfileId(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrListPersonalAccessTokens
+	}
+
+	res = entity.ListPersonalAccessTokensResponse{
+		PersonalAccessTokens: personalAccessTokens,
+	}
+
+	return res, nil
+}
+
+func (a authService) RevokePersonalAccessToken(ctx context.Context, request entity.RevokePersonalAccessTokenRequest) error {
+	deleted, err := a.personalAccessTokenRepository.DeletePersonalAccessToken(ctx, nil, request.ProfileId, request.PersonalAccessTokenId)
+	if err != nil {
+		return error_list.ErrRevokePersonalAccessToken
+	}
+
+	if !deleted {
+		return error_list.ErrPersonalAccessTokenNotFound
+	}
+
+	return nil
+}
+
+// authenticatePersonalAccessToken returns the claims a personal access token
+// stands for. They carry no session, and only the scopes of the token. A
+// token that never expires has a zero ExpiresAt.
+func (a authService) authenticatePersonalAccessToken(ctx context.Context, token string) (entity.TokenClaims, error) {
+	tokenHash := a.authhelper.HashToken(ctx, token)
+
+	personalAccessToken, err := a.personalAccessTokenRepository.GetPersonalAccessTokenByHash(ctx, nil, tokenHash)
+	if err != nil {
+		return entity.TokenClaims{}, error_list.ErrAuthenticate
+	}
+
+	now := time.Now().UTC()
+	if personalAccessToken.Id == "" || (personalAccessToken.ExpiresAt != nil && now.After(*personalAccessToken.ExpiresAt)) {
+		return entity.TokenClaims{}, error_list.ErrInvalidPersonalAccessToken
+	}
+
+	if personalAccessToken.LastUsedAt == nil || now.Sub(*personalAccessToken.LastUsedAt) > constant.PersonalAccessTokenLastUsedResolution {
+		// last used is informational, failing to record it must not fail the request
+		_ = a.personalAccessTokenRepository.TouchPersonalAccessToken(ctx, nil, personalAccessToken.Id, now)
+	}
+
+	claims := entity.TokenClaims{
+		ProfileId:             personalAccessToken.ProfileId,
+		PersonalAccessTokenId: personalAccessToken.Id,
+		Scopes:                strings.Fields(personalAccessToken.Scopes),
+	}
+	if personalAccessToken.ExpiresAt != nil {
+		claims.ExpiresAt = *personalAccessToken.ExpiresAt
+	}
+
+	return claims, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+	for _, value := range values {
+		if !containsString(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authService_CreatePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersonalAccessTokenRepository := mocks.NewMockPersonalAccessTokenRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	expiresAt := time.Date(2030, 1, 1, 7, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	expiresAtUTC := expiresAt.UTC()
+
+	tests := []struct {
+		name    string
+		want    entity.PersonalAccessToken
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success create personal access token",
+			want: entity.PersonalAccessToken{
+				Id:        "token-id-1",
+				ProfileId: "profile-id-1",
+				Name:      "backup script",
+				TokenHash: "token-hash-1",
+				Scopes:    "profile:read profile:write",
+				ExpiresAt: &expiresAtUTC,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("secret-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().InsertPersonalAccessToken(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, token entity.PersonalAccessToken) (string, error) {
+						assert.Equal(t, "token-hash-1", token.TokenHash)
+						assert.Equal(t, "profile:read profile:write", token.Scopes)
+						assert.Equal(t, &expiresAtUTC, token.ExpiresAt)
+						return "token-id-1", nil
+					},
+				)
+			},
+		},
+		{
+			name:    "error generate personal access token",
+			want:    entity.PersonalAccessToken{},
+			wantErr: errors.New("error when creating personal access token"),
+			mock: func() {
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("", errors.New("error rand"))
+			},
+		},
+		{
+			name:    "error insert personal access token",
+			want:    entity.PersonalAccessToken{},
+			wantErr: errors.New("error when creating personal access token"),
+			mock: func() {
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("secret-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().InsertPersonalAccessToken(gomock.Any(), nil, gomock.Any()).Return("", errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				personalAccessTokenRepository: mockPersonalAccessTokenRepository,
+				authhelper:                    mockHelper,
+			}
+			got, err := a.CreatePersonalAccessToken(context.TODO(), entity.CreatePersonalAccessTokenRequest{
+				ProfileId: "profile-id-1",
+				Name:      "backup script",
+				Scopes:    []string{"profile:read", "profile:write", "profile:read"},
+				ExpiresAt: &expiresAt,
+			})
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr != nil {
+				assert.Equal(t, entity.CreatePersonalAccessTokenResponse{}, got)
+				return
+			}
+
+			assert.Equal(t, "pat_secret-1", got.Token)
+			assert.False(t, got.PersonalAccessToken.CreatedAt.IsZero())
+			got.PersonalAccessToken.CreatedAt = time.Time{}
+			assert.Equal(t, tt.want, got.PersonalAccessToken)
+		})
+	}
+}
+
+func Test_authService_ListPersonalAccessTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersonalAccessTokenRepository := mocks.NewMockPersonalAccessTokenRepositoryInterface(ctrl)
+
+	personalAccessTokens := []entity.PersonalAccessToken{
+		{
+			Id:        "token-id-1",
+			ProfileId: "profile-id-1",
+			Name:      "backup script",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.ListPersonalAccessTokensResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success list personal access tokens",
+			want: entity.ListPersonalAccessTokensResponse{
+				PersonalAccessTokens: personalAccessTokens,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokensByProfileId(gomock.Any(), nil, "profile-id-1").Return(personalAccessTokens, nil)
+			},
+		},
+		{
+			name:    "error list personal access tokens",
+			want:    entity.ListPersonalAccessTokensResponse{},
+			wantErr: errors.New("error when listing personal access tokens"),
+			mock: func() {
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokensByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				personalAccessTokenRepository: mockPersonalAccessTokenRepository,
+			}
+			got, err := a.ListPersonalAccessTokens(context.TODO(), entity.ListPersonalAccessTokensRequest{
+				ProfileId: "profile-id-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_RevokePersonalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersonalAccessTokenRepository := mocks.NewMockPersonalAccessTokenRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke personal access token",
+			wantErr: nil,
+			mock: func() {
+				mockPersonalAccessTokenRepository.EXPECT().DeletePersonalAccessToken(gomock.Any(), nil, "profile-id-1", "token-id-1").Return(true, nil)
+			},
+		},
+		{
+			name:    "error personal access token not found",
+			wantErr: errors.New("error personal access token not found"),
+			mock: func() {
+				mockPersonalAccessTokenRepository.EXPECT().DeletePersonalAccessToken(gomock.Any(), nil, "profile-id-1", "token-id-1").Return(false, nil)
+			},
+		},
+		{
+			name:    "error revoke personal access token",
+			wantErr: errors.New("error when revoking personal access token"),
+			mock: func() {
+				mockPersonalAccessTokenRepository.EXPECT().DeletePersonalAccessToken(gomock.Any(), nil, "profile-id-1", "token-id-1").Return(false, errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				personalAccessTokenRepository: mockPersonalAccessTokenRepository,
+			}
+			err := a.RevokePersonalAccessToken(context.TODO(), entity.RevokePersonalAccessTokenRequest{
+				ProfileId:             "profile-id-1",
+				PersonalAccessTokenId: "token-id-1",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_Authenticate_personalAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPersonalAccessTokenRepository := mocks.NewMockPersonalAccessTokenRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	recently := time.Now().UTC()
+	longAgo := time.Now().Add(-time.Hour).UTC()
+	expired := time.Now().Add(-time.Minute).UTC()
+
+	claims := entity.TokenClaims{
+		ProfileId:             "profile-id-1",
+		PersonalAccessTokenId: "token-id-1",
+		Scopes:                []string{"profile:read", "profile:write"},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.TokenClaims
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success authenticate records last used",
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokenByHash(gomock.Any(), nil, "token-hash-1").Return(entity.PersonalAccessToken{
+					Id:         "token-id-1",
+					ProfileId:  "profile-id-1",
+					Scopes:     "profile:read profile:write",
+					LastUsedAt: &longAgo,
+				}, nil)
+				mockPersonalAccessTokenRepository.EXPECT().TouchPersonalAccessToken(gomock.Any(), nil, "token-id-1", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "success authenticate recently used",
+			want:    claims,
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokenByHash(gomock.Any(), nil, "token-hash-1").Return(entity.PersonalAccessToken{
+					Id:         "token-id-1",
+					ProfileId:  "profile-id-1",
+					Scopes:     "profile:read profile:write",
+					LastUsedAt: &recently,
+				}, nil)
+			},
+		},
+		{
+			name:    "error unknown personal access token",
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error invalid personal access token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokenByHash(gomock.Any(), nil, "token-hash-1").Return(entity.PersonalAccessToken{}, nil)
+			},
+		},
+		{
+			name:    "error expired personal access token",
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error invalid personal access token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokenByHash(gomock.Any(), nil, "token-hash-1").Return(entity.PersonalAccessToken{
+					Id:        "token-id-1",
+					ProfileId: "profile-id-1",
+					Scopes:    "profile:read",
+					ExpiresAt: &expired,
+				}, nil)
+			},
+		},
+		{
+			name:    "error get personal access token",
+			want:    entity.TokenClaims{},
+			wantErr: errors.New("error when authenticating token"),
+			mock: func() {
+				mockHelper.EXPECT().HashToken(gomock.Any(), "pat_secret-1").Return("token-hash-1")
+				mockPersonalAccessTokenRepository.EXPECT().GetPersonalAccessTokenByHash(gomock.Any(), nil, "token-hash-1").Return(entity.PersonalAccessToken{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				personalAccessTokenRepository: mockPersonalAccessTokenRepository,
+				authhelper:                    mockHelper,
+			}
+			got, err := a.Authenticate(context.TODO(), entity.AuthenticateRequest{
+				Token: "pat_secret-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const phoneVerificationMessageFormat = "Your sawitpro verification code is %s. It expires in %d minutes, do not share it with anyone."
+
+func (p profileService) VerifyPhone(ctx context.Context, request entity.VerifyPhoneRequest) error {
+	profile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, nil, request.PhoneNumber)
+	if err != nil {
+		return error_list.ErrVerifyPhone
+	}
+
+	if profile.Id == "" || profile.PhoneVerifiedAt != nil || unverifiedProfileExpired(profile) {
+		return error_list.ErrInvalidOneTimeCode
+	}
+
+	var codeErr error
+
+	err = p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		codeErr, err = p.redeemOneTimeCode(ctx, tx, profile.Id, constant.OneTimeCodePurposePhoneVerification, request.Code)
+		if err != nil {
+			return error_list.ErrVerifyPhone
+		}
+
+		// commit the failed attempt, the code error is returned below
+		if codeErr != nil {
+			return nil
+		}
+
+		err = p.profileRepository.MarkPhoneVerified(ctx, tx, profile.Id, time.Now().UTC())
+		if err != nil {
+			return error_list.ErrVerifyPhone
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return codeErr
+}
+
+// ConfirmPhoneChange moves the profile to its pending phone number once the
+// code sent to that number is given back, which verifies it again.
+func (p profileService) ConfirmPhoneChange(ctx context.Context, request entity.ConfirmPhoneChangeRequest) error {
+	profile, err := p.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return error_list.ErrConfirmPhoneChange
+	}
+
+	if profile.Id == "" || profile.PendingPhoneNumber == nil {
+		return error_list.ErrInvalidOneTimeCode
+	}
+
+	var codeErr error
+
+	err = p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		codeErr, err = p.redeemOneTimeCode(ctx, tx, profile.Id, constant.OneTimeCodePurposePhoneChange, request.Code)
+		if err != nil {
+			return error_list.ErrConfirmPhoneChange
+		}
+
+		// commit the failed attempt, the code error is returned below
+		if codeErr != nil {
+			return nil
+		}
+
+		// the number may have been registered since the change was asked
+		existingProfile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, tx, *profile.PendingPhoneNumber)
+		if err != nil {
+			return error_list.ErrConfirmPhoneChange
+		}
+
+		if existingProfile.Id != "" {
+			if !unverifiedProfileExpired(existingProfile) {
+				return error_list.ErrDataConflict
+			}
+
+			err = p.profileRepository.DeleteProfileById(ctx, tx, existingProfile.Id)
+			if err != nil {
+				return error_list.ErrConfirmPhoneChange
+			}
+		}
+
+		confirmed, err := p.profileRepository.ConfirmPendingPhoneNumber(ctx, tx, profile.Id, time.Now().UTC())
+		if err != nil {
+			return error_list.ErrConfirmPhoneChange
+		}
+
+		if !confirmed {
+			codeErr = error_list.ErrInvalidOneTimeCode
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return codeErr
+}
+
+func (p profileService) PruneUnverifiedProfiles(ctx context.Context) error {
+	createdBefore := time.Now().Add(-constant.UnverifiedProfileDuration).UTC()
+
+	_, err := p.profileRepository.DeleteUnverifiedProfiles(ctx, nil, createdBefore)
+	if err != nil {
+		return error_list.ErrPruneUnverifiedProfiles
+	}
+
+	return nil
+}
+
+// unverifiedProfileExpired reports whether profile is a registration whose
+// phone number was not verified in time.
+func unverifiedProfileExpired(profile entity.UserProfile) bool {
+	return profile.PhoneVerifiedAt == nil && time.Since(profile.CreatedAt) > constant.UnverifiedProfileDuration
+}
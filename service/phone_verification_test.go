@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileService_VerifyPhone(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	request := entity.VerifyPhoneRequest{
+		PhoneNumber: "+62812345678",
+		Code:        "012345",
+	}
+	profile := entity.UserProfile{
+		Id:          "profile-id-1",
+		FullName:    "jonathan",
+		PhoneNumber: "+62812345678",
+		CreatedAt:   time.Now().Add(-time.Minute),
+	}
+	activeCode := entity.OneTimeCode{
+		Id:        "code-id-1",
+		ProfileId: "profile-id-1",
+		Purpose:   "phone_verification",
+		CodeHash:  "code-hash",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success verify phone",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "phone_verification").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_verification").Return(nil)
+				mockProfileRepository.EXPECT().MarkPhoneVerified(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "error unknown phone number",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error phone already verified",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				verifiedAt := time.Now()
+				verifiedProfile := profile
+				verifiedProfile.PhoneVerifiedAt = &verifiedAt
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(verifiedProfile, nil)
+			},
+		},
+		{
+			name:    "error registration expired",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				expiredProfile := profile
+				expiredProfile.CreatedAt = time.Now().Add(-time.Hour)
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(expiredProfile, nil)
+			},
+		},
+		{
+			name:    "error wrong code",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "phone_verification").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("other-code-hash")
+				mockOneTimeCodeRepository.EXPECT().IncreaseOneTimeCodeAttempts(gomock.Any(), mockTx, "code-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error when mark phone verified",
+			wantErr: errors.New("error when verifying phone number"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "phone_verification").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_verification").Return(nil)
+				mockProfileRepository.EXPECT().MarkPhoneVerified(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(errors.New("error update"))
+			},
+		},
+		{
+			name:    "error when get profile",
+			wantErr: errors.New("error when verifying phone number"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(entity.UserProfile{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+			}
+			err := p.VerifyPhone(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_ConfirmPhoneChange(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	request := entity.ConfirmPhoneChangeRequest{
+		ProfileId: "profile-id-1",
+		Code:      "012345",
+	}
+	verifiedAt := time.Now().Add(-time.Hour)
+	pendingPhoneNumber := "+62812345679"
+	profile := entity.UserProfile{
+		Id:                 "profile-id-1",
+		FullName:           "jonathan",
+		PhoneNumber:        "+62812345678",
+		PhoneVerifiedAt:    &verifiedAt,
+		PendingPhoneNumber: &pendingPhoneNumber,
+	}
+	activeCode := entity.OneTimeCode{
+		Id:        "code-id-1",
+		ProfileId: "profile-id-1",
+		Purpose:   "phone_change",
+		CodeHash:  "code-hash",
+		ExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+	redeemCode := func() {
+		mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(activeCode, nil)
+		mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+		mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(nil)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success change phone number",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				redeemCode()
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62812345679").Return(entity.UserProfile{}, nil)
+				mockProfileRepository.EXPECT().ConfirmPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, id string, verifiedAt time.Time) (bool, error) {
+						assert.WithinDuration(t, time.Now(), verifiedAt, time.Minute)
+						return true, nil
+					},
+				)
+			},
+		},
+		{
+			name:    "success change phone number of an expired registration",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				redeemCode()
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62812345679").Return(entity.UserProfile{
+					Id:          "profile-id-2",
+					PhoneNumber: "+62812345679",
+					CreatedAt:   time.Now().Add(-time.Hour),
+				}, nil)
+				mockProfileRepository.EXPECT().DeleteProfileById(gomock.Any(), mockTx, "profile-id-2").Return(nil)
+				mockProfileRepository.EXPECT().ConfirmPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:    "error number registered since the change was asked",
+			wantErr: errors.New("error there existing data conficted with new data"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				redeemCode()
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62812345679").Return(entity.UserProfile{
+					Id:              "profile-id-2",
+					PhoneNumber:     "+62812345679",
+					CreatedAt:       verifiedAt,
+					PhoneVerifiedAt: &verifiedAt,
+				}, nil)
+			},
+		},
+		{
+			name:    "error no pending phone number",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				unchangedProfile := profile
+				unchangedProfile.PendingPhoneNumber = nil
+
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(unchangedProfile, nil)
+			},
+		},
+		{
+			name:    "error wrong code",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				mockOneTimeCodeRepository.EXPECT().GetActiveOneTimeCode(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(activeCode, nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("other-code-hash")
+				mockOneTimeCodeRepository.EXPECT().IncreaseOneTimeCodeAttempts(gomock.Any(), mockTx, "code-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error pending phone number gone meanwhile",
+			wantErr: errors.New("error invalid or expired code"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				redeemCode()
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62812345679").Return(entity.UserProfile{}, nil)
+				mockProfileRepository.EXPECT().ConfirmPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name:    "error when confirm pending phone number",
+			wantErr: errors.New("error when confirming phone number change"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				runWithTransaction()
+				redeemCode()
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62812345679").Return(entity.UserProfile{}, nil)
+				mockProfileRepository.EXPECT().ConfirmPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(false, errors.New("error update"))
+			},
+		},
+		{
+			name:    "error when get profile",
+			wantErr: errors.New("error when confirming phone number change"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+			}
+			err := p.ConfirmPhoneChange(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_PruneUnverifiedProfiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().DeleteUnverifiedProfiles(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, createdBefore time.Time) (int64, error) {
+						assert.WithinDuration(t, time.Now().Add(-10*time.Minute), createdBefore, time.Minute)
+						return 1, nil
+					},
+				)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning unverified profiles"),
+			mock: func() {
+				mockProfileRepository.EXPECT().DeleteUnverifiedProfiles(gomock.Any(), nil, gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository: mockProfileRepository,
+			}
+			err := p.PruneUnverifiedProfiles(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
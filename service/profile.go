@@ -2,34 +2,105 @@ package service
 
 import (
 	"context"
+	"sawitpro/constant"
 	"sawitpro/entity"
 	"sawitpro/error_list"
 	"sawitpro/helper"
 	"sawitpro/repository"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type profileService struct {
-	profileRepository repository.UserProfileRepositoryInterface
-	authhelper        helper.AuthHelperInterface
+	profileRepository             repository.UserProfileRepositoryInterface
+	oneTimeCodeRepository         repository.OneTimeCodeRepositoryInterface
+	recoveryCodeRepository        repository.RecoveryCodeRepositoryInterface
+	totpCredentialRepository      repository.TOTPCredentialRepositoryInterface
+	mfaChallengeRepository        repository.MFAChallengeRepositoryInterface
+	passwordHistoryRepository     repository.PasswordHistoryRepositoryInterface
+	sessionRepository             repository.SessionRepositoryInterface
+	refreshTokenRepository        repository.RefreshTokenRepositoryInterface
+	personalAccessTokenRepository repository.PersonalAccessTokenRepositoryInterface
+	oauthGrantRepository          repository.OAuthGrantRepositoryInterface
+	authhelper                    helper.AuthHelperInterface
+	totpHelper                    helper.TOTPHelperInterface
+	smsSender                     helper.SMSSenderInterface
+	validatorHelper               helper.ValidatorHelperInterface
+	breachedPasswordChecker       helper.BreachedPasswordCheckerInterface
+	authService                   AuthServiceInterface
+	loginLockoutPolicy            LoginLockoutPolicy
+	passwordHistorySize           int
+	passwordMaxAge                time.Duration
 }
 
 type ProfileServiceDeps struct {
-	ProfileRepository repository.UserProfileRepositoryInterface
-	Authhelper        helper.AuthHelperInterface
+	ProfileRepository             repository.UserProfileRepositoryInterface
+	OneTimeCodeRepository         repository.OneTimeCodeRepositoryInterface
+	RecoveryCodeRepository        repository.RecoveryCodeRepositoryInterface
+	TOTPCredentialRepository      repository.TOTPCredentialRepositoryInterface
+	MFAChallengeRepository        repository.MFAChallengeRepositoryInterface
+	PasswordHistoryRepository     repository.PasswordHistoryRepositoryInterface
+	SessionRepository             repository.SessionRepositoryInterface
+	RefreshTokenRepository        repository.RefreshTokenRepositoryInterface
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepositoryInterface
+	OAuthGrantRepository          repository.OAuthGrantRepositoryInterface
+	Authhelper                    helper.AuthHelperInterface
+	TOTPHelper                    helper.TOTPHelperInterface
+	SMSSender                     helper.SMSSenderInterface
+	ValidatorHelper               helper.ValidatorHelperInterface
+	BreachedPasswordChecker       helper.BreachedPasswordCheckerInterface
+	AuthService                   AuthServiceInterface
+	LoginLockoutPolicy            LoginLockoutPolicy
+	// PasswordHistorySize is how many previous passwords cannot be picked
+	// again, the current one never can
+	PasswordHistorySize int
+	// PasswordMaxAge is how long a password lasts before it has to be
+	// replaced on the next login, zero when passwords never expire
+	PasswordMaxAge time.Duration
 }
 
 func NewProfileService(deps ProfileServiceDeps) profileService {
 	return profileService{
-		profileRepository: deps.ProfileRepository,
-		authhelper:        deps.Authhelper,
+		profileRepository:             deps.ProfileRepository,
+		oneTimeCodeRepository:         deps.OneTimeCodeRepository,
+		recoveryCodeRepository:        deps.RecoveryCodeRepository,
+		totpCredentialRepository:      deps.TOTPCredentialRepository,
+		mfaChallengeRepository:        deps.MFAChallengeRepository,
+		passwordHistoryRepository:     deps.PasswordHistoryRepository,
+		sessionRepository:             deps.SessionRepository,
+		refreshTokenRepository:        deps.RefreshTokenRepository,
+		personalAccessTokenRepository: deps.PersonalAccessTokenRepository,
+		oauthGrantRepository:          deps.OAuthGrantRepository,
+		authhelper:                    deps.Authhelper,
+		totpHelper:                    deps.TOTPHelper,
+		smsSender:                     deps.SMSSender,
+		validatorHelper:               deps.ValidatorHelper,
+		breachedPasswordChecker:       deps.BreachedPasswordChecker,
+		authService:                   deps.AuthService,
+		loginLockoutPolicy:            deps.LoginLockoutPolicy,
+		passwordHistorySize:           deps.PasswordHistorySize,
+		passwordMaxAge:                deps.PasswordMaxAge,
 	}
 }
 
 func (p profileService) Register(ctx context.Context, request entity.ProfileRegisterRequest) (entity.ProfileRegisterResponse, error) {
 	var res = entity.ProfileRegisterResponse{}
 
+	err := p.validatorHelper.ValidatePassword(request.Password, request.FullName, request.PhoneNumber)
+	if err != nil {
+		return res, err
+	}
+
+	breached, err := p.breachedPasswordChecker.IsBreached(ctx, request.Password)
+	if err != nil {
+		return res, error_list.ErrProfileRegister
+	}
+
+	if breached {
+		return res, error_list.ErrBreachedPassword
+	}
+
 	hashedPassword, err := p.authhelper.HashPassword(ctx, request.Password)
 	if err != nil {
 		return res, error_list.ErrProfileRegister
@@ -44,7 +115,17 @@ func (p profileService) Register(ctx context.Context, request entity.ProfileRegi
 		}
 
 		if existingProfile.PhoneNumber == request.PhoneNumber {
-			return error_list.ErrDataConflict
+			if existingProfile.PhoneVerifiedAt != nil {
+				return error_list.ErrDataConflict
+			}
+
+			// nobody proved the number is theirs yet, registering again
+			// replaces the registration and sends a new code, which is how
+			// a lost or expired code is sent again
+			err = p.profileRepository.DeleteProfileById(ctx, tx, existingProfile.Id)
+			if err != nil {
+				return error_list.ErrProfileRegister
+			}
 		}
 
 		profileId, err = p.profileRepository.InsertProfile(ctx, tx, entity.UserProfile{
@@ -56,6 +137,18 @@ func (p profileService) Register(ctx context.Context, request entity.ProfileRegi
 			return error_list.ErrProfileRegister
 		}
 
+		code, err := p.createOneTimeCode(ctx, tx, profileId, constant.OneTimeCodePurposePhoneVerification)
+		if err != nil {
+			return error_list.ErrProfileRegister
+		}
+
+		// sent before committing, so no registration is kept whose code
+		// never reached the phone
+		err = p.sendOneTimeCode(ctx, request.PhoneNumber, phoneVerificationMessageFormat, code)
+		if err != nil {
+			return error_list.ErrProfileRegister
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -82,9 +175,15 @@ func (p profileService) GetProfile(ctx context.Context, request entity.GetProfil
 		return res, error_list.ErrProfileNotFound
 	}
 
+	recoveryCodesRemaining, err := p.recoveryCodeRepository.CountUnusedRecoveryCodes(ctx, nil, profile.Id)
+	if err != nil {
+		return res, error_list.ErrGetProfile
+	}
+
 	res = entity.GetProfileResponse{
-		FullName:    profile.FullName,
-		PhoneNumber: profile.PhoneNumber,
+		FullName:               profile.FullName,
+		PhoneNumber:            profile.PhoneNumber,
+		RecoveryCodesRemaining: recoveryCodesRemaining,
 	}
 
 	return res, nil
@@ -102,15 +201,63 @@ func (p profileService) Login(ctx context.Context, request entity.LoginRequest)
 		return res, error_list.ErrLoginCredential
 	}
 
+	// checked before the password, so guesses against a locked account cost
+	// no hashing and tell nothing
+	if profile.LockedUntil != nil && time.Now().Before(*profile.LockedUntil) {
+		return res, error_list.ErrAccountLocked
+	}
+
+	if request.Password == "" {
+		return p.loginWithRecoveryCode(ctx, profile, request)
+	}
+
 	err = p.authhelper.VerifyPassword(ctx, request.Password, profile.Password)
 	if err != nil {
 		if err == error_list.ErrPasswordNotMatch {
+			err = p.recordFailedLogin(ctx, profile.Id)
+			if err != nil {
+				return res, error_list.ErrLogin
+			}
 			return res, error_list.ErrLoginCredential
 		}
 		return res, error_list.ErrLogin
 	}
 
-	token, err := p.authhelper.GenerateToken(ctx, profile.Id)
+	// the plain password is only at hand here, a hash that fails to upgrade
+	// still verifies and is upgraded on the next login
+	_ = p.rehashPassword(ctx, profile, request.Password)
+
+	if profile.PhoneVerifiedAt == nil {
+		return res, error_list.ErrPhoneNotVerified
+	}
+
+	totpCredential, err := p.totpCredentialRepository.GetTOTPCredential(ctx, nil, profile.Id)
+	if err != nil {
+		return res, error_list.ErrLogin
+	}
+
+	if totpCredential.Secret != nil {
+		// the password alone is not enough, the login is completed by
+		// VerifyLoginMFA
+		return p.startMFAChallenge(ctx, profile.Id)
+	}
+
+	return p.completeLogin(ctx, profile, entity.IssueTokenRequest{
+		ProfileId:  profile.Id,
+		DeviceName: request.DeviceName,
+		UserAgent:  request.UserAgent,
+		IpAddress:  request.IpAddress,
+	})
+}
+
+// completeLogin issues the tokens of a login whose every factor has been
+// checked and settles the login counters of the profile.
+func (p profileService) completeLogin(ctx context.Context, profile entity.UserProfile, request entity.IssueTokenRequest) (entity.LoginResponse, error) {
+	var res = entity.LoginResponse{}
+
+	request.PasswordChangeRequired = p.passwordChangeRequired(profile, time.Now())
+
+	token, err := p.authService.IssueToken(ctx, request)
 	if err != nil {
 		return res, error_list.ErrLogin
 	}
@@ -122,6 +269,13 @@ func (p profileService) Login(ctx context.Context, request entity.LoginRequest)
 			return error_list.ErrLogin
 		}
 
+		if profile.FailedLoginCount > 0 || profile.LockedUntil != nil {
+			err = p.profileRepository.ResetFailedLoginCount(ctx, tx, profile.Id)
+			if err != nil {
+				return error_list.ErrLogin
+			}
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -129,31 +283,84 @@ func (p profileService) Login(ctx context.Context, request entity.LoginRequest)
 	}
 
 	res = entity.LoginResponse{
-		Token: token,
+		Token:                  token.Token,
+		RefreshToken:           token.RefreshToken,
+		ExpiresIn:              token.ExpiresIn,
+		PasswordChangeRequired: request.PasswordChangeRequired,
 	}
 
 	return res, nil
 }
 
+// rehashPassword hashes a verified password again when its stored hash is of
+// an older algorithm or weaker parameters than the ones configured.
+func (p profileService) rehashPassword(ctx context.Context, profile entity.UserProfile, password string) error {
+	if !p.authhelper.PasswordNeedsRehash(ctx, profile.Password) {
+		return nil
+	}
+
+	hashedPassword, err := p.authhelper.HashPassword(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	return p.profileRepository.RehashPasswordById(ctx, nil, profile.Id, profile.Password, hashedPassword)
+}
+
+// UpdateProfile saves the full name right away. A new phone number is only
+// kept pending and a code is sent to it, the profile moves to the number once
+// ConfirmPhoneChange is given that code.
 func (p profileService) UpdateProfile(ctx context.Context, request entity.UpdateProfileRequest) error {
 	err := p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
-		existingProfile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, tx, request.PhoneNumber)
+		profile, err := p.profileRepository.GetProfileById(ctx, tx, request.Id)
 		if err != nil {
 			return error_list.ErrUpdateProfile
 		}
 
-		if existingProfile.PhoneNumber == request.PhoneNumber {
-			return error_list.ErrDataConflict
+		if profile.Id == "" {
+			return error_list.ErrProfileNotFound
 		}
 
-		err = p.profileRepository.UpdateProfileById(ctx, tx, request.Id, entity.UserProfile{
-			FullName:    request.FullName,
-			PhoneNumber: request.PhoneNumber,
+		phoneNumberChanged := request.PhoneNumber != profile.PhoneNumber
+		if phoneNumberChanged {
+			existingProfile, err := p.profileRepository.GetProfileByPhoneNumber(ctx, tx, request.PhoneNumber)
+			if err != nil {
+				return error_list.ErrUpdateProfile
+			}
+
+			if existingProfile.PhoneNumber == request.PhoneNumber && !unverifiedProfileExpired(existingProfile) {
+				return error_list.ErrDataConflict
+			}
+		}
+
+		err = p.profileRepository.UpdateProfileById(ctx, tx, profile.Id, entity.UserProfile{
+			FullName: request.FullName,
 		})
 		if err != nil {
 			return error_list.ErrUpdateProfile
 		}
 
+		if !phoneNumberChanged {
+			return nil
+		}
+
+		err = p.profileRepository.SetPendingPhoneNumber(ctx, tx, profile.Id, request.PhoneNumber)
+		if err != nil {
+			return error_list.ErrUpdateProfile
+		}
+
+		code, err := p.createOneTimeCode(ctx, tx, profile.Id, constant.OneTimeCodePurposePhoneChange)
+		if err != nil {
+			return error_list.ErrUpdateProfile
+		}
+
+		// sent before committing, so no number is left pending whose code
+		// never reached the phone
+		err = p.sendOneTimeCode(ctx, request.PhoneNumber, phoneVerificationMessageFormat, code)
+		if err != nil {
+			return error_list.ErrUpdateProfile
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -162,3 +369,93 @@ func (p profileService) UpdateProfile(ctx context.Context, request entity.Update
 
 	return nil
 }
+
+func (p profileService) ChangePassword(ctx context.Context, request entity.ChangePasswordRequest) error {
+	profile, err := p.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return error_list.ErrChangePassword
+	}
+
+	if profile.Id == "" {
+		return error_list.ErrProfileNotFound
+	}
+
+	err = p.verifyCurrentPassword(ctx, profile, request.CurrentPassword)
+	if err != nil {
+		if err == error_list.ErrCurrentPasswordNotMatch || err == error_list.ErrAccountLocked {
+			return err
+		}
+		return error_list.ErrChangePassword
+	}
+
+	err = p.validatorHelper.ValidatePassword(request.NewPassword, profile.FullName, profile.PhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	breached, err := p.breachedPasswordChecker.IsBreached(ctx, request.NewPassword)
+	if err != nil {
+		return error_list.ErrChangePassword
+	}
+
+	if breached {
+		return error_list.ErrBreachedPassword
+	}
+
+	err = p.checkPasswordHistory(ctx, nil, profile, request.NewPassword)
+	if err != nil {
+		if err == error_list.ErrPasswordReused {
+			return err
+		}
+		return error_list.ErrChangePassword
+	}
+
+	hashedPassword, err := p.authhelper.HashPassword(ctx, request.NewPassword)
+	if err != nil {
+		return error_list.ErrChangePassword
+	}
+
+	return p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := p.replacePassword(ctx, tx, profile, hashedPassword)
+		if err != nil {
+			return error_list.ErrChangePassword
+		}
+
+		// whoever else held the old password may still be signed in elsewhere
+		// or hold a token made with it, only the session that made the change
+		// stays valid
+		err = p.revokeOtherCredentials(ctx, tx, profile.Id, request.SessionId)
+		if err != nil {
+			return error_list.ErrChangePassword
+		}
+
+		return nil
+	})
+}
+
+// revokeOtherCredentials ends every session of the profile but the current
+// one, and takes back its personal access tokens and the grants of its oauth
+// clients, with the refresh tokens issued under them.
+func (p profileService) revokeOtherCredentials(ctx context.Context, tx *sqlx.Tx, profileId string, currentSessionId string) error {
+	sessionIds, err := p.sessionRepository.DeleteOtherSessions(ctx, tx, profileId, currentSessionId)
+	if err != nil {
+		return err
+	}
+
+	grantIds, err := p.oauthGrantRepository.DeleteOAuthGrantsByProfileId(ctx, tx, profileId)
+	if err != nil {
+		return err
+	}
+
+	// sessions and grants both name the refresh token family issued under them
+	for _, familyId := range append(sessionIds, grantIds...) {
+		err = p.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, familyId)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = p.personalAccessTokenRepository.DeletePersonalAccessTokensByProfileId(ctx, tx, profileId)
+
+	return err
+}
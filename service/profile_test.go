@@ -9,6 +9,7 @@ import (
 	"sawitpro/mocks"
 	"sawitpro/repository"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/jmoiron/sqlx"
@@ -21,6 +22,16 @@ func TestNewProfileService(t *testing.T) {
 
 	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
 	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockSMSSender := mocks.NewMockSMSSenderInterface(ctrl)
+	mockRecoveryCodeRepository := mocks.NewMockRecoveryCodeRepositoryInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockMFAChallengeRepository := mocks.NewMockMFAChallengeRepositoryInterface(ctrl)
+	mockTOTPHelper := mocks.NewMockTOTPHelperInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+	mockBreachedPasswordChecker := mocks.NewMockBreachedPasswordCheckerInterface(ctrl)
+	mockPasswordHistoryRepository := mocks.NewMockPasswordHistoryRepositoryInterface(ctrl)
 
 	type args struct {
 		deps ProfileServiceDeps
@@ -34,13 +45,41 @@ func TestNewProfileService(t *testing.T) {
 			name: "return profile service instance",
 			args: args{
 				deps: ProfileServiceDeps{
-					ProfileRepository: mockProfileRepository,
-					Authhelper:        mockHelper,
+					ProfileRepository:         mockProfileRepository,
+					OneTimeCodeRepository:     mockOneTimeCodeRepository,
+					RecoveryCodeRepository:    mockRecoveryCodeRepository,
+					TOTPCredentialRepository:  mockTOTPCredentialRepository,
+					MFAChallengeRepository:    mockMFAChallengeRepository,
+					PasswordHistoryRepository: mockPasswordHistoryRepository,
+					Authhelper:                mockHelper,
+					TOTPHelper:                mockTOTPHelper,
+					SMSSender:                 mockSMSSender,
+					ValidatorHelper:           mockValidatorHelper,
+					BreachedPasswordChecker:   mockBreachedPasswordChecker,
+					AuthService:               mockAuthService,
+					LoginLockoutPolicy: LoginLockoutPolicy{
+						LockoutAfter: 10,
+					},
+					PasswordHistorySize: 5,
 				},
 			},
 			want: profileService{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:         mockProfileRepository,
+				oneTimeCodeRepository:     mockOneTimeCodeRepository,
+				recoveryCodeRepository:    mockRecoveryCodeRepository,
+				totpCredentialRepository:  mockTOTPCredentialRepository,
+				mfaChallengeRepository:    mockMFAChallengeRepository,
+				passwordHistoryRepository: mockPasswordHistoryRepository,
+				authhelper:                mockHelper,
+				totpHelper:                mockTOTPHelper,
+				smsSender:                 mockSMSSender,
+				validatorHelper:           mockValidatorHelper,
+				breachedPasswordChecker:   mockBreachedPasswordChecker,
+				authService:               mockAuthService,
+				loginLockoutPolicy: LoginLockoutPolicy{
+					LockoutAfter: 10,
+				},
+				passwordHistorySize: 5,
 			},
 		},
 	}
@@ -54,16 +93,25 @@ func TestNewProfileService(t *testing.T) {
 
 func Test_profileService_Register(t *testing.T) {
 	mockTx := &sqlx.Tx{}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
 	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockSMSSender := mocks.NewMockSMSSenderInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+	mockBreachedPasswordChecker := mocks.NewMockBreachedPasswordCheckerInterface(ctrl)
 
 	type fields struct {
-		profileRepository repository.UserProfileRepositoryInterface
-		authhelper        helper.AuthHelperInterface
+		profileRepository       repository.UserProfileRepositoryInterface
+		oneTimeCodeRepository   repository.OneTimeCodeRepositoryInterface
+		authhelper              helper.AuthHelperInterface
+		smsSender               helper.SMSSenderInterface
+		validatorHelper         helper.ValidatorHelperInterface
+		breachedPasswordChecker helper.BreachedPasswordCheckerInterface
 	}
 	type args struct {
 		ctx     context.Context
@@ -80,8 +128,12 @@ func Test_profileService_Register(t *testing.T) {
 		{
 			name: "success register",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -96,6 +148,8 @@ func Test_profileService_Register(t *testing.T) {
 			},
 			wantErr: nil,
 			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
 				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
 					entity.UserProfile{}, nil,
@@ -105,6 +159,65 @@ func Test_profileService_Register(t *testing.T) {
 					PhoneNumber: "+62345",
 					Password:    "hashedPassword",
 				}).Return("profil-id-1", nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profil-id-1", "phone_verification").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345",
+					"Your sawitpro verification code is 012345. It expires in 10 minutes, do not share it with anyone.").Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+			},
+		},
+		{
+			name: "success register over an expired unverified registration",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want: entity.ProfileRegisterResponse{
+				Id: "profil-id-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{
+						Id:          "profil-id-0",
+						FullName:    "someone",
+						PhoneNumber: "+62345",
+						Password:    "12345",
+						CreatedAt:   time.Now().Add(-time.Hour),
+					}, nil,
+				)
+				mockProfileRepository.EXPECT().DeleteProfileById(gomock.Any(), mockTx, "profil-id-0").Return(nil)
+				mockProfileRepository.EXPECT().InsertProfile(gomock.Any(), mockTx, entity.UserProfile{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "hashedPassword",
+				}).Return("profil-id-1", nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profil-id-1", "phone_verification").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345", gomock.Any()).Return(nil)
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
@@ -115,8 +228,12 @@ func Test_profileService_Register(t *testing.T) {
 		{
 			name: "error when insert profile",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -129,6 +246,8 @@ func Test_profileService_Register(t *testing.T) {
 			want:    entity.ProfileRegisterResponse{},
 			wantErr: errors.New("error when register a new profile"),
 			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
 				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
 					entity.UserProfile{}, nil,
@@ -145,11 +264,59 @@ func Test_profileService_Register(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "error when send verification code",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.ProfileRegisterResponse{},
+			wantErr: errors.New("error when register a new profile"),
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{}, nil,
+				)
+				mockProfileRepository.EXPECT().InsertProfile(gomock.Any(), mockTx, entity.UserProfile{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "hashedPassword",
+				}).Return("profil-id-1", nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profil-id-1", "phone_verification").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345", gomock.Any()).Return(errors.New("error send"))
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+			},
+		},
 		{
 			name: "error duplicate phone number",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -162,14 +329,70 @@ func Test_profileService_Register(t *testing.T) {
 			want:    entity.ProfileRegisterResponse{},
 			wantErr: errors.New("error there existing data conficted with new data"),
 			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
 				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
 					entity.UserProfile{
-						FullName:    "jonathan",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+			},
+		},
+		{
+			name: "success register over a registration pending verification",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want: entity.ProfileRegisterResponse{
+				Id: "profil-id-1",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{
+						Id:          "profil-id-0",
+						FullName:    "someone",
 						PhoneNumber: "+62345",
 						Password:    "12345",
+						CreatedAt:   time.Now().Add(-time.Minute),
 					}, nil,
 				)
+				mockProfileRepository.EXPECT().DeleteProfileById(gomock.Any(), mockTx, "profil-id-0").Return(nil)
+				mockProfileRepository.EXPECT().InsertProfile(gomock.Any(), mockTx, entity.UserProfile{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "hashedPassword",
+				}).Return("profil-id-1", nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profil-id-1", "phone_verification").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345", gomock.Any()).Return(nil)
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
@@ -180,8 +403,12 @@ func Test_profileService_Register(t *testing.T) {
 		{
 			name: "error when get profile",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -194,6 +421,8 @@ func Test_profileService_Register(t *testing.T) {
 			want:    entity.ProfileRegisterResponse{},
 			wantErr: errors.New("error when register a new profile"),
 			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
 				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("hashedPassword", nil)
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
 					entity.UserProfile{}, errors.New("error get"),
@@ -205,11 +434,93 @@ func Test_profileService_Register(t *testing.T) {
 				)
 			},
 		},
+		{
+			name: "error password breaks the policy",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want: entity.ProfileRegisterResponse{},
+			wantErr: error_list.PasswordPolicyError{
+				Violations: []string{"must be at least 10 characters"},
+			},
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(error_list.PasswordPolicyError{
+					Violations: []string{"must be at least 10 characters"},
+				})
+			},
+		},
+		{
+			name: "error breached password",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.ProfileRegisterResponse{},
+			wantErr: errors.New("error password has appeared in a data breach, choose another one"),
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(true, nil)
+			},
+		},
+		{
+			name: "error when screen password",
+			fields: fields{
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.ProfileRegisterRequest{
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.ProfileRegisterResponse{},
+			wantErr: errors.New("error when register a new profile"),
+			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, errors.New("error read"))
+			},
+		},
 		{
 			name: "error when hashing password",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:       mockProfileRepository,
+				oneTimeCodeRepository:   mockOneTimeCodeRepository,
+				authhelper:              mockHelper,
+				smsSender:               mockSMSSender,
+				validatorHelper:         mockValidatorHelper,
+				breachedPasswordChecker: mockBreachedPasswordChecker,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -222,6 +533,8 @@ func Test_profileService_Register(t *testing.T) {
 			want:    entity.ProfileRegisterResponse{},
 			wantErr: errors.New("error when register a new profile"),
 			mock: func() {
+				mockValidatorHelper.EXPECT().ValidatePassword("12345", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "12345").Return(false, nil)
 				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("", errors.New("error hash password"))
 
 			},
@@ -232,8 +545,12 @@ func Test_profileService_Register(t *testing.T) {
 			tt.mock()
 
 			p := profileService{
-				profileRepository: tt.fields.profileRepository,
-				authhelper:        tt.fields.authhelper,
+				profileRepository:       tt.fields.profileRepository,
+				oneTimeCodeRepository:   tt.fields.oneTimeCodeRepository,
+				authhelper:              tt.fields.authhelper,
+				smsSender:               tt.fields.smsSender,
+				validatorHelper:         tt.fields.validatorHelper,
+				breachedPasswordChecker: tt.fields.breachedPasswordChecker,
 			}
 			got, err := p.Register(tt.args.ctx, tt.args.request)
 			assert.Equal(t, tt.want, got)
@@ -248,6 +565,7 @@ func Test_profileService_GetProfile(t *testing.T) {
 
 	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
 	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockRecoveryCodeRepository := mocks.NewMockRecoveryCodeRepositoryInterface(ctrl)
 
 	type fields struct {
 		profileRepository repository.UserProfileRepositoryInterface
@@ -278,8 +596,9 @@ func Test_profileService_GetProfile(t *testing.T) {
 				},
 			},
 			want: entity.GetProfileResponse{
-				FullName:    "jonathan",
-				PhoneNumber: "+62345",
+				FullName:               "jonathan",
+				PhoneNumber:            "+62345",
+				RecoveryCodesRemaining: 7,
 			},
 			wantErr: nil,
 			mock: func() {
@@ -291,6 +610,32 @@ func Test_profileService_GetProfile(t *testing.T) {
 						Password:    "12345",
 					}, nil,
 				)
+				mockRecoveryCodeRepository.EXPECT().CountUnusedRecoveryCodes(gomock.Any(), nil, "profile-id-1").Return(7, nil)
+			},
+		},
+		{
+			name: "error count recovery codes",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.GetProfileRequest{
+					ProfileId: "profile-id-1",
+				},
+			},
+			want:    entity.GetProfileResponse{},
+			wantErr: errors.New("error when get user profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(
+					entity.UserProfile{
+						Id:          "profile-id-1",
+						FullName:    "jonathan",
+						PhoneNumber: "+62345",
+					}, nil,
+				)
+				mockRecoveryCodeRepository.EXPECT().CountUnusedRecoveryCodes(gomock.Any(), nil, "profile-id-1").Return(0, errors.New("error count"))
 			},
 		},
 		{
@@ -339,8 +684,9 @@ func Test_profileService_GetProfile(t *testing.T) {
 			tt.mock()
 
 			p := profileService{
-				profileRepository: tt.fields.profileRepository,
-				authhelper:        tt.fields.authhelper,
+				profileRepository:      tt.fields.profileRepository,
+				recoveryCodeRepository: mockRecoveryCodeRepository,
+				authhelper:             tt.fields.authhelper,
 			}
 			got, err := p.GetProfile(tt.args.ctx, tt.args.request)
 			assert.Equal(t, tt.want, got)
@@ -351,15 +697,28 @@ func Test_profileService_GetProfile(t *testing.T) {
 
 func Test_profileService_Login(t *testing.T) {
 	mockTx := &sqlx.Tx{}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	loginLockoutPolicy := LoginLockoutPolicy{
+		DelayAfter:      3,
+		BaseDelay:       time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+	}
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
 	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockMFAChallengeRepository := mocks.NewMockMFAChallengeRepositoryInterface(ctrl)
+	totpSecret := "encrypted-secret"
 
 	type fields struct {
 		profileRepository repository.UserProfileRepositoryInterface
 		authhelper        helper.AuthHelperInterface
+		authService       AuthServiceInterface
+		passwordMaxAge    time.Duration
 	}
 	type args struct {
 		ctx     context.Context
@@ -378,6 +737,7 @@ func Test_profileService_Login(t *testing.T) {
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -387,20 +747,31 @@ func Test_profileService_Login(t *testing.T) {
 				},
 			},
 			want: entity.LoginResponse{
-				Token: "token-1",
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
 			},
 			wantErr: nil,
 			mock: func() {
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
 					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
 					}, nil,
 				)
 				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
-				mockHelper.EXPECT().GenerateToken(gomock.Any(), "profile-id-1").Return("token-1", nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
 				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
@@ -410,10 +781,12 @@ func Test_profileService_Login(t *testing.T) {
 			},
 		},
 		{
-			name: "error when increasing counter",
+			name: "success login with expired password",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
+				passwordMaxAge:    90 * 24 * time.Hour,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -422,20 +795,34 @@ func Test_profileService_Login(t *testing.T) {
 					Password:    "12345",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error when try to login"),
+			want: entity.LoginResponse{
+				Token:                  "token-1",
+				ExpiresIn:              900,
+				PasswordChangeRequired: true,
+			},
+			wantErr: nil,
 			mock: func() {
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
 					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
+						Id:                "profile-id-1",
+						FullName:          "jonathan",
+						PhoneNumber:       "+62345",
+						Password:          "12345",
+						PhoneVerifiedAt:   &verifiedAt,
+						PasswordChangedAt: time.Now().Add(-100 * 24 * time.Hour),
 					}, nil,
 				)
 				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
-				mockHelper.EXPECT().GenerateToken(gomock.Any(), "profile-id-1").Return("token-1", nil)
-				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(errors.New("error update"))
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId:              "profile-id-1",
+					PasswordChangeRequired: true,
+				}).Return(entity.IssueTokenResponse{
+					Token:     "token-1",
+					ExpiresIn: 900,
+				}, nil)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
@@ -444,10 +831,11 @@ func Test_profileService_Login(t *testing.T) {
 			},
 		},
 		{
-			name: "error when generate token",
+			name: "success login with password change forced by admin",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
 				ctx: context.TODO(),
@@ -456,149 +844,1036 @@ func Test_profileService_Login(t *testing.T) {
 					Password:    "12345",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error when try to login"),
+			want: entity.LoginResponse{
+				Token:                  "token-1",
+				ExpiresIn:              900,
+				PasswordChangeRequired: true,
+			},
+			wantErr: nil,
 			mock: func() {
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
 					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
+						Id:                 "profile-id-1",
+						FullName:           "jonathan",
+						PhoneNumber:        "+62345",
+						Password:           "12345",
+						PhoneVerifiedAt:    &verifiedAt,
+						MustChangePassword: true,
 					}, nil,
 				)
 				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
-				mockHelper.EXPECT().GenerateToken(gomock.Any(), "profile-id-1").Return("", errors.New("error token"))
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId:              "profile-id-1",
+					PasswordChangeRequired: true,
+				}).Return(entity.IssueTokenResponse{
+					Token:     "token-1",
+					ExpiresIn: 900,
+				}, nil)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
 			},
 		},
 		{
-			name: "error when password not match",
+			name: "success login upgrades legacy hash",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
 				ctx: context.TODO(),
 				request: entity.LoginRequest{
 					PhoneNumber: "+62345",
-					Password:    "123456",
+					Password:    "12345",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error credentials combination not match"),
+			want: entity.LoginResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
 			mock: func() {
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
 					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "$2a$10$legacy-bcrypt-hash",
+						PhoneVerifiedAt: &verifiedAt,
 					}, nil,
 				)
-				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(error_list.ErrPasswordNotMatch)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "$2a$10$legacy-bcrypt-hash").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "$2a$10$legacy-bcrypt-hash").Return(true)
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("$argon2id$v=19$m=19456,t=2,p=1$salt$key", nil)
+				mockProfileRepository.EXPECT().RehashPasswordById(gomock.Any(), nil, "profile-id-1", "$2a$10$legacy-bcrypt-hash", "$argon2id$v=19$m=19456,t=2,p=1$salt$key").Return(nil)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
 			},
 		},
 		{
-			name: "error when verifying password",
+			name: "success login when upgrading hash fails",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
 				ctx: context.TODO(),
 				request: entity.LoginRequest{
 					PhoneNumber: "+62345",
-					Password:    "123456",
+					Password:    "12345",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error when try to login"),
+			want: entity.LoginResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
 			mock: func() {
 				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
 					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "$2a$10$legacy-bcrypt-hash",
+						PhoneVerifiedAt: &verifiedAt,
 					}, nil,
 				)
-				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(errors.New("error verify"))
-			},
-		},
-		{
-			name: "error when profile not found",
-			fields: fields{
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "$2a$10$legacy-bcrypt-hash").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "$2a$10$legacy-bcrypt-hash").Return(true)
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "12345").Return("$argon2id$v=19$m=19456,t=2,p=1$salt$key", nil)
+				mockProfileRepository.EXPECT().RehashPasswordById(gomock.Any(), nil, "profile-id-1", "$2a$10$legacy-bcrypt-hash", "$argon2id$v=19$m=19456,t=2,p=1$salt$key").Return(errors.New("error update"))
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+			},
+		},
+		{
+			name: "error when increasing counter",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(errors.New("error update"))
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+			},
+		},
+		{
+			name: "error when generate token",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{}, errors.New("error when issuing token"))
+			},
+		},
+		{
+			name: "error when password not match",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error credentials combination not match"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(error_list.ErrPasswordNotMatch)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(1, nil)
+			},
+		},
+		{
+			name: "error when password not match locks the account",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error credentials combination not match"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:               "profile-id-1",
+						FullName:         "jonathan",
+						PhoneNumber:      "+62345",
+						Password:         "12345",
+						PhoneVerifiedAt:  &verifiedAt,
+						FailedLoginCount: 9,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(error_list.ErrPasswordNotMatch)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(10, nil)
+				mockProfileRepository.EXPECT().LockProfile(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, profileId string, lockedUntil time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(15*time.Minute), lockedUntil, time.Minute)
+						return nil
+					},
+				)
+			},
+		},
+		{
+			name: "error when record failed login",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(error_list.ErrPasswordNotMatch)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(0, errors.New("error update"))
+			},
+		},
+		{
+			name: "error account locked",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error account is temporarily locked, try again later"),
+			mock: func() {
+				lockedUntil := time.Now().Add(time.Minute)
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:               "profile-id-1",
+						FullName:         "jonathan",
+						PhoneNumber:      "+62345",
+						Password:         "12345",
+						PhoneVerifiedAt:  &verifiedAt,
+						FailedLoginCount: 10,
+						LockedUntil:      &lockedUntil,
+					}, nil,
+				)
+			},
+		},
+		{
+			name: "success login after lock expired resets failed logins",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want: entity.LoginResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
+			mock: func() {
+				lockedUntil := time.Now().Add(-time.Minute)
+
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:               "profile-id-1",
+						FullName:         "jonathan",
+						PhoneNumber:      "+62345",
+						Password:         "12345",
+						PhoneVerifiedAt:  &verifiedAt,
+						FailedLoginCount: 10,
+						LockedUntil:      &lockedUntil,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId: "profile-id-1",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockProfileRepository.EXPECT().ResetFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+			},
+		},
+		{
+			name: "error phone number not verified",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error phone number not verified"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:          "profile-id-1",
+						FullName:    "jonathan",
+						PhoneNumber: "+62345",
+						Password:    "12345",
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+			},
+		},
+		{
+			name: "error when verifying password",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						FullName:        "jonathan",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "123456", "12345").Return(errors.New("error verify"))
+			},
+		},
+		{
+			name: "error when profile not found",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error credentials combination not match"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{}, nil,
+				)
+			},
+		},
+		{
+			name: "password accepted, two-factor challenge issued",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want: entity.LoginResponse{
+				MFAToken:     "mfa-token-1",
+				MFAExpiresIn: 300,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(
+					entity.TOTPCredential{ProfileId: "profile-id-1", Secret: &totpSecret}, nil,
+				)
+				mockHelper.EXPECT().GenerateRefreshToken(gomock.Any()).Return("mfa-token-1", nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "mfa-token-1").Return("mfa-token-hash-1")
+				mockMFAChallengeRepository.EXPECT().InsertMFAChallenge(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, challenge entity.MFAChallenge) (string, error) {
+						assert.Equal(t, "profile-id-1", challenge.ProfileId)
+						assert.Equal(t, "mfa-token-hash-1", challenge.TokenHash)
+						assert.WithinDuration(t, time.Now().Add(5*time.Minute), challenge.ExpiresAt, time.Minute)
+						return "challenge-id-1", nil
+					},
+				)
+			},
+		},
+		{
+			name: "error when get totp credential",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "12345",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-1",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
+				)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345", "12345").Return(nil)
+				mockHelper.EXPECT().PasswordNeedsRehash(gomock.Any(), "12345").Return(false)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(
+					entity.TOTPCredential{}, errors.New("error get"),
+				)
+			},
+		},
+		{
+			name: "error when get profile",
+			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.LoginRequest{
+					PhoneNumber: "+62345",
+					Password:    "123456",
+				},
+			},
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
+					entity.UserProfile{}, errors.New("error get"),
+				)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:        tt.fields.profileRepository,
+				totpCredentialRepository: mockTOTPCredentialRepository,
+				mfaChallengeRepository:   mockMFAChallengeRepository,
+				authhelper:               tt.fields.authhelper,
+				authService:              tt.fields.authService,
+				loginLockoutPolicy:       loginLockoutPolicy,
+				passwordMaxAge:           tt.fields.passwordMaxAge,
+			}
+			got, err := p.Login(tt.args.ctx, tt.args.request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_UpdateProfile(t *testing.T) {
+
+	mockTx := &sqlx.Tx{}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockOneTimeCodeRepository := mocks.NewMockOneTimeCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockSMSSender := mocks.NewMockSMSSenderInterface(ctrl)
+
+	profile := entity.UserProfile{
+		Id:              "profile-id-1",
+		FullName:        "jon",
+		PhoneNumber:     "+62123",
+		PhoneVerifiedAt: &verifiedAt,
+	}
+
+	type fields struct {
+		profileRepository     repository.UserProfileRepositoryInterface
+		oneTimeCodeRepository repository.OneTimeCodeRepositoryInterface
+		authhelper            helper.AuthHelperInterface
+		smsSender             helper.SMSSenderInterface
+	}
+	type args struct {
+		ctx     context.Context
+		request entity.UpdateProfileRequest
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success update profile",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{}, nil,
+				)
+				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
+					FullName: "jonathan",
+				}).Return(nil)
+				mockProfileRepository.EXPECT().SetPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", "+62345").Return(nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345",
+					"Your sawitpro verification code is 012345. It expires in 10 minutes, do not share it with anyone.").Return(nil)
+			},
+		},
+		{
+			name: "success update full name keeps the phone number",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62123",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
+					FullName: "jonathan",
+				}).Return(nil)
+			},
+		},
+		{
+			name: "success update profile to the number of an expired registration",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{
+						Id:          "profile-id-2",
+						FullName:    "someone",
+						PhoneNumber: "+62345",
+						CreatedAt:   time.Now().Add(-time.Hour),
+					}, nil,
+				)
+				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
+					FullName: "jonathan",
+				}).Return(nil)
+				mockProfileRepository.EXPECT().SetPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", "+62345").Return(nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error when update the profile",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: errors.New("error when updating profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{}, nil,
+				)
+				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
+					FullName: "jonathan",
+				}).Return(errors.New("error update"))
+			},
+		},
+		{
+			name: "error when send verification code",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: errors.New("error when updating profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{}, nil,
+				)
+				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
+					FullName: "jonathan",
+				}).Return(nil)
+				mockProfileRepository.EXPECT().SetPendingPhoneNumber(gomock.Any(), mockTx, "profile-id-1", "+62345").Return(nil)
+				mockHelper.EXPECT().GenerateOneTimeCode(gomock.Any()).Return("012345", nil)
+				mockOneTimeCodeRepository.EXPECT().ConsumeOneTimeCodes(gomock.Any(), mockTx, "profile-id-1", "phone_change").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "012345").Return("code-hash")
+				mockOneTimeCodeRepository.EXPECT().InsertOneTimeCode(gomock.Any(), mockTx, gomock.Any()).Return("code-id-1", nil)
+				mockSMSSender.EXPECT().SendSMS(gomock.Any(), "+62345", gomock.Any()).Return(errors.New("error send"))
+			},
+		},
+		{
+			name: "error duplicate phone number",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
 			},
 			args: args{
 				ctx: context.TODO(),
-				request: entity.LoginRequest{
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
 					PhoneNumber: "+62345",
-					Password:    "123456",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error credentials combination not match"),
+			wantErr: errors.New("error there existing data conficted with new data"),
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
-					entity.UserProfile{}, nil,
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{
+						Id:              "profile-id-2",
+						FullName:        "someone",
+						PhoneNumber:     "+62345",
+						Password:        "12345",
+						PhoneVerifiedAt: &verifiedAt,
+					}, nil,
 				)
 			},
 		},
 		{
-			name: "error when get profile",
+			name: "error duplicate phone number pending verification",
 			fields: fields{
-				profileRepository: mockProfileRepository,
-				authhelper:        mockHelper,
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
 			},
 			args: args{
 				ctx: context.TODO(),
-				request: entity.LoginRequest{
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
 					PhoneNumber: "+62345",
-					Password:    "123456",
 				},
 			},
-			want:    entity.LoginResponse{},
-			wantErr: errors.New("error when try to login"),
+			wantErr: errors.New("error there existing data conficted with new data"),
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62345").Return(
-					entity.UserProfile{}, errors.New("error get"),
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{
+						Id:          "profile-id-2",
+						FullName:    "someone",
+						PhoneNumber: "+62345",
+						CreatedAt:   time.Now().Add(-time.Minute),
+					}, nil,
+				)
+			},
+		},
+		{
+			name: "error when get existing profile",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: errors.New("error when updating profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
+					entity.UserProfile{}, errors.New("error select"),
+				)
+			},
+		},
+		{
+			name: "error profile not found",
+			fields: fields{
+				profileRepository:     mockProfileRepository,
+				oneTimeCodeRepository: mockOneTimeCodeRepository,
+				authhelper:            mockHelper,
+				smsSender:             mockSMSSender,
+			},
+			args: args{
+				ctx: context.TODO(),
+				request: entity.UpdateProfileRequest{
+					Id:          "profile-id-1",
+					FullName:    "jonathan",
+					PhoneNumber: "+62345",
+				},
+			},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
 				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(entity.UserProfile{}, nil)
 			},
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.mock()
+		tt.mock()
 
+		t.Run(tt.name, func(t *testing.T) {
 			p := profileService{
-				profileRepository: tt.fields.profileRepository,
-				authhelper:        tt.fields.authhelper,
+				profileRepository:     tt.fields.profileRepository,
+				oneTimeCodeRepository: tt.fields.oneTimeCodeRepository,
+				authhelper:            tt.fields.authhelper,
+				smsSender:             tt.fields.smsSender,
 			}
-			got, err := p.Login(tt.args.ctx, tt.args.request)
-			assert.Equal(t, tt.want, got)
+			err := p.UpdateProfile(tt.args.ctx, tt.args.request)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
 }
 
-func Test_profileService_UpdateProfile(t *testing.T) {
-
+func Test_profileService_ChangePassword(t *testing.T) {
 	mockTx := &sqlx.Tx{}
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
 	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	mockValidatorHelper := mocks.NewMockValidatorHelperInterface(ctrl)
+	mockBreachedPasswordChecker := mocks.NewMockBreachedPasswordCheckerInterface(ctrl)
+	mockPasswordHistoryRepository := mocks.NewMockPasswordHistoryRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockPersonalAccessTokenRepository := mocks.NewMockPersonalAccessTokenRepositoryInterface(ctrl)
+	mockOAuthGrantRepository := mocks.NewMockOAuthGrantRepositoryInterface(ctrl)
+
+	request := entity.ChangePasswordRequest{
+		ProfileId:       "profile-id-1",
+		SessionId:       "session-id-1",
+		CurrentPassword: "12345A!",
+		NewPassword:     "67890B!",
+	}
+	profile := entity.UserProfile{
+		Id:          "profile-id-1",
+		FullName:    "jonathan",
+		PhoneNumber: "+62345",
+		Password:    "hashed-password-1",
+	}
+
+	checkPasswordHistory := func() {
+		mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), nil, "profile-id-1", 5).Return([]entity.PasswordHistory{
+			{Id: "history-id-1", ProfileId: "profile-id-1", Password: "hashed-password-0"},
+		}, nil)
+		mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+		mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-0").Return(error_list.ErrPasswordNotMatch)
+	}
+	recordPasswordHistory := func() {
+		mockPasswordHistoryRepository.EXPECT().InsertPasswordHistory(gomock.Any(), mockTx, "profile-id-1", "hashed-password-1").Return(nil)
+		mockPasswordHistoryRepository.EXPECT().PrunePasswordHistory(gomock.Any(), mockTx, "profile-id-1", 5).Return(nil)
+	}
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+	loginLockoutPolicy := LoginLockoutPolicy{
+		DelayAfter:      3,
+		BaseDelay:       time.Second,
+		LockoutAfter:    5,
+		LockoutDuration: 15 * time.Minute,
+	}
 
 	type fields struct {
 		profileRepository repository.UserProfileRepositoryInterface
 		authhelper        helper.AuthHelperInterface
+		authService       AuthServiceInterface
 	}
 	type args struct {
 		ctx     context.Context
-		request entity.UpdateProfileRequest
+		request entity.ChangePasswordRequest
 	}
 	tests := []struct {
 		name    string
@@ -608,89 +1883,221 @@ func Test_profileService_UpdateProfile(t *testing.T) {
 		mock    func()
 	}{
 		{
-			name: "success update profile",
+			name: "success change password revokes every other credential",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
-				ctx: context.TODO(),
-				request: entity.UpdateProfileRequest{
-					Id:          "profile-id-1",
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				},
+				ctx:     context.TODO(),
+				request: request,
 			},
 			wantErr: nil,
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
-					entity.UserProfile{}, nil,
-				)
-				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				}).Return(nil)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				recordPasswordHistory()
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
 					},
 				)
+				// nothing made with the old password outlives it but the
+				// session that changed it: other sessions, the refresh
+				// tokens of oauth clients and personal access tokens
+				mockSessionRepository.EXPECT().DeleteOtherSessions(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return([]string{"session-id-2"}, nil)
+				mockOAuthGrantRepository.EXPECT().DeleteOAuthGrantsByProfileId(gomock.Any(), mockTx, "profile-id-1").Return([]string{"grant-id-1"}, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "grant-id-1").Return(nil)
+				mockPersonalAccessTokenRepository.EXPECT().DeletePersonalAccessTokensByProfileId(gomock.Any(), mockTx, "profile-id-1").Return(int64(1), nil)
 			},
 		},
 		{
-			name: "error when update the profile",
+			name: "error current password not match",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
-				ctx: context.TODO(),
-				request: entity.UpdateProfileRequest{
-					Id:          "profile-id-1",
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				},
+				ctx:     context.TODO(),
+				request: request,
 			},
-			wantErr: errors.New("error when updating profile"),
+			wantErr: errors.New("error current password not match"),
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
-					entity.UserProfile{}, nil,
-				)
-				mockProfileRepository.EXPECT().UpdateProfileById(gomock.Any(), mockTx, "profile-id-1", entity.UserProfile{
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				}).Return(errors.New("error update"))
-				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
-					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
-						return handleFunc(mockTx)
-					},
-				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(1, nil)
 			},
 		},
 		{
-			name: "error duplicate phone number",
+			name: "error current password not match locks the account",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
-				ctx: context.TODO(),
-				request: entity.UpdateProfileRequest{
-					Id:          "profile-id-1",
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				},
+				ctx:     context.TODO(),
+				request: request,
 			},
-			wantErr: errors.New("error there existing data conficted with new data"),
+			wantErr: errors.New("error current password not match"),
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
-					entity.UserProfile{
-						Id:          "profile-id-1",
-						FullName:    "jonathan",
-						PhoneNumber: "+62345",
-						Password:    "12345",
-					}, nil,
-				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(5, nil)
+				mockProfileRepository.EXPECT().LockProfile(gomock.Any(), mockTx, "profile-id-1", gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "error account locked",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: error_list.ErrAccountLocked,
+			mock: func() {
+				lockedUntil := time.Now().Add(time.Minute)
+				lockedProfile := profile
+				lockedProfile.LockedUntil = &lockedUntil
+
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(lockedProfile, nil)
+			},
+		},
+		{
+			name: "error password breaks the policy",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: error_list.PasswordPolicyError{
+				Violations: []string{"must contain a digit"},
+			},
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(error_list.PasswordPolicyError{
+					Violations: []string{"must contain a digit"},
+				})
+			},
+		},
+		{
+			name: "error breached password",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error password has appeared in a data breach, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(true, nil)
+			},
+		},
+		{
+			name: "error password used recently",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error password was used recently, choose another one"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), nil, "profile-id-1", 5).Return([]entity.PasswordHistory{
+					{Id: "history-id-1", ProfileId: "profile-id-1", Password: "hashed-password-0"},
+				}, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-1").Return(error_list.ErrPasswordNotMatch)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "67890B!", "hashed-password-0").Return(nil)
+			},
+		},
+		{
+			name: "error when get password history",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error when changing password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				mockPasswordHistoryRepository.EXPECT().GetPasswordHistory(gomock.Any(), nil, "profile-id-1", 5).Return(nil, errors.New("error select"))
+			},
+		},
+		{
+			name: "error profile not found",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name: "error when update password",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error when changing password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(errors.New("error update"))
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
@@ -699,29 +2106,59 @@ func Test_profileService_UpdateProfile(t *testing.T) {
 			},
 		},
 		{
-			name: "error when get existing profile",
+			name: "error when revoke other sessions",
 			fields: fields{
 				profileRepository: mockProfileRepository,
 				authhelper:        mockHelper,
+				authService:       mockAuthService,
 			},
 			args: args{
-				ctx: context.TODO(),
-				request: entity.UpdateProfileRequest{
-					Id:          "profile-id-1",
-					FullName:    "jonathan",
-					PhoneNumber: "+62345",
-				},
+				ctx:     context.TODO(),
+				request: request,
 			},
-			wantErr: errors.New("error when updating profile"),
+			wantErr: errors.New("error when changing password"),
 			mock: func() {
-				mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), mockTx, "+62345").Return(
-					entity.UserProfile{}, errors.New("error select"),
-				)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				recordPasswordHistory()
 				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
 					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
 						return handleFunc(mockTx)
 					},
 				)
+				mockSessionRepository.EXPECT().DeleteOtherSessions(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(nil, errors.New("error delete"))
+			},
+		},
+		{
+			name: "error when revoke personal access tokens",
+			fields: fields{
+				profileRepository: mockProfileRepository,
+				authhelper:        mockHelper,
+				authService:       mockAuthService,
+			},
+			args: args{
+				ctx:     context.TODO(),
+				request: request,
+			},
+			wantErr: errors.New("error when changing password"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password-1").Return(nil)
+				mockValidatorHelper.EXPECT().ValidatePassword("67890B!", "jonathan", "+62345").Return(nil)
+				mockBreachedPasswordChecker.EXPECT().IsBreached(gomock.Any(), "67890B!").Return(false, nil)
+				checkPasswordHistory()
+				mockHelper.EXPECT().HashPassword(gomock.Any(), "67890B!").Return("hashed-password-2", nil)
+				mockProfileRepository.EXPECT().UpdatePasswordById(gomock.Any(), mockTx, "profile-id-1", "hashed-password-2").Return(nil)
+				recordPasswordHistory()
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteOtherSessions(gomock.Any(), mockTx, "profile-id-1", "session-id-1").Return(nil, nil)
+				mockOAuthGrantRepository.EXPECT().DeleteOAuthGrantsByProfileId(gomock.Any(), mockTx, "profile-id-1").Return(nil, nil)
+				mockPersonalAccessTokenRepository.EXPECT().DeletePersonalAccessTokensByProfileId(gomock.Any(), mockTx, "profile-id-1").Return(int64(0), errors.New("error delete"))
 			},
 		},
 	}
@@ -730,10 +2167,20 @@ func Test_profileService_UpdateProfile(t *testing.T) {
 
 		t.Run(tt.name, func(t *testing.T) {
 			p := profileService{
-				profileRepository: tt.fields.profileRepository,
-				authhelper:        tt.fields.authhelper,
+				profileRepository:             tt.fields.profileRepository,
+				authhelper:                    tt.fields.authhelper,
+				validatorHelper:               mockValidatorHelper,
+				breachedPasswordChecker:       mockBreachedPasswordChecker,
+				authService:                   tt.fields.authService,
+				passwordHistoryRepository:     mockPasswordHistoryRepository,
+				sessionRepository:             mockSessionRepository,
+				refreshTokenRepository:        mockRefreshTokenRepository,
+				personalAccessTokenRepository: mockPersonalAccessTokenRepository,
+				oauthGrantRepository:          mockOAuthGrantRepository,
+				loginLockoutPolicy:            loginLockoutPolicy,
+				passwordHistorySize:           5,
 			}
-			err := p.UpdateProfile(tt.args.ctx, tt.args.request)
+			err := p.ChangePassword(tt.args.ctx, tt.args.request)
 			assert.Equal(t, tt.wantErr, err)
 		})
 	}
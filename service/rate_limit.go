@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/repository"
+	"time"
+)
+
+type rateLimitService struct {
+	rateLimitRepository repository.RateLimitRepositoryInterface
+}
+
+type RateLimitServiceDeps struct {
+	RateLimitRepository repository.RateLimitRepositoryInterface
+}
+
+func NewRateLimitService(deps RateLimitServiceDeps) rateLimitService {
+	return rateLimitService{
+		rateLimitRepository: deps.RateLimitRepository,
+	}
+}
+
+// TakeRateLimitToken takes a token from the bucket of the request. When none
+// is left it reports how long until the bucket has refilled one.
+func (r rateLimitService) TakeRateLimitToken(ctx context.Context, request entity.TakeRateLimitTokenRequest) (entity.TakeRateLimitTokenResponse, error) {
+	var res = entity.TakeRateLimitTokenResponse{}
+
+	now := time.Now().UTC()
+	refillPerSecond := request.Rule.RefillPerMinute / 60
+
+	taken, bucket, err := r.rateLimitRepository.TakeRateLimitToken(ctx, request.BucketKey, request.Rule.Capacity, refillPerSecond, now)
+	if err != nil {
+		return res, error_list.ErrRateLimit
+	}
+
+	if taken {
+		res.Allowed = true
+		return res, nil
+	}
+
+	elapsed := math.Max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+	tokens := math.Min(request.Rule.Capacity, bucket.Tokens+elapsed*refillPerSecond)
+	res.RetryAfter = time.Duration((1 - tokens) / refillPerSecond * float64(time.Second))
+
+	return res, nil
+}
+
+func (r rateLimitService) PruneRateLimitBuckets(ctx context.Context) error {
+	_, err := r.rateLimitRepository.DeleteIdleRateLimitBuckets(ctx, time.Now().Add(-constant.RateLimitBucketIdleTimeout).UTC())
+	if err != nil {
+		return error_list.ErrPruneRateLimitBuckets
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitService_TakeRateLimitToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRateLimitRepository := mocks.NewMockRateLimitRepositoryInterface(ctrl)
+
+	request := entity.TakeRateLimitTokenRequest{
+		BucketKey: "login:ip:192.0.2.1",
+		Rule: entity.RateLimitRule{
+			Key:             "ip",
+			Capacity:        5,
+			RefillPerMinute: 6,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.TakeRateLimitTokenResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "token taken",
+			want: entity.TakeRateLimitTokenResponse{
+				Allowed: true,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRateLimitRepository.EXPECT().TakeRateLimitToken(gomock.Any(), "login:ip:192.0.2.1", float64(5), 0.1, gomock.Any()).
+					Return(true, entity.RateLimitBucket{Tokens: 3}, nil)
+			},
+		},
+		{
+			name: "bucket empty",
+			want: entity.TakeRateLimitTokenResponse{
+				Allowed:    false,
+				RetryAfter: 5 * time.Second,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRateLimitRepository.EXPECT().TakeRateLimitToken(gomock.Any(), "login:ip:192.0.2.1", float64(5), 0.1, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, bucketKey string, capacity float64, refillPerSecond float64, now time.Time) (bool, entity.RateLimitBucket, error) {
+						return false, entity.RateLimitBucket{Tokens: 0.5, UpdatedAt: now}, nil
+					},
+				)
+			},
+		},
+		{
+			name:    "error take token",
+			want:    entity.TakeRateLimitTokenResponse{},
+			wantErr: errors.New("error when checking rate limit"),
+			mock: func() {
+				mockRateLimitRepository.EXPECT().TakeRateLimitToken(gomock.Any(), "login:ip:192.0.2.1", float64(5), 0.1, gomock.Any()).
+					Return(false, entity.RateLimitBucket{}, errors.New("error upsert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := rateLimitService{
+				rateLimitRepository: mockRateLimitRepository,
+			}
+			got, err := r.TakeRateLimitToken(context.TODO(), request)
+			assert.Equal(t, tt.want.Allowed, got.Allowed)
+			assert.InDelta(t, tt.want.RetryAfter, got.RetryAfter, float64(10*time.Millisecond))
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_rateLimitService_PruneRateLimitBuckets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRateLimitRepository := mocks.NewMockRateLimitRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockRateLimitRepository.EXPECT().DeleteIdleRateLimitBuckets(gomock.Any(), gomock.Any()).Return(int64(4), nil)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning rate limit buckets"),
+			mock: func() {
+				mockRateLimitRepository.EXPECT().DeleteIdleRateLimitBuckets(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := rateLimitService{
+				rateLimitRepository: mockRateLimitRepository,
+			}
+			err := r.PruneRateLimitBuckets(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GenerateRecoveryCodes replaces the recovery codes of the profile with a
+// new set. The codes are only ever shown in the response, only their hashes
+// are stored.
+func (p profileService) GenerateRecoveryCodes(ctx context.Context, request entity.GenerateRecoveryCodesRequest) (entity.GenerateRecoveryCodesResponse, error) {
+	var res = entity.GenerateRecoveryCodesResponse{}
+
+	codes := make([]string, 0, constant.RecoveryCodeCount)
+	for i := 0; i < constant.RecoveryCodeCount; i++ {
+		code, err := p.authhelper.GenerateRecoveryCode(ctx)
+		if err != nil {
+			return res, error_list.ErrGenerateRecoveryCodes
+		}
+		codes = append(codes, code)
+	}
+
+	err := p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := p.recoveryCodeRepository.DeleteRecoveryCodes(ctx, tx, request.ProfileId)
+		if err != nil {
+			return error_list.ErrGenerateRecoveryCodes
+		}
+
+		for _, code := range codes {
+			err = p.recoveryCodeRepository.InsertRecoveryCode(ctx, tx, entity.RecoveryCode{
+				ProfileId: request.ProfileId,
+				CodeHash:  p.authhelper.HashToken(ctx, normalizeRecoveryCode(code)),
+			})
+			if err != nil {
+				return error_list.ErrGenerateRecoveryCodes
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	res = entity.GenerateRecoveryCodesResponse{
+		Codes: codes,
+	}
+
+	return res, nil
+}
+
+// loginWithRecoveryCode signs in with a recovery code in place of the
+// password. The code stands in for every factor, whoever lost the phone
+// number has usually lost the authenticator app with it.
+func (p profileService) loginWithRecoveryCode(ctx context.Context, profile entity.UserProfile, request entity.LoginRequest) (entity.LoginResponse, error) {
+	var res = entity.LoginResponse{}
+
+	codeHash := p.authhelper.HashToken(ctx, normalizeRecoveryCode(request.RecoveryCode))
+
+	used, err := p.recoveryCodeRepository.UseRecoveryCode(ctx, nil, profile.Id, codeHash, time.Now().UTC())
+	if err != nil {
+		return res, error_list.ErrLogin
+	}
+
+	if !used {
+		err = p.recordFailedLogin(ctx, profile.Id)
+		if err != nil {
+			return res, error_list.ErrLogin
+		}
+		return res, error_list.ErrLoginCredential
+	}
+
+	if profile.PhoneVerifiedAt == nil {
+		return res, error_list.ErrPhoneNotVerified
+	}
+
+	return p.completeLogin(ctx, profile, entity.IssueTokenRequest{
+		ProfileId:  profile.Id,
+		DeviceName: request.DeviceName,
+		UserAgent:  request.UserAgent,
+		IpAddress:  request.IpAddress,
+	})
+}
+
+// normalizeRecoveryCode drops the separators and case a code may be typed
+// with, so it hashes the same as when it was generated.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+
+	return code
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileService_GenerateRecoveryCodes(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRecoveryCodeRepository := mocks.NewMockRecoveryCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	request := entity.GenerateRecoveryCodesRequest{
+		ProfileId: "profile-id-1",
+	}
+	codes := []string{
+		"ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS",
+		"ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS", "ABCD-EFGH-JKMN-PQRS",
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.GenerateRecoveryCodesResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success generate recovery codes",
+			want: entity.GenerateRecoveryCodesResponse{
+				Codes: codes,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockHelper.EXPECT().GenerateRecoveryCode(gomock.Any()).Return("ABCD-EFGH-JKMN-PQRS", nil).Times(10)
+				runWithTransaction()
+				mockRecoveryCodeRepository.EXPECT().DeleteRecoveryCodes(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "ABCDEFGHJKMNPQRS").Return("code-hash").Times(10)
+				mockRecoveryCodeRepository.EXPECT().InsertRecoveryCode(gomock.Any(), mockTx, entity.RecoveryCode{
+					ProfileId: "profile-id-1",
+					CodeHash:  "code-hash",
+				}).Return(nil).Times(10)
+			},
+		},
+		{
+			name:    "error when generate code",
+			want:    entity.GenerateRecoveryCodesResponse{},
+			wantErr: errors.New("error when generating recovery codes"),
+			mock: func() {
+				mockHelper.EXPECT().GenerateRecoveryCode(gomock.Any()).Return("", errors.New("error random"))
+			},
+		},
+		{
+			name:    "error when delete old codes",
+			want:    entity.GenerateRecoveryCodesResponse{},
+			wantErr: errors.New("error when generating recovery codes"),
+			mock: func() {
+				mockHelper.EXPECT().GenerateRecoveryCode(gomock.Any()).Return("ABCD-EFGH-JKMN-PQRS", nil).Times(10)
+				runWithTransaction()
+				mockRecoveryCodeRepository.EXPECT().DeleteRecoveryCodes(gomock.Any(), mockTx, "profile-id-1").Return(errors.New("error delete"))
+			},
+		},
+		{
+			name:    "error when insert code",
+			want:    entity.GenerateRecoveryCodesResponse{},
+			wantErr: errors.New("error when generating recovery codes"),
+			mock: func() {
+				mockHelper.EXPECT().GenerateRecoveryCode(gomock.Any()).Return("ABCD-EFGH-JKMN-PQRS", nil).Times(10)
+				runWithTransaction()
+				mockRecoveryCodeRepository.EXPECT().DeleteRecoveryCodes(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockHelper.EXPECT().HashToken(gomock.Any(), "ABCDEFGHJKMNPQRS").Return("code-hash")
+				mockRecoveryCodeRepository.EXPECT().InsertRecoveryCode(gomock.Any(), mockTx, gomock.Any()).Return(errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:      mockProfileRepository,
+				recoveryCodeRepository: mockRecoveryCodeRepository,
+				authhelper:             mockHelper,
+			}
+			got, err := p.GenerateRecoveryCodes(context.TODO(), request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_Login_recoveryCode(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRecoveryCodeRepository := mocks.NewMockRecoveryCodeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	request := entity.LoginRequest{
+		PhoneNumber:  "+62812345678",
+		RecoveryCode: "abcd-efgh-jkmn-pqrs",
+		DeviceName:   "field tablet",
+	}
+	verifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := entity.UserProfile{
+		Id:              "profile-id-1",
+		PhoneNumber:     "+62812345678",
+		Password:        "hashed-password",
+		PhoneVerifiedAt: &verifiedAt,
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+	useCode := func(profile entity.UserProfile, used bool, err error) {
+		mockProfileRepository.EXPECT().GetProfileByPhoneNumber(gomock.Any(), nil, "+62812345678").Return(profile, nil)
+		mockHelper.EXPECT().HashToken(gomock.Any(), "ABCDEFGHJKMNPQRS").Return("code-hash")
+		mockRecoveryCodeRepository.EXPECT().UseRecoveryCode(gomock.Any(), nil, "profile-id-1", "code-hash", gomock.Any()).Return(used, err)
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.LoginResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success login skips two-factor",
+			want: entity.LoginResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
+			mock: func() {
+				useCode(profile, true, nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId:  "profile-id-1",
+					DeviceName: "field tablet",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error unknown or used code counts as failed login",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error credentials combination not match"),
+			mock: func() {
+				useCode(profile, false, nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(1, nil)
+			},
+		},
+		{
+			name:    "error phone not verified",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error phone number not verified"),
+			mock: func() {
+				unverifiedProfile := profile
+				unverifiedProfile.PhoneVerifiedAt = nil
+
+				useCode(unverifiedProfile, true, nil)
+			},
+		},
+		{
+			name:    "error when use code",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				useCode(profile, false, errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:      mockProfileRepository,
+				recoveryCodeRepository: mockRecoveryCodeRepository,
+				authhelper:             mockHelper,
+				authService:            mockAuthService,
+				loginLockoutPolicy: LoginLockoutPolicy{
+					DelayAfter:      3,
+					BaseDelay:       time.Second,
+					LockoutAfter:    10,
+					LockoutDuration: 15 * time.Minute,
+				},
+			}
+			got, err := p.Login(context.TODO(), request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/pkg/authn"
+	"sawitpro/repository"
+	"strings"
+	"time"
+)
+
+type roleService struct {
+	roleRepository    repository.RoleRepositoryInterface
+	profileRepository repository.UserProfileRepositoryInterface
+}
+
+type RoleServiceDeps struct {
+	RoleRepository    repository.RoleRepositoryInterface
+	ProfileRepository repository.UserProfileRepositoryInterface
+}
+
+func NewRoleService(deps RoleServiceDeps) roleService {
+	return roleService{
+		roleRepository:    deps.RoleRepository,
+		profileRepository: deps.ProfileRepository,
+	}
+}
+
+func (r roleService) ListRoles(ctx context.Context) (entity.ListRolesResponse, error) {
+	var res = entity.ListRolesResponse{}
+
+	roles, err := r.roleRepository.GetRoles(ctx, nil)
+	if err != nil {
+		return res, error_list.ErrListRoles
+	}
+
+	res = entity.ListRolesResponse{
+		Roles: roles,
+	}
+
+	return res, nil
+}
+
+func (r roleService) ListProfileRoles(ctx context.Context, request entity.ListProfileRolesRequest) (entity.ListProfileRolesResponse, error) {
+	var res = entity.ListProfileRolesResponse{}
+
+	profile, err := r.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrListProfileRoles
+	}
+
+	if profile.Id == "" {
+		return res, error_list.ErrProfileNotFound
+	}
+
+	roles, err := r.roleRepository.GetRolesByProfileId(ctx, nil, profile.Id)
+	if err != nil {
+		return res, error_list.ErrListProfileRoles
+	}
+
+	res = entity.ListProfileRolesResponse{
+		Roles: roles,
+	}
+
+	return res, nil
+}
+
+// AssignRole gives the profile the role, assigning a role it holds already
+// succeeds. The profile gets its permissions when its token is next issued
+// or refreshed.
+func (r roleService) AssignRole(ctx context.Context, request entity.AssignRoleRequest) error {
+	profile, err := r.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return error_list.ErrAssignRole
+	}
+
+	if profile.Id == "" {
+		return error_list.ErrProfileNotFound
+	}
+
+	role, err := r.roleRepository.GetRoleByName(ctx, nil, request.RoleName)
+	if err != nil {
+		return error_list.ErrAssignRole
+	}
+
+	if role.Id == "" {
+		return error_list.ErrRoleNotFound
+	}
+
+	var assignedBy *string
+	if request.AssignedBy != "" {
+		assignedBy = &request.AssignedBy
+	}
+
+	_, err = r.roleRepository.AssignProfileRole(ctx, nil, entity.ProfileRole{
+		ProfileId:  profile.Id,
+		RoleId:     role.Id,
+		AssignedBy: assignedBy,
+		AssignedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return error_list.ErrAssignRole
+	}
+
+	return nil
+}
+
+// UnassignRole takes the role away. Access tokens issued before keep its
+// permissions until they expire, at most constant.AccessTokenDuration.
+func (r roleService) UnassignRole(ctx context.Context, request entity.UnassignRoleRequest) error {
+	role, err := r.roleRepository.GetRoleByName(ctx, nil, request.RoleName)
+	if err != nil {
+		return error_list.ErrUnassignRole
+	}
+
+	if role.Id == "" {
+		return error_list.ErrRoleNotFound
+	}
+
+	unassigned, err := r.roleRepository.UnassignProfileRole(ctx, nil, request.ProfileId, role.Id)
+	if err != nil {
+		return error_list.ErrUnassignRole
+	}
+
+	if !unassigned {
+		return error_list.ErrRoleNotAssigned
+	}
+
+	return nil
+}
+
+// roleClaims returns the names of the roles and the scopes a login token
+// carries, those of every login and the permissions of the roles.
+func roleClaims(roles []entity.Role) ([]string, []string) {
+	var names []string
+	scopes := authn.LoginScopes()
+
+	for _, role := range roles {
+		names = append(names, role.Name)
+		scopes = append(scopes, strings.Fields(role.Permissions)...)
+	}
+
+	return names, uniqueStrings(scopes)
+}
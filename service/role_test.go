@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRoleService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+
+	got := NewRoleService(RoleServiceDeps{
+		RoleRepository:    mockRoleRepository,
+		ProfileRepository: mockProfileRepository,
+	})
+	assert.Equal(t, roleService{
+		roleRepository:    mockRoleRepository,
+		profileRepository: mockProfileRepository,
+	}, got)
+}
+
+func Test_roleService_ListRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+
+	roles := []entity.Role{
+		{
+			Id:          "role-id-1",
+			Name:        "admin",
+			Permissions: "roles:read roles:write",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.ListRolesResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success list roles",
+			want: entity.ListRolesResponse{
+				Roles: roles,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoles(gomock.Any(), nil).Return(roles, nil)
+			},
+		},
+		{
+			name:    "error list roles",
+			want:    entity.ListRolesResponse{},
+			wantErr: errors.New("error when listing roles"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoles(gomock.Any(), nil).Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := roleService{
+				roleRepository: mockRoleRepository,
+			}
+			got, err := r.ListRoles(context.TODO())
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_roleService_ListProfileRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+
+	roles := []entity.Role{
+		{
+			Id:          "role-id-1",
+			Name:        "admin",
+			Permissions: "roles:read roles:write",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.ListProfileRolesResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success list profile roles",
+			want: entity.ListProfileRolesResponse{
+				Roles: roles,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(roles, nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			want:    entity.ListProfileRolesResponse{},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error when get profile",
+			want:    entity.ListProfileRolesResponse{},
+			wantErr: errors.New("error when listing roles of profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, errors.New("error select"))
+			},
+		},
+		{
+			name:    "error when get roles",
+			want:    entity.ListProfileRolesResponse{},
+			wantErr: errors.New("error when listing roles of profile"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRolesByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := roleService{
+				roleRepository:    mockRoleRepository,
+				profileRepository: mockProfileRepository,
+			}
+			got, err := r.ListProfileRoles(context.TODO(), entity.ListProfileRolesRequest{
+				ProfileId: "profile-id-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_roleService_AssignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		request entity.AssignRoleRequest
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success assign role",
+			request: entity.AssignRoleRequest{
+				ProfileId:  "profile-id-1",
+				RoleName:   "admin",
+				AssignedBy: "profile-id-2",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().AssignProfileRole(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, profileRole entity.ProfileRole) (bool, error) {
+						assert.Equal(t, "profile-id-1", profileRole.ProfileId)
+						assert.Equal(t, "role-id-1", profileRole.RoleId)
+						assert.Equal(t, "profile-id-2", *profileRole.AssignedBy)
+						return true, nil
+					},
+				)
+			},
+		},
+		{
+			name: "success assign role held already from the admin command",
+			request: entity.AssignRoleRequest{
+				ProfileId: "profile-id-1",
+				RoleName:  "admin",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().AssignProfileRole(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, profileRole entity.ProfileRole) (bool, error) {
+						assert.Nil(t, profileRole.AssignedBy)
+						return false, nil
+					},
+				)
+			},
+		},
+		{
+			name: "error profile not found",
+			request: entity.AssignRoleRequest{
+				ProfileId: "profile-id-1",
+				RoleName:  "admin",
+			},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name: "error role not found",
+			request: entity.AssignRoleRequest{
+				ProfileId: "profile-id-1",
+				RoleName:  "owner",
+			},
+			wantErr: errors.New("error role not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "owner").Return(entity.Role{}, nil)
+			},
+		},
+		{
+			name: "error when assign role",
+			request: entity.AssignRoleRequest{
+				ProfileId: "profile-id-1",
+				RoleName:  "admin",
+			},
+			wantErr: errors.New("error when assigning role"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{Id: "profile-id-1"}, nil)
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().AssignProfileRole(gomock.Any(), nil, gomock.Any()).Return(false, errors.New("error insert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := roleService{
+				roleRepository:    mockRoleRepository,
+				profileRepository: mockProfileRepository,
+			}
+			err := r.AssignRole(context.TODO(), tt.request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_roleService_UnassignRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRoleRepository := mocks.NewMockRoleRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success unassign role",
+			wantErr: nil,
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().UnassignProfileRole(gomock.Any(), nil, "profile-id-1", "role-id-1").Return(true, nil)
+			},
+		},
+		{
+			name:    "error role not found",
+			wantErr: errors.New("error role not found"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{}, nil)
+			},
+		},
+		{
+			name:    "error role not assigned",
+			wantErr: errors.New("error profile does not hold the role"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().UnassignProfileRole(gomock.Any(), nil, "profile-id-1", "role-id-1").Return(false, nil)
+			},
+		},
+		{
+			name:    "error when unassign role",
+			wantErr: errors.New("error when unassigning role"),
+			mock: func() {
+				mockRoleRepository.EXPECT().GetRoleByName(gomock.Any(), nil, "admin").Return(entity.Role{Id: "role-id-1", Name: "admin"}, nil)
+				mockRoleRepository.EXPECT().UnassignProfileRole(gomock.Any(), nil, "profile-id-1", "role-id-1").Return(false, errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			r := roleService{
+				roleRepository: mockRoleRepository,
+			}
+			err := r.UnassignRole(context.TODO(), entity.UnassignRoleRequest{
+				ProfileId: "profile-id-1",
+				RoleName:  "admin",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
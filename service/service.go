@@ -9,5 +9,71 @@ type ProfileServiceInterface interface {
 	Register(ctx context.Context, request entity.ProfileRegisterRequest) (entity.ProfileRegisterResponse, error)
 	Login(ctx context.Context, request entity.LoginRequest) (entity.LoginResponse, error)
 	UpdateProfile(ctx context.Context, request entity.UpdateProfileRequest) error
+	ChangePassword(ctx context.Context, request entity.ChangePasswordRequest) error
+	SetNewPassword(ctx context.Context, request entity.SetNewPasswordRequest) error
+	ForcePasswordChange(ctx context.Context, request entity.ForcePasswordChangeRequest) error
+	VerifyPhone(ctx context.Context, request entity.VerifyPhoneRequest) error
+	ConfirmPhoneChange(ctx context.Context, request entity.ConfirmPhoneChangeRequest) error
+	PruneUnverifiedProfiles(ctx context.Context) error
+	RequestPasswordReset(ctx context.Context, request entity.RequestPasswordResetRequest) error
+	ConfirmPasswordReset(ctx context.Context, request entity.ConfirmPasswordResetRequest) error
+	PruneOneTimeCodes(ctx context.Context) error
+	EnrollTOTP(ctx context.Context, request entity.EnrollTOTPRequest) (entity.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, request entity.ConfirmTOTPRequest) error
+	DisableTOTP(ctx context.Context, request entity.DisableTOTPRequest) error
+	VerifyLoginMFA(ctx context.Context, request entity.VerifyLoginMFARequest) (entity.LoginResponse, error)
+	PruneMFAChallenges(ctx context.Context) error
+	GenerateRecoveryCodes(ctx context.Context, request entity.GenerateRecoveryCodesRequest) (entity.GenerateRecoveryCodesResponse, error)
 	GetProfile(ctx context.Context, request entity.GetProfileRequest) (entity.GetProfileResponse, error)
 }
+
+type AuthServiceInterface interface {
+	IssueToken(ctx context.Context, request entity.IssueTokenRequest) (entity.IssueTokenResponse, error)
+	RefreshToken(ctx context.Context, request entity.RefreshTokenRequest) (entity.RefreshTokenResponse, error)
+	Authenticate(ctx context.Context, request entity.AuthenticateRequest) (entity.TokenClaims, error)
+	Logout(ctx context.Context, request entity.LogoutRequest) error
+	PruneRevokedTokens(ctx context.Context) error
+	ListSessions(ctx context.Context, request entity.ListSessionsRequest) (entity.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, request entity.RevokeSessionRequest) error
+	RevokeOtherSessions(ctx context.Context, request entity.RevokeOtherSessionsRequest) error
+	RevokeAllSessions(ctx context.Context, request entity.RevokeAllSessionsRequest) error
+	PruneIdleSessions(ctx context.Context) error
+	CreatePersonalAccessToken(ctx context.Context, request entity.CreatePersonalAccessTokenRequest) (entity.CreatePersonalAccessTokenResponse, error)
+	ListPersonalAccessTokens(ctx context.Context, request entity.ListPersonalAccessTokensRequest) (entity.ListPersonalAccessTokensResponse, error)
+	RevokePersonalAccessToken(ctx context.Context, request entity.RevokePersonalAccessTokenRequest) error
+}
+
+type OAuthServiceInterface interface {
+	RegisterOAuthClient(ctx context.Context, request entity.RegisterOAuthClientRequest) (entity.RegisterOAuthClientResponse, error)
+	GetOAuthAuthorization(ctx context.Context, request entity.OAuthAuthorizationRequest) (entity.OAuthAuthorizationResponse, error)
+	ConsentOAuthAuthorization(ctx context.Context, request entity.OAuthConsentRequest) (entity.OAuthConsentResponse, error)
+	ExchangeOAuthToken(ctx context.Context, request entity.OAuthTokenRequest) (entity.OAuthTokenResponse, error)
+	ListOAuthGrants(ctx context.Context, request entity.ListOAuthGrantsRequest) (entity.ListOAuthGrantsResponse, error)
+	RevokeOAuthGrant(ctx context.Context, request entity.RevokeOAuthGrantRequest) error
+	PruneOAuthAuthorizationCodes(ctx context.Context) error
+	AuthorizeOAuthDevice(ctx context.Context, request entity.OAuthDeviceAuthorizationRequest) (entity.OAuthDeviceAuthorizationResponse, error)
+	GetOAuthDevice(ctx context.Context, request entity.OAuthDeviceRequest) (entity.OAuthDeviceResponse, error)
+	ConsentOAuthDevice(ctx context.Context, request entity.OAuthDeviceConsentRequest) error
+	PruneOAuthDeviceCodes(ctx context.Context) error
+	IntrospectToken(ctx context.Context, request entity.IntrospectTokenRequest) (entity.IntrospectTokenResponse, error)
+	GetUserInfo(ctx context.Context, request entity.GetUserInfoRequest) (entity.UserInfo, error)
+	GetOpenIDConfiguration(ctx context.Context) (entity.OpenIDConfiguration, error)
+	EndOIDCSession(ctx context.Context, request entity.OIDCLogoutRequest) (entity.OIDCLogoutResponse, error)
+}
+
+type RoleServiceInterface interface {
+	ListRoles(ctx context.Context) (entity.ListRolesResponse, error)
+	ListProfileRoles(ctx context.Context, request entity.ListProfileRolesRequest) (entity.ListProfileRolesResponse, error)
+	AssignRole(ctx context.Context, request entity.AssignRoleRequest) error
+	UnassignRole(ctx context.Context, request entity.UnassignRoleRequest) error
+}
+
+type SigningKeyServiceInterface interface {
+	RotateSigningKeys(ctx context.Context) error
+	GetJSONWebKeySet(ctx context.Context) (entity.JSONWebKeySet, error)
+}
+
+type RateLimitServiceInterface interface {
+	TakeRateLimitToken(ctx context.Context, request entity.TakeRateLimitTokenRequest) (entity.TakeRateLimitTokenResponse, error)
+	PruneRateLimitBuckets(ctx context.Context) error
+}
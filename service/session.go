@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func (a authService) ListSessions(ctx context.Context, request entity.ListSessionsRequest) (entity.ListSessionsResponse, error) {
+	var res = entity.ListSessionsResponse{}
+
+	sessions, err := a.sessionRepository.GetSessionsByProfileId(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrListSessions
+	}
+
+	res = entity.ListSessionsResponse{
+		Sessions: sessions,
+	}
+
+	return res, nil
+}
+
+func (a authService) RevokeSession(ctx context.Context, request entity.RevokeSessionRequest) error {
+	var deleted bool
+
+	err := a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		deleted, err = a.endSession(ctx, tx, request.ProfileId, request.SessionId)
+		if err != nil {
+			return error_list.ErrRevokeSession
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		return error_list.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (a authService) RevokeOtherSessions(ctx context.Context, request entity.RevokeOtherSessionsRequest) error {
+	return a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		sessionIds, err := a.sessionRepository.DeleteOtherSessions(ctx, tx, request.ProfileId, request.CurrentSessionId)
+		if err != nil {
+			return error_list.ErrRevokeSession
+		}
+
+		for _, sessionId := range sessionIds {
+			err = a.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, sessionId)
+			if err != nil {
+				return error_list.ErrRevokeSession
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a authService) RevokeAllSessions(ctx context.Context, request entity.RevokeAllSessionsRequest) error {
+	return a.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		sessionIds, err := a.sessionRepository.DeleteSessionsByProfileId(ctx, tx, request.ProfileId)
+		if err != nil {
+			return error_list.ErrRevokeSession
+		}
+
+		for _, sessionId := range sessionIds {
+			err = a.refreshTokenRepository.RevokeRefreshTokenFamily(ctx, tx, sessionId)
+			if err != nil {
+				return error_list.ErrRevokeSession
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a authService) PruneIdleSessions(ctx context.Context) error {
+	lastSeenBefore := time.Now().Add(-constant.SessionIdleTimeout).UTC()
+
+	_, err := a.sessionRepository.DeleteIdleSessions(ctx, nil, lastSeenBefore)
+	if err != nil {
+		return error_list.ErrPruneIdleSessions
+	}
+
+	return nil
+}
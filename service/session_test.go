@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authService_ListSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+
+	sessions := []entity.Session{
+		{
+			Id:        "session-id-1",
+			ProfileId: "profile-id-1",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.ListSessionsResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success list sessions",
+			want: entity.ListSessionsResponse{
+				Sessions: sessions,
+			},
+			wantErr: nil,
+			mock: func() {
+				mockSessionRepository.EXPECT().GetSessionsByProfileId(gomock.Any(), nil, "profile-id-1").Return(sessions, nil)
+			},
+		},
+		{
+			name:    "error list sessions",
+			want:    entity.ListSessionsResponse{},
+			wantErr: errors.New("error when listing sessions"),
+			mock: func() {
+				mockSessionRepository.EXPECT().GetSessionsByProfileId(gomock.Any(), nil, "profile-id-1").Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				sessionRepository: mockSessionRepository,
+			}
+			got, err := a.ListSessions(context.TODO(), entity.ListSessionsRequest{
+				ProfileId: "profile-id-1",
+			})
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_RevokeSession(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke session",
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-2").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+			},
+		},
+		{
+			name:    "error session not found",
+			wantErr: errors.New("error session not found"),
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-2").Return(false, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+			},
+		},
+		{
+			name:    "error when revoke refresh tokens",
+			wantErr: errors.New("error when revoking session"),
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSession(gomock.Any(), mockTx, "profile-id-1", "session-id-2").Return(true, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+			}
+			err := a.RevokeSession(context.TODO(), entity.RevokeSessionRequest{
+				ProfileId: "profile-id-1",
+				SessionId: "session-id-2",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_RevokeOtherSessions(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke other sessions",
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteOtherSessions(gomock.Any(), mockTx, "profile-id-1", "session-id-1").
+					Return([]string{"session-id-2", "session-id-3"}, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-3").Return(nil)
+			},
+		},
+		{
+			name:    "error when delete other sessions",
+			wantErr: errors.New("error when revoking session"),
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteOtherSessions(gomock.Any(), mockTx, "profile-id-1", "session-id-1").
+					Return(nil, errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+			}
+			err := a.RevokeOtherSessions(context.TODO(), entity.RevokeOtherSessionsRequest{
+				ProfileId:        "profile-id-1",
+				CurrentSessionId: "session-id-1",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_RevokeAllSessions(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepositoryInterface(ctrl)
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success revoke all sessions",
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSessionsByProfileId(gomock.Any(), mockTx, "profile-id-1").
+					Return([]string{"session-id-1", "session-id-2"}, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-2").Return(nil)
+			},
+		},
+		{
+			name:    "error when delete sessions",
+			wantErr: errors.New("error when revoking session"),
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSessionsByProfileId(gomock.Any(), mockTx, "profile-id-1").
+					Return(nil, errors.New("error delete"))
+			},
+		},
+		{
+			name:    "error when revoke refresh token family",
+			wantErr: errors.New("error when revoking session"),
+			mock: func() {
+				runWithTransaction()
+				mockSessionRepository.EXPECT().DeleteSessionsByProfileId(gomock.Any(), mockTx, "profile-id-1").
+					Return([]string{"session-id-1"}, nil)
+				mockRefreshTokenRepository.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), mockTx, "session-id-1").Return(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				profileRepository:      mockProfileRepository,
+				refreshTokenRepository: mockRefreshTokenRepository,
+				sessionRepository:      mockSessionRepository,
+			}
+			err := a.RevokeAllSessions(context.TODO(), entity.RevokeAllSessionsRequest{
+				ProfileId: "profile-id-1",
+			})
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_authService_PruneIdleSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepository := mocks.NewMockSessionRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockSessionRepository.EXPECT().DeleteIdleSessions(gomock.Any(), nil, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, lastSeenBefore time.Time) (int64, error) {
+						assert.True(t, lastSeenBefore.Before(time.Now().Add(-24*time.Hour)))
+						return 2, nil
+					},
+				)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning idle sessions"),
+			mock: func() {
+				mockSessionRepository.EXPECT().DeleteIdleSessions(gomock.Any(), nil, gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			a := authService{
+				sessionRepository: mockSessionRepository,
+			}
+			err := a.PruneIdleSessions(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
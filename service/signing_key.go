@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/helper"
+	"sawitpro/repository"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type signingKeyService struct {
+	signingKeyRepository repository.SigningKeyRepositoryInterface
+	keyRing              helper.KeyRingInterface
+}
+
+type SigningKeyServiceDeps struct {
+	SigningKeyRepository repository.SigningKeyRepositoryInterface
+	KeyRing              helper.KeyRingInterface
+}
+
+func NewSigningKeyService(deps SigningKeyServiceDeps) signingKeyService {
+	return signingKeyService{
+		signingKeyRepository: deps.SigningKeyRepository,
+		keyRing:              deps.KeyRing,
+	}
+}
+
+// RotateSigningKeys runs on every instance at a short interval. It mints a
+// replacement key once the newest key is older than the rotation interval,
+// schedules the keys it supersedes for retirement, drops fully retired keys
+// and reloads the key ring with whatever is left.
+func (s signingKeyService) RotateSigningKeys(ctx context.Context) error {
+	var keys []entity.SigningKey
+
+	err := s.signingKeyRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		err := s.signingKeyRepository.LockSigningKeys(ctx, tx)
+		if err != nil {
+			return error_list.ErrRotateSigningKeys
+		}
+
+		now := time.Now().UTC()
+
+		err = s.signingKeyRepository.DeleteRetiredSigningKeys(ctx, tx, now)
+		if err != nil {
+			return error_list.ErrRotateSigningKeys
+		}
+
+		keys, err = s.signingKeyRepository.GetSigningKeys(ctx, tx)
+		if err != nil {
+			return error_list.ErrRotateSigningKeys
+		}
+
+		newest := newestSigningKey(keys, now.Add(constant.SigningKeyPublishLeadTime))
+		if newest == nil ||
+			newest.Algorithm != s.keyRing.Algorithm(ctx) ||
+			now.Sub(newest.ActivatesAt) >= constant.SigningKeyRotationInterval {
+			key, err := s.keyRing.GenerateKey(ctx)
+			if err != nil {
+				return error_list.ErrRotateSigningKeys
+			}
+
+			// without any usable key there is nothing to overlap with, so the
+			// first key signs right away
+			key.ActivatesAt = now
+			if newest != nil {
+				key.ActivatesAt = now.Add(constant.SigningKeyPublishLeadTime)
+			}
+
+			err = s.signingKeyRepository.InsertSigningKey(ctx, tx, key)
+			if err != nil {
+				return error_list.ErrRotateSigningKeys
+			}
+
+			keys = append(keys, key)
+		}
+
+		active := newestSigningKey(keys, now)
+		if active == nil {
+			return nil
+		}
+
+		for i := range keys {
+			if keys[i].RetiresAt != nil || !keys[i].ActivatesAt.Before(active.ActivatesAt) {
+				continue
+			}
+
+			retiresAt := now.Add(constant.SigningKeyRetirementOverlap)
+
+			err = s.signingKeyRepository.RetireSigningKey(ctx, tx, keys[i].Id, retiresAt)
+			if err != nil {
+				return error_list.ErrRotateSigningKeys
+			}
+
+			keys[i].RetiresAt = &retiresAt
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.keyRing.LoadKeys(ctx, keys)
+	if err != nil {
+		return error_list.ErrRotateSigningKeys
+	}
+
+	return nil
+}
+
+func (s signingKeyService) GetJSONWebKeySet(ctx context.Context) (entity.JSONWebKeySet, error) {
+	res := entity.JSONWebKeySet{
+		Keys: s.keyRing.JSONWebKeys(ctx),
+	}
+
+	return res, nil
+}
+
+// newestSigningKey returns the most recently activated key that is not being
+// retired and activates no later than the given time.
+func newestSigningKey(keys []entity.SigningKey, activeAt time.Time) *entity.SigningKey {
+	var newest *entity.SigningKey
+
+	for i := range keys {
+		if keys[i].RetiresAt != nil || keys[i].ActivatesAt.After(activeAt) {
+			continue
+		}
+
+		if newest == nil || keys[i].ActivatesAt.After(newest.ActivatesAt) {
+			newest = &keys[i]
+		}
+	}
+
+	return newest
+}
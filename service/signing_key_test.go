@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/helper"
+	"sawitpro/mocks"
+	"sawitpro/repository"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSigningKeyService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigningKeyRepository := mocks.NewMockSigningKeyRepositoryInterface(ctrl)
+	mockKeyRing := mocks.NewMockKeyRingInterface(ctrl)
+
+	got := NewSigningKeyService(SigningKeyServiceDeps{
+		SigningKeyRepository: mockSigningKeyRepository,
+		KeyRing:              mockKeyRing,
+	})
+	assert.Equal(t, signingKeyService{
+		signingKeyRepository: mockSigningKeyRepository,
+		keyRing:              mockKeyRing,
+	}, got)
+}
+
+func Test_signingKeyService_RotateSigningKeys(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSigningKeyRepository := mocks.NewMockSigningKeyRepositoryInterface(ctrl)
+	mockKeyRing := mocks.NewMockKeyRingInterface(ctrl)
+
+	now := time.Now().UTC()
+
+	runWithTransaction := func() {
+		mockSigningKeyRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+		mockSigningKeyRepository.EXPECT().LockSigningKeys(gomock.Any(), mockTx).Return(nil)
+		mockSigningKeyRepository.EXPECT().DeleteRetiredSigningKeys(gomock.Any(), mockTx, gomock.Any()).Return(nil)
+	}
+
+	type fields struct {
+		signingKeyRepository repository.SigningKeyRepositoryInterface
+		keyRing              helper.KeyRingInterface
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "first key is active immediately",
+			fields: fields{
+				signingKeyRepository: mockSigningKeyRepository,
+				keyRing:              mockKeyRing,
+			},
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSigningKeyRepository.EXPECT().GetSigningKeys(gomock.Any(), mockTx).Return(nil, nil)
+				mockKeyRing.EXPECT().GenerateKey(gomock.Any()).Return(entity.SigningKey{
+					Id:        "key-1",
+					Algorithm: "RS256",
+				}, nil)
+				mockSigningKeyRepository.EXPECT().InsertSigningKey(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, key entity.SigningKey) error {
+						assert.Equal(t, "key-1", key.Id)
+						assert.False(t, key.ActivatesAt.After(time.Now()))
+						return nil
+					},
+				)
+				mockKeyRing.EXPECT().LoadKeys(gomock.Any(), gomock.Len(1)).Return(nil)
+			},
+		},
+		{
+			name: "current key is kept while it is fresh",
+			fields: fields{
+				signingKeyRepository: mockSigningKeyRepository,
+				keyRing:              mockKeyRing,
+			},
+			wantErr: nil,
+			mock: func() {
+				keys := []entity.SigningKey{
+					{
+						Id:          "key-1",
+						Algorithm:   "RS256",
+						ActivatesAt: now.Add(-time.Hour),
+					},
+				}
+
+				runWithTransaction()
+				mockSigningKeyRepository.EXPECT().GetSigningKeys(gomock.Any(), mockTx).Return(keys, nil)
+				mockKeyRing.EXPECT().Algorithm(gomock.Any()).Return("RS256")
+				mockKeyRing.EXPECT().LoadKeys(gomock.Any(), keys).Return(nil)
+			},
+		},
+		{
+			name: "expired rotation period publishes a pending key",
+			fields: fields{
+				signingKeyRepository: mockSigningKeyRepository,
+				keyRing:              mockKeyRing,
+			},
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSigningKeyRepository.EXPECT().GetSigningKeys(gomock.Any(), mockTx).Return([]entity.SigningKey{
+					{
+						Id:          "key-1",
+						Algorithm:   "RS256",
+						ActivatesAt: now.Add(-31 * 24 * time.Hour),
+					},
+				}, nil)
+				mockKeyRing.EXPECT().Algorithm(gomock.Any()).Return("RS256")
+				mockKeyRing.EXPECT().GenerateKey(gomock.Any()).Return(entity.SigningKey{
+					Id:        "key-2",
+					Algorithm: "RS256",
+				}, nil)
+				mockSigningKeyRepository.EXPECT().InsertSigningKey(gomock.Any(), mockTx, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sqlx.Tx, key entity.SigningKey) error {
+						assert.True(t, key.ActivatesAt.After(time.Now()))
+						return nil
+					},
+				)
+				mockKeyRing.EXPECT().LoadKeys(gomock.Any(), gomock.Len(2)).Return(nil)
+			},
+		},
+		{
+			name: "activated key retires its predecessor",
+			fields: fields{
+				signingKeyRepository: mockSigningKeyRepository,
+				keyRing:              mockKeyRing,
+			},
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockSigningKeyRepository.EXPECT().GetSigningKeys(gomock.Any(), mockTx).Return([]entity.SigningKey{
+					{
+						Id:          "key-1",
+						Algorithm:   "RS256",
+						ActivatesAt: now.Add(-31 * 24 * time.Hour),
+					},
+					{
+						Id:          "key-2",
+						Algorithm:   "RS256",
+						ActivatesAt: now.Add(-time.Minute),
+					},
+				}, nil)
+				mockKeyRing.EXPECT().Algorithm(gomock.Any()).Return("RS256")
+				mockSigningKeyRepository.EXPECT().RetireSigningKey(gomock.Any(), mockTx, "key-1", gomock.Any()).Return(nil)
+				mockKeyRing.EXPECT().LoadKeys(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, keys []entity.SigningKey) error {
+						assert.NotNil(t, keys[0].RetiresAt)
+						assert.Nil(t, keys[1].RetiresAt)
+						return nil
+					},
+				)
+			},
+		},
+		{
+			name: "error when get signing keys",
+			fields: fields{
+				signingKeyRepository: mockSigningKeyRepository,
+				keyRing:              mockKeyRing,
+			},
+			wantErr: errors.New("error when rotating signing keys"),
+			mock: func() {
+				runWithTransaction()
+				mockSigningKeyRepository.EXPECT().GetSigningKeys(gomock.Any(), mockTx).Return(nil, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			s := signingKeyService{
+				signingKeyRepository: tt.fields.signingKeyRepository,
+				keyRing:              tt.fields.keyRing,
+			}
+			err := s.RotateSigningKeys(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_signingKeyService_GetJSONWebKeySet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKeyRing := mocks.NewMockKeyRingInterface(ctrl)
+
+	keys := []entity.JSONWebKey{
+		{
+			KeyType:   "OKP",
+			KeyId:     "key-1",
+			Use:       "sig",
+			Algorithm: "EdDSA",
+			Curve:     "Ed25519",
+			X:         "x",
+		},
+	}
+	mockKeyRing.EXPECT().JSONWebKeys(gomock.Any()).Return(keys)
+
+	s := signingKeyService{
+		keyRing: mockKeyRing,
+	}
+	got, err := s.GetJSONWebKeySet(context.TODO())
+	assert.Equal(t, entity.JSONWebKeySet{Keys: keys}, got)
+	assert.Nil(t, err)
+}
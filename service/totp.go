@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"sawitpro/constant"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// EnrollTOTP generates a new TOTP secret for the profile. It only replaces
+// the secret logins are checked against once ConfirmTOTP has seen a code
+// generated from it, so re-enrolling cannot lock the owner out halfway.
+func (p profileService) EnrollTOTP(ctx context.Context, request entity.EnrollTOTPRequest) (entity.EnrollTOTPResponse, error) {
+	var res = entity.EnrollTOTPResponse{}
+
+	profile, err := p.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return res, error_list.ErrEnrollTOTP
+	}
+
+	if profile.Id == "" {
+		return res, error_list.ErrProfileNotFound
+	}
+
+	err = p.verifyCurrentPassword(ctx, profile, request.Password)
+	if err != nil {
+		if err == error_list.ErrCurrentPasswordNotMatch || err == error_list.ErrAccountLocked {
+			return res, err
+		}
+		return res, error_list.ErrEnrollTOTP
+	}
+
+	secret, err := p.totpHelper.GenerateSecret(ctx)
+	if err != nil {
+		return res, error_list.ErrEnrollTOTP
+	}
+
+	encryptedSecret, err := p.totpHelper.EncryptSecret(ctx, secret)
+	if err != nil {
+		return res, error_list.ErrEnrollTOTP
+	}
+
+	err = p.totpCredentialRepository.SetPendingTOTPSecret(ctx, nil, profile.Id, encryptedSecret)
+	if err != nil {
+		return res, error_list.ErrEnrollTOTP
+	}
+
+	res = entity.EnrollTOTPResponse{
+		Secret: secret,
+		KeyURI: p.totpHelper.KeyURI(ctx, profile.PhoneNumber, secret),
+	}
+
+	return res, nil
+}
+
+func (p profileService) ConfirmTOTP(ctx context.Context, request entity.ConfirmTOTPRequest) error {
+	return p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		credential, err := p.totpCredentialRepository.GetTOTPCredential(ctx, tx, request.ProfileId)
+		if err != nil {
+			return error_list.ErrConfirmTOTP
+		}
+
+		if credential.PendingSecret == nil {
+			return error_list.ErrTOTPNotPending
+		}
+
+		secret, err := p.totpHelper.DecryptSecret(ctx, *credential.PendingSecret)
+		if err != nil {
+			return error_list.ErrConfirmTOTP
+		}
+
+		now := time.Now()
+
+		step, matched := p.totpHelper.MatchCode(ctx, secret, request.Code, now)
+		if !matched {
+			return error_list.ErrInvalidTOTPCode
+		}
+
+		err = p.totpCredentialRepository.EnableTOTP(ctx, tx, request.ProfileId, step, now.UTC())
+		if err != nil {
+			return error_list.ErrConfirmTOTP
+		}
+
+		return nil
+	})
+}
+
+func (p profileService) DisableTOTP(ctx context.Context, request entity.DisableTOTPRequest) error {
+	profile, err := p.profileRepository.GetProfileById(ctx, nil, request.ProfileId)
+	if err != nil {
+		return error_list.ErrDisableTOTP
+	}
+
+	if profile.Id == "" {
+		return error_list.ErrProfileNotFound
+	}
+
+	err = p.verifyCurrentPassword(ctx, profile, request.Password)
+	if err != nil {
+		if err == error_list.ErrCurrentPasswordNotMatch || err == error_list.ErrAccountLocked {
+			return err
+		}
+		return error_list.ErrDisableTOTP
+	}
+
+	deleted, err := p.totpCredentialRepository.DeleteTOTPCredential(ctx, nil, profile.Id)
+	if err != nil {
+		return error_list.ErrDisableTOTP
+	}
+
+	if !deleted {
+		return error_list.ErrTOTPNotEnabled
+	}
+
+	return nil
+}
+
+// VerifyLoginMFA completes a login that was answered with an MFA challenge,
+// given a TOTP code the profile has not used before.
+func (p profileService) VerifyLoginMFA(ctx context.Context, request entity.VerifyLoginMFARequest) (entity.LoginResponse, error) {
+	var res = entity.LoginResponse{}
+	var profile entity.UserProfile
+	var codeErr error
+
+	now := time.Now()
+
+	err := p.profileRepository.RunWithTransaction(ctx, func(tx *sqlx.Tx) error {
+		challenge, err := p.mfaChallengeRepository.GetMFAChallengeByHash(ctx, tx, p.authhelper.HashToken(ctx, request.MFAToken))
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		if challenge.Id == "" || now.After(challenge.ExpiresAt) {
+			return error_list.ErrInvalidMFAChallenge
+		}
+
+		if challenge.Attempts >= constant.MFAChallengeMaxAttempts {
+			return error_list.ErrMFAChallengeAttemptsExceeded
+		}
+
+		profile, err = p.profileRepository.GetProfileById(ctx, tx, challenge.ProfileId)
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		if profile.Id == "" {
+			return error_list.ErrInvalidMFAChallenge
+		}
+
+		if profile.LockedUntil != nil && now.Before(*profile.LockedUntil) {
+			return error_list.ErrAccountLocked
+		}
+
+		credential, err := p.totpCredentialRepository.GetTOTPCredential(ctx, tx, profile.Id)
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		// two-factor authentication was turned off since the challenge
+		if credential.Secret == nil {
+			return error_list.ErrInvalidMFAChallenge
+		}
+
+		secret, err := p.totpHelper.DecryptSecret(ctx, *credential.Secret)
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		// a code is refused once its step has been used, even while it is
+		// still inside the drift window, so an observed code cannot be
+		// replayed
+		step, matched := p.totpHelper.MatchCode(ctx, secret, request.Code, now)
+		if !matched || step <= credential.LastUsedStep {
+			err = p.mfaChallengeRepository.IncreaseMFAChallengeAttempts(ctx, tx, challenge.Id)
+			if err != nil {
+				return error_list.ErrLogin
+			}
+
+			// commit the failed attempt, the code error is returned below
+			codeErr = error_list.ErrInvalidTOTPCode
+			return nil
+		}
+
+		err = p.totpCredentialRepository.UpdateTOTPLastUsedStep(ctx, tx, profile.Id, step)
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		err = p.mfaChallengeRepository.DeleteMFAChallenge(ctx, tx, challenge.Id)
+		if err != nil {
+			return error_list.ErrLogin
+		}
+
+		return nil
+	})
+	if err != nil {
+		return res, err
+	}
+
+	if codeErr != nil {
+		// wrong codes count as failed logins, so the lockout bounds guessing
+		// of the second factor across challenges too
+		err = p.recordFailedLogin(ctx, profile.Id)
+		if err != nil {
+			return res, error_list.ErrLogin
+		}
+		return res, codeErr
+	}
+
+	return p.completeLogin(ctx, profile, entity.IssueTokenRequest{
+		ProfileId:  profile.Id,
+		DeviceName: request.DeviceName,
+		UserAgent:  request.UserAgent,
+		IpAddress:  request.IpAddress,
+	})
+}
+
+func (p profileService) PruneMFAChallenges(ctx context.Context) error {
+	_, err := p.mfaChallengeRepository.DeleteExpiredMFAChallenges(ctx, nil, time.Now().UTC())
+	if err != nil {
+		return error_list.ErrPruneMFAChallenges
+	}
+
+	return nil
+}
+
+// startMFAChallenge answers a correct password of a profile with two-factor
+// authentication on. The challenge token is opaque and only its hash is kept.
+func (p profileService) startMFAChallenge(ctx context.Context, profileId string) (entity.LoginResponse, error) {
+	var res = entity.LoginResponse{}
+
+	token, err := p.authhelper.GenerateRefreshToken(ctx)
+	if err != nil {
+		return res, error_list.ErrLogin
+	}
+
+	_, err = p.mfaChallengeRepository.InsertMFAChallenge(ctx, nil, entity.MFAChallenge{
+		ProfileId: profileId,
+		TokenHash: p.authhelper.HashToken(ctx, token),
+		ExpiresAt: time.Now().Add(constant.MFAChallengeDuration).UTC(),
+	})
+	if err != nil {
+		return res, error_list.ErrLogin
+	}
+
+	res = entity.LoginResponse{
+		MFAToken:     token,
+		MFAExpiresIn: int64(constant.MFAChallengeDuration / time.Second),
+	}
+
+	return res, nil
+}
@@ -0,0 +1,543 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sawitpro/entity"
+	"sawitpro/error_list"
+	"sawitpro/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_profileService_EnrollTOTP(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockTOTPHelper := mocks.NewMockTOTPHelperInterface(ctrl)
+
+	request := entity.EnrollTOTPRequest{
+		ProfileId: "profile-id-1",
+		Password:  "12345A!",
+	}
+	profile := entity.UserProfile{
+		Id:          "profile-id-1",
+		PhoneNumber: "+62812345678",
+		Password:    "hashed-password",
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.EnrollTOTPResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success enroll",
+			want: entity.EnrollTOTPResponse{
+				Secret: "JBSWY3DPEHPK3PXP",
+				KeyURI: "otpauth://totp/SawitPro:%2B62812345678?secret=JBSWY3DPEHPK3PXP",
+			},
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(nil)
+				mockTOTPHelper.EXPECT().GenerateSecret(gomock.Any()).Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTPHelper.EXPECT().EncryptSecret(gomock.Any(), "JBSWY3DPEHPK3PXP").Return("encrypted-secret", nil)
+				mockTOTPCredentialRepository.EXPECT().SetPendingTOTPSecret(gomock.Any(), nil, "profile-id-1", "encrypted-secret").Return(nil)
+				mockTOTPHelper.EXPECT().KeyURI(gomock.Any(), "+62812345678", "JBSWY3DPEHPK3PXP").
+					Return("otpauth://totp/SawitPro:%2B62812345678?secret=JBSWY3DPEHPK3PXP")
+			},
+		},
+		{
+			name:    "error wrong password",
+			want:    entity.EnrollTOTPResponse{},
+			wantErr: errors.New("error current password not match"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(error_list.ErrPasswordNotMatch)
+				// counted as a failed login, the token alone cannot guess on
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(1, nil)
+			},
+		},
+		{
+			name:    "error account locked",
+			want:    entity.EnrollTOTPResponse{},
+			wantErr: error_list.ErrAccountLocked,
+			mock: func() {
+				lockedUntil := time.Now().Add(time.Minute)
+				lockedProfile := profile
+				lockedProfile.LockedUntil = &lockedUntil
+
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(lockedProfile, nil)
+			},
+		},
+		{
+			name:    "error profile not found",
+			want:    entity.EnrollTOTPResponse{},
+			wantErr: errors.New("error profile not found"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(entity.UserProfile{}, nil)
+			},
+		},
+		{
+			name:    "error when store pending secret",
+			want:    entity.EnrollTOTPResponse{},
+			wantErr: errors.New("error when enrolling two-factor authentication"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(nil)
+				mockTOTPHelper.EXPECT().GenerateSecret(gomock.Any()).Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTPHelper.EXPECT().EncryptSecret(gomock.Any(), "JBSWY3DPEHPK3PXP").Return("encrypted-secret", nil)
+				mockTOTPCredentialRepository.EXPECT().SetPendingTOTPSecret(gomock.Any(), nil, "profile-id-1", "encrypted-secret").Return(errors.New("error upsert"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:        mockProfileRepository,
+				totpCredentialRepository: mockTOTPCredentialRepository,
+				authhelper:               mockHelper,
+				totpHelper:               mockTOTPHelper,
+			}
+			got, err := p.EnrollTOTP(context.TODO(), request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_ConfirmTOTP(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockTOTPHelper := mocks.NewMockTOTPHelperInterface(ctrl)
+
+	request := entity.ConfirmTOTPRequest{
+		ProfileId: "profile-id-1",
+		Code:      "123456",
+	}
+	pendingSecret := "encrypted-pending-secret"
+	credential := entity.TOTPCredential{
+		ProfileId:     "profile-id-1",
+		PendingSecret: &pendingSecret,
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success confirm",
+			wantErr: nil,
+			mock: func() {
+				runWithTransaction()
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(credential, nil)
+				mockTOTPHelper.EXPECT().DecryptSecret(gomock.Any(), "encrypted-pending-secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTPHelper.EXPECT().MatchCode(gomock.Any(), "JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(57000000), true)
+				mockTOTPCredentialRepository.EXPECT().EnableTOTP(gomock.Any(), mockTx, "profile-id-1", int64(57000000), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "error nothing to confirm",
+			wantErr: errors.New("error no two-factor enrollment to confirm"),
+			mock: func() {
+				runWithTransaction()
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+			},
+		},
+		{
+			name:    "error wrong code",
+			wantErr: errors.New("error invalid two-factor code"),
+			mock: func() {
+				runWithTransaction()
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(credential, nil)
+				mockTOTPHelper.EXPECT().DecryptSecret(gomock.Any(), "encrypted-pending-secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTPHelper.EXPECT().MatchCode(gomock.Any(), "JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(0), false)
+			},
+		},
+		{
+			name:    "error when enable",
+			wantErr: errors.New("error when confirming two-factor authentication"),
+			mock: func() {
+				runWithTransaction()
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(credential, nil)
+				mockTOTPHelper.EXPECT().DecryptSecret(gomock.Any(), "encrypted-pending-secret").Return("JBSWY3DPEHPK3PXP", nil)
+				mockTOTPHelper.EXPECT().MatchCode(gomock.Any(), "JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(int64(57000000), true)
+				mockTOTPCredentialRepository.EXPECT().EnableTOTP(gomock.Any(), mockTx, "profile-id-1", int64(57000000), gomock.Any()).Return(errors.New("error update"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:        mockProfileRepository,
+				totpCredentialRepository: mockTOTPCredentialRepository,
+				totpHelper:               mockTOTPHelper,
+			}
+			err := p.ConfirmTOTP(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_DisableTOTP(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+
+	request := entity.DisableTOTPRequest{
+		ProfileId: "profile-id-1",
+		Password:  "12345A!",
+	}
+	profile := entity.UserProfile{
+		Id:       "profile-id-1",
+		Password: "hashed-password",
+	}
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success disable",
+			wantErr: nil,
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(nil)
+				mockTOTPCredentialRepository.EXPECT().DeleteTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(true, nil)
+			},
+		},
+		{
+			name:    "error not enabled",
+			wantErr: errors.New("error two-factor authentication is not enabled"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(nil)
+				mockTOTPCredentialRepository.EXPECT().DeleteTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(false, nil)
+			},
+		},
+		{
+			name:    "error wrong password",
+			wantErr: errors.New("error current password not match"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(error_list.ErrPasswordNotMatch)
+				// counted as a failed login, the token alone cannot guess on
+				mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+						return handleFunc(mockTx)
+					},
+				)
+				mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(1, nil)
+			},
+		},
+		{
+			name:    "error account locked",
+			wantErr: error_list.ErrAccountLocked,
+			mock: func() {
+				lockedUntil := time.Now().Add(time.Minute)
+				lockedProfile := profile
+				lockedProfile.LockedUntil = &lockedUntil
+
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(lockedProfile, nil)
+			},
+		},
+		{
+			name:    "error when delete",
+			wantErr: errors.New("error when disabling two-factor authentication"),
+			mock: func() {
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), nil, "profile-id-1").Return(profile, nil)
+				mockHelper.EXPECT().VerifyPassword(gomock.Any(), "12345A!", "hashed-password").Return(nil)
+				mockTOTPCredentialRepository.EXPECT().DeleteTOTPCredential(gomock.Any(), nil, "profile-id-1").Return(false, errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:        mockProfileRepository,
+				totpCredentialRepository: mockTOTPCredentialRepository,
+				authhelper:               mockHelper,
+			}
+			err := p.DisableTOTP(context.TODO(), request)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_VerifyLoginMFA(t *testing.T) {
+	mockTx := &sqlx.Tx{}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProfileRepository := mocks.NewMockUserProfileRepositoryInterface(ctrl)
+	mockTOTPCredentialRepository := mocks.NewMockTOTPCredentialRepositoryInterface(ctrl)
+	mockMFAChallengeRepository := mocks.NewMockMFAChallengeRepositoryInterface(ctrl)
+	mockHelper := mocks.NewMockAuthHelperInterface(ctrl)
+	mockTOTPHelper := mocks.NewMockTOTPHelperInterface(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+
+	request := entity.VerifyLoginMFARequest{
+		MFAToken:   "mfa-token-1",
+		Code:       "123456",
+		DeviceName: "field tablet",
+	}
+	challenge := entity.MFAChallenge{
+		Id:        "challenge-id-1",
+		ProfileId: "profile-id-1",
+		TokenHash: "mfa-token-hash-1",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	profile := entity.UserProfile{
+		Id:               "profile-id-1",
+		PhoneNumber:      "+62812345678",
+		FailedLoginCount: 1,
+	}
+	secret := "encrypted-secret"
+	credential := entity.TOTPCredential{
+		ProfileId:    "profile-id-1",
+		Secret:       &secret,
+		LastUsedStep: 56999999,
+	}
+
+	runWithTransaction := func() {
+		mockProfileRepository.EXPECT().RunWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, handleFunc func(tx *sqlx.Tx) error) interface{} {
+				return handleFunc(mockTx)
+			},
+		)
+	}
+	loadChallenge := func(challenge entity.MFAChallenge) {
+		runWithTransaction()
+		mockHelper.EXPECT().HashToken(gomock.Any(), "mfa-token-1").Return("mfa-token-hash-1")
+		mockMFAChallengeRepository.EXPECT().GetMFAChallengeByHash(gomock.Any(), mockTx, "mfa-token-hash-1").Return(challenge, nil)
+	}
+	matchCode := func(step int64, matched bool) {
+		mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+		mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(credential, nil)
+		mockTOTPHelper.EXPECT().DecryptSecret(gomock.Any(), "encrypted-secret").Return("JBSWY3DPEHPK3PXP", nil)
+		mockTOTPHelper.EXPECT().MatchCode(gomock.Any(), "JBSWY3DPEHPK3PXP", "123456", gomock.Any()).Return(step, matched)
+	}
+	rejectCode := func() {
+		mockMFAChallengeRepository.EXPECT().IncreaseMFAChallengeAttempts(gomock.Any(), mockTx, "challenge-id-1").Return(nil)
+		runWithTransaction()
+		mockProfileRepository.EXPECT().IncreaseFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(2, nil)
+	}
+
+	tests := []struct {
+		name    string
+		want    entity.LoginResponse
+		wantErr error
+		mock    func()
+	}{
+		{
+			name: "success verify",
+			want: entity.LoginResponse{
+				Token:        "token-1",
+				RefreshToken: "refresh-token-1",
+				ExpiresIn:    900,
+			},
+			wantErr: nil,
+			mock: func() {
+				loadChallenge(challenge)
+				matchCode(57000000, true)
+				mockTOTPCredentialRepository.EXPECT().UpdateTOTPLastUsedStep(gomock.Any(), mockTx, "profile-id-1", int64(57000000)).Return(nil)
+				mockMFAChallengeRepository.EXPECT().DeleteMFAChallenge(gomock.Any(), mockTx, "challenge-id-1").Return(nil)
+				mockAuthService.EXPECT().IssueToken(gomock.Any(), entity.IssueTokenRequest{
+					ProfileId:  "profile-id-1",
+					DeviceName: "field tablet",
+				}).Return(entity.IssueTokenResponse{
+					Token:        "token-1",
+					RefreshToken: "refresh-token-1",
+					ExpiresIn:    900,
+				}, nil)
+				runWithTransaction()
+				mockProfileRepository.EXPECT().IncreaseSuccessLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+				mockProfileRepository.EXPECT().ResetFailedLoginCount(gomock.Any(), mockTx, "profile-id-1").Return(nil)
+			},
+		},
+		{
+			name:    "error wrong code counts as failed login",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error invalid two-factor code"),
+			mock: func() {
+				loadChallenge(challenge)
+				matchCode(0, false)
+				rejectCode()
+			},
+		},
+		{
+			name:    "error replayed code",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error invalid two-factor code"),
+			mock: func() {
+				loadChallenge(challenge)
+				matchCode(56999999, true)
+				rejectCode()
+			},
+		},
+		{
+			name:    "error unknown challenge",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error invalid or expired two-factor challenge"),
+			mock: func() {
+				loadChallenge(entity.MFAChallenge{})
+			},
+		},
+		{
+			name:    "error expired challenge",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error invalid or expired two-factor challenge"),
+			mock: func() {
+				expiredChallenge := challenge
+				expiredChallenge.ExpiresAt = time.Now().Add(-time.Second)
+
+				loadChallenge(expiredChallenge)
+			},
+		},
+		{
+			name:    "error too many attempts",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error too many attempts for this two-factor challenge"),
+			mock: func() {
+				exhaustedChallenge := challenge
+				exhaustedChallenge.Attempts = 5
+
+				loadChallenge(exhaustedChallenge)
+			},
+		},
+		{
+			name:    "error account locked",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error account is temporarily locked, try again later"),
+			mock: func() {
+				lockedUntil := time.Now().Add(time.Minute)
+				lockedProfile := profile
+				lockedProfile.LockedUntil = &lockedUntil
+
+				loadChallenge(challenge)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(lockedProfile, nil)
+			},
+		},
+		{
+			name:    "error two-factor turned off meanwhile",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error invalid or expired two-factor challenge"),
+			mock: func() {
+				loadChallenge(challenge)
+				mockProfileRepository.EXPECT().GetProfileById(gomock.Any(), mockTx, "profile-id-1").Return(profile, nil)
+				mockTOTPCredentialRepository.EXPECT().GetTOTPCredential(gomock.Any(), mockTx, "profile-id-1").Return(entity.TOTPCredential{}, nil)
+			},
+		},
+		{
+			name:    "error when get challenge",
+			want:    entity.LoginResponse{},
+			wantErr: errors.New("error when try to login"),
+			mock: func() {
+				runWithTransaction()
+				mockHelper.EXPECT().HashToken(gomock.Any(), "mfa-token-1").Return("mfa-token-hash-1")
+				mockMFAChallengeRepository.EXPECT().GetMFAChallengeByHash(gomock.Any(), mockTx, "mfa-token-hash-1").Return(entity.MFAChallenge{}, errors.New("error select"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				profileRepository:        mockProfileRepository,
+				totpCredentialRepository: mockTOTPCredentialRepository,
+				mfaChallengeRepository:   mockMFAChallengeRepository,
+				authhelper:               mockHelper,
+				totpHelper:               mockTOTPHelper,
+				authService:              mockAuthService,
+				loginLockoutPolicy: LoginLockoutPolicy{
+					DelayAfter:      3,
+					BaseDelay:       time.Second,
+					LockoutAfter:    10,
+					LockoutDuration: 15 * time.Minute,
+				},
+			}
+			got, err := p.VerifyLoginMFA(context.TODO(), request)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_profileService_PruneMFAChallenges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFAChallengeRepository := mocks.NewMockMFAChallengeRepositoryInterface(ctrl)
+
+	tests := []struct {
+		name    string
+		wantErr error
+		mock    func()
+	}{
+		{
+			name:    "success prune",
+			wantErr: nil,
+			mock: func() {
+				mockMFAChallengeRepository.EXPECT().DeleteExpiredMFAChallenges(gomock.Any(), nil, gomock.Any()).Return(int64(2), nil)
+			},
+		},
+		{
+			name:    "error prune",
+			wantErr: errors.New("error when pruning two-factor challenges"),
+			mock: func() {
+				mockMFAChallengeRepository.EXPECT().DeleteExpiredMFAChallenges(gomock.Any(), nil, gomock.Any()).Return(int64(0), errors.New("error delete"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			p := profileService{
+				mfaChallengeRepository: mockMFAChallengeRepository,
+			}
+			err := p.PruneMFAChallenges(context.TODO())
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}